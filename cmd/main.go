@@ -1,20 +1,49 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"flag"
 	"fmt"
 	"log"
 	"time"
+	// Store schedule is set in IANA timezone, which should be available in any image
+	_ "time/tzdata"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sonyamoonglade/sancho-backend/internal/config"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	"github.com/sonyamoonglade/sancho-backend/internal/handler"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/middleware"
+	service "github.com/sonyamoonglade/sancho-backend/internal/services"
+	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
+	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
+	"github.com/sonyamoonglade/sancho-backend/pkg/blob"
 	"github.com/sonyamoonglade/sancho-backend/pkg/database"
+	"github.com/sonyamoonglade/sancho-backend/pkg/eventbus"
+	"github.com/sonyamoonglade/sancho-backend/pkg/hash"
 	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
+	"github.com/sonyamoonglade/sancho-backend/pkg/meta_cache"
+	"github.com/sonyamoonglade/sancho-backend/pkg/permission_cache"
+	"github.com/sonyamoonglade/sancho-backend/pkg/revocation_cache"
+	"github.com/sonyamoonglade/sancho-backend/pkg/sms"
+	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
 )
 
+// Server-Sent Events responses are written for as long as client is connected, so
+// they can't be limited with regular WriteTimeout. Clients reconnect with Last-Event-ID.
+const streamWriteTimeout = time.Hour
+
+// orderEventsPath is the only route that streams events, see handler.StreamOrderEvents
+const orderEventsPath = "/api/order/events"
+
+const (
+	// multipartOverhead is room for multipart headers and form fields of image upload
+	multipartOverhead = 1 << 20
+	blobMaxAge        = time.Hour * 24 * 365
+)
+
 func main() {
 	if err := run(); err != nil {
 		log.Fatal(err)
@@ -46,14 +75,98 @@ func run() error {
 	if err != nil {
 		return fmt.Errorf("error connecting to mongo: %v", err)
 	}
-	_ = mongo
+	// Categories are created and reordered in transactions
+	if err := mongo.CheckTransactions(ctx); err != nil {
+		return fmt.Errorf("error checking mongo: %v", err)
+	}
 
+	keySet, err := auth.NewKeySet(cfg.Auth.SigningKeyID, cfg.Auth.Keys...)
+	if err != nil {
+		return fmt.Errorf("error creating token provider: %v", err)
+	}
+	tokenProvider := auth.NewProviderWithKeys(
+		cfg.Auth.TTLStrategy.AccessTokenTTLs[domain.RoleCustomer],
+		keySet,
+		cfg.Auth.Issuer,
+	)
+
+	// There is no SMS provider yet, so codes are either logged or written to file
+	var smsSender service.SMSSender = sms.NewLogSender()
+	if cfg.SMS.File != "" {
+		smsSender = sms.NewFileSender(cfg.SMS.File)
+	}
+
+	blobStore, err := blob.NewLocalStore(cfg.Blob.Root, cfg.Blob.URLPrefix)
+	if err != nil {
+		return fmt.Errorf("error creating blob store: %v", err)
+	}
+
+	storages := storage.NewStorages(mongo)
+	services := service.NewServices(service.Deps{
+		Storages:            storages,
+		TokenProvider:       tokenProvider,
+		MetaProvider:        meta_cache.NewMetaCache(),
+		RevocationProvider:  revocation_cache.NewRevocationCache(),
+		PermissionsProvider: permission_cache.NewPermissionCache(),
+		Hasher:              hash.NewArgon2idHasher(hash.DefaultArgon2idParams),
+		TTLStrategy:         cfg.Auth.TTLStrategy,
+		OrderConfig:         cfg.Order,
+		MetaConfig:          cfg.Meta,
+		DeliverySlotConfig:  cfg.DeliverySlot,
+		OTPConfig:           cfg.OTP,
+		RevocationConfig:    cfg.Revocation,
+		PermissionConfig:    cfg.Permission,
+		IdempotencyConfig:   cfg.Idempotency,
+		ImageConfig:         cfg.Image,
+		SMSSender:           smsSender,
+		BlobStore:           blobStore,
+		EventBus:            eventbus.New(eventbus.DefaultBufferSize),
+	})
+
+	if err := services.Meta.Load(ctx); err != nil {
+		return fmt.Errorf("error loading business meta: %v", err)
+	}
+	if err := services.Revocation.Load(ctx); err != nil {
+		return fmt.Errorf("error loading token revocations: %v", err)
+	}
+	if err := services.Permission.Load(ctx); err != nil {
+		return fmt.Errorf("error loading role permissions: %v", err)
+	}
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go services.Meta.Watch(watchCtx)
+	go services.Revocation.Watch(watchCtx)
+	go services.Permission.Watch(watchCtx)
+
+	jwtAuth := middleware.NewJWTAuthMiddleware(services.Auth, services.Revocation, services.Permission, tokenProvider)
+	xReqID := new(middleware.XRequestIDMiddleware)
+	rateLimit := middleware.NewRateLimitMiddleware(cfg.RateLimits)
+	lockout := middleware.NewLockoutMiddleware(cfg.Lockout, cfg.AccountLockout)
+	idempotency := middleware.NewIdempotencyMiddleware(services.Idempotency)
+	middlewares := middleware.NewMiddlewares(jwtAuth, xReqID, rateLimit, lockout, idempotency)
+
+	imageMaxSize := cfg.Image.MaxSize
+	if imageMaxSize <= 0 {
+		imageMaxSize = service.DefaultImageMaxSize
+	}
 	app := fiber.New(fiber.Config{
 		Immutable:    false,
 		ReadTimeout:  time.Second * 10,
 		WriteTimeout: time.Second * 10,
+		// Too large image gets a clear error from product service instead of a closed connection
+		BodyLimit:    int(imageMaxSize) + multipartOverhead,
 		ErrorHandler: handler.HandleError,
 	})
+	app.Server().HeaderReceived = func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		if isOrderEventsRequest(header) {
+			return fasthttp.RequestConfig{WriteTimeout: streamWriteTimeout}
+		}
+		return fasthttp.RequestConfig{}
+	}
+
+	// Every upload is stored under new key, so files never change
+	app.Static(blobStore.URLPrefix(), blobStore.Root(), fiber.Static{MaxAge: int(blobMaxAge.Seconds())})
+	handler.NewHandler(services, middlewares).InitAPI(app)
 
 	logger.Get().Info("application is running",
 		zap.String("port", cfg.App.Port),
@@ -62,6 +175,17 @@ func run() error {
 	return app.Listen(":" + cfg.App.Port)
 }
 
+// isOrderEventsRequest checks if request is made to events stream. Other routes keep regular WriteTimeout
+// whatever they accept, so that slow clients can't hold connections of any route for long.
+func isOrderEventsRequest(header *fasthttp.RequestHeader) bool {
+	path := header.RequestURI()
+	if i := bytes.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	return string(path) == orderEventsPath &&
+		bytes.Contains(header.Peek(fiber.HeaderAccept), []byte("text/event-stream"))
+}
+
 func readCmdArgs() (string, string, bool, bool) {
 
 	production := flag.Bool("production", false, "if logger should write to file")
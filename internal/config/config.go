@@ -6,7 +6,11 @@ import (
 	"os"
 	"time"
 
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/middleware"
 	service "github.com/sonyamoonglade/sancho-backend/internal/services"
+	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
+	"github.com/sonyamoonglade/sancho-backend/pkg/ratelimit"
 	"github.com/spf13/viper"
 )
 
@@ -26,7 +30,48 @@ type AppConfig struct {
 		Port string
 	}
 
+	Auth struct {
+		// Tokens are signed with the key of SigningKeyID and verified with any of Keys
+		Keys         []auth.Key
+		SigningKeyID string
+		Issuer       string
+		TTLStrategy  service.TTLStrategy
+	}
+
 	Order service.OrderConfig
+
+	Meta service.MetaConfig
+
+	DeliverySlot service.DeliverySlotConfig
+
+	OTP service.OTPConfig
+
+	Revocation service.RevocationConfig
+
+	Permission service.PermissionConfig
+
+	// Route groups missing here are limited with middleware.DefaultRateLimits
+	RateLimits map[string]middleware.RateLimit
+
+	Lockout ratelimit.LockoutPolicy
+	// AccountLockout counts failures of login from any IP
+	AccountLockout ratelimit.LockoutPolicy
+
+	Idempotency service.IdempotencyConfig
+
+	Image service.ImageConfig
+
+	Blob struct {
+		// Directory uploaded files are written to
+		Root string
+		// Path the directory is served at
+		URLPrefix string
+	}
+
+	SMS struct {
+		// File messages are written to instead of being sent. Messages are logged if empty.
+		File string
+	}
 }
 
 func ReadConfig(path string) (AppConfig, error) {
@@ -57,11 +102,91 @@ func ReadConfig(path string) (AppConfig, error) {
 		return AppConfig{}, fmt.Errorf("missing DB_NAME env")
 	}
 
+	keys, signingKeyID, err := readAuthKeys()
+	if err != nil {
+		return AppConfig{}, err
+	}
+
+	issuer := viper.GetString("auth.issuer")
+	if issuer == "" {
+		return AppConfig{}, fmt.Errorf("missing auth.issuer in config")
+	}
+
+	ttlStrategy := service.TTLStrategy{
+		AccessTokenTTLs: make(map[domain.Role]time.Duration),
+		RefreshTokenTTL: make(map[domain.Role]time.Duration),
+	}
+	for _, role := range []domain.Role{domain.RoleCustomer, domain.RoleWorker, domain.RoleAdmin} {
+		accessTTLMinutes := viper.GetInt64("auth.access_ttl." + role.String())
+		if accessTTLMinutes == 0 {
+			return AppConfig{}, fmt.Errorf("missing auth.access_ttl.%s in config", role)
+		}
+		refreshTTLMinutes := viper.GetInt64("auth.refresh_ttl." + role.String())
+		if refreshTTLMinutes == 0 {
+			return AppConfig{}, fmt.Errorf("missing auth.refresh_ttl.%s in config", role)
+		}
+		ttlStrategy.AccessTokenTTLs[role] = time.Duration(accessTTLMinutes) * time.Minute
+		ttlStrategy.RefreshTokenTTL[role] = time.Duration(refreshTTLMinutes) * time.Minute
+	}
+
 	pendingOrderWaitTimeMinutes := viper.GetInt64("order.pending_wait_time")
 	if pendingOrderWaitTimeMinutes == 0 {
 		return AppConfig{}, fmt.Errorf("missing order.pending_wait_time in config")
 	}
 
+	// Optional, service.DefaultMetaPollInterval is used if missing
+	metaPollIntervalSeconds := viper.GetInt64("meta.poll_interval")
+
+	// Optional, service.DefaultDeliverySlotDuration and service.DefaultDeliverySlotCapacity are used if missing
+	deliverySlotDurationMinutes := viper.GetInt64("delivery_slot.duration")
+	deliverySlotCapacity := viper.GetInt64("delivery_slot.capacity")
+
+	// Optional, service.DefaultOTPTTL, service.DefaultOTPMaxAttempts and service.DefaultOTPResendInterval are used if missing
+	otpTTLSeconds := viper.GetInt64("otp.ttl")
+	otpMaxAttempts := viper.GetInt64("otp.max_attempts")
+	otpResendIntervalSeconds := viper.GetInt64("otp.resend_interval")
+
+	// Optional, service.DefaultRevocationSyncInterval is used if missing
+	revocationSyncIntervalSeconds := viper.GetInt64("revocation.sync_interval")
+
+	// Optional, service.DefaultPermissionPollInterval is used if missing
+	permissionPollIntervalSeconds := viper.GetInt64("permissions.poll_interval")
+
+	rateLimits, err := readRateLimits()
+	if err != nil {
+		return AppConfig{}, err
+	}
+
+	// Optional, middleware.DefaultLockoutPolicy is used for missing fields
+	lockoutMaxFailures := viper.GetInt("lockout.max_failures")
+	lockoutDurationSeconds := viper.GetInt64("lockout.duration")
+	lockoutMaxDurationSeconds := viper.GetInt64("lockout.max_duration")
+
+	// Optional, middleware.DefaultAccountLockoutPolicy is used for missing fields
+	accountLockoutMaxFailures := viper.GetInt("account_lockout.max_failures")
+	accountLockoutDurationSeconds := viper.GetInt64("account_lockout.duration")
+	accountLockoutMaxDurationSeconds := viper.GetInt64("account_lockout.max_duration")
+
+	// Optional, service.DefaultIdempotencyTTL is used if missing
+	idempotencyTTLHours := viper.GetInt64("idempotency.ttl")
+
+	// Optional, service.DefaultImageMaxSize, service.DefaultImageThumbnailSize and
+	// service.DefaultImageMediumSize are used if missing
+	imageMaxSizeMegabytes := viper.GetInt64("image.max_size")
+	imageThumbnailSize := viper.GetInt("image.thumbnail_size")
+	imageMediumSize := viper.GetInt("image.medium_size")
+
+	blobRoot := viper.GetString("blob.root")
+	if blobRoot == "" {
+		return AppConfig{}, fmt.Errorf("missing blob.root in config")
+	}
+	blobURLPrefix := viper.GetString("blob.url_prefix")
+	if blobURLPrefix == "" {
+		return AppConfig{}, fmt.Errorf("missing blob.url_prefix in config")
+	}
+
+	smsFile := viper.GetString("sms.file")
+
 	return AppConfig{
 		Database: struct {
 			URI  string
@@ -75,8 +200,154 @@ func ReadConfig(path string) (AppConfig, error) {
 		}{
 			Port: appPort,
 		},
+		Auth: struct {
+			Keys         []auth.Key
+			SigningKeyID string
+			Issuer       string
+			TTLStrategy  service.TTLStrategy
+		}{
+			Keys:         keys,
+			SigningKeyID: signingKeyID,
+			Issuer:       issuer,
+			TTLStrategy:  ttlStrategy,
+		},
 		Order: service.OrderConfig{
 			PendingOrderWaitTime: time.Duration(pendingOrderWaitTimeMinutes) * time.Minute,
 		},
+		Meta: service.MetaConfig{
+			PollInterval: time.Duration(metaPollIntervalSeconds) * time.Second,
+		},
+		DeliverySlot: service.DeliverySlotConfig{
+			Duration: time.Duration(deliverySlotDurationMinutes) * time.Minute,
+			Capacity: deliverySlotCapacity,
+		},
+		OTP: service.OTPConfig{
+			TTL:            time.Duration(otpTTLSeconds) * time.Second,
+			MaxAttempts:    otpMaxAttempts,
+			ResendInterval: time.Duration(otpResendIntervalSeconds) * time.Second,
+		},
+		Revocation: service.RevocationConfig{
+			SyncInterval: time.Duration(revocationSyncIntervalSeconds) * time.Second,
+		},
+		Permission: service.PermissionConfig{
+			PollInterval: time.Duration(permissionPollIntervalSeconds) * time.Second,
+		},
+		RateLimits: rateLimits,
+		Lockout: ratelimit.LockoutPolicy{
+			MaxFailures: lockoutMaxFailures,
+			Duration:    time.Duration(lockoutDurationSeconds) * time.Second,
+			MaxDuration: time.Duration(lockoutMaxDurationSeconds) * time.Second,
+		},
+		AccountLockout: ratelimit.LockoutPolicy{
+			MaxFailures: accountLockoutMaxFailures,
+			Duration:    time.Duration(accountLockoutDurationSeconds) * time.Second,
+			MaxDuration: time.Duration(accountLockoutMaxDurationSeconds) * time.Second,
+		},
+		Idempotency: service.IdempotencyConfig{
+			TTL: time.Duration(idempotencyTTLHours) * time.Hour,
+		},
+		Image: service.ImageConfig{
+			MaxSize:       imageMaxSizeMegabytes << 20,
+			ThumbnailSize: imageThumbnailSize,
+			MediumSize:    imageMediumSize,
+		},
+		Blob: struct {
+			Root      string
+			URLPrefix string
+		}{
+			Root:      blobRoot,
+			URLPrefix: blobURLPrefix,
+		},
+		SMS: struct {
+			File string
+		}{
+			File: smsFile,
+		},
 	}, nil
 }
+
+// readAuthKeys reads PEM keys listed in auth.keys. JWT_SIGNING_KEY is required only if none are listed,
+// otherwise it's kept as verification key for tokens issued before keys were rotated to asymmetric ones.
+func readAuthKeys() ([]auth.Key, string, error) {
+	var keyFiles []struct {
+		ID   string `mapstructure:"id"`
+		File string `mapstructure:"file"`
+	}
+	if err := viper.UnmarshalKey("auth.keys", &keyFiles); err != nil {
+		return nil, "", fmt.Errorf("invalid auth.keys in config: %w", err)
+	}
+
+	keys := make([]auth.Key, 0, len(keyFiles)+1)
+	for _, keyFile := range keyFiles {
+		if keyFile.ID == "" {
+			return nil, "", fmt.Errorf("missing id of auth key %s in config", keyFile.File)
+		}
+		data, err := os.ReadFile(keyFile.File)
+		if err != nil {
+			return nil, "", fmt.Errorf("error reading auth key %s: %w", keyFile.ID, err)
+		}
+		key, err := auth.ParsePEMKey(keyFile.ID, data)
+		if err != nil {
+			return nil, "", fmt.Errorf("error parsing auth key %s: %w", keyFile.ID, err)
+		}
+		keys = append(keys, key)
+	}
+
+	signingKey := os.Getenv("JWT_SIGNING_KEY")
+	if len(keys) == 0 {
+		if signingKey == "" {
+			return nil, "", fmt.Errorf("missing JWT_SIGNING_KEY env")
+		}
+		key, err := auth.NewHSKey("", []byte(signingKey))
+		if err != nil {
+			return nil, "", err
+		}
+		return []auth.Key{key}, "", nil
+	}
+
+	signingKeyID := viper.GetString("auth.signing_key_id")
+	if signingKeyID == "" {
+		return nil, "", fmt.Errorf("missing auth.signing_key_id in config")
+	}
+	if signingKey != "" {
+		key, err := auth.NewHSKey("", []byte(signingKey))
+		if err != nil {
+			return nil, "", err
+		}
+		keys = append(keys, key)
+	}
+	return keys, signingKeyID, nil
+}
+
+// readRateLimits reads limits of route groups set in rate_limit
+func readRateLimits() (map[string]middleware.RateLimit, error) {
+	rateLimits := make(map[string]middleware.RateLimit)
+	for _, group := range []string{middleware.RateLimitAuth, middleware.RateLimitOTP, middleware.RateLimitOTPIP, middleware.RateLimitOrders} {
+		prefix := "rate_limit." + group
+		if !viper.IsSet(prefix) {
+			continue
+		}
+		requests := viper.GetInt(prefix + ".requests")
+		periodSeconds := viper.GetInt64(prefix + ".period")
+		if requests <= 0 || periodSeconds <= 0 {
+			return nil, fmt.Errorf("missing %s.requests or %s.period in config", prefix, prefix)
+		}
+		key := middleware.RateLimitKey(viper.GetString(prefix + ".key"))
+		switch key {
+		case "":
+			key = middleware.RateLimitByIP
+		case middleware.RateLimitByIP, middleware.RateLimitByUser, middleware.RateLimitByPhone:
+		default:
+			return nil, fmt.Errorf("invalid %s.key in config: %s", prefix, key)
+		}
+		rateLimits[group] = middleware.RateLimit{
+			Limit: ratelimit.Limit{
+				Requests: requests,
+				Period:   time.Duration(periodSeconds) * time.Second,
+				Burst:    viper.GetInt(prefix + ".burst"),
+			},
+			Key: key,
+		}
+	}
+	return rateLimits, nil
+}
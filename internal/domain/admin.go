@@ -17,5 +17,4 @@ type Admin struct {
 	Login    string             `json:"login" bson:"login"`
 	Role     Role               `json:"role" bson:"role"`
 	Password string             `json:"password" bson:"password"`
-	Session  Session            `json:"session,omitempty" bson:"session,omitempty"`
 }
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidCombo              = errors.New("combo components should be existing products that are not combos")
+	ErrComboComponentNotApproved = errors.New("combo has components that are not approved")
+)
+
+const (
+	emptyCombo             = "combo has no components"
+	invalidComponentAmount = "component quantity should be positive"
+	invalidSwap            = "component can not be swapped for itself"
+	duplicateSwap          = "component swaps should be unique"
+)
+
+const (
+	CartLineUnknownSwap           = "swap not found"
+	CartLineComponentNotAvailable = "combo component is not available"
+)
+
+// Combo is a set of products sold together for the price of the combo product
+type Combo struct {
+	Components []ComboComponent `bson:"components" json:"components"`
+}
+
+// ComboComponent is a slot of combo filled with ProductID unless customer swaps it for one of Swaps
+type ComboComponent struct {
+	ComponentID primitive.ObjectID `bson:"_id" json:"componentId"`
+	ProductID   primitive.ObjectID `bson:"productId" json:"productId"`
+	Quantity    int32              `bson:"quantity" json:"quantity"`
+	Swaps       []ComboSwap        `bson:"swaps,omitempty" json:"swaps,omitempty"`
+}
+
+// ComboSwap is a product component can be swapped for, PriceDelta is added to combo price
+type ComboSwap struct {
+	ProductID  primitive.ObjectID `bson:"productId" json:"productId"`
+	PriceDelta int64              `bson:"priceDelta" json:"priceDelta"`
+}
+
+// ComboItem is a copy of product combo line is made of, so that kitchen sees what to prepare
+type ComboItem struct {
+	ComponentID primitive.ObjectID `bson:"componentId" json:"componentId"`
+	ProductID   primitive.ObjectID `bson:"productId" json:"productId"`
+	Name        string             `bson:"name" json:"name"`
+	TranslateRU string             `bson:"translateRu" json:"translateRu"`
+	// Quantity in one combo
+	Quantity   int32 `bson:"quantity" json:"quantity"`
+	IsSwapped  bool  `bson:"isSwapped" json:"isSwapped"`
+	PriceDelta int64 `bson:"priceDelta" json:"priceDelta"`
+}
+
+// ComboChoiceError describes why combo can not be ordered with the chosen swaps.
+// ComponentID is empty when the error is not about a particular component.
+type ComboChoiceError struct {
+	ComponentID string
+	Reason      string
+}
+
+func (e ComboChoiceError) Error() string {
+	if e.ComponentID == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("combo component %s: %s", e.ComponentID, e.Reason)
+}
+
+func (p Product) IsCombo() bool {
+	return p.Combo != nil
+}
+
+// ProductIDs returns ids of every product combo can be made of, swaps included
+func (c Combo) ProductIDs() []string {
+	var ids []string
+	for _, component := range c.Components {
+		ids = append(ids, component.ProductID.Hex())
+		for _, swap := range component.Swaps {
+			ids = append(ids, swap.ProductID.Hex())
+		}
+	}
+	return ids
+}
+
+func (c Combo) IsValid() (bool, string) {
+	if len(c.Components) == 0 {
+		return false, emptyCombo
+	}
+	for _, component := range c.Components {
+		if component.Quantity <= 0 {
+			return false, invalidComponentAmount
+		}
+		swaps := make(map[primitive.ObjectID]struct{}, len(component.Swaps))
+		for _, swap := range component.Swaps {
+			if swap.ProductID == component.ProductID {
+				return false, invalidSwap
+			}
+			if _, ok := swaps[swap.ProductID]; ok {
+				return false, duplicateSwap
+			}
+			swaps[swap.ProductID] = struct{}{}
+			if swap.PriceDelta < 0 {
+				return false, negativePriceDelta
+			}
+		}
+	}
+	return true, ""
+}
+
+// ChooseComboItems resolves products the combo is made of. Swaps are keyed by component id,
+// value is id of the product component is swapped for. Products should have every product of combo
+// that can be chosen, only approved ones are available.
+func (p Product) ChooseComboItems(swaps map[string]string, products map[string]Product) ([]ComboItem, error) {
+	if !p.IsCombo() {
+		if len(swaps) > 0 {
+			return nil, ComboChoiceError{Reason: CartLineUnknownSwap}
+		}
+		return nil, nil
+	}
+
+	components := make(map[string]struct{}, len(p.Combo.Components))
+	for _, component := range p.Combo.Components {
+		components[component.ComponentID.Hex()] = struct{}{}
+	}
+	for componentID := range swaps {
+		if _, ok := components[componentID]; !ok {
+			return nil, ComboChoiceError{Reason: CartLineUnknownSwap}
+		}
+	}
+
+	items := make([]ComboItem, 0, len(p.Combo.Components))
+	for _, component := range p.Combo.Components {
+		componentID := component.ComponentID.Hex()
+		item := ComboItem{
+			ComponentID: component.ComponentID,
+			ProductID:   component.ProductID,
+			Quantity:    component.Quantity,
+		}
+		// Swapping for the default product is the same as not swapping
+		if productID, ok := swaps[componentID]; ok && productID != component.ProductID.Hex() {
+			swap, ok := component.findSwap(productID)
+			if !ok {
+				return nil, ComboChoiceError{ComponentID: componentID, Reason: CartLineUnknownSwap}
+			}
+			item.ProductID, item.IsSwapped, item.PriceDelta = swap.ProductID, true, swap.PriceDelta
+		}
+
+		product, ok := products[item.ProductID.Hex()]
+		if !ok || !product.IsApproved {
+			return nil, ComboChoiceError{ComponentID: componentID, Reason: CartLineComponentNotAvailable}
+		}
+		item.Name, item.TranslateRU = product.Name, product.TranslateRU
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (c ComboComponent) findSwap(productID string) (ComboSwap, bool) {
+	for _, swap := range c.Swaps {
+		if swap.ProductID.Hex() == productID {
+			return swap, true
+		}
+	}
+	return ComboSwap{}, false
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestComboIsValid(t *testing.T) {
+	var (
+		burger = primitive.NewObjectID()
+		cola   = primitive.NewObjectID()
+		juice  = primitive.NewObjectID()
+	)
+	valid := func() Combo {
+		return Combo{Components: []ComboComponent{
+			{ProductID: burger, Quantity: 1},
+			{ProductID: cola, Quantity: 1, Swaps: []ComboSwap{{ProductID: juice, PriceDelta: 30}}},
+		}}
+	}
+
+	ok, msg := valid().IsValid()
+	require.True(t, ok)
+	require.Zero(t, msg)
+
+	for expected, modify := range map[string]func(c *Combo){
+		emptyCombo:             func(c *Combo) { c.Components = nil },
+		invalidComponentAmount: func(c *Combo) { c.Components[0].Quantity = 0 },
+		invalidSwap:            func(c *Combo) { c.Components[1].Swaps[0].ProductID = cola },
+		duplicateSwap:          func(c *Combo) { c.Components[1].Swaps = append(c.Components[1].Swaps, ComboSwap{ProductID: juice}) },
+		negativePriceDelta:     func(c *Combo) { c.Components[1].Swaps[0].PriceDelta = -1 },
+	} {
+		combo := valid()
+		modify(&combo)
+		ok, msg := combo.IsValid()
+		require.False(t, ok)
+		require.Equal(t, expected, msg)
+	}
+}
+
+func TestProductChooseComboItems(t *testing.T) {
+	var (
+		burger = Product{ProductID: primitive.NewObjectID(), Name: "burger", IsApproved: true}
+		cola   = Product{ProductID: primitive.NewObjectID(), Name: "cola", IsApproved: true}
+		juice  = Product{ProductID: primitive.NewObjectID(), Name: "juice", IsApproved: true}
+		drink  = ComboComponent{
+			ComponentID: primitive.NewObjectID(),
+			ProductID:   cola.ProductID,
+			Quantity:    1,
+			Swaps:       []ComboSwap{{ProductID: juice.ProductID, PriceDelta: 30}},
+		}
+		combo = Product{
+			ProductID: primitive.NewObjectID(),
+			Price:     499,
+			Combo: &Combo{Components: []ComboComponent{
+				{ComponentID: primitive.NewObjectID(), ProductID: burger.ProductID, Quantity: 2},
+				drink,
+			}},
+		}
+		products = map[string]Product{
+			burger.ProductID.Hex(): burger,
+			cola.ProductID.Hex():   cola,
+			juice.ProductID.Hex():  juice,
+		}
+	)
+
+	t.Run("should use default products", func(t *testing.T) {
+		items, err := combo.ChooseComboItems(nil, products)
+		require.NoError(t, err)
+		require.Equal(t, []ComboItem{
+			{ComponentID: combo.Combo.Components[0].ComponentID, ProductID: burger.ProductID, Name: "burger", Quantity: 2},
+			{ComponentID: drink.ComponentID, ProductID: cola.ProductID, Name: "cola", Quantity: 1},
+		}, items)
+	})
+
+	t.Run("should swap component and price the swap in", func(t *testing.T) {
+		items, err := combo.ChooseComboItems(map[string]string{drink.ComponentID.Hex(): juice.ProductID.Hex()}, products)
+		require.NoError(t, err)
+		require.Equal(t, ComboItem{
+			ComponentID: drink.ComponentID,
+			ProductID:   juice.ProductID,
+			Name:        "juice",
+			Quantity:    1,
+			IsSwapped:   true,
+			PriceDelta:  30,
+		}, items[1])
+
+		cartProduct := CartProduct{Product: combo, Quantity: 2, Components: items}
+		require.Equal(t, int64(1058), cartProduct.Amount())
+	})
+
+	t.Run("should return CartLineUnknownSwap", func(t *testing.T) {
+		_, err := combo.ChooseComboItems(map[string]string{drink.ComponentID.Hex(): burger.ProductID.Hex()}, products)
+		require.Equal(t, ComboChoiceError{ComponentID: drink.ComponentID.Hex(), Reason: CartLineUnknownSwap}, err)
+
+		_, err = combo.ChooseComboItems(map[string]string{primitive.NewObjectID().Hex(): juice.ProductID.Hex()}, products)
+		require.Equal(t, ComboChoiceError{Reason: CartLineUnknownSwap}, err)
+
+		_, err = burger.ChooseComboItems(map[string]string{drink.ComponentID.Hex(): juice.ProductID.Hex()}, products)
+		require.Equal(t, ComboChoiceError{Reason: CartLineUnknownSwap}, err)
+	})
+
+	t.Run("should return CartLineComponentNotAvailable", func(t *testing.T) {
+		notApproved := juice
+		notApproved.IsApproved = false
+		withDraft := map[string]Product{
+			burger.ProductID.Hex(): burger,
+			juice.ProductID.Hex():  notApproved,
+		}
+
+		// Cola is missing
+		_, err := combo.ChooseComboItems(nil, withDraft)
+		require.Equal(t, ComboChoiceError{ComponentID: drink.ComponentID.Hex(), Reason: CartLineComponentNotAvailable}, err)
+
+		_, err = combo.ChooseComboItems(map[string]string{drink.ComponentID.Hex(): juice.ProductID.Hex()}, withDraft)
+		require.Equal(t, ComboChoiceError{ComponentID: drink.ComponentID.Hex(), Reason: CartLineComponentNotAvailable}, err)
+	})
+}
+
+func TestOrderProductQuantities(t *testing.T) {
+	var (
+		burger = primitive.NewObjectID()
+		cola   = primitive.NewObjectID()
+		order  = Order{Cart: []CartProduct{
+			{Product: Product{ProductID: cola}, Quantity: 1},
+			{
+				Product:  Product{ProductID: primitive.NewObjectID()},
+				Quantity: 3,
+				Components: []ComboItem{
+					{ProductID: burger, Quantity: 2},
+					{ProductID: cola, Quantity: 1},
+				},
+			},
+		}}
+	)
+
+	require.Equal(t, map[primitive.ObjectID]int32{burger: 6, cola: 4}, order.ProductQuantities())
+}
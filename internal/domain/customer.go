@@ -17,7 +17,6 @@ type Customer struct {
 	PhoneNumber     string               `json:"phoneNumber" bson:"phoneNumber"`
 	Name            *string              `json:"name,omitempty" bson:"name,omitempty"`
 	DeliveryAddress *UserDeliveryAddress `json:"deliveryAddress,omitempty" bson:"deliveryAddress,omitempty"`
-	Session         *Session             `json:"session,omitempty" bson:"session,omitempty"`
 }
 
 type UserDeliveryAddress struct {
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrDeliverySlotFull   = errors.New("delivery slot is full")
+	ErrDeliverySlotPassed = errors.New("delivery slot has already passed")
+)
+
+// DeliverySlot is a window scheduled deliveries are limited in
+type DeliverySlot struct {
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	Available int64     `json:"available"`
+}
+
+// DeliverySlotReservation is number of orders scheduled to the slot
+type DeliverySlotReservation struct {
+	StartsAt time.Time `bson:"_id"`
+	Reserved int64     `bson:"reserved"`
+}
+
+// DeliverySlotAt returns start of the slot t falls into. Slots are counted from opening of the working hours.
+// It returns false if store is closed at t.
+func (s Schedule) DeliverySlotAt(t time.Time, duration time.Duration) (time.Time, bool) {
+	opensAt, _, ok := s.HoursAt(t)
+	if !ok {
+		return time.Time{}, false
+	}
+	return opensAt.Add(t.Sub(opensAt) / duration * duration), true
+}
+
+// DeliverySlotsOn returns starts of all slots of working hours that start on the date of day,
+// slots after midnight included. The last slot may be cut by closing.
+func (s Schedule) DeliverySlotsOn(day time.Time, duration time.Duration) []time.Time {
+	opensAt, closesAt, ok := s.HoursOn(day.In(s.Location()))
+	if !ok {
+		return nil
+	}
+	var slots []time.Time
+	for startsAt := opensAt; startsAt.Before(closesAt); startsAt = startsAt.Add(duration) {
+		slots = append(slots, startsAt)
+	}
+	return slots
+}
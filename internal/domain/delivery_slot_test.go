@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleDeliverySlots(t *testing.T) {
+	var (
+		schedule = getSchedule()
+		loc      = schedule.Location()
+		duration = time.Minute * 30
+	)
+
+	t.Run("should return slot of time", func(t *testing.T) {
+		slot, ok := schedule.DeliverySlotAt(time.Date(2023, 1, 30, 12, 45, 0, 0, loc), duration)
+		require.True(t, ok)
+		require.True(t, time.Date(2023, 1, 30, 12, 30, 0, 0, loc).Equal(slot))
+
+		slot, ok = schedule.DeliverySlotAt(time.Date(2023, 1, 30, 13, 0, 0, 0, loc), duration)
+		require.True(t, ok)
+		require.True(t, time.Date(2023, 1, 30, 13, 0, 0, 0, loc).Equal(slot))
+	})
+
+	t.Run("should count slots from opening", func(t *testing.T) {
+		shifted := Schedule{
+			Timezone: schedule.Timezone,
+			Weekly:   []WorkingHours{{Weekday: time.Monday, Open: "10:15", Close: "12:00"}},
+		}
+		slot, ok := shifted.DeliverySlotAt(time.Date(2023, 1, 30, 10, 50, 0, 0, loc), duration)
+		require.True(t, ok)
+		require.True(t, time.Date(2023, 1, 30, 10, 45, 0, 0, loc).Equal(slot))
+
+		slots := shifted.DeliverySlotsOn(time.Date(2023, 1, 30, 0, 0, 0, 0, loc), duration)
+		require.Len(t, slots, 4)
+		// The last one is cut by closing
+		require.True(t, time.Date(2023, 1, 30, 11, 45, 0, 0, loc).Equal(slots[3]))
+	})
+
+	t.Run("should not return slot because store is closed", func(t *testing.T) {
+		_, ok := schedule.DeliverySlotAt(time.Date(2023, 1, 30, 23, 30, 0, 0, loc), duration)
+		require.False(t, ok)
+
+		require.Empty(t, schedule.DeliverySlotsOn(time.Date(2023, 2, 23, 0, 0, 0, 0, loc), duration))
+	})
+
+	t.Run("should return slots of the whole day", func(t *testing.T) {
+		slots := schedule.DeliverySlotsOn(time.Date(2023, 1, 30, 0, 0, 0, 0, loc), duration)
+		// 10:00 - 23:00
+		require.Len(t, slots, 26)
+		require.True(t, time.Date(2023, 1, 30, 10, 0, 0, 0, loc).Equal(slots[0]))
+
+		slots = Schedule{}.DeliverySlotsOn(time.Date(2023, 1, 30, 0, 0, 0, 0, time.UTC), time.Hour)
+		require.Len(t, slots, 24)
+	})
+}
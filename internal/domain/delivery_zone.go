@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const GeoPolygonType = "Polygon"
+
+var (
+	ErrDeliveryZoneNotFound = errors.New("delivery zone not found")
+	// ErrInvalidDeliveryZoneArea is returned when area is not a valid polygon, e.g. has self-intersections
+	ErrInvalidDeliveryZoneArea = errors.New("invalid delivery zone area")
+	ErrOutsideDeliveryZone     = errors.New("delivery address is outside of delivery zones")
+	ErrDeliveryZoneMinOrder    = errors.New("order amount is too small for delivery to this address")
+)
+
+// DeliveryZone is an area orders are delivered to on its own terms
+type DeliveryZone struct {
+	DeliveryZoneID primitive.ObjectID `json:"deliveryZoneId" bson:"_id,omitempty"`
+	Name           string             `json:"name" bson:"name"`
+	Area           GeoPolygon         `json:"area" bson:"area"`
+	Fee            int64              `json:"fee" bson:"fee"`
+	// Delivery is free for orders of at least this amount. Zero means it's never free.
+	FreeDeliveryThreshold int64     `json:"freeDeliveryThreshold" bson:"freeDeliveryThreshold"`
+	MinOrderAmount        int64     `json:"minOrderAmount" bson:"minOrderAmount"`
+	ETAMinutes            int64     `json:"etaMinutes" bson:"etaMinutes"`
+	CreatedAt             time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// GeoPolygon is GeoJSON polygon. Coordinates are rings of [longitude, latitude] points,
+// the first ring is the outer boundary and the rest are holes.
+type GeoPolygon struct {
+	Type        string        `json:"type" bson:"type"`
+	Coordinates [][][]float64 `json:"coordinates" bson:"coordinates"`
+}
+
+func NewGeoPolygon(rings ...[][]float64) GeoPolygon {
+	return GeoPolygon{
+		Type:        GeoPolygonType,
+		Coordinates: rings,
+	}
+}
+
+// OrderDelivery holds terms of the delivery zone order has been placed in
+type OrderDelivery struct {
+	DeliveryZoneID primitive.ObjectID `json:"deliveryZoneId" bson:"deliveryZoneId"`
+	ZoneName       string             `json:"zoneName" bson:"zoneName"`
+	Fee            int64              `json:"fee" bson:"fee"`
+	ETAMinutes     int64              `json:"etaMinutes" bson:"etaMinutes"`
+	// SlotStartsAt is set if delivery is scheduled to a slot
+	SlotStartsAt *time.Time `json:"slotStartsAt,omitempty" bson:"slotStartsAt,omitempty"`
+}
+
+// FeeFor returns delivery fee of order with given amount
+func (z DeliveryZone) FeeFor(amount int64) int64 {
+	if z.FreeDeliveryThreshold > 0 && amount >= z.FreeDeliveryThreshold {
+		return 0
+	}
+	return z.Fee
+}
+
+// Deliver checks that order with given amount can be delivered within the zone and returns its delivery terms
+func (z DeliveryZone) Deliver(amount int64) (OrderDelivery, error) {
+	if amount < z.MinOrderAmount {
+		return OrderDelivery{}, ErrDeliveryZoneMinOrder
+	}
+	return OrderDelivery{
+		DeliveryZoneID: z.DeliveryZoneID,
+		ZoneName:       z.Name,
+		Fee:            z.FeeFor(amount),
+		ETAMinutes:     z.ETAMinutes,
+	}, nil
+}
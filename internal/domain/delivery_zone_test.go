@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryZoneDeliver(t *testing.T) {
+	zone := DeliveryZone{
+		Name:                  "center",
+		Fee:                   150,
+		FreeDeliveryThreshold: 2000,
+		MinOrderAmount:        500,
+		ETAMinutes:            40,
+	}
+
+	t.Run("should charge fee", func(t *testing.T) {
+		delivery, err := zone.Deliver(1000)
+		require.NoError(t, err)
+		require.Equal(t, int64(150), delivery.Fee)
+		require.Equal(t, zone.Name, delivery.ZoneName)
+		require.Equal(t, zone.ETAMinutes, delivery.ETAMinutes)
+	})
+
+	t.Run("should deliver for free", func(t *testing.T) {
+		delivery, err := zone.Deliver(2000)
+		require.NoError(t, err)
+		require.Zero(t, delivery.Fee)
+	})
+
+	t.Run("should never deliver for free without threshold", func(t *testing.T) {
+		noThreshold := zone
+		noThreshold.FreeDeliveryThreshold = 0
+		delivery, err := noThreshold.Deliver(100000)
+		require.NoError(t, err)
+		require.Equal(t, int64(150), delivery.Fee)
+	})
+
+	t.Run("should return ErrDeliveryZoneMinOrder", func(t *testing.T) {
+		_, err := zone.Deliver(499)
+		require.ErrorIs(t, err, ErrDeliveryZoneMinOrder)
+	})
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyNotFound   = errors.New("idempotency key not found")
+	ErrIdempotencyKeyExists     = errors.New("idempotency key already exists")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key has already been used with another request")
+	ErrIdempotencyKeyInProgress = errors.New("request with the same idempotency key is in progress")
+)
+
+// IdempotencyRecord remembers request made with idempotency key and the response it got,
+// so that retry of the request gets the same response instead of being handled again
+type IdempotencyRecord struct {
+	// Key is scoped to route and user
+	Key         string `bson:"_id"`
+	RequestHash string `bson:"requestHash"`
+	// Response is nil while request is being handled
+	Response  *IdempotentResponse `bson:"response"`
+	CreatedAt time.Time           `bson:"createdAt"`
+	ExpiresAt time.Time           `bson:"expiresAt"`
+}
+
+type IdempotentResponse struct {
+	StatusCode  int    `bson:"statusCode"`
+	ContentType string `bson:"contentType"`
+	Body        []byte `bson:"body"`
+}
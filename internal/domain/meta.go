@@ -1,11 +1,20 @@
 package domain
 
+import "errors"
+
+var (
+	ErrMetaNotFound = errors.New("business meta not found")
+)
+
 type MetaProvider interface {
 	Get() *BusinessMeta
 	Set(meta BusinessMeta)
 }
 
 type BusinessMeta struct {
-	DeliveryPunishmentThreshold int64 `json:"deliveryPunishmentThreshold" bson:"deliveryPunishmentThreshold"`
-	DeliveryPunishmentValue     int64 `json:"deliveryPunishmentValue" bson:"deliveryPunishmentValue"`
+	// Deprecated: delivery is charged by fee of the delivery zone only, punishment is not applied to orders
+	// and can't be changed. Fields are kept so that meta saved before is read as it is.
+	DeliveryPunishmentThreshold int64    `json:"deliveryPunishmentThreshold" bson:"deliveryPunishmentThreshold"`
+	DeliveryPunishmentValue     int64    `json:"deliveryPunishmentValue" bson:"deliveryPunishmentValue"`
+	Schedule                    Schedule `json:"schedule" bson:"schedule"`
 }
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	emptyOptionGroupName   = "option group name is empty"
+	emptyOptionGroup       = "option group has no options"
+	emptyOptionName        = "option name is empty"
+	duplicateOptionName    = "option names should be unique in group"
+	negativePriceDelta     = "option price delta can not be negative"
+	invalidMinChoices      = "min choices can not be negative"
+	invalidMaxChoices      = "max choices should be at least min choices and at most number of options"
+	invalidSingleMaxChoice = "single select group allows one choice"
+)
+
+const (
+	CartLineUnknownOption   = "option not found"
+	CartLineDuplicateOption = "option is chosen more than once"
+	CartLineTooFewOptions   = "too few options are chosen"
+	CartLineTooManyOptions  = "too many options are chosen"
+)
+
+// OptionGroup is a choice customer makes for product, e.g. size, extra toppings or sauce.
+// Group with MinChoices 0 is optional.
+type OptionGroup struct {
+	GroupID primitive.ObjectID `bson:"_id" json:"groupId"`
+	Name    string             `bson:"name" json:"name"`
+	// Multiple groups allow choosing several options, single select ones allow one at most
+	Multiple   bool     `bson:"multiple" json:"multiple"`
+	MinChoices int32    `bson:"minChoices" json:"minChoices"`
+	MaxChoices int32    `bson:"maxChoices" json:"maxChoices"`
+	Options    []Option `bson:"options" json:"options"`
+}
+
+type Option struct {
+	OptionID primitive.ObjectID `bson:"_id" json:"optionId"`
+	Name     string             `bson:"name" json:"name"`
+	// PriceDelta is added to product price for every unit
+	PriceDelta int64 `bson:"priceDelta" json:"priceDelta"`
+}
+
+// ChosenOption is a copy of option chosen for cart line, so that order keeps it as it was ordered
+type ChosenOption struct {
+	GroupID    primitive.ObjectID `bson:"groupId" json:"groupId"`
+	GroupName  string             `bson:"groupName" json:"groupName"`
+	OptionID   primitive.ObjectID `bson:"optionId" json:"optionId"`
+	Name       string             `bson:"name" json:"name"`
+	PriceDelta int64              `bson:"priceDelta" json:"priceDelta"`
+}
+
+// OptionChoiceError describes why options chosen for product can not be ordered.
+// GroupID is empty when the error is not about a particular group.
+type OptionChoiceError struct {
+	GroupID string
+	Reason  string
+}
+
+func (e OptionChoiceError) Error() string {
+	if e.GroupID == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("option group %s: %s", e.GroupID, e.Reason)
+}
+
+func (g OptionGroup) IsValid() (bool, string) {
+	if strings.TrimSpace(g.Name) == "" {
+		return false, emptyOptionGroupName
+	}
+	if len(g.Options) == 0 {
+		return false, emptyOptionGroup
+	}
+	names := make(map[string]struct{}, len(g.Options))
+	for _, option := range g.Options {
+		name := strings.TrimSpace(option.Name)
+		if name == "" {
+			return false, emptyOptionName
+		}
+		if _, ok := names[name]; ok {
+			return false, duplicateOptionName
+		}
+		names[name] = struct{}{}
+		if option.PriceDelta < 0 {
+			return false, negativePriceDelta
+		}
+	}
+	if g.MinChoices < 0 {
+		return false, invalidMinChoices
+	}
+	if g.MaxChoices < 1 || g.MaxChoices < g.MinChoices || int(g.MaxChoices) > len(g.Options) {
+		return false, invalidMaxChoices
+	}
+	if !g.Multiple && g.MaxChoices != 1 {
+		return false, invalidSingleMaxChoice
+	}
+	return true, ""
+}
+
+// ChooseOptions resolves options chosen by their ids and checks them against option groups of the product.
+// Chosen options are returned in the order of groups and options of the product.
+func (p Product) ChooseOptions(optionIDs []string) ([]ChosenOption, error) {
+	chosen := make(map[string]struct{}, len(optionIDs))
+	for _, optionID := range optionIDs {
+		if _, ok := chosen[optionID]; ok {
+			return nil, OptionChoiceError{Reason: CartLineDuplicateOption}
+		}
+		chosen[optionID] = struct{}{}
+	}
+	known := make(map[string]struct{})
+	for _, group := range p.OptionGroups {
+		for _, option := range group.Options {
+			known[option.OptionID.Hex()] = struct{}{}
+		}
+	}
+	for optionID := range chosen {
+		if _, ok := known[optionID]; !ok {
+			return nil, OptionChoiceError{Reason: CartLineUnknownOption}
+		}
+	}
+
+	var options []ChosenOption
+	for _, group := range p.OptionGroups {
+		var count int32
+		for _, option := range group.Options {
+			if _, ok := chosen[option.OptionID.Hex()]; !ok {
+				continue
+			}
+			count++
+			options = append(options, ChosenOption{
+				GroupID:    group.GroupID,
+				GroupName:  group.Name,
+				OptionID:   option.OptionID,
+				Name:       option.Name,
+				PriceDelta: option.PriceDelta,
+			})
+		}
+		if count < group.MinChoices {
+			return nil, OptionChoiceError{GroupID: group.GroupID.Hex(), Reason: CartLineTooFewOptions}
+		}
+		if count > group.MaxChoices {
+			return nil, OptionChoiceError{GroupID: group.GroupID.Hex(), Reason: CartLineTooManyOptions}
+		}
+	}
+	return options, nil
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestOptionGroupIsValid(t *testing.T) {
+	valid := func() OptionGroup {
+		return OptionGroup{
+			Name:       "toppings",
+			Multiple:   true,
+			MaxChoices: 2,
+			Options:    []Option{{Name: "cheese", PriceDelta: 50}, {Name: "bacon", PriceDelta: 70}},
+		}
+	}
+
+	ok, msg := valid().IsValid()
+	require.True(t, ok)
+	require.Zero(t, msg)
+
+	for expected, modify := range map[string]func(g *OptionGroup){
+		emptyOptionGroupName:   func(g *OptionGroup) { g.Name = " " },
+		emptyOptionGroup:       func(g *OptionGroup) { g.Options = nil },
+		emptyOptionName:        func(g *OptionGroup) { g.Options[0].Name = "" },
+		duplicateOptionName:    func(g *OptionGroup) { g.Options[1].Name = "cheese" },
+		negativePriceDelta:     func(g *OptionGroup) { g.Options[0].PriceDelta = -1 },
+		invalidMinChoices:      func(g *OptionGroup) { g.MinChoices = -1 },
+		invalidMaxChoices:      func(g *OptionGroup) { g.MaxChoices = 3 },
+		invalidSingleMaxChoice: func(g *OptionGroup) { g.Multiple = false },
+	} {
+		group := valid()
+		modify(&group)
+		ok, msg := group.IsValid()
+		require.False(t, ok)
+		require.Equal(t, expected, msg)
+	}
+
+	group := valid()
+	group.MinChoices, group.MaxChoices = 2, 1
+	ok, msg = group.IsValid()
+	require.False(t, ok)
+	require.Equal(t, invalidMaxChoices, msg)
+}
+
+func TestProductChooseOptions(t *testing.T) {
+	sauce := OptionGroup{
+		GroupID:    primitive.NewObjectID(),
+		Name:       "sauce",
+		MaxChoices: 1,
+		Options:    []Option{{OptionID: primitive.NewObjectID(), Name: "garlic", PriceDelta: 40}},
+	}
+	product := Product{ProductID: primitive.NewObjectID(), Price: 300, OptionGroups: []OptionGroup{sauce}}
+
+	// Optional group can be skipped
+	options, err := product.ChooseOptions(nil)
+	require.NoError(t, err)
+	require.Empty(t, options)
+
+	options, err = product.ChooseOptions([]string{sauce.Options[0].OptionID.Hex()})
+	require.NoError(t, err)
+	require.Len(t, options, 1)
+
+	cartProduct := CartProduct{Product: product, Quantity: 3, Options: options}
+	require.Equal(t, int64(340), cartProduct.UnitPrice())
+	require.Equal(t, int64(1020), cartProduct.Amount())
+
+	// Product without option groups knows no options
+	_, err = Product{}.ChooseOptions([]string{"garlic"})
+	require.Equal(t, OptionChoiceError{Reason: CartLineUnknownOption}, err)
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -12,13 +13,47 @@ const (
 	invalidFloor        = "invalid floor"
 	invalidApartment    = "invalid apartment"
 	invalidDeliveryTime = "invalid delivery time"
+	invalidCoordinates  = "invalid coordinates"
 )
 
 var (
 	ErrOrderNotFound    = errors.New("order not found")
 	ErrHavePendingOrder = errors.New("have pending order")
+	// ErrOrderNotInKitchen is returned when cart of an order that is not verified is being prepared
+	ErrOrderNotInKitchen = errors.New("order is not being prepared")
+	ErrCartLineNotFound  = errors.New("cart line not found")
+	ErrInvalidCart       = errors.New("cart has products that can not be ordered")
 )
 
+const (
+	CartLineProductNotFound    = "product not found"
+	CartLineProductNotApproved = "product is not available"
+)
+
+// CartError is returned when some cart lines can not be ordered, every such line is listed
+type CartError struct {
+	Lines []CartLineError
+}
+
+// CartLineError describes why cart line can not be ordered. Line is index of the line in cart.
+// GroupID is set when chosen options violate limits of the option group,
+// ComponentID is set when combo component can not be ordered as chosen.
+type CartLineError struct {
+	Line        int    `json:"line"`
+	ProductID   string `json:"productId"`
+	GroupID     string `json:"groupId,omitempty"`
+	ComponentID string `json:"componentId,omitempty"`
+	Reason      string `json:"reason"`
+}
+
+func (e CartError) Error() string {
+	return fmt.Sprintf("%d cart lines can not be ordered", len(e.Lines))
+}
+
+func (e CartError) Unwrap() error {
+	return ErrInvalidCart
+}
+
 type Order struct {
 	OrderID           primitive.ObjectID    `json:"orderId" bson:"_id,omitempty"`
 	NanoID            string                `json:"nanoId" bson:"nanoId"`
@@ -36,6 +71,33 @@ type Order struct {
 	CompletedAt       *time.Time            `json:"completedAt,omitempty" bson:"completedAt,omitempty"`
 	CancelledAt       *time.Time            `json:"cancelledAt,omitempty" bson:"cancelledAt,omitempty"`
 	CancelExplanation *string               `json:"cancelExplanation,omitempty" bson:"cancelExplanation,omitempty"`
+	PromoCode         *OrderPromoCode       `json:"promoCode,omitempty" bson:"promoCode,omitempty"`
+	Delivery          *OrderDelivery        `json:"delivery,omitempty" bson:"delivery,omitempty"`
+}
+
+// IsCartReady reports whether every cart line has been prepared
+func (o Order) IsCartReady() bool {
+	for _, cartProduct := range o.Cart {
+		if !cartProduct.IsReady {
+			return false
+		}
+	}
+	return true
+}
+
+// ProductQuantities counts units of every product the order is made of, combos are counted by their components
+func (o Order) ProductQuantities() map[primitive.ObjectID]int32 {
+	quantities := make(map[primitive.ObjectID]int32)
+	for _, cartProduct := range o.Cart {
+		if len(cartProduct.Components) == 0 {
+			quantities[cartProduct.ProductID] += cartProduct.Quantity
+			continue
+		}
+		for _, component := range cartProduct.Components {
+			quantities[component.ProductID] += component.Quantity * cartProduct.Quantity
+		}
+	}
+	return quantities
 }
 
 type OrderDeliveryAddress struct {
@@ -45,6 +107,9 @@ type OrderDeliveryAddress struct {
 	Floor       int64     `json:"floor" bson:"floor"`
 	Apartment   int64     `json:"apartment" bson:"apartment"`
 	DeliveredAt time.Time `json:"deliveredAt" bson:"deliveredAt"`
+	// Coordinates of the address. Delivery zone is resolved by them.
+	Latitude  float64 `json:"latitude" bson:"latitude"`
+	Longitude float64 `json:"longitude" bson:"longitude"`
 }
 
 func (o *OrderDeliveryAddress) ToUserDeliveryAddress() *UserDeliveryAddress {
@@ -69,5 +134,8 @@ func (o *OrderDeliveryAddress) IsValid() (bool, string) {
 	if o.DeliveredAt.Before(time.Now().UTC()) {
 		return false, invalidDeliveryTime
 	}
+	if o.Latitude < -90 || o.Latitude > 90 || o.Longitude < -180 || o.Longitude > 180 {
+		return false, invalidCoordinates
+	}
 	return true, ""
 }
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+const (
+	OrderEventCreated       = "order.created"
+	OrderEventStatusChanged = "order.status_changed"
+	OrderEventCartChanged   = "order.cart_changed"
+)
+
+// OrderEvent is sent to customers and workers whenever order is created, moved to another status
+// or its cart lines are prepared
+type OrderEvent struct {
+	OrderID    string      `json:"orderId"`
+	NanoID     string      `json:"nanoId"`
+	CustomerID string      `json:"customerId"`
+	Status     OrderStatus `json:"status"`
+	At         time.Time   `json:"at"`
+}
+
+func NewOrderEvent(order Order, at time.Time) OrderEvent {
+	return OrderEvent{
+		OrderID:    order.OrderID.Hex(),
+		NanoID:     order.NanoID,
+		CustomerID: order.CustomerID,
+		Status:     order.Status,
+		At:         at,
+	}
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -13,6 +15,33 @@ var (
 	StatusCancelled              = OrderStatus{"cancelled"}
 )
 
+var (
+	ErrInvalidStatusTransition = errors.New("invalid order status transition")
+	ErrOrderStatusChanged      = errors.New("order status has been changed by someone else")
+
+	// transitions describes which statuses an order is allowed to move to
+	// from the status in the key. Completed and cancelled orders are final.
+	transitions = map[string][]OrderStatus{
+		StatusWaitingForVerification.V: {StatusVerified, StatusCancelled},
+		StatusVerified.V:               {StatusCompleted, StatusCancelled},
+	}
+)
+
+// StatusTransitionError is returned when an order is asked to move
+// to a status which is not reachable from its current one.
+type StatusTransitionError struct {
+	From OrderStatus
+	To   OrderStatus
+}
+
+func (e StatusTransitionError) Error() string {
+	return fmt.Sprintf("order can not be moved from %q to %q", e.From, e.To)
+}
+
+func (e StatusTransitionError) Unwrap() error {
+	return ErrInvalidStatusTransition
+}
+
 type OrderStatus struct {
 	V string
 }
@@ -21,6 +50,24 @@ func (o OrderStatus) String() string {
 	return o.V
 }
 
+// CanTransitionTo checks if an order with current status can be moved to next
+func (o OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range transitions[o.V] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo returns StatusTransitionError if next status isn't reachable from current one
+func (o OrderStatus) TransitionTo(next OrderStatus) error {
+	if !o.CanTransitionTo(next) {
+		return StatusTransitionError{From: o, To: next}
+	}
+	return nil
+}
+
 func (o OrderStatus) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		Status string `json:"status"`
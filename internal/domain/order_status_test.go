@@ -19,3 +19,37 @@ func TestOrderStatusCustomJSONSerialize(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, StatusWaitingForVerification, decodedOrderStatus)
 }
+
+func TestOrderStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from    OrderStatus
+		to      OrderStatus
+		allowed bool
+	}{
+		{StatusWaitingForVerification, StatusVerified, true},
+		{StatusWaitingForVerification, StatusCancelled, true},
+		{StatusWaitingForVerification, StatusCompleted, false},
+		{StatusVerified, StatusCompleted, true},
+		{StatusVerified, StatusCancelled, true},
+		{StatusVerified, StatusVerified, false},
+		{StatusCompleted, StatusVerified, false},
+		{StatusCompleted, StatusCancelled, false},
+		{StatusCancelled, StatusVerified, false},
+		{StatusCancelled, StatusCompleted, false},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.allowed, test.from.CanTransitionTo(test.to), "%s -> %s", test.from, test.to)
+
+		err := test.from.TransitionTo(test.to)
+		if test.allowed {
+			require.NoError(t, err)
+			continue
+		}
+		require.ErrorIs(t, err, ErrInvalidStatusTransition)
+		var transitionErr StatusTransitionError
+		require.ErrorAs(t, err, &transitionErr)
+		require.Equal(t, test.from, transitionErr.From)
+		require.Equal(t, test.to, transitionErr.To)
+	}
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// OTPCodeLength is number of digits in one-time code
+const OTPCodeLength = 6
+
+var (
+	ErrOTPNotFound         = errors.New("code has not been requested or has expired")
+	ErrInvalidOTP          = errors.New("invalid code")
+	ErrOTPAttemptsExceeded = errors.New("too many attempts, request a new code")
+	ErrOTPResendTooSoon    = errors.New("code has been sent recently, try again later")
+)
+
+// OTP is one-time code sent to phone number to log customer in. There is at most one code per phone number.
+type OTP struct {
+	PhoneNumber string `json:"phoneNumber" bson:"_id"`
+	// CodeHash is hash of the code, code itself is never stored
+	CodeHash string `json:"-" bson:"codeHash"`
+	// Attempts is number of times the code has been checked
+	Attempts  int64     `json:"attempts" bson:"attempts"`
+	SentAt    time.Time `json:"sentAt" bson:"sentAt"`
+	ExpiresAt time.Time `json:"expiresAt" bson:"expiresAt"`
+}
+
+func (o OTP) IsExpiredAt(t time.Time) bool {
+	return !t.Before(o.ExpiresAt)
+}
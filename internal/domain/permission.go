@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrUnknownPermission   = errors.New("unknown permission")
+	ErrUnknownRole         = errors.New("unknown role")
+	ErrRolesManageRequired = errors.New("admin role can't lose permission to manage roles")
+)
+
+// Permission names an action that is allowed to roles having it
+type Permission string
+
+const (
+	PermissionOrdersCreate   Permission = "orders.create"
+	PermissionOrdersView     Permission = "orders.view"
+	PermissionOrdersVerify   Permission = "orders.verify"
+	PermissionOrdersComplete Permission = "orders.complete"
+	PermissionOrdersCancel   Permission = "orders.cancel"
+	// PermissionOrdersOwn allows customer to create, view and follow own orders
+	PermissionOrdersOwn Permission = "orders.own"
+
+	PermissionProductsEdit    Permission = "products.edit"
+	PermissionProductsApprove Permission = "products.approve"
+
+	PermissionPromoCodesEdit    Permission = "promocodes.edit"
+	PermissionDeliveryZonesEdit Permission = "delivery_zones.edit"
+	PermissionMetaEdit          Permission = "meta.edit"
+
+	PermissionWorkersManage  Permission = "workers.manage"
+	PermissionSessionsRevoke Permission = "sessions.revoke"
+	PermissionRolesManage    Permission = "roles.manage"
+)
+
+// Permissions lists every known permission
+var Permissions = []Permission{
+	PermissionOrdersCreate,
+	PermissionOrdersView,
+	PermissionOrdersVerify,
+	PermissionOrdersComplete,
+	PermissionOrdersCancel,
+	PermissionOrdersOwn,
+	PermissionProductsEdit,
+	PermissionProductsApprove,
+	PermissionPromoCodesEdit,
+	PermissionDeliveryZonesEdit,
+	PermissionMetaEdit,
+	PermissionWorkersManage,
+	PermissionSessionsRevoke,
+	PermissionRolesManage,
+}
+
+// Roles lists roles permissions are granted to
+var Roles = []Role{RoleCustomer, RoleWorker, RoleAdmin}
+
+// DefaultRolePermissions are used for roles whose permissions have not been saved by admin yet
+var DefaultRolePermissions = map[Role][]Permission{
+	RoleCustomer: {PermissionOrdersOwn},
+	RoleWorker: {
+		PermissionOrdersCreate,
+		PermissionOrdersView,
+		PermissionOrdersVerify,
+		PermissionOrdersComplete,
+		PermissionOrdersCancel,
+	},
+	RoleAdmin: Permissions,
+}
+
+func (p Permission) IsKnown() bool {
+	for _, known := range Permissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Role) IsKnown() bool {
+	for _, known := range Roles {
+		if r == known {
+			return true
+		}
+	}
+	return false
+}
+
+type PermissionsProvider interface {
+	// Has checks if role has every one of permissions
+	Has(role Role, permissions ...Permission) bool
+	// Set replaces permissions of the roles
+	Set(rolePermissions ...RolePermissions)
+}
+
+// RolePermissions is a set of permissions granted to every user of the role
+type RolePermissions struct {
+	Role        Role         `json:"role" bson:"role"`
+	Permissions []Permission `json:"permissions" bson:"permissions"`
+	UpdatedAt   time.Time    `json:"updatedAt" bson:"updatedAt"`
+}
+
+func (r RolePermissions) Has(permission Permission) bool {
+	for _, granted := range r.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -14,6 +15,11 @@ var (
 	ErrNoProducts                = errors.New("products not found")
 	ErrCategoryNotFound          = errors.New("category not found")
 	ErrNoCategories              = errors.New("categories not found")
+	ErrCategoryAlreadyExists     = errors.New("category already exists")
+	ErrCategoryNotEmpty          = errors.New("category has products")
+	ErrInvalidCategoryOrder      = errors.New("order should list every category once")
+	ErrUnsupportedImage          = errors.New("image should be jpeg, png or gif")
+	ErrImageTooLarge             = errors.New("image is too large")
 )
 
 type Product struct {
@@ -21,18 +27,62 @@ type Product struct {
 	Name        string             `bson:"name" json:"name"`
 	TranslateRU string             `bson:"translateRu" json:"translateRu"`
 	Description string             `bson:"description" json:"description"`
-	ImageURL    *string            `bson:"imageUrl" json:"imageUrl"`
+	ImageURL    *ImageURLs         `bson:"imageUrl" json:"imageUrl"`
 	IsApproved  bool               `bson:"isApproved" json:"isApproved"`
 	Price       int64              `bson:"price" json:"price"`
 	Category    Category           `bson:"category" json:"category"`
 	Features    Features           `bson:"features" json:"features"`
+	// OptionGroups are choices of the product, e.g. size or toppings, each option adds its price delta
+	OptionGroups []OptionGroup `bson:"optionGroups,omitempty" json:"optionGroups,omitempty"`
+	// Combo is set for products that are sets of other products
+	Combo *Combo `bson:"combo,omitempty" json:"combo,omitempty"`
 }
 
 type CartProduct struct {
 	Product
 	Quantity int32 `json:"quantity" bson:"quantity"`
+	// IsReady is set by the kitchen when the line is prepared
+	IsReady bool       `json:"isReady" bson:"isReady"`
+	ReadyAt *time.Time `json:"readyAt,omitempty" bson:"readyAt,omitempty"`
+	// Options chosen for the line, they are priced into every unit
+	Options []ChosenOption `json:"options,omitempty" bson:"options,omitempty"`
+	// Components are products of combo line, with swaps applied
+	Components []ComboItem `json:"components,omitempty" bson:"components,omitempty"`
 }
 
+// UnitPrice is price of one unit with chosen options and swaps
+func (c CartProduct) UnitPrice() int64 {
+	price := c.Price
+	for _, option := range c.Options {
+		price += option.PriceDelta
+	}
+	for _, component := range c.Components {
+		price += component.PriceDelta
+	}
+	return price
+}
+
+// Amount is price of the whole line
+func (c CartProduct) Amount() int64 {
+	return c.UnitPrice() * int64(c.Quantity)
+}
+
+// ImageURLs are URLs of resized variants of product image
+type ImageURLs struct {
+	Thumbnail string `bson:"thumbnail" json:"thumbnail"`
+	Medium    string `bson:"medium" json:"medium"`
+	// Keys of the variants in blob store, they're deleted along with product or replaced image
+	Keys []string `bson:"keys,omitempty" json:"-"`
+}
+
+// CatalogCategory is a category with its products, as customers see them in catalog
+type CatalogCategory struct {
+	Category
+	Products []Product `json:"products"`
+}
+
+// Category is copied into every product of it, so that catalog is sorted by rank without a lookup.
+// Categories with higher rank go first.
 type Category struct {
 	CategoryID primitive.ObjectID `bson:"_id" json:"categoryId"`
 	Rank       int32              `bson:"rank" json:"rank"`
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	DiscountPercent = DiscountKind{"percent"}
+	DiscountFixed   = DiscountKind{"fixed"}
+)
+
+var (
+	ErrPromoCodeNotFound      = errors.New("promo code not found")
+	ErrPromoCodeAlreadyExists = errors.New("promo code already exists")
+	ErrPromoCodeNotActive     = errors.New("promo code is not active")
+	ErrPromoCodeMinCartAmount = errors.New("cart amount is too small for promo code")
+	ErrPromoCodeNotApplicable = errors.New("promo code is not applicable to any product in cart")
+	ErrPromoCodeUsageLimit    = errors.New("promo code usage limit is reached")
+)
+
+type PromoCode struct {
+	PromoCodeID primitive.ObjectID `json:"promoCodeId" bson:"_id,omitempty"`
+	// Code is stored in upper case
+	Code string       `json:"code" bson:"code"`
+	Kind DiscountKind `json:"kind" bson:"kind"`
+	// Percent is fraction of eligible amount (0.15 is 15%). Used with DiscountPercent
+	Percent float64 `json:"percent,omitempty" bson:"percent,omitempty"`
+	// Amount is subtracted from eligible amount. Used with DiscountFixed
+	Amount        int64      `json:"amount,omitempty" bson:"amount,omitempty"`
+	MinCartAmount int64      `json:"minCartAmount" bson:"minCartAmount"`
+	ValidFrom     *time.Time `json:"validFrom,omitempty" bson:"validFrom,omitempty"`
+	ValidTo       *time.Time `json:"validTo,omitempty" bson:"validTo,omitempty"`
+	// Limits of redemptions, zero means unlimited
+	UsageLimit       int64 `json:"usageLimit" bson:"usageLimit"`
+	PerCustomerLimit int64 `json:"perCustomerLimit" bson:"perCustomerLimit"`
+	UsedCount        int64 `json:"usedCount" bson:"usedCount"`
+	// Promo code is applied only to products of these categories or these products.
+	// If both are empty, it's applied to the whole cart.
+	CategoryIDs []primitive.ObjectID `json:"categoryIds" bson:"categoryIds"`
+	ProductIDs  []primitive.ObjectID `json:"productIds" bson:"productIds"`
+	CreatedAt   time.Time            `json:"createdAt" bson:"createdAt"`
+}
+
+// OrderPromoCode is a promo code redeemed by an order
+type OrderPromoCode struct {
+	PromoCodeID primitive.ObjectID `json:"promoCodeId" bson:"promoCodeId"`
+	Code        string             `json:"code" bson:"code"`
+}
+
+func (p PromoCode) IsActiveAt(t time.Time) bool {
+	if p.ValidFrom != nil && t.Before(*p.ValidFrom) {
+		return false
+	}
+	if p.ValidTo != nil && t.After(*p.ValidTo) {
+		return false
+	}
+	return true
+}
+
+func (p PromoCode) IsRestricted() bool {
+	return len(p.CategoryIDs) > 0 || len(p.ProductIDs) > 0
+}
+
+func (p PromoCode) AppliesTo(product Product) bool {
+	if !p.IsRestricted() {
+		return true
+	}
+	for _, categoryID := range p.CategoryIDs {
+		if product.Category.CategoryID == categoryID {
+			return true
+		}
+	}
+	for _, productID := range p.ProductIDs {
+		if product.ProductID == productID {
+			return true
+		}
+	}
+	return false
+}
+
+// Discount returns fraction of amount the promo code takes off the cart.
+// Only eligible cart lines are discounted, fixed discount can't exceed their amount.
+func (p PromoCode) Discount(cart []CartProduct, amount int64) (float64, error) {
+	if amount <= 0 {
+		return 0, ErrPromoCodeNotApplicable
+	}
+	if amount < p.MinCartAmount {
+		return 0, ErrPromoCodeMinCartAmount
+	}
+
+	var eligibleAmount int64
+	for _, cartProduct := range cart {
+		if p.AppliesTo(cartProduct.Product) {
+			eligibleAmount += cartProduct.Amount()
+		}
+	}
+	if eligibleAmount == 0 {
+		return 0, ErrPromoCodeNotApplicable
+	}
+
+	var discountAmount float64
+	switch p.Kind {
+	case DiscountPercent:
+		discountAmount = p.Percent * float64(eligibleAmount)
+	case DiscountFixed:
+		discountAmount = float64(p.Amount)
+		if p.Amount > eligibleAmount {
+			discountAmount = float64(eligibleAmount)
+		}
+	}
+
+	return discountAmount / float64(amount), nil
+}
+
+type DiscountKind struct {
+	K string
+}
+
+func (d DiscountKind) String() string {
+	return d.K
+}
+
+func (d DiscountKind) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Kind string `json:"kind"`
+	}{
+		Kind: d.String(),
+	})
+}
+
+func (d *DiscountKind) UnmarshalJSON(raw []byte) error {
+	decoded := new(struct {
+		Kind string `json:"kind"`
+	})
+	if err := json.Unmarshal(raw, decoded); err != nil {
+		return err
+	}
+	d.K = decoded.Kind
+	return nil
+}
+
+func (d DiscountKind) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(struct {
+		Kind string `bson:"kind"`
+	}{
+		Kind: d.String(),
+	})
+}
+
+func (d *DiscountKind) UnmarshalBSON(raw []byte) error {
+	decoded := new(struct {
+		Kind string `bson:"kind"`
+	})
+	if err := bson.Unmarshal(raw, decoded); err != nil {
+		return err
+	}
+	d.K = decoded.Kind
+	return nil
+}
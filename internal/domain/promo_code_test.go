@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDiscountKindCustomJSONSerialize(t *testing.T) {
+	marshalled, err := json.Marshal(DiscountFixed)
+	require.NoError(t, err)
+	require.Equal(t, `{"kind":"fixed"}`, string(marshalled))
+
+	var decoded DiscountKind
+	require.NoError(t, json.Unmarshal(marshalled, &decoded))
+	require.Equal(t, DiscountFixed, decoded)
+}
+
+func TestPromoCodeIsActiveAt(t *testing.T) {
+	var (
+		now       = time.Now().UTC()
+		yesterday = now.Add(-time.Hour * 24)
+		tomorrow  = now.Add(time.Hour * 24)
+	)
+
+	require.True(t, PromoCode{}.IsActiveAt(now))
+	require.True(t, PromoCode{ValidFrom: &yesterday, ValidTo: &tomorrow}.IsActiveAt(now))
+	require.False(t, PromoCode{ValidFrom: &tomorrow}.IsActiveAt(now))
+	require.False(t, PromoCode{ValidTo: &yesterday}.IsActiveAt(now))
+}
+
+func TestPromoCodeDiscount(t *testing.T) {
+	var (
+		pizza = Category{CategoryID: primitive.NewObjectID()}
+		drink = Category{CategoryID: primitive.NewObjectID()}
+		cart  = []CartProduct{
+			{Product: Product{ProductID: primitive.NewObjectID(), Price: 400, Category: pizza}, Quantity: 2},
+			{Product: Product{ProductID: primitive.NewObjectID(), Price: 100, Category: drink}, Quantity: 2},
+		}
+		// 2 * 400 + 2 * 100
+		amount int64 = 1000
+	)
+
+	t.Run("should discount whole cart by percent", func(t *testing.T) {
+		promoCode := PromoCode{Kind: DiscountPercent, Percent: 0.1}
+		discount, err := promoCode.Discount(cart, amount)
+		require.NoError(t, err)
+		require.InDelta(t, 0.1, discount, 1e-9)
+	})
+
+	t.Run("should discount only products of category", func(t *testing.T) {
+		promoCode := PromoCode{Kind: DiscountPercent, Percent: 0.5, CategoryIDs: []primitive.ObjectID{drink.CategoryID}}
+		discount, err := promoCode.Discount(cart, amount)
+		require.NoError(t, err)
+		// Half of 200 out of 1000
+		require.InDelta(t, 0.1, discount, 1e-9)
+	})
+
+	t.Run("should discount fixed amount", func(t *testing.T) {
+		promoCode := PromoCode{Kind: DiscountFixed, Amount: 250}
+		discount, err := promoCode.Discount(cart, amount)
+		require.NoError(t, err)
+		require.InDelta(t, 0.25, discount, 1e-9)
+	})
+
+	t.Run("should not discount more than eligible amount", func(t *testing.T) {
+		promoCode := PromoCode{Kind: DiscountFixed, Amount: 500, ProductIDs: []primitive.ObjectID{cart[1].ProductID}}
+		discount, err := promoCode.Discount(cart, amount)
+		require.NoError(t, err)
+		require.InDelta(t, 0.2, discount, 1e-9)
+	})
+
+	t.Run("should discount chosen options of eligible lines", func(t *testing.T) {
+		withOptions := []CartProduct{
+			{Product: cart[0].Product, Quantity: 2, Options: []ChosenOption{{PriceDelta: 100}}},
+			cart[1],
+		}
+		promoCode := PromoCode{Kind: DiscountPercent, Percent: 0.5, CategoryIDs: []primitive.ObjectID{pizza.CategoryID}}
+		discount, err := promoCode.Discount(withOptions, 1200)
+		require.NoError(t, err)
+		// Half of 2 * (400 + 100) out of 1200
+		require.InDelta(t, 500.0/1200, discount, 1e-9)
+	})
+
+	t.Run("should return ErrPromoCodeMinCartAmount", func(t *testing.T) {
+		promoCode := PromoCode{Kind: DiscountPercent, Percent: 0.1, MinCartAmount: 1500}
+		_, err := promoCode.Discount(cart, amount)
+		require.ErrorIs(t, err, ErrPromoCodeMinCartAmount)
+	})
+
+	t.Run("should return ErrPromoCodeNotApplicable because no product is eligible", func(t *testing.T) {
+		promoCode := PromoCode{Kind: DiscountPercent, Percent: 0.1, CategoryIDs: []primitive.ObjectID{primitive.NewObjectID()}}
+		_, err := promoCode.Discount(cart, amount)
+		require.ErrorIs(t, err, ErrPromoCodeNotApplicable)
+	})
+}
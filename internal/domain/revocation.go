@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+)
+
+type RevocationProvider interface {
+	Get(key string) (Revocation, bool)
+	Set(revocations ...Revocation)
+	Prune(t time.Time)
+}
+
+// Revocation makes access tokens invalid before they expire. It revokes either a single token,
+// every token of a session or every token of a user issued until RevokedAt.
+type Revocation struct {
+	// Key is one of TokenRevocationKey, SessionRevocationKey or UserRevocationKey
+	Key       string    `json:"key" bson:"_id"`
+	RevokedAt time.Time `json:"revokedAt" bson:"revokedAt"`
+	// ExpiresAt is when every token it revokes has expired on its own, so it's no longer needed
+	ExpiresAt time.Time `json:"expiresAt" bson:"expiresAt"`
+}
+
+func TokenRevocationKey(tokenID string) string {
+	return "token:" + tokenID
+}
+
+func SessionRevocationKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+func UserRevocationKey(role Role, userID string) string {
+	return "user:" + role.String() + ":" + userID
+}
+
+// Revokes reports whether token issued at issuedAt is revoked
+func (r Revocation) Revokes(issuedAt time.Time) bool {
+	return !issuedAt.After(r.RevokedAt)
+}
+
+func (r Revocation) IsExpiredAt(t time.Time) bool {
+	return !t.Before(r.ExpiresAt)
+}
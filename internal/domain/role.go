@@ -11,13 +11,6 @@ var (
 	RoleCustomer = Role{"customer"}
 	RoleWorker   = Role{"worker"}
 	RoleAdmin    = Role{"admin"}
-
-	permissions = map[string]int{
-		RoleUnknown.R:  0,
-		RoleCustomer.R: 1,
-		RoleWorker.R:   2,
-		RoleAdmin.R:    3,
-	}
 )
 
 type Role struct {
@@ -28,11 +21,6 @@ func (r Role) String() string {
 	return r.R
 }
 
-// CheckPermissions checks if compareWith Role has higher or equal permissions as current Role
-func (r Role) CheckPermissions(required Role) bool {
-	return permissions[r.R] >= permissions[required.R]
-}
-
 func (r Role) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
 		Role string `json:"role"`
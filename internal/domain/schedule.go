@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DateLayout is layout of ScheduleOverride.Date
+	DateLayout = "2006-01-02"
+	// Opening is looked up this far ahead, so that long closures can be set up with overrides
+	scheduleLookahead = 62
+)
+
+var (
+	ErrStoreClosed              = errors.New("store is closed")
+	ErrDeliveryTimeOutsideHours = errors.New("delivery time is outside of working hours")
+)
+
+// Schedule is working hours of the store. Empty schedule means the store never closes.
+type Schedule struct {
+	// Timezone is IANA name of the zone hours are set in, e.g. Asia/Yekaterinburg
+	Timezone string         `json:"timezone" bson:"timezone"`
+	Weekly   []WorkingHours `json:"weekly" bson:"weekly"`
+	// Overrides replace weekly hours on specific dates, e.g. holidays
+	Overrides []ScheduleOverride `json:"overrides" bson:"overrides"`
+}
+
+// WorkingHours of a weekday. Store is closed on weekdays that have no working hours.
+type WorkingHours struct {
+	Weekday time.Weekday `json:"weekday" bson:"weekday"`
+	// Open and Close are "15:04" clock times, Close can be "24:00" for the end of day.
+	// Close before Open means store closes on the next day, e.g. 18:00-02:00.
+	Open  string `json:"open" bson:"open"`
+	Close string `json:"close" bson:"close"`
+}
+
+type ScheduleOverride struct {
+	Date     string `json:"date" bson:"date"`
+	IsClosed bool   `json:"isClosed" bson:"isClosed"`
+	// Open and Close are set unless store is closed for the whole day. They can span past midnight as weekly hours do.
+	Open  string `json:"open,omitempty" bson:"open,omitempty"`
+	Close string `json:"close,omitempty" bson:"close,omitempty"`
+}
+
+func (s Schedule) IsSet() bool {
+	return len(s.Weekly) > 0 || len(s.Overrides) > 0
+}
+
+// Location returns timezone of the schedule. Timezone is validated on update, so UTC fallback is never hit in practice.
+func (s Schedule) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func (s Schedule) IsOpenAt(t time.Time) bool {
+	if !s.IsSet() {
+		return true
+	}
+	_, _, ok := s.HoursAt(t)
+	return ok
+}
+
+// HoursAt returns opening and closing time of working hours t falls into.
+// Hours that have opened the day before and close after midnight are looked up as well.
+func (s Schedule) HoursAt(t time.Time) (opensAt, closesAt time.Time, ok bool) {
+	local := t.In(s.Location())
+	for _, day := range []time.Time{local.AddDate(0, 0, -1), local} {
+		opensAt, closesAt, ok := s.HoursOn(day)
+		if ok && !local.Before(opensAt) && local.Before(closesAt) {
+			return opensAt, closesAt, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// NextOpening returns t if store is open at t, otherwise the time it opens after t.
+// It returns false if store doesn't open within the lookahead.
+func (s Schedule) NextOpening(t time.Time) (time.Time, bool) {
+	if !s.IsSet() {
+		return t, true
+	}
+	local := t.In(s.Location())
+	// Hours of the day before may still be going on
+	for i := -1; i <= scheduleLookahead; i++ {
+		opensAt, closesAt, ok := s.HoursOn(local.AddDate(0, 0, i))
+		if !ok || !local.Before(closesAt) {
+			continue
+		}
+		if local.Before(opensAt) {
+			return opensAt, true
+		}
+		return local, true
+	}
+	return time.Time{}, false
+}
+
+// HoursOn returns opening and closing time of working hours that start on the date of day.
+// Closing time is on the next day if hours span past midnight. Day should be in schedule's location.
+// Store that has no schedule is open all day long.
+func (s Schedule) HoursOn(day time.Time) (opensAt, closesAt time.Time, ok bool) {
+	if !s.IsSet() {
+		year, month, date := day.Date()
+		opensAt = time.Date(year, month, date, 0, 0, 0, 0, day.Location())
+		return opensAt, opensAt.AddDate(0, 0, 1), true
+	}
+	openClock, closeClock, ok := s.clockOn(day)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	openMinutes, ok := ParseClock(openClock)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	closeMinutes, ok := ParseClock(closeClock)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	year, month, date := day.Date()
+	// time.Date normalizes 24:00 to midnight of the next day
+	opensAt = time.Date(year, month, date, openMinutes/60, openMinutes%60, 0, 0, day.Location())
+	closesAt = time.Date(year, month, date, closeMinutes/60, closeMinutes%60, 0, 0, day.Location())
+	if closeMinutes <= openMinutes {
+		closesAt = time.Date(year, month, date+1, closeMinutes/60, closeMinutes%60, 0, 0, day.Location())
+	}
+	return opensAt, closesAt, true
+}
+
+func (s Schedule) clockOn(day time.Time) (openClock, closeClock string, ok bool) {
+	date := day.Format(DateLayout)
+	for _, override := range s.Overrides {
+		if override.Date == date {
+			if override.IsClosed {
+				return "", "", false
+			}
+			return override.Open, override.Close, true
+		}
+	}
+	for _, hours := range s.Weekly {
+		if hours.Weekday == day.Weekday() {
+			return hours.Open, hours.Close, true
+		}
+	}
+	return "", "", false
+}
+
+// ParseClock parses "15:04" clock time into minutes since midnight. "24:00" is allowed.
+func ParseClock(clock string) (int, bool) {
+	hoursStr, minutesStr, found := strings.Cut(clock, ":")
+	if !found || len(hoursStr) != 2 || len(minutesStr) != 2 {
+		return 0, false
+	}
+	hours, err := strconv.Atoi(hoursStr)
+	if err != nil {
+		return 0, false
+	}
+	minutes, err := strconv.Atoi(minutesStr)
+	if err != nil {
+		return 0, false
+	}
+	if hours < 0 || hours > 24 || minutes < 0 || minutes > 59 || (hours == 24 && minutes != 0) {
+		return 0, false
+	}
+	return hours*60 + minutes, true
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"testing"
+	"time"
+	// Schedule is tested in a real timezone, so tests don't depend on zoneinfo of the machine
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleIsOpenAt(t *testing.T) {
+	schedule := getSchedule()
+	loc := schedule.Location()
+
+	// 2023-01-30 is Monday
+	require.True(t, schedule.IsOpenAt(time.Date(2023, 1, 30, 10, 0, 0, 0, loc)))
+	require.True(t, schedule.IsOpenAt(time.Date(2023, 1, 30, 22, 59, 0, 0, loc)))
+	require.False(t, schedule.IsOpenAt(time.Date(2023, 1, 30, 23, 0, 0, 0, loc)))
+	require.False(t, schedule.IsOpenAt(time.Date(2023, 1, 30, 3, 0, 0, 0, loc)))
+	// Same instant in UTC, Yekaterinburg is UTC+5
+	require.True(t, schedule.IsOpenAt(time.Date(2023, 1, 30, 5, 30, 0, 0, time.UTC)))
+	// Open until the end of Saturday
+	require.True(t, schedule.IsOpenAt(time.Date(2023, 2, 4, 23, 59, 0, 0, loc)))
+	// Sunday has no working hours
+	require.False(t, schedule.IsOpenAt(time.Date(2023, 2, 5, 12, 0, 0, 0, loc)))
+	// Holiday
+	require.False(t, schedule.IsOpenAt(time.Date(2023, 2, 23, 12, 0, 0, 0, loc)))
+	// Short day
+	require.True(t, schedule.IsOpenAt(time.Date(2023, 3, 7, 15, 0, 0, 0, loc)))
+	require.False(t, schedule.IsOpenAt(time.Date(2023, 3, 7, 19, 0, 0, 0, loc)))
+
+	require.True(t, Schedule{}.IsOpenAt(time.Date(2023, 1, 30, 3, 0, 0, 0, loc)))
+}
+
+func TestScheduleOvernight(t *testing.T) {
+	schedule := getSchedule()
+	schedule.Weekly[4].Close = "02:00"
+	loc := schedule.Location()
+
+	// 2023-02-03 is Friday, store is open until 02:00 of Saturday
+	require.True(t, schedule.IsOpenAt(time.Date(2023, 2, 3, 23, 30, 0, 0, loc)))
+	require.True(t, schedule.IsOpenAt(time.Date(2023, 2, 4, 1, 0, 0, 0, loc)))
+	require.False(t, schedule.IsOpenAt(time.Date(2023, 2, 4, 2, 0, 0, 0, loc)))
+	// Saturday hours are not affected
+	require.True(t, schedule.IsOpenAt(time.Date(2023, 2, 4, 12, 0, 0, 0, loc)))
+	// Thursday closes before midnight
+	require.False(t, schedule.IsOpenAt(time.Date(2023, 2, 3, 1, 0, 0, 0, loc)))
+
+	opensAt, closesAt, ok := schedule.HoursAt(time.Date(2023, 2, 4, 1, 0, 0, 0, loc))
+	require.True(t, ok)
+	require.True(t, time.Date(2023, 2, 3, 10, 0, 0, 0, loc).Equal(opensAt))
+	require.True(t, time.Date(2023, 2, 4, 2, 0, 0, 0, loc).Equal(closesAt))
+
+	now := time.Date(2023, 2, 4, 1, 0, 0, 0, loc)
+	opening, ok := schedule.NextOpening(now)
+	require.True(t, ok)
+	require.True(t, now.Equal(opening))
+
+	opening, ok = schedule.NextOpening(time.Date(2023, 2, 4, 2, 30, 0, 0, loc))
+	require.True(t, ok)
+	require.True(t, time.Date(2023, 2, 4, 12, 0, 0, 0, loc).Equal(opening))
+
+	slot, ok := schedule.DeliverySlotAt(time.Date(2023, 2, 4, 1, 10, 0, 0, loc), time.Hour)
+	require.True(t, ok)
+	require.True(t, time.Date(2023, 2, 4, 1, 0, 0, 0, loc).Equal(slot))
+
+	slots := schedule.DeliverySlotsOn(time.Date(2023, 2, 3, 0, 0, 0, 0, loc), time.Hour)
+	require.Len(t, slots, 16)
+	require.True(t, time.Date(2023, 2, 4, 1, 0, 0, 0, loc).Equal(slots[len(slots)-1]))
+}
+
+func TestScheduleNextOpening(t *testing.T) {
+	schedule := getSchedule()
+	loc := schedule.Location()
+
+	t.Run("should return same time because store is open", func(t *testing.T) {
+		now := time.Date(2023, 1, 30, 12, 0, 0, 0, loc)
+		opening, ok := schedule.NextOpening(now)
+		require.True(t, ok)
+		require.True(t, now.Equal(opening))
+	})
+
+	t.Run("should return opening of the same day", func(t *testing.T) {
+		opening, ok := schedule.NextOpening(time.Date(2023, 1, 30, 3, 0, 0, 0, loc))
+		require.True(t, ok)
+		require.True(t, time.Date(2023, 1, 30, 10, 0, 0, 0, loc).Equal(opening))
+	})
+
+	t.Run("should skip day off and holiday", func(t *testing.T) {
+		// Saturday is over, Sunday is day off
+		opening, ok := schedule.NextOpening(time.Date(2023, 2, 5, 0, 30, 0, 0, loc))
+		require.True(t, ok)
+		require.True(t, time.Date(2023, 2, 6, 10, 0, 0, 0, loc).Equal(opening))
+
+		opening, ok = schedule.NextOpening(time.Date(2023, 2, 22, 23, 30, 0, 0, loc))
+		require.True(t, ok)
+		require.True(t, time.Date(2023, 2, 24, 10, 0, 0, 0, loc).Equal(opening))
+	})
+
+	t.Run("should not find opening", func(t *testing.T) {
+		closed := Schedule{
+			Timezone:  schedule.Timezone,
+			Overrides: []ScheduleOverride{{Date: "2023-01-30", IsClosed: true}},
+		}
+		_, ok := closed.NextOpening(time.Date(2023, 1, 30, 12, 0, 0, 0, loc))
+		require.False(t, ok)
+	})
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		clock   string
+		minutes int
+		ok      bool
+	}{
+		{"00:00", 0, true},
+		{"09:30", 570, true},
+		{"24:00", 1440, true},
+		{"24:01", 0, false},
+		{"9:30", 0, false},
+		{"10:60", 0, false},
+		{"1030", 0, false},
+	}
+	for _, test := range tests {
+		minutes, ok := ParseClock(test.clock)
+		require.Equal(t, test.ok, ok, test.clock)
+		require.Equal(t, test.minutes, minutes, test.clock)
+	}
+}
+
+func getSchedule() Schedule {
+	schedule := Schedule{
+		Timezone: "Asia/Yekaterinburg",
+		Overrides: []ScheduleOverride{
+			{Date: "2023-02-23", IsClosed: true},
+			{Date: "2023-03-07", Open: "10:00", Close: "18:00"},
+		},
+	}
+	for weekday := time.Monday; weekday <= time.Friday; weekday++ {
+		schedule.Weekly = append(schedule.Weekly, WorkingHours{Weekday: weekday, Open: "10:00", Close: "23:00"})
+	}
+	schedule.Weekly = append(schedule.Weekly, WorkingHours{Weekday: time.Saturday, Open: "12:00", Close: "24:00"})
+	return schedule
+}
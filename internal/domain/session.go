@@ -1,10 +1,35 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrSessionNotFound = errors.New("session not found or has expired")
+
+// Session is login of user on a device. User has a session per device it's logged in on.
 type Session struct {
-	RefreshToken string    `json:"refreshToken" bson:"refreshToken"`
-	ExpiresAt    time.Time `json:"expiresAt" bson:"expiresAt"`
+	SessionID primitive.ObjectID `json:"sessionId" bson:"_id,omitempty"`
+	UserID    string             `json:"userId" bson:"userId"`
+	Role      Role               `json:"role" bson:"role"`
+	// RefreshToken is rotated every time it's used
+	RefreshToken string `json:"-" bson:"refreshToken"`
+	// Device is label given by user on login, e.g. "kitchen tablet"
+	Device     string    `json:"device,omitempty" bson:"device,omitempty"`
+	IP         string    `json:"ip" bson:"ip"`
+	UserAgent  string    `json:"userAgent" bson:"userAgent"`
+	CreatedAt  time.Time `json:"createdAt" bson:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt" bson:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt" bson:"expiresAt"`
+}
+
+// SessionClient is device session is used from
+type SessionClient struct {
+	Device    string
+	IP        string
+	UserAgent string
 }
 
 func NewExpiresAt(ttl time.Duration) time.Time {
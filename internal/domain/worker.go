@@ -1,14 +1,25 @@
 package domain
 
 import (
+	"errors"
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var (
+	ErrWorkerNotFound      = errors.New("worker not found")
+	ErrWorkerAlreadyExists = errors.New("worker with such login exists")
+	ErrWorkerDisabled      = errors.New("worker is disabled")
+)
+
 type Worker struct {
-	UserID   primitive.ObjectID `json:"userId" bson:"_id"`
+	UserID   primitive.ObjectID `json:"userId" bson:"_id,omitempty"`
 	Name     string             `json:"name" bson:"name"`
 	Login    string             `json:"login" bson:"login"`
-	Password string             `json:"password" bson:"password"`
+	Password string             `json:"-" bson:"password"`
 	Role     Role               `json:"role" bson:"role"`
-	Session  Session            `json:"session,omitempty" bson:"session,omitempty"`
+	// Disabled worker can't log in and its sessions are revoked
+	IsDisabled bool      `json:"isDisabled" bson:"isDisabled"`
+	CreatedAt  time.Time `json:"createdAt" bson:"createdAt"`
 }
@@ -1,13 +1,26 @@
 package handler
 
 import (
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
 	"github.com/sonyamoonglade/sancho-backend/internal/validation"
 )
 
+func (h Handler) AdminGetProducts(c *fiber.Ctx) error {
+	products, err := h.services.Product.GetAll(c.Context())
+	if err != nil {
+		return err
+	}
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"products": products,
+	})
+}
+
 func (h Handler) AdminCreateProduct(c *fiber.Ctx) error {
 	var inp input.CreateProductInput
 	if err := c.BodyParser(&inp); err != nil {
@@ -17,6 +30,12 @@ func (h Handler) AdminCreateProduct(c *fiber.Ctx) error {
 	if ok, msg := validation.ValidateFeatures(inp.Features); !ok {
 		return c.Status(http.StatusBadRequest).SendString(msg)
 	}
+	if ok, msg := validation.ValidateOptionGroups(inp.OptionGroups); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+	if ok, msg := validation.ValidateCombo(inp.Combo); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
 
 	productID, err := h.services.Product.Create(c.Context(), inp.ToDTO())
 	if err != nil {
@@ -54,6 +73,54 @@ func (h Handler) AdminUpdateProduct(c *fiber.Ctx) error {
 	return c.SendStatus(http.StatusOK)
 }
 
+func (h Handler) AdminUpdateProductOptions(c *fiber.Ctx) error {
+	productID := c.Params("id", "")
+	if productID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	var inp input.UpdateOptionGroupsInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateOptionGroups(inp.OptionGroups); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+	if err := h.services.Product.UpdateOptionGroups(c.Context(), inp.ToDTO(productID)); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+// AdminUploadProductImage accepts multipart form with image in "image" field
+func (h Handler) AdminUploadProductImage(c *fiber.Ctx) error {
+	productID := c.Params("id", "")
+	if productID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		return c.Status(http.StatusBadRequest).SendString("missing image")
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+
+	imageURL, err := h.services.Product.UploadImage(c.Context(), productID, data)
+	if err != nil {
+		return err
+	}
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"imageUrl": imageURL,
+	})
+}
+
 func (h Handler) AdminApproveProduct(c *fiber.Ctx) error {
 	productID := c.Params("id", "")
 	if productID == "" {
@@ -75,3 +142,320 @@ func (h Handler) AdminDisapproveProduct(c *fiber.Ctx) error {
 	}
 	return c.SendStatus(http.StatusOK)
 }
+
+func (h Handler) AdminCreateCategory(c *fiber.Ctx) error {
+	var inp input.CategoryInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateCategoryInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+
+	categoryID, err := h.services.Product.CreateCategory(c.Context(), strings.TrimSpace(inp.Name))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"categoryId": categoryID,
+	})
+}
+
+func (h Handler) AdminRenameCategory(c *fiber.Ctx) error {
+	categoryID := c.Params("id", "")
+	if categoryID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	var inp input.CategoryInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateCategoryInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+	if err := h.services.Product.RenameCategory(c.Context(), inp.ToDTO(categoryID)); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) AdminReorderCategories(c *fiber.Ctx) error {
+	var inp input.ReorderCategoriesInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateReorderCategoriesInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+	if err := h.services.Product.ReorderCategories(c.Context(), inp.CategoryIDs); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) AdminDeleteCategory(c *fiber.Ctx) error {
+	categoryID := c.Params("id", "")
+	if categoryID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	if err := h.services.Product.DeleteCategory(c.Context(), categoryID); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) AdminGetMeta(c *fiber.Ctx) error {
+	meta, err := h.services.Meta.Get(c.Context())
+	if err != nil {
+		return err
+	}
+	return c.Status(http.StatusOK).JSON(meta)
+}
+
+func (h Handler) AdminUpdateSchedule(c *fiber.Ctx) error {
+	var inp input.UpdateScheduleInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateUpdateScheduleInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+	if err := h.services.Meta.UpdateSchedule(c.Context(), inp.ToDTO()); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) AdminGetPromoCodes(c *fiber.Ctx) error {
+	promoCodes, err := h.services.PromoCode.GetAll(c.Context())
+	if err != nil {
+		return err
+	}
+	return c.Status(http.StatusOK).JSON(promoCodes)
+}
+
+func (h Handler) AdminGetPromoCode(c *fiber.Ctx) error {
+	promoCodeID := c.Params("id", "")
+	if promoCodeID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	promoCode, err := h.services.PromoCode.GetByID(c.Context(), promoCodeID)
+	if err != nil {
+		return err
+	}
+	return c.Status(http.StatusOK).JSON(promoCode)
+}
+
+func (h Handler) AdminCreatePromoCode(c *fiber.Ctx) error {
+	var inp input.CreatePromoCodeInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateCreatePromoCodeInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+
+	promoCodeID, err := h.services.PromoCode.Create(c.Context(), inp.ToDTO())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"promoCodeId": promoCodeID,
+	})
+}
+
+func (h Handler) AdminUpdatePromoCode(c *fiber.Ctx) error {
+	promoCodeID := c.Params("id", "")
+	if promoCodeID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	var inp input.UpdatePromoCodeInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidatePromoCodeTermsInput(inp.PromoCodeTermsInput); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+	if err := h.services.PromoCode.Update(c.Context(), inp.ToDTO(promoCodeID)); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) AdminDeletePromoCode(c *fiber.Ctx) error {
+	promoCodeID := c.Params("id", "")
+	if promoCodeID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	if err := h.services.PromoCode.Delete(c.Context(), promoCodeID); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) AdminGetDeliveryZone(c *fiber.Ctx) error {
+	deliveryZoneID := c.Params("id", "")
+	if deliveryZoneID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	deliveryZone, err := h.services.DeliveryZone.GetByID(c.Context(), deliveryZoneID)
+	if err != nil {
+		return err
+	}
+	return c.Status(http.StatusOK).JSON(deliveryZone)
+}
+
+func (h Handler) AdminCreateDeliveryZone(c *fiber.Ctx) error {
+	var inp input.DeliveryZoneInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateDeliveryZoneInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+
+	deliveryZoneID, err := h.services.DeliveryZone.Create(c.Context(), inp.ToCreateDTO())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"deliveryZoneId": deliveryZoneID,
+	})
+}
+
+func (h Handler) AdminUpdateDeliveryZone(c *fiber.Ctx) error {
+	deliveryZoneID := c.Params("id", "")
+	if deliveryZoneID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	var inp input.DeliveryZoneInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateDeliveryZoneInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+	if err := h.services.DeliveryZone.Update(c.Context(), inp.ToUpdateDTO(deliveryZoneID)); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) AdminDeleteDeliveryZone(c *fiber.Ctx) error {
+	deliveryZoneID := c.Params("id", "")
+	if deliveryZoneID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	if err := h.services.DeliveryZone.Delete(c.Context(), deliveryZoneID); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) AdminGetWorkers(c *fiber.Ctx) error {
+	workers, err := h.services.User.GetWorkers(c.Context())
+	if err != nil {
+		return err
+	}
+	return c.Status(http.StatusOK).JSON(workers)
+}
+
+func (h Handler) AdminCreateWorker(c *fiber.Ctx) error {
+	var inp input.CreateWorkerInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateCreateWorkerInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+
+	workerID, err := h.services.Auth.RegisterWorker(c.Context(), inp.ToDTO())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"workerId": workerID,
+	})
+}
+
+func (h Handler) AdminDisableWorker(c *fiber.Ctx) error {
+	workerID := c.Params("id", "")
+	if workerID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	if err := h.services.User.DisableWorker(c.Context(), workerID); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) AdminResetWorkerPassword(c *fiber.Ctx) error {
+	workerID := c.Params("id", "")
+	if workerID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	var inp input.ResetWorkerPasswordInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateResetWorkerPasswordInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+	if err := h.services.Auth.ResetWorkerPassword(c.Context(), workerID, inp.Password); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) AdminRevokeWorkerSessions(c *fiber.Ctx) error {
+	return h.adminRevokeSessions(c, domain.RoleWorker)
+}
+
+func (h Handler) AdminRevokeCustomerSessions(c *fiber.Ctx) error {
+	return h.adminRevokeSessions(c, domain.RoleCustomer)
+}
+
+// adminRevokeSessions logs user out of every device, e.g. when tablet is lost
+func (h Handler) adminRevokeSessions(c *fiber.Ctx, role domain.Role) error {
+	userID := c.Params("id", "")
+	if userID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	if err := h.services.Session.RevokeAll(c.Context(), role, userID); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) AdminGetRolePermissions(c *fiber.Ctx) error {
+	roles, err := h.services.Permission.GetAll(c.Context())
+	if err != nil {
+		return err
+	}
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"roles":       roles,
+		"permissions": domain.Permissions,
+	})
+}
+
+func (h Handler) AdminUpdateRolePermissions(c *fiber.Ctx) error {
+	role := c.Params("role", "")
+	if role == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty role")
+	}
+	var inp input.UpdateRolePermissionsInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateUpdateRolePermissionsInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+	if err := h.services.Permission.Update(c.Context(), inp.ToDTO(domain.Role{R: role})); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
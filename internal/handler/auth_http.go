@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/middleware"
+	"github.com/sonyamoonglade/sancho-backend/internal/validation"
+	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
+)
+
+func (h Handler) RequestCustomerOTP(c *fiber.Ctx) error {
+	var inp input.RequestOTPInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateRequestOTPInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+
+	if err := h.services.Auth.RequestCustomerOTP(c.Context(), inp.PhoneNumber); err != nil {
+		return err
+	}
+
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) LoginCustomer(c *fiber.Ctx) error {
+	var inp input.LoginCustomerInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateLoginCustomerInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+
+	tokens, err := h.services.Auth.LoginCustomer(c.Context(), inp.ToDTO(middleware.GetSessionClient(c, inp.Device)))
+	if err != nil {
+		return err
+	}
+
+	return sendTokens(c, tokens)
+}
+
+func (h Handler) LoginWorker(c *fiber.Ctx) error {
+	var inp input.LoginInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateStruct(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+
+	tokens, err := h.services.Auth.LoginWorker(c.Context(), inp.ToWorkerDTO(middleware.GetSessionClient(c, inp.Device)))
+	if err != nil {
+		return err
+	}
+
+	return sendTokens(c, tokens)
+}
+
+func (h Handler) LoginAdmin(c *fiber.Ctx) error {
+	var inp input.LoginInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateStruct(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+
+	tokens, err := h.services.Auth.LoginAdmin(c.Context(), inp.ToAdminDTO(middleware.GetSessionClient(c, inp.Device)))
+	if err != nil {
+		return err
+	}
+
+	return sendTokens(c, tokens)
+}
+
+func (h Handler) Logout(c *fiber.Ctx) error {
+	claims, err := middleware.GetClaimsFromCtx(c)
+	if err != nil {
+		return err
+	}
+	if err := h.services.Auth.Logout(c.Context(), claims); err != nil {
+		return err
+	}
+	middleware.ClearRefreshTokenCookie(c)
+	return c.SendStatus(http.StatusOK)
+}
+
+// LogoutEverywhere logs user out of every device
+func (h Handler) LogoutEverywhere(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromCtx(c)
+	if err != nil {
+		return err
+	}
+	role, err := middleware.GetRoleFromCtx(c)
+	if err != nil {
+		return err
+	}
+	if err := h.services.Session.RevokeAll(c.Context(), role, userID); err != nil {
+		return err
+	}
+	middleware.ClearRefreshTokenCookie(c)
+	return c.SendStatus(http.StatusOK)
+}
+
+// sendTokens sets refresh token cookie for browsers and returns both tokens for other clients
+func sendTokens(c *fiber.Ctx, tokens auth.Pair) error {
+	middleware.SetRefreshTokenCookie(c, tokens.RefreshToken)
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"accessToken":  tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
+	})
+}
+
+func (h Handler) GetJWKS(c *fiber.Ctx) error {
+	// Verifiers refetch keys on unknown kid, so short caching is fine during rotation
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(http.StatusOK).JSON(h.services.Auth.JWKS())
+}
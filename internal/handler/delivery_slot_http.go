@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/sonyamoonglade/sancho-backend/internal/validation"
+)
+
+// GetDeliverySlots lists slots of the day that scheduled delivery can be placed in
+func (h Handler) GetDeliverySlots(c *fiber.Ctx) error {
+	var inp input.DeliverySlotsInput
+	if err := c.QueryParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateDeliverySlotsInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+
+	slots, err := h.services.DeliverySlot.GetAvailable(c.Context(), inp.Date)
+	if err != nil {
+		return err
+	}
+	return c.Status(http.StatusOK).JSON(slots)
+}
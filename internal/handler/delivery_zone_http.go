@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetDeliveryZones lets customers see where orders are delivered to and on what terms
+func (h Handler) GetDeliveryZones(c *fiber.Ctx) error {
+	deliveryZones, err := h.services.DeliveryZone.GetAll(c.Context())
+	if err != nil {
+		return err
+	}
+	return c.Status(http.StatusOK).JSON(deliveryZones)
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/sonyamoonglade/sancho-backend/internal/appErrors"
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	service "github.com/sonyamoonglade/sancho-backend/internal/services"
+	"github.com/sonyamoonglade/sancho-backend/pkg/cursor"
 	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -32,11 +35,28 @@ func HandleError(c *fiber.Ctx, err error) error {
 		})
 	}
 
+	// Errors of fiber itself such as unknown route or missing static file
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return c.Status(fiberErr.Code).JSON(fiber.Map{
+			"message": fiberErr.Message,
+		})
+	}
+
 	// Domain errors
 	logger.Get().Debug("domain error",
 		zap.String("X-Request-Id", c.GetRespHeaders()["X-Request-Id"]),
 		zap.Error(err),
 	)
+
+	// Client should know which cart lines to fix
+	var cartErr domain.CartError
+	if errors.As(err, &cartErr) {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": domain.ErrInvalidCart.Error(),
+			"lines":   cartErr.Lines,
+		})
+	}
 	msg, code := domainErrorToHTTP(err)
 	return c.Status(code).JSON(fiber.Map{
 		"message": msg,
@@ -50,17 +70,73 @@ func domainErrorToHTTP(err error) (string, int) {
 		is(err, domain.ErrNoCategories),
 		is(err, domain.ErrProductNotFound),
 		is(err, domain.ErrAdminNotFound),
-		is(err, domain.ErrUserNotFound):
+		is(err, domain.ErrUserNotFound),
+		is(err, domain.ErrOrderNotFound),
+		is(err, domain.ErrCartLineNotFound),
+		is(err, domain.ErrMetaNotFound),
+		is(err, domain.ErrPromoCodeNotFound),
+		is(err, domain.ErrDeliveryZoneNotFound),
+		is(err, domain.ErrWorkerNotFound),
+		is(err, domain.ErrSessionNotFound),
+		is(err, domain.ErrUnknownRole):
 		return err.Error(), http.StatusNotFound
 
 	case is(err, domain.ErrProductAlreadyApproved),
-		is(err, domain.ErrProductAlreadyDisapproved):
+		is(err, domain.ErrProductAlreadyDisapproved),
+		is(err, domain.ErrPromoCodeNotActive),
+		is(err, domain.ErrPromoCodeMinCartAmount),
+		is(err, domain.ErrPromoCodeNotApplicable),
+		is(err, domain.ErrInvalidDeliveryZoneArea),
+		is(err, domain.ErrOutsideDeliveryZone),
+		is(err, domain.ErrDeliveryZoneMinOrder),
+		is(err, domain.ErrDeliveryTimeOutsideHours),
+		is(err, domain.ErrDeliverySlotPassed),
+		is(err, domain.ErrUnknownPermission),
+		is(err, domain.ErrInvalidCategoryOrder),
+		is(err, domain.ErrInvalidCombo),
+		is(err, cursor.ErrInvalidCursor),
+		is(err, input.ErrInvalidTimestamp):
 		return err.Error(), http.StatusBadRequest
 
 	case is(err, domain.ErrProductAlreadyExists),
-		is(err, domain.ErrAdminAlreadyExists):
+		is(err, domain.ErrCategoryAlreadyExists),
+		is(err, domain.ErrCategoryNotEmpty),
+		is(err, domain.ErrComboComponentNotApproved),
+		is(err, domain.ErrAdminAlreadyExists),
+		is(err, domain.ErrWorkerAlreadyExists),
+		is(err, domain.ErrInvalidStatusTransition),
+		is(err, domain.ErrOrderStatusChanged),
+		is(err, domain.ErrOrderNotInKitchen),
+		is(err, domain.ErrPromoCodeAlreadyExists),
+		is(err, domain.ErrPromoCodeUsageLimit),
+		is(err, domain.ErrHavePendingOrder),
+		is(err, domain.ErrStoreClosed),
+		is(err, domain.ErrDeliverySlotFull),
+		is(err, domain.ErrRolesManageRequired),
+		is(err, domain.ErrIdempotencyKeyInProgress):
 		return err.Error(), http.StatusConflict
 
+	case is(err, domain.ErrIdempotencyKeyReused):
+		return err.Error(), http.StatusUnprocessableEntity
+
+	case is(err, domain.ErrImageTooLarge):
+		return err.Error(), http.StatusRequestEntityTooLarge
+
+	case is(err, domain.ErrUnsupportedImage):
+		return err.Error(), http.StatusUnsupportedMediaType
+
+	case is(err, domain.ErrOTPNotFound),
+		is(err, domain.ErrInvalidOTP),
+		is(err, service.ErrInvalidPassword):
+		return err.Error(), http.StatusUnauthorized
+
+	case is(err, domain.ErrWorkerDisabled):
+		return err.Error(), http.StatusForbidden
+
+	case is(err, domain.ErrOTPAttemptsExceeded),
+		is(err, domain.ErrOTPResendTooSoon):
+		return err.Error(), http.StatusTooManyRequests
+
 	default:
 		return err.Error(), http.StatusInternalServerError
 	}
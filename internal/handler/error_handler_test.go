@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/sonyamoonglade/sancho-backend/pkg/cursor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainErrorToHTTP(t *testing.T) {
+	for _, err := range []error{
+		cursor.ErrInvalidCursor,
+		fmt.Errorf("createdFrom: %w", input.ErrInvalidTimestamp),
+		domain.ErrDeliverySlotPassed,
+	} {
+		msg, code := domainErrorToHTTP(err)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Equal(t, err.Error(), msg)
+	}
+}
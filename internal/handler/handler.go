@@ -20,9 +20,14 @@ func NewHandler(services *service.Services, middlewares *middleware.Middlewares)
 
 func (h Handler) InitAPI(router fiber.Router) {
 	m := h.middlewares
+	router.Get("/.well-known/jwks.json", h.GetJWKS)
 	api := router.Group("/api")
 	api.Use(m.XRequestID.Use())
+	h.initAuthAPI(api)
+	h.initSessionsAPI(api)
 	h.initProductAPI(api)
+	h.initDeliveryAPI(api)
+	h.initScheduleAPI(api)
 	h.initAdminsAPI(api)
 	h.initOrdersAPI(api)
 }
@@ -0,0 +1,48 @@
+package input
+
+import (
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+)
+
+type RequestOTPInput struct {
+	PhoneNumber string `json:"phoneNumber" validate:"required"`
+}
+
+type LoginCustomerInput struct {
+	PhoneNumber string `json:"phoneNumber" validate:"required"`
+	Code        string `json:"code" validate:"required"`
+	// Device is optional label of the session, e.g. "kitchen tablet"
+	Device string `json:"device" validate:"max=64"`
+}
+
+func (l LoginCustomerInput) ToDTO(client domain.SessionClient) dto.LoginCustomerDTO {
+	return dto.LoginCustomerDTO{
+		PhoneNumber: l.PhoneNumber,
+		Code:        l.Code,
+		Client:      client,
+	}
+}
+
+// LoginInput is used by both admins and workers
+type LoginInput struct {
+	Login    string `json:"login" validate:"required"`
+	Password string `json:"password" validate:"required"`
+	Device   string `json:"device" validate:"max=64"`
+}
+
+func (l LoginInput) ToAdminDTO(client domain.SessionClient) dto.LoginAdminDTO {
+	return dto.LoginAdminDTO{
+		Login:    l.Login,
+		Password: l.Password,
+		Client:   client,
+	}
+}
+
+func (l LoginInput) ToWorkerDTO(client domain.SessionClient) dto.LoginWorkerDTO {
+	return dto.LoginWorkerDTO{
+		Login:    l.Login,
+		Password: l.Password,
+		Client:   client,
+	}
+}
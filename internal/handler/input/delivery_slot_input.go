@@ -0,0 +1,6 @@
+package input
+
+// DeliverySlotsInput is parsed from query string. Date is YYYY-MM-DD in store's timezone.
+type DeliverySlotsInput struct {
+	Date string `query:"date" validate:"required"`
+}
@@ -0,0 +1,41 @@
+package input
+
+import (
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+)
+
+// DeliveryZoneInput is used both to create and to update a zone. Update replaces all fields.
+type DeliveryZoneInput struct {
+	Name string `json:"name" validate:"required"`
+	// Area is GeoJSON polygon, so it can be drawn in any GeoJSON editor
+	Area                  domain.GeoPolygon `json:"area"`
+	Fee                   int64             `json:"fee"`
+	FreeDeliveryThreshold int64             `json:"freeDeliveryThreshold"`
+	MinOrderAmount        int64             `json:"minOrderAmount"`
+	ETAMinutes            int64             `json:"etaMinutes" validate:"required"`
+}
+
+func (d DeliveryZoneInput) toTermsDTO() dto.DeliveryZoneTermsDTO {
+	return dto.DeliveryZoneTermsDTO{
+		Name:                  d.Name,
+		Area:                  d.Area,
+		Fee:                   d.Fee,
+		FreeDeliveryThreshold: d.FreeDeliveryThreshold,
+		MinOrderAmount:        d.MinOrderAmount,
+		ETAMinutes:            d.ETAMinutes,
+	}
+}
+
+func (d DeliveryZoneInput) ToCreateDTO() dto.CreateDeliveryZoneDTO {
+	return dto.CreateDeliveryZoneDTO{
+		DeliveryZoneTermsDTO: d.toTermsDTO(),
+	}
+}
+
+func (d DeliveryZoneInput) ToUpdateDTO(deliveryZoneID string) dto.UpdateDeliveryZoneDTO {
+	return dto.UpdateDeliveryZoneDTO{
+		DeliveryZoneID:       deliveryZoneID,
+		DeliveryZoneTermsDTO: d.toTermsDTO(),
+	}
+}
@@ -0,0 +1,14 @@
+package input
+
+const (
+	KitchenMessageItemReady  = "item_ready"
+	KitchenMessageOrderReady = "order_ready"
+)
+
+// KitchenMessageInput is sent by kitchen display when cook ticks off a cart line or a whole order
+type KitchenMessageInput struct {
+	Type    string `json:"type" validate:"required"`
+	OrderID string `json:"orderId" validate:"required"`
+	// Line is index of order's cart line. Required for item_ready
+	Line *int `json:"line"`
+}
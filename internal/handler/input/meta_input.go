@@ -0,0 +1,21 @@
+package input
+
+import (
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+)
+
+// UpdateScheduleInput replaces the whole schedule
+type UpdateScheduleInput struct {
+	Timezone  string                    `json:"timezone" validate:"required"`
+	Weekly    []domain.WorkingHours     `json:"weekly"`
+	Overrides []domain.ScheduleOverride `json:"overrides"`
+}
+
+func (u UpdateScheduleInput) ToDTO() dto.UpdateScheduleDTO {
+	return dto.UpdateScheduleDTO{
+		Timezone:  u.Timezone,
+		Weekly:    u.Weekly,
+		Overrides: u.Overrides,
+	}
+}
@@ -1,15 +1,24 @@
 package input
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	"github.com/sonyamoonglade/sancho-backend/pkg/cursor"
 )
 
+var ErrInvalidTimestamp = errors.New("timestamp should be in RFC3339 format")
+
 type CreateUserOrderInput struct {
 	Pay             domain.Pay                   `json:"pay" validate:"required"`
 	Cart            []CartProductInput           `json:"cart" validate:"required"`
 	IsDelivered     bool                         `json:"isDelivered"`
 	DeliveryAddress *domain.OrderDeliveryAddress `json:"deliveryAddress,omitempty"`
+	PromoCode       *string                      `json:"promoCode,omitempty"`
 }
 
 func (c CreateUserOrderInput) ToDTO(customerID string) dto.CreateUserOrderDTO {
@@ -18,6 +27,8 @@ func (c CreateUserOrderInput) ToDTO(customerID string) dto.CreateUserOrderDTO {
 		cart = append(cart, dto.CartProductDTO{
 			ProductID: cartProduct.ProductID,
 			Quantity:  cartProduct.Quantity,
+			OptionIDs: cartProduct.OptionIDs,
+			Swaps:     cartProduct.Swaps,
 		})
 	}
 	return dto.CreateUserOrderDTO{
@@ -26,6 +37,7 @@ func (c CreateUserOrderInput) ToDTO(customerID string) dto.CreateUserOrderDTO {
 		Cart:            cart,
 		IsDelivered:     c.IsDelivered,
 		DeliveryAddress: c.DeliveryAddress,
+		PromoCode:       c.PromoCode,
 	}
 }
 
@@ -45,6 +57,8 @@ func (c CreateWorkerOrderInput) ToDTO(customerID string) dto.CreateWorkerOrderDT
 		cart = append(cart, dto.CartProductDTO{
 			ProductID: cartProduct.ProductID,
 			Quantity:  cartProduct.Quantity,
+			OptionIDs: cartProduct.OptionIDs,
+			Swaps:     cartProduct.Swaps,
 		})
 	}
 	return dto.CreateWorkerOrderDTO{
@@ -62,4 +76,92 @@ func (c CreateWorkerOrderInput) ToDTO(customerID string) dto.CreateWorkerOrderDT
 type CartProductInput struct {
 	ProductID string `json:"productId" validate:"required"`
 	Quantity  int32  `json:"quantity" validate:"required"`
+	// OptionIDs are ids of options chosen for the product
+	OptionIDs []string `json:"options,omitempty"`
+	// Swaps of combo components, keyed by component id. Value is id of the product component is swapped for.
+	Swaps map[string]string `json:"swaps,omitempty"`
+}
+
+type CancelOrderInput struct {
+	Explanation string `json:"explanation" validate:"required"`
+}
+
+// CustomerOrdersInput is parsed from query string of customer's order history
+type CustomerOrdersInput struct {
+	Cursor string `query:"cursor"`
+	Limit  int64  `query:"limit"`
+}
+
+func (l CustomerOrdersInput) ToDTO(customerID string) (dto.ListOrdersDTO, error) {
+	out := dto.ListOrdersDTO{
+		CustomerID: &customerID,
+		Limit:      l.Limit,
+	}
+	if l.Cursor != "" {
+		c, err := cursor.Decode(l.Cursor)
+		if err != nil {
+			return dto.ListOrdersDTO{}, err
+		}
+		out.Cursor = &c
+	}
+	return out, nil
+}
+
+// ListOrdersInput is parsed from query string. Empty values are not applied as filters.
+// CreatedFrom and CreatedTo are RFC3339 timestamps.
+type ListOrdersInput struct {
+	Status       string `query:"status"`
+	Pay          string `query:"pay"`
+	IsDelivered  string `query:"isDelivered"`
+	CreatedFrom  string `query:"createdFrom"`
+	CreatedTo    string `query:"createdTo"`
+	PhoneNumber  string `query:"phoneNumber"`
+	NanoIDPrefix string `query:"nanoId"`
+	Cursor       string `query:"cursor"`
+	Limit        int64  `query:"limit"`
+}
+
+// ToDTO expects input to be validated with validation.ValidateListOrdersInput
+func (l ListOrdersInput) ToDTO() (dto.ListOrdersDTO, error) {
+	out := dto.ListOrdersDTO{
+		Limit: l.Limit,
+	}
+	if l.Status != "" {
+		out.Status = &domain.OrderStatus{V: l.Status}
+	}
+	if l.Pay != "" {
+		out.Pay = &domain.Pay{P: l.Pay}
+	}
+	if l.IsDelivered != "" {
+		isDelivered := l.IsDelivered == "true"
+		out.IsDelivered = &isDelivered
+	}
+	if l.CreatedFrom != "" {
+		createdFrom, err := time.Parse(time.RFC3339, l.CreatedFrom)
+		if err != nil {
+			return dto.ListOrdersDTO{}, fmt.Errorf("createdFrom: %w", ErrInvalidTimestamp)
+		}
+		createdFrom = createdFrom.UTC()
+		out.CreatedFrom = &createdFrom
+	}
+	if l.CreatedTo != "" {
+		createdTo, err := time.Parse(time.RFC3339, l.CreatedTo)
+		if err != nil {
+			return dto.ListOrdersDTO{}, fmt.Errorf("createdTo: %w", ErrInvalidTimestamp)
+		}
+		createdTo = createdTo.UTC()
+		out.CreatedTo = &createdTo
+	}
+	if l.NanoIDPrefix != "" {
+		prefix := strings.ToUpper(l.NanoIDPrefix)
+		out.NanoIDPrefix = &prefix
+	}
+	if l.Cursor != "" {
+		c, err := cursor.Decode(l.Cursor)
+		if err != nil {
+			return dto.ListOrdersDTO{}, err
+		}
+		out.Cursor = &c
+	}
+	return out, nil
 }
@@ -0,0 +1,17 @@
+package input
+
+import (
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+)
+
+type UpdateRolePermissionsInput struct {
+	Permissions []domain.Permission `json:"permissions" validate:"required"`
+}
+
+func (u UpdateRolePermissionsInput) ToDTO(role domain.Role) dto.UpdateRolePermissionsDTO {
+	return dto.UpdateRolePermissionsDTO{
+		Role:        role,
+		Permissions: u.Permissions,
+	}
+}
@@ -1,8 +1,11 @@
 package input
 
 import (
+	"strings"
+
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type CreateProductInput struct {
@@ -12,6 +15,10 @@ type CreateProductInput struct {
 	CategoryName string          `json:"categoryName" validate:"required"`
 	Price        int64           `json:"price" validate:"required"`
 	Features     domain.Features `json:"features" validate:"required"`
+	// OptionGroups are optional, product without them is ordered as is
+	OptionGroups []OptionGroupInput `json:"optionGroups,omitempty"`
+	// Combo makes product a set of other products sold for its price
+	Combo *ComboInput `json:"combo,omitempty"`
 }
 
 func (c CreateProductInput) ToDTO() dto.CreateProductDTO {
@@ -22,6 +29,112 @@ func (c CreateProductInput) ToDTO() dto.CreateProductDTO {
 		CategoryName: c.CategoryName,
 		Price:        c.Price,
 		Features:     c.Features,
+		OptionGroups: optionGroupsToDomain(c.OptionGroups),
+		Combo:        c.Combo.ToDomain(),
+	}
+}
+
+// OptionGroupInput keeps ids of existing groups and options, so that they stay the same for carts
+// when product options are edited. Groups and options without id are new.
+type OptionGroupInput struct {
+	GroupID    string `json:"groupId,omitempty"`
+	Name       string `json:"name"`
+	Multiple   bool   `json:"multiple"`
+	MinChoices int32  `json:"minChoices"`
+	// MaxChoices of single select group defaults to 1
+	MaxChoices int32         `json:"maxChoices"`
+	Options    []OptionInput `json:"options"`
+}
+
+type OptionInput struct {
+	OptionID   string `json:"optionId,omitempty"`
+	Name       string `json:"name"`
+	PriceDelta int64  `json:"priceDelta"`
+}
+
+func (o OptionGroupInput) ToDomain() domain.OptionGroup {
+	// Invalid ids are rejected by validation, empty ones stay zero and are assigned by service
+	groupID, _ := primitive.ObjectIDFromHex(o.GroupID)
+	group := domain.OptionGroup{
+		GroupID:    groupID,
+		Name:       strings.TrimSpace(o.Name),
+		Multiple:   o.Multiple,
+		MinChoices: o.MinChoices,
+		MaxChoices: o.MaxChoices,
+		Options:    make([]domain.Option, 0, len(o.Options)),
+	}
+	if !group.Multiple && group.MaxChoices == 0 {
+		group.MaxChoices = 1
+	}
+	for _, option := range o.Options {
+		optionID, _ := primitive.ObjectIDFromHex(option.OptionID)
+		group.Options = append(group.Options, domain.Option{
+			OptionID:   optionID,
+			Name:       strings.TrimSpace(option.Name),
+			PriceDelta: option.PriceDelta,
+		})
+	}
+	return group
+}
+
+func optionGroupsToDomain(groups []OptionGroupInput) []domain.OptionGroup {
+	optionGroups := make([]domain.OptionGroup, 0, len(groups))
+	for _, group := range groups {
+		optionGroups = append(optionGroups, group.ToDomain())
+	}
+	return optionGroups
+}
+
+type ComboInput struct {
+	Components []ComboComponentInput `json:"components"`
+}
+
+type ComboComponentInput struct {
+	ProductID string           `json:"productId"`
+	Quantity  int32            `json:"quantity"`
+	Swaps     []ComboSwapInput `json:"swaps,omitempty"`
+}
+
+type ComboSwapInput struct {
+	ProductID  string `json:"productId"`
+	PriceDelta int64  `json:"priceDelta"`
+}
+
+// ToDomain returns nil for product that is not a combo. Ids of components are assigned by service.
+func (c *ComboInput) ToDomain() *domain.Combo {
+	if c == nil {
+		return nil
+	}
+	combo := &domain.Combo{
+		Components: make([]domain.ComboComponent, 0, len(c.Components)),
+	}
+	for _, component := range c.Components {
+		productID, _ := primitive.ObjectIDFromHex(component.ProductID)
+		comboComponent := domain.ComboComponent{
+			ProductID: productID,
+			Quantity:  component.Quantity,
+		}
+		for _, swap := range component.Swaps {
+			swapProductID, _ := primitive.ObjectIDFromHex(swap.ProductID)
+			comboComponent.Swaps = append(comboComponent.Swaps, domain.ComboSwap{
+				ProductID:  swapProductID,
+				PriceDelta: swap.PriceDelta,
+			})
+		}
+		combo.Components = append(combo.Components, comboComponent)
+	}
+	return combo
+}
+
+// UpdateOptionGroupsInput replaces every option group of product, empty list removes them
+type UpdateOptionGroupsInput struct {
+	OptionGroups []OptionGroupInput `json:"optionGroups"`
+}
+
+func (u UpdateOptionGroupsInput) ToDTO(productID string) dto.UpdateOptionGroupsDTO {
+	return dto.UpdateOptionGroupsDTO{
+		ProductID:    productID,
+		OptionGroups: optionGroupsToDomain(u.OptionGroups),
 	}
 }
 
@@ -29,7 +142,6 @@ type UpdateProductInput struct {
 	Name        *string `json:"name"`
 	TranslateRU *string `json:"translateRu"`
 	Description *string `json:"description"`
-	ImageURL    *string `json:"imageUrl"`
 	Price       *int64  `json:"price"`
 }
 
@@ -39,7 +151,22 @@ func (u UpdateProductInput) ToDTO(productID string) dto.UpdateProductDTO {
 		Name:        u.Name,
 		TranslateRU: u.TranslateRU,
 		Description: u.Description,
-		ImageURL:    u.ImageURL,
 		Price:       u.Price,
 	}
 }
+
+type CategoryInput struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func (c CategoryInput) ToDTO(categoryID string) dto.RenameCategoryDTO {
+	return dto.RenameCategoryDTO{
+		CategoryID: categoryID,
+		Name:       strings.TrimSpace(c.Name),
+	}
+}
+
+type ReorderCategoriesInput struct {
+	// CategoryIDs lists every category, first goes first
+	CategoryIDs []string `json:"categoryIds" validate:"required"`
+}
@@ -0,0 +1,71 @@
+package input
+
+import (
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PromoCodeTermsInput struct {
+	Kind             domain.DiscountKind `json:"kind" validate:"required"`
+	Percent          float64             `json:"percent,omitempty"`
+	Amount           int64               `json:"amount,omitempty"`
+	MinCartAmount    int64               `json:"minCartAmount"`
+	ValidFrom        *time.Time          `json:"validFrom,omitempty"`
+	ValidTo          *time.Time          `json:"validTo,omitempty"`
+	UsageLimit       int64               `json:"usageLimit"`
+	PerCustomerLimit int64               `json:"perCustomerLimit"`
+	CategoryIDs      []string            `json:"categoryIds"`
+	ProductIDs       []string            `json:"productIds"`
+}
+
+// ToDTO expects input to be validated with validation.ValidatePromoCodeTermsInput
+func (p PromoCodeTermsInput) ToDTO() dto.PromoCodeTermsDTO {
+	out := dto.PromoCodeTermsDTO{
+		Kind:             p.Kind,
+		Percent:          p.Percent,
+		Amount:           p.Amount,
+		MinCartAmount:    p.MinCartAmount,
+		ValidFrom:        p.ValidFrom,
+		ValidTo:          p.ValidTo,
+		UsageLimit:       p.UsageLimit,
+		PerCustomerLimit: p.PerCustomerLimit,
+		CategoryIDs:      make([]primitive.ObjectID, 0, len(p.CategoryIDs)),
+		ProductIDs:       make([]primitive.ObjectID, 0, len(p.ProductIDs)),
+	}
+	for _, id := range p.CategoryIDs {
+		categoryID, _ := primitive.ObjectIDFromHex(id)
+		out.CategoryIDs = append(out.CategoryIDs, categoryID)
+	}
+	for _, id := range p.ProductIDs {
+		productID, _ := primitive.ObjectIDFromHex(id)
+		out.ProductIDs = append(out.ProductIDs, productID)
+	}
+	return out
+}
+
+type CreatePromoCodeInput struct {
+	Code string `json:"code" validate:"required"`
+	PromoCodeTermsInput
+}
+
+func (c CreatePromoCodeInput) ToDTO() dto.CreatePromoCodeDTO {
+	return dto.CreatePromoCodeDTO{
+		Code:              c.Code,
+		PromoCodeTermsDTO: c.PromoCodeTermsInput.ToDTO(),
+	}
+}
+
+// UpdatePromoCodeInput replaces all terms of promo code. Code itself can't be changed.
+type UpdatePromoCodeInput struct {
+	PromoCodeTermsInput
+}
+
+func (u UpdatePromoCodeInput) ToDTO(promoCodeID string) dto.UpdatePromoCodeDTO {
+	return dto.UpdatePromoCodeDTO{
+		PromoCodeID:       promoCodeID,
+		PromoCodeTermsDTO: u.PromoCodeTermsInput.ToDTO(),
+	}
+}
@@ -0,0 +1,21 @@
+package input
+
+import "github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+
+type CreateWorkerInput struct {
+	Name     string `json:"name" validate:"required"`
+	Login    string `json:"login" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+func (c CreateWorkerInput) ToDTO() dto.RegisterWorkerDTO {
+	return dto.RegisterWorkerDTO{
+		Name:     c.Name,
+		Login:    c.Login,
+		Password: c.Password,
+	}
+}
+
+type ResetWorkerPasswordInput struct {
+	Password string `json:"password" validate:"required"`
+}
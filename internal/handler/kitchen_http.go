@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/middleware"
+	service "github.com/sonyamoonglade/sancho-backend/internal/services"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	"github.com/sonyamoonglade/sancho-backend/internal/validation"
+	"github.com/sonyamoonglade/sancho-backend/pkg/cursor"
+	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	kitchenMessageSnapshot = "snapshot"
+	kitchenMessageOrder    = "order"
+	kitchenMessageError    = "error"
+
+	kitchenPingInterval   = time.Second * 30
+	kitchenWriteTimeout   = time.Second * 10
+	kitchenRequestTimeout = time.Second * 10
+)
+
+// kitchenMessage is sent to kitchen display. Snapshot carries all verified orders and is sent
+// once connection is established, then every created or updated order is sent on its own.
+type kitchenMessage struct {
+	Type    string         `json:"type"`
+	Orders  []domain.Order `json:"orders,omitempty"`
+	Order   *domain.Order  `json:"order,omitempty"`
+	Message string         `json:"message,omitempty"`
+}
+
+func RequireWebSocketUpgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+	return c.Next()
+}
+
+// KitchenFeed serves kitchen display. It pushes verified orders and accepts
+// input.KitchenMessageInput to tick off prepared cart lines. Connection is closed once token is revoked.
+func (h Handler) KitchenFeed(conn *websocket.Conn) {
+	claims, err := middleware.GetClaimsFromConn(conn)
+	if err != nil {
+		h.writeKitchenMessage(conn, kitchenErrorMessage(err))
+		return
+	}
+
+	// Subscribe before taking snapshot so that no update is lost in between
+	sub, _ := h.services.OrderEvents.SubscribeWorkers(0)
+	defer sub.Close()
+
+	orders, err := h.getKitchenOrders()
+	if err != nil {
+		h.writeKitchenMessage(conn, kitchenErrorMessage(err))
+		return
+	}
+	if err := h.writeKitchenMessage(conn, kitchenMessage{Type: kitchenMessageSnapshot, Orders: orders}); err != nil {
+		return
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	incoming := make(chan []byte)
+	go func() {
+		defer close(incoming)
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			select {
+			case incoming <- msg:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(kitchenPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case msg, ok := <-incoming:
+			// Client has gone
+			if !ok {
+				return
+			}
+			// Token is checked on connect only, so fired or logged out worker must not keep using it
+			if h.services.Revocation.IsRevoked(claims) {
+				h.closeRevokedKitchenConn(conn)
+				return
+			}
+			if resp, reply := h.handleKitchenMessage(claims.Role, msg); reply {
+				if err := h.writeKitchenMessage(conn, resp); err != nil {
+					return
+				}
+			}
+		case event, ok := <-sub.Events():
+			// Subscription is dropped because client is too slow. It will reconnect and get fresh snapshot.
+			if !ok {
+				return
+			}
+			order, send, err := h.getKitchenOrder(event.Data)
+			if err != nil {
+				logger.Get().Error("kitchen feed", zap.Error(err))
+				continue
+			}
+			if !send {
+				continue
+			}
+			if err := h.writeKitchenMessage(conn, kitchenMessage{Type: kitchenMessageOrder, Order: &order}); err != nil {
+				return
+			}
+		case <-ping.C:
+			// Pings keep connection alive, so revocation is checked as often
+			if h.services.Revocation.IsRevoked(claims) {
+				h.closeRevokedKitchenConn(conn)
+				return
+			}
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(kitchenWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleKitchenMessage returns error message and true if client should be replied
+func (h Handler) handleKitchenMessage(role domain.Role, msg []byte) (kitchenMessage, bool) {
+	var inp input.KitchenMessageInput
+	if err := json.Unmarshal(msg, &inp); err != nil {
+		return kitchenMessage{Type: kitchenMessageError, Message: "invalid message"}, true
+	}
+	if ok, msg := validation.ValidateKitchenMessageInput(inp); !ok {
+		return kitchenMessage{Type: kitchenMessageError, Message: msg}, true
+	}
+	// Feed is open to anyone viewing orders, but ready cart completes the order
+	if !h.services.Permission.Has(role, domain.PermissionOrdersComplete) {
+		return kitchenMessage{Type: kitchenMessageError, Message: middleware.ResponseAccessDenied}, true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kitchenRequestTimeout)
+	defer cancel()
+
+	var err error
+	switch inp.Type {
+	case input.KitchenMessageItemReady:
+		err = h.services.Order.MarkCartProductReady(ctx, inp.OrderID, *inp.Line)
+	case input.KitchenMessageOrderReady:
+		err = h.services.Order.MarkOrderReady(ctx, inp.OrderID)
+	}
+	if err != nil {
+		return kitchenErrorMessage(err), true
+	}
+	// Updated order is delivered through the event
+	return kitchenMessage{}, false
+}
+
+func (h Handler) getKitchenOrders() ([]domain.Order, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kitchenRequestTimeout)
+	defer cancel()
+
+	var (
+		status  = domain.StatusVerified
+		orders  []domain.Order
+		listDTO = dto.ListOrdersDTO{
+			Status: &status,
+			Limit:  service.MaxOrdersPageLimit,
+		}
+	)
+	for {
+		page, err := h.services.Order.ListOrders(ctx, listDTO)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, page.Orders...)
+		if page.NextCursor == nil {
+			return orders, nil
+		}
+		c, err := cursor.Decode(*page.NextCursor)
+		if err != nil {
+			return nil, err
+		}
+		listDTO.Cursor = &c
+	}
+}
+
+// getKitchenOrder returns order of the event and false if kitchen is not interested in it
+func (h Handler) getKitchenOrder(data []byte) (domain.Order, bool, error) {
+	var event domain.OrderEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return domain.Order{}, false, err
+	}
+	// Orders waiting for verification are not cooked yet
+	if event.Status == domain.StatusWaitingForVerification {
+		return domain.Order{}, false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kitchenRequestTimeout)
+	defer cancel()
+
+	order, err := h.services.Order.GetOrderByID(ctx, event.OrderID)
+	if err != nil {
+		return domain.Order{}, false, err
+	}
+	return order, true, nil
+}
+
+func (h Handler) writeKitchenMessage(conn *websocket.Conn, msg kitchenMessage) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(kitchenWriteTimeout)); err != nil {
+		return err
+	}
+	return conn.WriteJSON(msg)
+}
+
+// closeRevokedKitchenConn tells kitchen display why it's disconnected, so that it asks to log in again
+func (h Handler) closeRevokedKitchenConn(conn *websocket.Conn) {
+	_ = h.writeKitchenMessage(conn, kitchenMessage{Type: kitchenMessageError, Message: middleware.ResponseTokenRevoked})
+	closeMessage := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, middleware.ResponseTokenRevoked)
+	_ = conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(kitchenWriteTimeout))
+}
+
+func kitchenErrorMessage(err error) kitchenMessage {
+	msg, code := domainErrorToHTTP(err)
+	if code == http.StatusInternalServerError {
+		logger.Get().Error("kitchen feed", zap.Error(err))
+		msg = "internal error"
+	}
+	return kitchenMessage{Type: kitchenMessageError, Message: msg}
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/middleware"
+	service "github.com/sonyamoonglade/sancho-backend/internal/services"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	mock_service "github.com/sonyamoonglade/sancho-backend/internal/services/mocks"
+	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
+	"github.com/sonyamoonglade/sancho-backend/pkg/eventbus"
+	"github.com/stretchr/testify/require"
+)
+
+type kitchenFeedMocks struct {
+	order      *mock_service.MockOrder
+	revocation *mock_service.MockRevocation
+}
+
+// serveKitchenFeed runs kitchen feed behind auth middleware on a local listener
+// and returns token of a worker and URL to dial
+func serveKitchenFeed(t *testing.T) (string, string, kitchenFeedMocks) {
+	ctrl := gomock.NewController(t)
+	mocks := kitchenFeedMocks{
+		order:      mock_service.NewMockOrder(ctrl),
+		revocation: mock_service.NewMockRevocation(ctrl),
+	}
+	permissionService := mock_service.NewMockPermission(ctrl)
+	permissionService.EXPECT().Has(domain.RoleWorker, gomock.Any()).Return(true).AnyTimes()
+	mocks.order.EXPECT().ListOrders(gomock.Any(), gomock.Any()).Return(dto.OrdersPageDTO{}, nil).AnyTimes()
+
+	tokenProvider, err := auth.NewProvider(time.Minute, []byte("abcd"), "http://localhost")
+	require.NoError(t, err)
+	tokens, err := tokenProvider.GenerateNewPair(auth.UserAuth{UserID: uuid.NewString(), Role: domain.RoleWorker})
+	require.NoError(t, err)
+
+	h := Handler{services: &service.Services{
+		Order:       mocks.order,
+		OrderEvents: service.NewOrderEventsService(eventbus.New(10)),
+		Permission:  permissionService,
+		Revocation:  mocks.revocation,
+	}}
+	jwtAuth := middleware.NewJWTAuthMiddleware(mock_service.NewMockAuth(ctrl), mocks.revocation, permissionService, tokenProvider)
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/kitchen", jwtAuth.Require(domain.PermissionOrdersView), RequireWebSocketUpgrade, websocket.New(h.KitchenFeed, websocket.Config{
+		Subprotocols: []string{middleware.WebSocketTokenProtocol},
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln) //nolint:errcheck
+	t.Cleanup(func() {
+		_ = app.Shutdown()
+	})
+	return tokens.AccessToken, "ws://" + ln.Addr().String() + "/kitchen", mocks
+}
+
+func TestKitchenFeed(t *testing.T) {
+	t.Run("should close connection once token is revoked", func(t *testing.T) {
+		token, url, mocks := serveKitchenFeed(t)
+		// Token is valid on connect and gets revoked afterwards
+		mocks.revocation.EXPECT().IsRevoked(gomock.Any()).Return(false).Times(1)
+		mocks.revocation.EXPECT().IsRevoked(gomock.Any()).Return(true)
+		mocks.order.EXPECT().MarkOrderReady(gomock.Any(), gomock.Any()).Times(0)
+
+		conn, _, err := fastws.DefaultDialer.Dial(url, http.Header{middleware.HeaderAuthorization: {"Bearer " + token}})
+		require.NoError(t, err)
+		defer conn.Close()
+
+		var msg kitchenMessage
+		require.NoError(t, conn.ReadJSON(&msg))
+		require.Equal(t, kitchenMessageSnapshot, msg.Type)
+
+		require.NoError(t, conn.WriteMessage(fastws.TextMessage, []byte(`{"type":"order_ready","orderId":"63e0e1f0a7b1c2d3e4f5a6b7"}`)))
+		require.NoError(t, conn.ReadJSON(&msg))
+		require.Equal(t, kitchenMessage{Type: kitchenMessageError, Message: middleware.ResponseTokenRevoked}, msg)
+
+		_, _, err = conn.ReadMessage()
+		require.True(t, fastws.IsCloseError(err, fastws.ClosePolicyViolation), err)
+	})
+
+	t.Run("should connect with access token passed as subprotocol", func(t *testing.T) {
+		token, url, mocks := serveKitchenFeed(t)
+		mocks.revocation.EXPECT().IsRevoked(gomock.Any()).Return(false).AnyTimes()
+
+		// Browser WebSocket API can only set protocols
+		dialer := *fastws.DefaultDialer
+		dialer.Subprotocols = []string{middleware.WebSocketTokenProtocol, token}
+		conn, _, err := dialer.Dial(url, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		require.Equal(t, middleware.WebSocketTokenProtocol, conn.Subprotocol())
+
+		var msg kitchenMessage
+		require.NoError(t, conn.ReadJSON(&msg))
+		require.Equal(t, kitchenMessageSnapshot, msg.Type)
+	})
+
+	t.Run("should not connect without access token", func(t *testing.T) {
+		_, url, _ := serveKitchenFeed(t)
+
+		dialer := *fastws.DefaultDialer
+		dialer.Subprotocols = []string{middleware.WebSocketTokenProtocol}
+		_, res, err := dialer.Dial(url, nil)
+		require.ErrorIs(t, err, fastws.ErrBadHandshake)
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+}
+
+func TestHandleKitchenMessage(t *testing.T) {
+	const orderID = "63e0e1f0a7b1c2d3e4f5a6b7"
+
+	getHandler := func(t *testing.T, canComplete bool) (Handler, *mock_service.MockOrder) {
+		ctrl := gomock.NewController(t)
+		orderService := mock_service.NewMockOrder(ctrl)
+		permissionService := mock_service.NewMockPermission(ctrl)
+		permissionService.
+			EXPECT().
+			Has(domain.RoleWorker, domain.PermissionOrdersComplete).
+			Return(canComplete).
+			AnyTimes()
+		return Handler{services: &service.Services{Order: orderService, Permission: permissionService}}, orderService
+	}
+
+	t.Run("should mark item ready without replying", func(t *testing.T) {
+		h, orderService := getHandler(t, true)
+		orderService.EXPECT().MarkCartProductReady(gomock.Any(), orderID, 1).Return(nil)
+
+		resp, reply := h.handleKitchenMessage(domain.RoleWorker, []byte(`{"type":"item_ready","orderId":"`+orderID+`","line":1}`))
+		require.False(t, reply)
+		require.Zero(t, resp)
+	})
+
+	t.Run("should mark order ready without replying", func(t *testing.T) {
+		h, orderService := getHandler(t, true)
+		orderService.EXPECT().MarkOrderReady(gomock.Any(), orderID).Return(nil)
+
+		_, reply := h.handleKitchenMessage(domain.RoleWorker, []byte(`{"type":"order_ready","orderId":"`+orderID+`"}`))
+		require.False(t, reply)
+	})
+
+	t.Run("should reply with error because line is not in cart", func(t *testing.T) {
+		h, orderService := getHandler(t, true)
+		orderService.EXPECT().MarkCartProductReady(gomock.Any(), orderID, 5).Return(domain.ErrCartLineNotFound)
+
+		resp, reply := h.handleKitchenMessage(domain.RoleWorker, []byte(`{"type":"item_ready","orderId":"`+orderID+`","line":5}`))
+		require.True(t, reply)
+		require.Equal(t, kitchenMessage{Type: kitchenMessageError, Message: domain.ErrCartLineNotFound.Error()}, resp)
+	})
+
+	t.Run("should reply with error because line is negative", func(t *testing.T) {
+		h, orderService := getHandler(t, true)
+		orderService.EXPECT().MarkCartProductReady(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		resp, reply := h.handleKitchenMessage(domain.RoleWorker, []byte(`{"type":"item_ready","orderId":"`+orderID+`","line":-1}`))
+		require.True(t, reply)
+		require.Equal(t, kitchenMessageError, resp.Type)
+	})
+
+	t.Run("should reply with error because order is not verified", func(t *testing.T) {
+		h, orderService := getHandler(t, true)
+		orderService.EXPECT().MarkOrderReady(gomock.Any(), orderID).Return(domain.ErrOrderNotInKitchen)
+
+		resp, reply := h.handleKitchenMessage(domain.RoleWorker, []byte(`{"type":"order_ready","orderId":"`+orderID+`"}`))
+		require.True(t, reply)
+		require.Equal(t, kitchenMessage{Type: kitchenMessageError, Message: domain.ErrOrderNotInKitchen.Error()}, resp)
+	})
+
+	t.Run("should reply with error because role can't complete orders", func(t *testing.T) {
+		h, orderService := getHandler(t, false)
+		orderService.EXPECT().MarkOrderReady(gomock.Any(), gomock.Any()).Times(0)
+
+		resp, reply := h.handleKitchenMessage(domain.RoleWorker, []byte(`{"type":"order_ready","orderId":"`+orderID+`"}`))
+		require.True(t, reply)
+		require.Equal(t, kitchenMessage{Type: kitchenMessageError, Message: middleware.ResponseAccessDenied}, resp)
+	})
+
+	t.Run("should reply with error because message is not json", func(t *testing.T) {
+		h, _ := getHandler(t, true)
+
+		resp, reply := h.handleKitchenMessage(domain.RoleWorker, []byte(`ready`))
+		require.True(t, reply)
+		require.Equal(t, kitchenMessageError, resp.Type)
+	})
+}
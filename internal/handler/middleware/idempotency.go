@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	service "github.com/sonyamoonglade/sancho-backend/internal/services"
+	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set on responses replayed from storage
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+
+	ResponseInvalidIdempotencyKey = "idempotency key should be at most 255 characters"
+)
+
+// IdempotencyMiddleware replays stored response to retries of the request made with Idempotency-Key header,
+// so that retry does not create the same order twice. Keys are scoped to route and user, so it must go after JWTAuthMiddleware.
+// It should go before RateLimitMiddleware, so that replayed retries are not counted.
+type IdempotencyMiddleware struct {
+	idempotencyService service.Idempotency
+}
+
+func NewIdempotencyMiddleware(idempotencyService service.Idempotency) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		idempotencyService: idempotencyService,
+	}
+}
+
+func (m IdempotencyMiddleware) Use() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		idempotencyKey := c.Get(HeaderIdempotencyKey)
+		// Header is optional
+		if idempotencyKey == "" {
+			return c.Next()
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			return c.Status(http.StatusBadRequest).SendString(ResponseInvalidIdempotencyKey)
+		}
+		userID, err := GetUserIDFromCtx(c)
+		if err != nil {
+			return err
+		}
+
+		key := strings.Join([]string{c.Path(), userID, idempotencyKey}, ":")
+		stored, err := m.idempotencyService.Begin(c.Context(), key, requestHash(c))
+		if err != nil {
+			return err
+		}
+		if stored != nil {
+			c.Set(HeaderIdempotentReplayed, "true")
+			c.Set(fiber.HeaderContentType, stored.ContentType)
+			return c.Status(stored.StatusCode).Send(stored.Body)
+		}
+
+		// Error is handled here, so that response it's turned into is stored as well
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				m.release(c, key)
+				return err
+			}
+		}
+
+		// Only successful responses are stored. Retry of failed request is handled again,
+		// so that client can fix the request or wait for the server to recover.
+		if status := c.Response().StatusCode(); status < http.StatusOK || status >= http.StatusMultipleChoices {
+			m.release(c, key)
+			return nil
+		}
+		err = m.idempotencyService.Complete(c.Context(), key, domain.IdempotentResponse{
+			StatusCode:  c.Response().StatusCode(),
+			ContentType: string(c.Response().Header.ContentType()),
+			// Body is reused by fasthttp once response is sent
+			Body: append([]byte(nil), c.Response().Body()...),
+		})
+		if err != nil {
+			// Request has been handled, so client still gets the response
+			logger.Get().Error("store idempotent response",
+				zap.String("key", key),
+				zap.Error(err),
+			)
+		}
+		return nil
+	}
+}
+
+func (m IdempotencyMiddleware) release(c *fiber.Ctx, key string) {
+	if err := m.idempotencyService.Release(c.Context(), key); err != nil {
+		logger.Get().Error("release idempotency key",
+			zap.String("key", key),
+			zap.Error(err),
+		)
+	}
+}
+
+// requestHash tells retry of the request from another request made with the same key
+func requestHash(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method() + " " + c.Path() + "\n"))
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
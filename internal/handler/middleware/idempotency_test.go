@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	mock_service "github.com/sonyamoonglade/sancho-backend/internal/services/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	const (
+		userID  = "user-1"
+		created = `{"orderId":"1"}`
+	)
+
+	newApp := func(t *testing.T, handler fiber.Handler) (*fiber.App, *mock_service.MockIdempotency, *int) {
+		ctrl := gomock.NewController(t)
+		idempotencyService := mock_service.NewMockIdempotency(ctrl)
+		m := NewIdempotencyMiddleware(idempotencyService)
+
+		var handled int
+		app := fiber.New()
+		app.Post("/order/create", func(c *fiber.Ctx) error {
+			c.Locals(userIDCtx, userID)
+			return c.Next()
+		}, m.Use(), func(c *fiber.Ctx) error {
+			handled++
+			return handler(c)
+		})
+		return app, idempotencyService, &handled
+	}
+	createOrder := func(c *fiber.Ctx) error {
+		return c.Status(http.StatusCreated).JSON(fiber.Map{"orderId": "1"})
+	}
+	send := func(app *fiber.App, idempotencyKey string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, baseURL+"/order/create", strings.NewReader(`{"cart":[]}`))
+		req.Header.Set("Content-Type", "application/json")
+		if idempotencyKey != "" {
+			req.Header.Set(HeaderIdempotencyKey, idempotencyKey)
+		}
+		res, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("should handle request without key as usual", func(t *testing.T) {
+		app, _, handled := newApp(t, createOrder)
+
+		res := send(app, "")
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		require.Equal(t, 1, *handled)
+	})
+
+	t.Run("should store response of handled request", func(t *testing.T) {
+		app, idempotencyService, handled := newApp(t, createOrder)
+		key := "/order/create:" + userID + ":key"
+
+		idempotencyService.EXPECT().Begin(gomock.Any(), key, gomock.Any()).Return(nil, nil)
+		idempotencyService.EXPECT().Complete(gomock.Any(), key, domain.IdempotentResponse{
+			StatusCode:  http.StatusCreated,
+			ContentType: fiber.MIMEApplicationJSON,
+			Body:        []byte(created),
+		}).Return(nil)
+
+		res := send(app, "key")
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		require.Equal(t, created, string(readBody(res.Body)))
+		require.Equal(t, 1, *handled)
+	})
+
+	t.Run("should replay stored response", func(t *testing.T) {
+		app, idempotencyService, handled := newApp(t, createOrder)
+
+		idempotencyService.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Return(&domain.IdempotentResponse{
+			StatusCode:  http.StatusCreated,
+			ContentType: fiber.MIMEApplicationJSON,
+			Body:        []byte(created),
+		}, nil)
+
+		res := send(app, "key")
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		require.Equal(t, "true", res.Header.Get(HeaderIdempotentReplayed))
+		require.Equal(t, fiber.MIMEApplicationJSON, res.Header.Get(fiber.HeaderContentType))
+		require.Equal(t, created, string(readBody(res.Body)))
+		require.Zero(t, *handled)
+	})
+
+	t.Run("should release key because request has failed", func(t *testing.T) {
+		app, idempotencyService, _ := newApp(t, func(c *fiber.Ctx) error {
+			return errors.New("mongo is down")
+		})
+
+		idempotencyService.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+		idempotencyService.EXPECT().Release(gomock.Any(), gomock.Any()).Return(nil)
+
+		res := send(app, "key")
+		require.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+
+	t.Run("should release key because request is invalid", func(t *testing.T) {
+		app, idempotencyService, _ := newApp(t, func(c *fiber.Ctx) error {
+			return c.Status(http.StatusBadRequest).SendString("cart is empty")
+		})
+
+		idempotencyService.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+		idempotencyService.EXPECT().Complete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		idempotencyService.EXPECT().Release(gomock.Any(), gomock.Any()).Return(nil)
+
+		res := send(app, "key")
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("should return 400 because key is too long", func(t *testing.T) {
+		app, _, handled := newApp(t, createOrder)
+
+		res := send(app, strings.Repeat("k", maxIdempotencyKeyLength+1))
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.Zero(t, *handled)
+	})
+}
@@ -6,58 +6,72 @@ import (
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	service "github.com/sonyamoonglade/sancho-backend/internal/services"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
 	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
 )
 
 const (
 	HeaderAuthorization = "Authorization"
+	// WebSocketTokenProtocol is offered along with access token as Sec-WebSocket-Protocol values,
+	// because browsers can't set Authorization header of WebSocket. Server selects it back.
+	WebSocketTokenProtocol = "access_token"
 
 	ResponseUnauthorized = "unauthorized"
 	ResponseTokenExpired = "token has expired"
+	ResponseTokenRevoked = "token has been revoked"
 	ResponseAccessDenied = "access denied"
 )
 
 // JWTAuthMiddleware validates incoming Bearer access token,
-// does RBAC, checking incoming user role against required
-// and enriches request's context with userID of the token owner.
+// checks that role of the user has required permissions,
+// rejects revoked tokens and enriches request's context with claims of the token owner.
 type JWTAuthMiddleware struct {
-	tokenProvider auth.TokenProvider
-	authService   service.Auth
+	tokenProvider     auth.TokenProvider
+	authService       service.Auth
+	revocationService service.Revocation
+	permissionService service.Permission
 }
 
-func NewJWTAuthMiddleware(authService service.Auth, tokenProvider auth.TokenProvider) *JWTAuthMiddleware {
+func NewJWTAuthMiddleware(authService service.Auth,
+	revocationService service.Revocation,
+	permissionService service.Permission,
+	tokenProvider auth.TokenProvider) *JWTAuthMiddleware {
 	return &JWTAuthMiddleware{
-		authService:   authService,
-		tokenProvider: tokenProvider,
+		authService:       authService,
+		revocationService: revocationService,
+		permissionService: permissionService,
+		tokenProvider:     tokenProvider,
 	}
 }
 
-func (m JWTAuthMiddleware) Use(requiredRole domain.Role) fiber.Handler {
+// Authenticate lets through user of any role with valid access token
+func (m JWTAuthMiddleware) Authenticate() fiber.Handler {
+	return m.use(nil)
+}
+
+// Require lets through user whose role has every one of permissions
+func (m JWTAuthMiddleware) Require(permissions ...domain.Permission) fiber.Handler {
+	return m.use(permissions)
+}
+
+func (m JWTAuthMiddleware) use(permissions []domain.Permission) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		var (
-			headers    = c.GetReqHeaders()
-			authHeader = headers[HeaderAuthorization]
-			token      string
-		)
-		if authHeader == "" {
-			return c.Status(http.StatusUnauthorized).SendString(ResponseUnauthorized)
-		}
-		split := strings.Split(authHeader, " ")
-		if len(split) == 1 || split[1] == "" {
+		token := bearerToken(c)
+		if token == "" {
 			return c.Status(http.StatusUnauthorized).SendString(ResponseUnauthorized)
 		}
 
-		token = split[1]
-		userAuth, err := m.tokenProvider.ParseAndValidate(token)
+		claims, err := m.tokenProvider.ParseAndValidateClaims(token)
 
 		// Token isn't valid
 		if err != nil {
 			switch true {
 			// If token has expired userAuth is not nil. See jwt.go:122
 			case errors.Is(err, auth.ErrTokenExpired):
-				return m.refreshTokens(c, userAuth)
+				return m.refreshTokens(c, claims.UserAuth)
 			case errors.Is(err, auth.ErrInvalidToken),
 				errors.Is(err, auth.ErrInvalidIssuer):
 				return c.Status(http.StatusUnauthorized).SendString(ResponseUnauthorized)
@@ -66,16 +80,46 @@ func (m JWTAuthMiddleware) Use(requiredRole domain.Role) fiber.Handler {
 			return err
 		}
 
-		// Check if role's insufficient.
-		if !userAuth.Role.CheckPermissions(requiredRole) {
+		// Token has been revoked by logout, disabling user or password change
+		if m.revocationService.IsRevoked(claims) {
+			return c.Status(http.StatusUnauthorized).SendString(ResponseTokenRevoked)
+		}
+
+		// Permissions are checked on every request, so that changes made by admin apply to issued tokens
+		if len(permissions) > 0 && !m.permissionService.Has(claims.Role, permissions...) {
 			return c.Status(http.StatusForbidden).SendString(ResponseAccessDenied)
 		}
 
-		c.Locals(userIDCtx, userAuth.UserID)
+		c.Locals(userIDCtx, claims.UserID)
+		c.Locals(roleCtx, claims.Role)
+		c.Locals(sessionIDCtx, claims.SessionID)
+		c.Locals(claimsCtx, claims)
 		return c.Next()
 	}
 }
 
+// bearerToken reads access token from Authorization header. WebSocket upgrade request
+// can carry it in Sec-WebSocket-Protocol as well, right after WebSocketTokenProtocol.
+func bearerToken(c *fiber.Ctx) string {
+	if authHeader := c.Get(HeaderAuthorization); authHeader != "" {
+		split := strings.Split(authHeader, " ")
+		if len(split) == 1 {
+			return ""
+		}
+		return split[1]
+	}
+	if !websocket.IsWebSocketUpgrade(c) {
+		return ""
+	}
+	protocols := strings.Split(c.Get(fiber.HeaderSecWebSocketProtocol), ",")
+	for i := 0; i < len(protocols)-1; i++ {
+		if strings.TrimSpace(protocols[i]) == WebSocketTokenProtocol {
+			return strings.TrimSpace(protocols[i+1])
+		}
+	}
+	return ""
+}
+
 func (m JWTAuthMiddleware) refreshTokens(c *fiber.Ctx, userAuth auth.UserAuth) error {
 	// Refresh tokens here
 	refreshToken := c.Cookies("refresh-token", "")
@@ -83,26 +127,24 @@ func (m JWTAuthMiddleware) refreshTokens(c *fiber.Ctx, userAuth auth.UserAuth) e
 		return c.Status(http.StatusUnauthorized).SendString(ResponseTokenExpired)
 	}
 
-	switch userAuth.Role {
-	case domain.RoleCustomer:
-		//TODO
-		return nil
-	case domain.RoleWorker:
-		// TODO
-		return nil
-	case domain.RoleAdmin:
-		newTokens, err := m.authService.RefreshAdminToken(c.Context(), userAuth.UserID, refreshToken)
-		if err != nil {
-			return err
+	newTokens, err := m.authService.RefreshTokens(c.Context(), dto.RefreshTokensDTO{
+		Role:         userAuth.Role,
+		UserID:       userAuth.UserID,
+		RefreshToken: refreshToken,
+		Client:       GetSessionClient(c, ""),
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			return c.Status(http.StatusUnauthorized).SendString(ResponseTokenExpired)
 		}
-		m.setRefreshTokenCookie(c, newTokens.RefreshToken)
-		return m.returnAccessToken(c, newTokens.AccessToken)
-	default:
-		return c.SendStatus(http.StatusInternalServerError)
+		return err
 	}
+	SetRefreshTokenCookie(c, newTokens.RefreshToken)
+	return m.returnAccessToken(c, newTokens.AccessToken)
 }
 
-func (m JWTAuthMiddleware) setRefreshTokenCookie(c *fiber.Ctx, refreshToken string) {
+// SetRefreshTokenCookie sets cookie the refresh token is read from once access token expires
+func SetRefreshTokenCookie(c *fiber.Ctx, refreshToken string) {
 	c.Cookie(&fiber.Cookie{
 		Name:  "refresh-token",
 		Value: refreshToken,
@@ -114,6 +156,18 @@ func (m JWTAuthMiddleware) setRefreshTokenCookie(c *fiber.Ctx, refreshToken stri
 	})
 }
 
+// ClearRefreshTokenCookie removes refresh token cookie on logout
+func ClearRefreshTokenCookie(c *fiber.Ctx) {
+	c.Cookie(&fiber.Cookie{
+		Name:     "refresh-token",
+		Value:    "",
+		MaxAge:   -1,
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: "lax",
+	})
+}
+
 func (m JWTAuthMiddleware) returnAccessToken(c *fiber.Ctx, accessToken string) error {
 	return c.Status(http.StatusOK).JSON(fiber.Map{
 		"accessToken": accessToken,
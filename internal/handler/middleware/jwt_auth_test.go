@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
 	mock_service "github.com/sonyamoonglade/sancho-backend/internal/services/mocks"
 	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
 	"github.com/stretchr/testify/require"
@@ -33,12 +35,15 @@ func TestJWTAuthMiddleware(t *testing.T) {
 	require.NoError(t, err)
 
 	authService := mock_service.NewMockAuth(ctrl)
-	m := NewJWTAuthMiddleware(authService, p)
+	revocationService := mock_service.NewMockRevocation(ctrl)
+	revocationService.EXPECT().IsRevoked(gomock.Any()).Return(false).AnyTimes()
+	permissionService := mock_service.NewMockPermission(ctrl)
+	m := NewJWTAuthMiddleware(authService, revocationService, permissionService, p)
 	require.NotNil(t, m)
 
 	t.Run("should pass through middleware because token is all valid", func(t *testing.T) {
 		app := fiber.New()
-		app.Use(m.Use(domain.RoleCustomer))
+		app.Use(m.Authenticate())
 		app.Get("/ping", func(ctx *fiber.Ctx) error {
 			return ctx.Status(http.StatusOK).SendString(pong)
 		})
@@ -57,9 +62,69 @@ func TestJWTAuthMiddleware(t *testing.T) {
 		require.Equal(t, pong, string(body))
 	})
 
+	t.Run("should put user and session of the token to context", func(t *testing.T) {
+		userAuth := auth.UserAuth{
+			UserID:    uuid.NewString(),
+			Role:      domain.RoleWorker,
+			SessionID: uuid.NewString(),
+		}
+		app := fiber.New()
+		app.Use(m.Authenticate())
+		app.Get("/ping", func(ctx *fiber.Ctx) error {
+			userID, err := GetUserIDFromCtx(ctx)
+			require.NoError(t, err)
+			role, err := GetRoleFromCtx(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, userAuth.UserID, userID)
+			require.Equal(t, userAuth.Role, role)
+			require.Equal(t, userAuth.SessionID, GetSessionIDFromCtx(ctx))
+			claims, err := GetClaimsFromCtx(ctx)
+			require.NoError(t, err)
+			require.Equal(t, userAuth, claims.UserAuth)
+			return ctx.Status(http.StatusOK).SendString(pong)
+		})
+
+		tokens, err := p.GenerateNewPair(userAuth)
+		require.NoError(t, err)
+
+		res, err := app.Test(getRequest(tokens.AccessToken), -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("should return 401 because token has been revoked", func(t *testing.T) {
+		userAuth := auth.UserAuth{
+			UserID: uuid.NewString(),
+			Role:   domain.RoleWorker,
+		}
+		tokens, err := p.GenerateNewPair(userAuth)
+		require.NoError(t, err)
+
+		revocationService := mock_service.NewMockRevocation(ctrl)
+		revocationService.
+			EXPECT().
+			IsRevoked(gomock.Any()).
+			DoAndReturn(func(claims auth.Claims) bool {
+				require.Equal(t, userAuth, claims.UserAuth)
+				require.NotZero(t, claims.TokenID)
+				return true
+			})
+		app := fiber.New()
+		app.Use(NewJWTAuthMiddleware(authService, revocationService, permissionService, p).Authenticate())
+		app.Get("/ping", func(ctx *fiber.Ctx) error {
+			return ctx.Status(http.StatusOK).SendString(pong)
+		})
+
+		res, err := app.Test(getRequest(tokens.AccessToken), -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		require.Equal(t, ResponseTokenRevoked, string(readBody(res.Body)))
+	})
+
 	t.Run("should return 401 Unauthorized because token is missing", func(t *testing.T) {
 		app := fiber.New()
-		app.Use(m.Use(domain.RoleCustomer))
+		app.Use(m.Authenticate())
 		app.Get("/ping", func(ctx *fiber.Ctx) error {
 			return ctx.Status(http.StatusOK).SendString(pong)
 		})
@@ -72,9 +137,35 @@ func TestJWTAuthMiddleware(t *testing.T) {
 		require.Equal(t, ResponseUnauthorized, string(body))
 	})
 
+	t.Run("should return 401 Unauthorized because token is passed as subprotocol of regular request", func(t *testing.T) {
+		app := fiber.New()
+		app.Use(m.Authenticate())
+		app.Get("/ping", func(ctx *fiber.Ctx) error {
+			return ctx.Status(http.StatusOK).SendString(pong)
+		})
+		tokens, err := p.GenerateNewPair(auth.UserAuth{
+			UserID: uuid.NewString(),
+			Role:   domain.RoleWorker,
+		})
+		require.NoError(t, err)
+
+		// Subprotocol is read only from WebSocket upgrade request
+		req, _ := http.NewRequest(http.MethodGet, baseURL+"/ping", nil)
+		req.Header.Set(fiber.HeaderSecWebSocketProtocol, WebSocketTokenProtocol+", "+tokens.AccessToken)
+		res, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+		req.Header.Set(fiber.HeaderConnection, "Upgrade")
+		req.Header.Set(fiber.HeaderUpgrade, "websocket")
+		res, err = app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
 	t.Run("should return 401 Unauthorized because header is in invalid format", func(t *testing.T) {
 		app := fiber.New()
-		app.Use(m.Use(domain.RoleCustomer))
+		app.Use(m.Authenticate())
 		app.Get("/ping", func(ctx *fiber.Ctx) error {
 			return ctx.Status(http.StatusOK).SendString(pong)
 		})
@@ -89,7 +180,7 @@ func TestJWTAuthMiddleware(t *testing.T) {
 
 	t.Run("should return 401 Unauthorized because token is not valid (invalid format)", func(t *testing.T) {
 		app := fiber.New()
-		app.Use(m.Use(domain.RoleCustomer))
+		app.Use(m.Authenticate())
 		app.Get("/ping", func(ctx *fiber.Ctx) error {
 			return ctx.Status(http.StatusOK).SendString(pong)
 		})
@@ -104,10 +195,30 @@ func TestJWTAuthMiddleware(t *testing.T) {
 		require.Equal(t, ResponseUnauthorized, string(body))
 	})
 
-	t.Run("should return 403 Access Denied because role's too low", func(t *testing.T) {
+	t.Run("should pass through middleware because role has every required permission", func(t *testing.T) {
 		app := fiber.New()
-		// Only for admins!!
-		app.Use(m.Use(domain.RoleAdmin))
+		app.Use(m.Require(domain.PermissionOrdersView, domain.PermissionOrdersCancel))
+		app.Get("/ping", func(ctx *fiber.Ctx) error {
+			return ctx.Status(http.StatusOK).SendString(pong)
+		})
+
+		tokens, err := p.GenerateNewPair(auth.UserAuth{
+			Role:   domain.RoleWorker,
+			UserID: uuid.NewString(),
+		})
+		require.NoError(t, err)
+		permissionService.EXPECT().
+			Has(domain.RoleWorker, domain.PermissionOrdersView, domain.PermissionOrdersCancel).
+			Return(true)
+
+		res, err := app.Test(getRequest(tokens.AccessToken), -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("should return 403 Access Denied because role lacks permission", func(t *testing.T) {
+		app := fiber.New()
+		app.Use(m.Require(domain.PermissionMetaEdit))
 		app.Get("/ping", func(ctx *fiber.Ctx) error {
 			return ctx.Status(http.StatusOK).SendString(pong)
 		})
@@ -116,6 +227,8 @@ func TestJWTAuthMiddleware(t *testing.T) {
 			Role:   domain.RoleCustomer,
 			UserID: uuid.NewString(),
 		})
+		require.NoError(t, err)
+		permissionService.EXPECT().Has(domain.RoleCustomer, domain.PermissionMetaEdit).Return(false)
 
 		req := getRequest(tokens.AccessToken)
 		res, err := app.Test(req, -1)
@@ -127,7 +240,7 @@ func TestJWTAuthMiddleware(t *testing.T) {
 
 	t.Run("should return 401 because access token has expired and no refresh token is present in cookies", func(t *testing.T) {
 		app := fiber.New()
-		app.Use(m.Use(domain.RoleCustomer))
+		app.Use(m.Authenticate())
 		app.Get("/ping", func(ctx *fiber.Ctx) error {
 			return ctx.Status(http.StatusOK).SendString(pong)
 		})
@@ -150,7 +263,7 @@ func TestJWTAuthMiddleware(t *testing.T) {
 	t.Run("should return 200 OK and rotate tokens because access token has expired but valid refresh token is present", func(t *testing.T) {
 		var userID = uuid.NewString()
 		app := fiber.New()
-		app.Use(m.Use(domain.RoleAdmin))
+		app.Use(m.Require(domain.PermissionMetaEdit))
 		app.Get("/ping", func(ctx *fiber.Ctx) error {
 			return ctx.Status(http.StatusOK).SendString(pong)
 		})
@@ -179,8 +292,13 @@ func TestJWTAuthMiddleware(t *testing.T) {
 		}, time.Millisecond*10)
 
 		authService.EXPECT().
-			RefreshAdminToken(gomock.Any(), userID, tokens.RefreshToken).
-			Return(newTokens, nil).
+			RefreshTokens(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, refreshDTO dto.RefreshTokensDTO) (auth.Pair, error) {
+				require.Equal(t, domain.RoleAdmin, refreshDTO.Role)
+				require.Equal(t, userID, refreshDTO.UserID)
+				require.Equal(t, tokens.RefreshToken, refreshDTO.RefreshToken)
+				return newTokens, nil
+			}).
 			Times(1)
 
 		res, err := app.Test(req, -1)
@@ -197,6 +315,81 @@ func TestJWTAuthMiddleware(t *testing.T) {
 
 		require.Equal(t, newTokens.AccessToken, r.AccessToken)
 	})
+
+	t.Run("should rotate tokens of customer and worker the same way", func(t *testing.T) {
+		for _, role := range []domain.Role{domain.RoleCustomer, domain.RoleWorker} {
+			var userID = uuid.NewString()
+			app := fiber.New()
+			app.Use(m.Authenticate())
+			app.Get("/ping", func(ctx *fiber.Ctx) error {
+				return ctx.Status(http.StatusOK).SendString(pong)
+			})
+
+			tokens, err := p.GenerateNewPairWithTTL(auth.UserAuth{
+				UserID: userID,
+				Role:   role,
+			}, time.Millisecond*5)
+			require.NoError(t, err)
+			time.Sleep(time.Millisecond * 5)
+
+			req := getRequest(tokens.AccessToken)
+			req.AddCookie(&http.Cookie{Name: "refresh-token", Value: tokens.RefreshToken})
+
+			newTokens, _ := p.GenerateNewPair(auth.UserAuth{
+				UserID: userID,
+				Role:   role,
+			})
+			authService.EXPECT().
+				RefreshTokens(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, refreshDTO dto.RefreshTokensDTO) (auth.Pair, error) {
+					require.Equal(t, role, refreshDTO.Role)
+					require.Equal(t, userID, refreshDTO.UserID)
+					require.Equal(t, tokens.RefreshToken, refreshDTO.RefreshToken)
+					return newTokens, nil
+				}).
+				Times(1)
+
+			res, err := app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			require.Contains(t, res.Header.Get("Set-Cookie"), newTokens.RefreshToken)
+		}
+	})
+
+	t.Run("should return 401 because refresh token does not match session", func(t *testing.T) {
+		var userID = uuid.NewString()
+		app := fiber.New()
+		app.Use(m.Authenticate())
+		app.Get("/ping", func(ctx *fiber.Ctx) error {
+			return ctx.Status(http.StatusOK).SendString(pong)
+		})
+
+		tokens, err := p.GenerateNewPairWithTTL(auth.UserAuth{
+			UserID: userID,
+			Role:   domain.RoleCustomer,
+		}, time.Millisecond*5)
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond * 5)
+
+		req := getRequest(tokens.AccessToken)
+		req.AddCookie(&http.Cookie{Name: "refresh-token", Value: tokens.RefreshToken})
+
+		authService.EXPECT().
+			RefreshTokens(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, refreshDTO dto.RefreshTokensDTO) (auth.Pair, error) {
+				require.Equal(t, domain.RoleCustomer, refreshDTO.Role)
+				require.Equal(t, userID, refreshDTO.UserID)
+				require.Equal(t, tokens.RefreshToken, refreshDTO.RefreshToken)
+				return auth.Pair{}, domain.ErrSessionNotFound
+			}).
+			Times(1)
+
+		res, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		body := readBody(res.Body)
+		require.Equal(t, ResponseTokenExpired, string(body))
+	})
 }
 
 func getRequest(token string) *http.Request {
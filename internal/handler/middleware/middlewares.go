@@ -4,23 +4,43 @@ import (
 	"errors"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
 )
 
-const userIDCtx = "userid"
+const (
+	userIDCtx    = "userid"
+	roleCtx      = "role"
+	sessionIDCtx = "sessionid"
+	claimsCtx    = "claims"
+)
 
 var (
 	ErrInvalidUserIDFormat = errors.New("invalid user id format")
+	ErrInvalidRoleFormat   = errors.New("invalid role format")
+	ErrInvalidClaimsFormat = errors.New("invalid claims format")
 )
 
 type Middlewares struct {
-	JWTAuth    *JWTAuthMiddleware
-	XRequestID *XRequestIDMiddleware
+	JWTAuth     *JWTAuthMiddleware
+	XRequestID  *XRequestIDMiddleware
+	RateLimit   *RateLimitMiddleware
+	Lockout     *LockoutMiddleware
+	Idempotency *IdempotencyMiddleware
 }
 
-func NewMiddlewares(jwtAuth *JWTAuthMiddleware, xRequestID *XRequestIDMiddleware) *Middlewares {
+func NewMiddlewares(jwtAuth *JWTAuthMiddleware,
+	xRequestID *XRequestIDMiddleware,
+	rateLimit *RateLimitMiddleware,
+	lockout *LockoutMiddleware,
+	idempotency *IdempotencyMiddleware) *Middlewares {
 	return &Middlewares{
-		JWTAuth:    jwtAuth,
-		XRequestID: xRequestID,
+		JWTAuth:     jwtAuth,
+		XRequestID:  xRequestID,
+		RateLimit:   rateLimit,
+		Lockout:     lockout,
+		Idempotency: idempotency,
 	}
 }
 
@@ -32,3 +52,47 @@ func GetUserIDFromCtx(c *fiber.Ctx) (string, error) {
 
 	return userID.(string), nil
 }
+
+func GetRoleFromCtx(c *fiber.Ctx) (domain.Role, error) {
+	role, ok := c.Locals(roleCtx).(domain.Role)
+	if !ok {
+		return domain.RoleUnknown, ErrInvalidRoleFormat
+	}
+
+	return role, nil
+}
+
+// GetClaimsFromConn returns claims of websocket connection owner. Connection keeps locals of the upgrade request.
+func GetClaimsFromConn(conn *websocket.Conn) (auth.Claims, error) {
+	claims, ok := conn.Locals(claimsCtx).(auth.Claims)
+	if !ok {
+		return auth.Claims{}, ErrInvalidClaimsFormat
+	}
+
+	return claims, nil
+}
+
+// GetSessionIDFromCtx returns id of the session access token has been issued for.
+// It's empty for tokens issued before sessions were tracked per device.
+func GetSessionIDFromCtx(c *fiber.Ctx) string {
+	sessionID, _ := c.Locals(sessionIDCtx).(string)
+	return sessionID
+}
+
+func GetClaimsFromCtx(c *fiber.Ctx) (auth.Claims, error) {
+	claims, ok := c.Locals(claimsCtx).(auth.Claims)
+	if !ok {
+		return auth.Claims{}, ErrInvalidClaimsFormat
+	}
+
+	return claims, nil
+}
+
+// GetSessionClient returns device request is made from. Device label is given by user.
+func GetSessionClient(c *fiber.Ctx, device string) domain.SessionClient {
+	return domain.SessionClient{
+		Device:    device,
+		IP:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}
+}
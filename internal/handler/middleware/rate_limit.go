@@ -0,0 +1,221 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	service "github.com/sonyamoonglade/sancho-backend/internal/services"
+	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
+	"github.com/sonyamoonglade/sancho-backend/pkg/ratelimit"
+	"go.uber.org/zap"
+)
+
+const (
+	ResponseTooManyRequests = "too many requests"
+	ResponseLockedOut       = "too many failed login attempts"
+)
+
+// Route groups rate limits are configured for
+const (
+	RateLimitAuth = "auth"
+	RateLimitOTP  = "otp"
+	// RateLimitOTPIP limits sending one-time codes from IP, so that codes can't be sent to numbers one by one
+	RateLimitOTPIP  = "otp_ip"
+	RateLimitOrders = "orders"
+)
+
+// RateLimitKey is what requests are counted by
+type RateLimitKey string
+
+const (
+	RateLimitByIP RateLimitKey = "ip"
+	// RateLimitByUser counts requests by user ID of the token, so JWTAuthMiddleware must go first
+	RateLimitByUser RateLimitKey = "user"
+	// RateLimitByPhone counts requests by phoneNumber of the body
+	RateLimitByPhone RateLimitKey = "phone"
+)
+
+type RateLimit struct {
+	ratelimit.Limit
+	// Requests are counted by IP if key can't be read from request
+	Key RateLimitKey
+}
+
+// DefaultRateLimits are used for groups missing from config
+var DefaultRateLimits = map[string]RateLimit{
+	RateLimitAuth:   {Limit: ratelimit.Limit{Requests: 10, Period: time.Minute, Burst: 5}, Key: RateLimitByIP},
+	RateLimitOTP:    {Limit: ratelimit.Limit{Requests: 3, Period: time.Minute}, Key: RateLimitByPhone},
+	RateLimitOTPIP:  {Limit: ratelimit.Limit{Requests: 20, Period: time.Hour, Burst: 5}, Key: RateLimitByIP},
+	RateLimitOrders: {Limit: ratelimit.Limit{Requests: 5, Period: time.Minute}, Key: RateLimitByUser},
+}
+
+// DefaultLockoutPolicy is used if lockout is missing from config
+var DefaultLockoutPolicy = ratelimit.LockoutPolicy{
+	MaxFailures: 5,
+	Duration:    time.Second * 30,
+	MaxDuration: time.Hour,
+}
+
+// DefaultAccountLockoutPolicy is used if account lockout is missing from config
+var DefaultAccountLockoutPolicy = ratelimit.LockoutPolicy{
+	MaxFailures: 20,
+	Duration:    time.Minute,
+	MaxDuration: time.Hour,
+}
+
+// RateLimitMiddleware limits requests of a route group with token bucket per key
+type RateLimitMiddleware struct {
+	limiters map[string]*ratelimit.Limiter
+	keys     map[string]RateLimitKey
+}
+
+func NewRateLimitMiddleware(limits map[string]RateLimit) *RateLimitMiddleware {
+	m := &RateLimitMiddleware{
+		limiters: make(map[string]*ratelimit.Limiter),
+		keys:     make(map[string]RateLimitKey),
+	}
+	for group, limit := range DefaultRateLimits {
+		if configured, ok := limits[group]; ok {
+			limit = configured
+		}
+		m.limiters[group] = ratelimit.NewLimiter(limit.Limit)
+		m.keys[group] = limit.Key
+	}
+	return m
+}
+
+// Use limits requests of the group. Requests of unknown group are not limited.
+func (m RateLimitMiddleware) Use(group string) fiber.Handler {
+	limiter, ok := m.limiters[group]
+	if !ok {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+	key := m.keys[group]
+	return func(c *fiber.Ctx) error {
+		if ok, retryAfter := limiter.Allow(rateLimitKey(c, key)); !ok {
+			return tooManyRequests(c, retryAfter, ResponseTooManyRequests)
+		}
+		return c.Next()
+	}
+}
+
+func rateLimitKey(c *fiber.Ctx, key RateLimitKey) string {
+	switch key {
+	case RateLimitByUser:
+		if userID, err := GetUserIDFromCtx(c); err == nil {
+			return "user:" + userID
+		}
+	case RateLimitByPhone:
+		if phoneNumber := readCredentials(c).PhoneNumber; phoneNumber != "" {
+			return "phone:" + phoneNumber
+		}
+	}
+	return "ip:" + c.IP()
+}
+
+// LockoutMiddleware locks client out of login after repeated failures,
+// locking it for longer with every failure after that. Account is locked out
+// for every client once failures from all of them reach the account policy.
+type LockoutMiddleware struct {
+	lockout        *ratelimit.Lockout
+	accountLockout *ratelimit.Lockout
+}
+
+func NewLockoutMiddleware(policy, accountPolicy ratelimit.LockoutPolicy) *LockoutMiddleware {
+	return &LockoutMiddleware{
+		lockout:        ratelimit.NewLockout(withDefaultLockoutPolicy(policy, DefaultLockoutPolicy)),
+		accountLockout: ratelimit.NewLockout(withDefaultLockoutPolicy(accountPolicy, DefaultAccountLockoutPolicy)),
+	}
+}
+
+func withDefaultLockoutPolicy(policy, defaultPolicy ratelimit.LockoutPolicy) ratelimit.LockoutPolicy {
+	if policy.MaxFailures <= 0 {
+		policy.MaxFailures = defaultPolicy.MaxFailures
+	}
+	if policy.Duration <= 0 {
+		policy.Duration = defaultPolicy.Duration
+	}
+	if policy.MaxDuration < policy.Duration {
+		policy.MaxDuration = defaultPolicy.MaxDuration
+	}
+	return policy
+}
+
+func (m LockoutMiddleware) Use() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		credentials := readCredentials(c)
+		keys := []string{strings.Join([]string{c.Path(), c.IP(), credentials.Login, credentials.PhoneNumber}, ":")}
+		lockouts := []*ratelimit.Lockout{m.lockout}
+		// Password guessing spread over many IPs is counted by account. Its policy allows far more failures,
+		// otherwise anyone could lock admins out.
+		if credentials.Login != "" || credentials.PhoneNumber != "" {
+			keys = append(keys, strings.Join([]string{c.Path(), credentials.Login, credentials.PhoneNumber}, ":"))
+			lockouts = append(lockouts, m.accountLockout)
+		}
+
+		for i, lockout := range lockouts {
+			if locked, retryAfter := lockout.Locked(keys[i]); locked {
+				return tooManyRequests(c, retryAfter, ResponseLockedOut)
+			}
+		}
+
+		err := c.Next()
+		switch true {
+		case isFailedLogin(err):
+			for i, lockout := range lockouts {
+				if duration := lockout.Fail(keys[i]); duration > 0 {
+					logger.Get().Warn("login is locked out",
+						zap.String("path", c.Path()),
+						zap.String("ip", c.IP()),
+						zap.String("login", credentials.Login),
+						zap.Bool("account", lockout == m.accountLockout),
+						zap.Duration("duration", duration),
+					)
+				}
+			}
+		case err == nil && c.Response().StatusCode() == http.StatusOK:
+			for i, lockout := range lockouts {
+				lockout.Reset(keys[i])
+			}
+		}
+		return err
+	}
+}
+
+// isFailedLogin checks if login has failed because of wrong credentials
+func isFailedLogin(err error) bool {
+	is := errors.Is
+	return is(err, service.ErrInvalidPassword) ||
+		is(err, domain.ErrAdminNotFound) ||
+		is(err, domain.ErrWorkerNotFound) ||
+		is(err, domain.ErrInvalidOTP) ||
+		is(err, domain.ErrOTPNotFound)
+}
+
+type credentials struct {
+	Login       string `json:"login"`
+	PhoneNumber string `json:"phoneNumber"`
+}
+
+// readCredentials reads login or phone number from body of auth request. Fields are empty if body is not JSON.
+func readCredentials(c *fiber.Ctx) credentials {
+	var creds credentials
+	_ = json.Unmarshal(c.Body(), &creds)
+	// Same number with and without + must not get separate limits
+	creds.PhoneNumber = strings.TrimPrefix(creds.PhoneNumber, "+")
+	return creds
+}
+
+func tooManyRequests(c *fiber.Ctx, retryAfter time.Duration, msg string) error {
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	return c.Status(http.StatusTooManyRequests).SendString(msg)
+}
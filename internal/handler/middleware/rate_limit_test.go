@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	service "github.com/sonyamoonglade/sancho-backend/internal/services"
+	"github.com/sonyamoonglade/sancho-backend/pkg/ratelimit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	const pong = "pong"
+	oncePerMinute := ratelimit.Limit{Requests: 1, Period: time.Minute}
+
+	send := func(app *fiber.App, body interface{}) *http.Response {
+		b, _ := json.Marshal(body)
+		req, _ := http.NewRequest(http.MethodPost, baseURL+"/ping", bytes.NewReader(b))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("should return 429 with Retry-After once limit is exceeded", func(t *testing.T) {
+		m := NewRateLimitMiddleware(map[string]RateLimit{
+			RateLimitAuth: {Limit: oncePerMinute, Key: RateLimitByIP},
+		})
+		app := fiber.New()
+		app.Post("/ping", m.Use(RateLimitAuth), func(c *fiber.Ctx) error {
+			return c.SendString(pong)
+		})
+
+		res := send(app, nil)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		res = send(app, nil)
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		require.Equal(t, "60", res.Header.Get(fiber.HeaderRetryAfter))
+		require.Equal(t, ResponseTooManyRequests, string(readBody(res.Body)))
+	})
+
+	t.Run("should count requests by phone number", func(t *testing.T) {
+		m := NewRateLimitMiddleware(map[string]RateLimit{
+			RateLimitOTP: {Limit: oncePerMinute, Key: RateLimitByPhone},
+		})
+		app := fiber.New()
+		app.Post("/ping", m.Use(RateLimitOTP), func(c *fiber.Ctx) error {
+			return c.SendString(pong)
+		})
+
+		res := send(app, fiber.Map{"phoneNumber": "+79991234567"})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		res = send(app, fiber.Map{"phoneNumber": "79991234567"})
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		res = send(app, fiber.Map{"phoneNumber": "+79997654321"})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("should limit requests from IP before counting them by phone number", func(t *testing.T) {
+		m := NewRateLimitMiddleware(map[string]RateLimit{
+			RateLimitOTPIP: {Limit: ratelimit.Limit{Requests: 2, Period: time.Minute}, Key: RateLimitByIP},
+			RateLimitOTP:   {Limit: oncePerMinute, Key: RateLimitByPhone},
+		})
+		app := fiber.New()
+		app.Post("/ping", m.Use(RateLimitOTPIP), m.Use(RateLimitOTP), func(c *fiber.Ctx) error {
+			return c.SendString(pong)
+		})
+
+		require.Equal(t, http.StatusOK, send(app, fiber.Map{"phoneNumber": "+79990000001"}).StatusCode)
+		require.Equal(t, http.StatusOK, send(app, fiber.Map{"phoneNumber": "+79990000002"}).StatusCode)
+		// Every number is new, but IP has sent too many codes
+		require.Equal(t, http.StatusTooManyRequests, send(app, fiber.Map{"phoneNumber": "+79990000003"}).StatusCode)
+	})
+
+	t.Run("should count requests by user", func(t *testing.T) {
+		m := NewRateLimitMiddleware(map[string]RateLimit{
+			RateLimitOrders: {Limit: oncePerMinute, Key: RateLimitByUser},
+		})
+		app := fiber.New()
+		userID := "user-1"
+		app.Post("/ping", func(c *fiber.Ctx) error {
+			c.Locals(userIDCtx, userID)
+			return c.Next()
+		}, m.Use(RateLimitOrders), func(c *fiber.Ctx) error {
+			return c.SendString(pong)
+		})
+
+		require.Equal(t, http.StatusOK, send(app, nil).StatusCode)
+		require.Equal(t, http.StatusTooManyRequests, send(app, nil).StatusCode)
+		userID = "user-2"
+		require.Equal(t, http.StatusOK, send(app, nil).StatusCode)
+	})
+
+	t.Run("should not limit unknown group", func(t *testing.T) {
+		m := NewRateLimitMiddleware(nil)
+		app := fiber.New()
+		app.Post("/ping", m.Use("unknown"), func(c *fiber.Ctx) error {
+			return c.SendString(pong)
+		})
+
+		for i := 0; i < 20; i++ {
+			require.Equal(t, http.StatusOK, send(app, nil).StatusCode)
+		}
+	})
+}
+
+func TestLockoutMiddleware(t *testing.T) {
+	var handled int
+	m := NewLockoutMiddleware(ratelimit.LockoutPolicy{
+		MaxFailures: 2,
+		Duration:    time.Minute,
+		MaxDuration: time.Hour,
+	}, ratelimit.LockoutPolicy{
+		MaxFailures: 3,
+		Duration:    time.Minute * 5,
+		MaxDuration: time.Hour,
+	})
+	app := fiber.New(fiber.Config{ProxyHeader: fiber.HeaderXForwardedFor})
+	app.Post("/login", m.Use(), func(c *fiber.Ctx) error {
+		handled++
+		var creds struct {
+			Password string `json:"password"`
+		}
+		if err := c.BodyParser(&creds); err != nil {
+			return err
+		}
+		if creds.Password != "correct" {
+			return service.ErrInvalidPassword
+		}
+		return c.SendStatus(http.StatusOK)
+	})
+
+	loginFrom := func(ip, login, password string) *http.Response {
+		b, _ := json.Marshal(fiber.Map{"login": login, "password": password})
+		req, _ := http.NewRequest(http.MethodPost, baseURL+"/login", bytes.NewReader(b))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(fiber.HeaderXForwardedFor, ip)
+		res, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return res
+	}
+	login := func(login, password string) *http.Response {
+		return loginFrom("10.0.0.1", login, password)
+	}
+
+	t.Run("should forget failures after successful login", func(t *testing.T) {
+		require.NotEqual(t, http.StatusOK, login("worker", "wrong").StatusCode)
+		require.Equal(t, http.StatusOK, login("worker", "correct").StatusCode)
+		require.NotEqual(t, http.StatusOK, login("worker", "wrong").StatusCode)
+		require.Equal(t, http.StatusOK, login("worker", "correct").StatusCode)
+	})
+
+	t.Run("should lock out login after repeated failures", func(t *testing.T) {
+		login("admin", "wrong")
+		login("admin", "wrong")
+
+		handledBefore := handled
+		res := login("admin", "correct")
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		require.Equal(t, "60", res.Header.Get(fiber.HeaderRetryAfter))
+		require.Equal(t, ResponseLockedOut, string(readBody(res.Body)))
+		// Password is not even checked while locked out
+		require.Equal(t, handledBefore, handled)
+
+		// Other logins are not affected
+		require.Equal(t, http.StatusOK, login("worker", "correct").StatusCode)
+	})
+	t.Run("should lock out account after failures from many IPs", func(t *testing.T) {
+		for _, ip := range []string{"10.0.1.1", "10.0.1.2", "10.0.1.3"} {
+			require.NotEqual(t, http.StatusOK, loginFrom(ip, "manager", "wrong").StatusCode)
+		}
+
+		res := loginFrom("10.0.1.4", "manager", "correct")
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		require.Equal(t, "300", res.Header.Get(fiber.HeaderRetryAfter))
+
+		// Other logins from the same IPs are not affected
+		require.Equal(t, http.StatusOK, loginFrom("10.0.1.1", "worker", "correct").StatusCode)
+	})
+}
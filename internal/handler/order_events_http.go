@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/middleware"
+	"github.com/sonyamoonglade/sancho-backend/pkg/eventbus"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	HeaderLastEventID = "Last-Event-ID"
+
+	sseHeartbeatInterval = time.Second * 15
+)
+
+// StreamOrderEvents streams order events as Server-Sent Events. Customers receive events of their own
+// orders, workers and admins receive events of all orders.
+func (h Handler) StreamOrderEvents(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromCtx(c)
+	if err != nil {
+		return err
+	}
+	claims, err := middleware.GetClaimsFromCtx(c)
+	if err != nil {
+		return err
+	}
+
+	var lastEventID uint64
+	if raw := c.Get(HeaderLastEventID); raw != "" {
+		lastEventID, err = strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).SendString("invalid Last-Event-ID")
+		}
+	}
+
+	sub, missed, ok := h.subscribeOrderEvents(claims.Role, userID, lastEventID)
+	if !ok {
+		return c.Status(http.StatusForbidden).SendString(middleware.ResponseAccessDenied)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	// Disable response buffering in nginx
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		heartbeat := time.NewTicker(sseHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		streamEvents(w, sub, missed, heartbeat.C, func() bool {
+			return h.services.Revocation.IsRevoked(claims)
+		})
+	}))
+
+	return nil
+}
+
+// subscribeOrderEvents subscribes customer to events of own orders and others to events of all orders.
+// It returns false if role isn't allowed to follow them.
+func (h Handler) subscribeOrderEvents(role domain.Role, userID string, lastEventID uint64) (*eventbus.Subscription, []eventbus.Event, bool) {
+	if role == domain.RoleCustomer {
+		if !h.services.Permission.Has(role, domain.PermissionOrdersOwn) {
+			return nil, nil, false
+		}
+		sub, missed := h.services.OrderEvents.SubscribeCustomer(userID, lastEventID)
+		return sub, missed, true
+	}
+	if !h.services.Permission.Has(role, domain.PermissionOrdersView) {
+		return nil, nil, false
+	}
+	sub, missed := h.services.OrderEvents.SubscribeWorkers(lastEventID)
+	return sub, missed, true
+}
+
+// streamEvents writes missed events and then live ones until subscription is dropped, client is gone
+// or token is revoked. Revocation is checked on every heartbeat.
+func streamEvents(w *bufio.Writer, sub *eventbus.Subscription, missed []eventbus.Event, heartbeat <-chan time.Time, isRevoked func() bool) {
+	// Write error means client has gone, so unsubscribe
+	defer sub.Close()
+
+	for _, event := range missed {
+		writeSSEEvent(w, event)
+	}
+	if err := w.Flush(); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			// Subscription is dropped because client is too slow. It will reconnect with Last-Event-ID.
+			if !ok {
+				return
+			}
+			writeSSEEvent(w, event)
+		case <-heartbeat:
+			// Client reconnects and gets 401 for revoked token
+			if isRevoked() {
+				return
+			}
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeSSEEvent(w *bufio.Writer, event eventbus.Event) {
+	fmt.Fprintf(w, "id: %d\n", event.ID)
+	fmt.Fprintf(w, "event: %s\n", event.Type)
+	fmt.Fprintf(w, "data: %s\n\n", event.Data)
+}
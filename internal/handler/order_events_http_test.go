@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	service "github.com/sonyamoonglade/sancho-backend/internal/services"
+	mock_service "github.com/sonyamoonglade/sancho-backend/internal/services/mocks"
+	"github.com/sonyamoonglade/sancho-backend/pkg/eventbus"
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection closed")
+}
+
+func TestSubscribeOrderEvents(t *testing.T) {
+	getHandler := func(t *testing.T) (Handler, *mock_service.MockPermission) {
+		ctrl := gomock.NewController(t)
+		permissionService := mock_service.NewMockPermission(ctrl)
+		return Handler{services: &service.Services{
+			OrderEvents: service.NewOrderEventsService(eventbus.New(10)),
+			Permission:  permissionService,
+		}}, permissionService
+	}
+
+	t.Run("should subscribe worker to events of all orders", func(t *testing.T) {
+		h, permissionService := getHandler(t)
+		permissionService.EXPECT().Has(domain.RoleWorker, domain.PermissionOrdersView).Return(true)
+
+		sub, _, ok := h.subscribeOrderEvents(domain.RoleWorker, "worker", 0)
+		require.True(t, ok)
+		defer sub.Close()
+
+		h.services.OrderEvents.Publish(domain.OrderEventCreated, domain.OrderEvent{CustomerID: "customer"})
+		require.Len(t, sub.Events(), 1)
+	})
+
+	t.Run("should not subscribe role that can't view orders", func(t *testing.T) {
+		h, permissionService := getHandler(t)
+		permissionService.EXPECT().Has(domain.RoleWorker, domain.PermissionOrdersView).Return(false)
+
+		sub, _, ok := h.subscribeOrderEvents(domain.RoleWorker, "worker", 0)
+		require.False(t, ok)
+		require.Nil(t, sub)
+	})
+
+	t.Run("should subscribe customer to events of own orders only", func(t *testing.T) {
+		h, permissionService := getHandler(t)
+		permissionService.EXPECT().Has(domain.RoleCustomer, domain.PermissionOrdersOwn).Return(true)
+
+		sub, _, ok := h.subscribeOrderEvents(domain.RoleCustomer, "customer", 0)
+		require.True(t, ok)
+		defer sub.Close()
+
+		h.services.OrderEvents.Publish(domain.OrderEventCreated, domain.OrderEvent{CustomerID: "other"})
+		require.Len(t, sub.Events(), 0)
+		h.services.OrderEvents.Publish(domain.OrderEventCreated, domain.OrderEvent{CustomerID: "customer"})
+		require.Len(t, sub.Events(), 1)
+	})
+
+	t.Run("should not subscribe customer without orders.own", func(t *testing.T) {
+		h, permissionService := getHandler(t)
+		permissionService.EXPECT().Has(domain.RoleCustomer, domain.PermissionOrdersOwn).Return(false)
+
+		_, _, ok := h.subscribeOrderEvents(domain.RoleCustomer, "customer", 0)
+		require.False(t, ok)
+	})
+}
+
+func TestStreamEvents(t *testing.T) {
+	notRevoked := func() bool { return false }
+
+	// stream runs streamEvents writing to pipe, done is closed once it returns
+	stream := func(sub *eventbus.Subscription, missed []eventbus.Event, heartbeat <-chan time.Time, isRevoked func() bool) (*bufio.Reader, chan struct{}) {
+		pr, pw := io.Pipe()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			defer pw.Close()
+			streamEvents(bufio.NewWriter(pw), sub, missed, heartbeat, isRevoked)
+		}()
+		return bufio.NewReader(pr), done
+	}
+
+	readEvent := func(t *testing.T, r *bufio.Reader) string {
+		var event string
+		for {
+			line, err := r.ReadString('\n')
+			require.NoError(t, err)
+			if line == "\n" {
+				return event
+			}
+			event += line
+		}
+	}
+
+	sseEvent := func(event eventbus.Event) string {
+		return fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n", event.ID, event.Type, event.Data)
+	}
+
+	t.Run("should replay events after Last-Event-ID and then stream live ones", func(t *testing.T) {
+		bus := eventbus.New(10)
+		var published []eventbus.Event
+		for i := 0; i < 3; i++ {
+			published = append(published, bus.Publish("a", domain.OrderEventCreated, []byte(fmt.Sprintf(`{"n":%d}`, i))))
+		}
+
+		sub, missed := bus.Subscribe("a", published[0].ID)
+		r, done := stream(sub, missed, nil, notRevoked)
+
+		require.Equal(t, sseEvent(published[1]), readEvent(t, r))
+		require.Equal(t, sseEvent(published[2]), readEvent(t, r))
+
+		live := bus.Publish("a", domain.OrderEventStatusChanged, []byte(`{}`))
+		require.Equal(t, sseEvent(live), readEvent(t, r))
+
+		sub.Close()
+		<-done
+	})
+
+	t.Run("should write heartbeat comment", func(t *testing.T) {
+		bus := eventbus.New(10)
+		sub, _ := bus.Subscribe("a", 0)
+		heartbeat := make(chan time.Time)
+		r, done := stream(sub, nil, heartbeat, notRevoked)
+
+		heartbeat <- time.Now()
+		require.Equal(t, ": heartbeat\n", readEvent(t, r))
+
+		sub.Close()
+		<-done
+	})
+
+	t.Run("should end stream on heartbeat once token is revoked", func(t *testing.T) {
+		bus := eventbus.New(10)
+		sub, _ := bus.Subscribe("a", 0)
+		heartbeat := make(chan time.Time)
+		var revoked bool
+		r, done := stream(sub, nil, heartbeat, func() bool { return revoked })
+
+		heartbeat <- time.Now()
+		require.Equal(t, ": heartbeat\n", readEvent(t, r))
+
+		revoked = true
+		heartbeat <- time.Now()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("stream has not returned")
+		}
+		_, ok := <-sub.Events()
+		require.False(t, ok)
+	})
+
+	t.Run("should unsubscribe once client is gone", func(t *testing.T) {
+		bus := eventbus.New(10)
+		sub, _ := bus.Subscribe("a", 0)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			streamEvents(bufio.NewWriterSize(failingWriter{}, 16), sub, nil, nil, notRevoked)
+		}()
+
+		bus.Publish("a", domain.OrderEventCreated, []byte(`{}`))
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("stream has not returned")
+		}
+
+		// Channel of subscription is closed on unsubscribe
+		_, ok := <-sub.Events()
+		require.False(t, ok)
+	})
+}
@@ -105,3 +105,130 @@ func (h Handler) CreateWorkerOrder(c *fiber.Ctx) error {
 		"orderId": orderID,
 	})
 }
+
+func (h Handler) GetCustomerOrders(c *fiber.Ctx) error {
+	var inp input.CustomerOrdersInput
+	if err := c.QueryParser(&inp); err != nil {
+		return c.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	if ok, msg := validation.ValidateCustomerOrdersInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+
+	customerID, err := middleware.GetUserIDFromCtx(c)
+	if err != nil {
+		return err
+	}
+
+	listDTO, err := inp.ToDTO(customerID)
+	if err != nil {
+		return err
+	}
+
+	page, err := h.services.Order.ListOrders(c.Context(), listDTO)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"orders":     page.Orders,
+		"nextCursor": page.NextCursor,
+	})
+}
+
+func (h Handler) GetCustomerCurrentOrder(c *fiber.Ctx) error {
+	customerID, err := middleware.GetUserIDFromCtx(c)
+	if err != nil {
+		return err
+	}
+
+	order, err := h.services.Order.GetCurrentOrderByCustomerID(c.Context(), customerID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"nanoId": order.NanoID,
+		"order":  order,
+	})
+}
+
+func (h Handler) ListOrders(c *fiber.Ctx) error {
+	var inp input.ListOrdersInput
+	if err := c.QueryParser(&inp); err != nil {
+		return c.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	if ok, msg := validation.ValidateListOrdersInput(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+
+	listDTO, err := inp.ToDTO()
+	if err != nil {
+		return err
+	}
+	// Orders reference customers by id, so phone number is resolved into customer first
+	if inp.PhoneNumber != "" {
+		customer, err := h.services.User.GetCustomerByPhoneNumber(c.Context(), inp.PhoneNumber)
+		if err != nil {
+			if errors.Is(err, domain.ErrCustomerNotFound) {
+				return c.Status(http.StatusOK).JSON(fiber.Map{
+					"orders":     []domain.Order{},
+					"nextCursor": nil,
+				})
+			}
+			return err
+		}
+		customerID := customer.UserID.Hex()
+		listDTO.CustomerID = &customerID
+	}
+
+	page, err := h.services.Order.ListOrders(c.Context(), listDTO)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"orders":     page.Orders,
+		"nextCursor": page.NextCursor,
+	})
+}
+
+func (h Handler) VerifyOrder(c *fiber.Ctx) error {
+	orderID := c.Params("id", "")
+	if orderID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	if err := h.services.Order.VerifyOrder(c.Context(), orderID); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) CompleteOrder(c *fiber.Ctx) error {
+	orderID := c.Params("id", "")
+	if orderID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	if err := h.services.Order.CompleteOrder(c.Context(), orderID); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h Handler) CancelOrder(c *fiber.Ctx) error {
+	orderID := c.Params("id", "")
+	if orderID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	var inp input.CancelOrderInput
+	if err := c.BodyParser(&inp); err != nil {
+		return err
+	}
+	if ok, msg := validation.ValidateStruct(inp); !ok {
+		return c.Status(http.StatusBadRequest).SendString(msg)
+	}
+	if err := h.services.Order.CancelOrder(c.Context(), orderID, inp.Explanation); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	service "github.com/sonyamoonglade/sancho-backend/internal/services"
+	mock_service "github.com/sonyamoonglade/sancho-backend/internal/services/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCustomerOrders(t *testing.T) {
+	t.Run("should return 400 because cursor is invalid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		orderService := mock_service.NewMockOrder(ctrl)
+		orderService.EXPECT().ListOrders(gomock.Any(), gomock.Any()).Times(0)
+		h := Handler{services: &service.Services{Order: orderService}}
+
+		app := fiber.New(fiber.Config{ErrorHandler: HandleError})
+		app.Get("/api/order/my", h.GetCustomerOrders)
+
+		res, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/order/my?cursor=not-a-cursor", nil), -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
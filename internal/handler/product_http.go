@@ -7,7 +7,7 @@ import (
 )
 
 func (h Handler) GetCatalog(c *fiber.Ctx) error {
-	catalog, err := h.services.Product.GetAll(c.Context())
+	catalog, err := h.services.Product.GetCatalog(c.Context())
 	if err != nil {
 		return err
 	}
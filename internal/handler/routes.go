@@ -2,9 +2,40 @@ package handler
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/middleware"
 )
 
+func (h Handler) initAuthAPI(api fiber.Router) {
+	m := h.middlewares
+
+	anyAuth := m.JWTAuth.Authenticate()
+	rateLimit := m.RateLimit.Use(middleware.RateLimitAuth)
+	lockout := m.Lockout.Use()
+	a := api.Group("/auth")
+	{
+		a.Post("/customer/otp", m.RateLimit.Use(middleware.RateLimitOTPIP), m.RateLimit.Use(middleware.RateLimitOTP), h.RequestCustomerOTP)
+		a.Post("/customer/login", rateLimit, lockout, h.LoginCustomer)
+		a.Post("/worker/login", rateLimit, lockout, h.LoginWorker)
+		a.Post("/admin/login", rateLimit, lockout, h.LoginAdmin)
+		a.Post("/logout", anyAuth, h.Logout)
+		a.Post("/logout/all", anyAuth, h.LogoutEverywhere)
+	}
+}
+
+func (h Handler) initSessionsAPI(api fiber.Router) {
+	m := h.middlewares
+
+	// Anyone can manage own sessions
+	sessions := api.Group("/sessions")
+	sessions.Use(m.JWTAuth.Authenticate())
+	{
+		sessions.Get("/", h.GetSessions)
+		sessions.Delete("/:id", h.RevokeSession)
+	}
+}
+
 func (h Handler) initProductAPI(api fiber.Router) {
 	p := api.Group("/products")
 	{
@@ -13,36 +44,121 @@ func (h Handler) initProductAPI(api fiber.Router) {
 	}
 }
 
+func (h Handler) initDeliveryAPI(api fiber.Router) {
+	d := api.Group("/delivery")
+	{
+		d.Get("/zones", h.GetDeliveryZones)
+		d.Get("/slots", h.GetDeliverySlots)
+	}
+}
+
+func (h Handler) initScheduleAPI(api fiber.Router) {
+	api.Get("/schedule", h.GetSchedule)
+}
+
 func (h Handler) initAdminsAPI(api fiber.Router) {
 	m := h.middlewares
 
+	// Every route requires its own permission, see domain.Permissions
 	admins := api.Group("/admins")
-	admins.Use(m.JWTAuth.Use(domain.RoleAdmin))
 
 	products := admins.Group("/products")
 	{
-		products.Post("/create", h.AdminCreateProduct)
-		products.Put("/:id/update", h.AdminUpdateProduct)
-		products.Delete("/:id/delete", h.AdminDeleteProduct)
-		products.Put("/:id/approve", h.AdminApproveProduct)
-		products.Put("/:id/disapprove", h.AdminDisapproveProduct)
+		edit := m.JWTAuth.Require(domain.PermissionProductsEdit)
+		approve := m.JWTAuth.Require(domain.PermissionProductsApprove)
+		// Unlike catalog, lists products that are not approved yet
+		products.Get("/", edit, h.AdminGetProducts)
+		products.Post("/create", edit, h.AdminCreateProduct)
+		products.Put("/:id/update", edit, h.AdminUpdateProduct)
+		products.Delete("/:id/delete", edit, h.AdminDeleteProduct)
+		products.Put("/:id/image", edit, h.AdminUploadProductImage)
+		products.Put("/:id/options", edit, h.AdminUpdateProductOptions)
+		products.Put("/:id/approve", approve, h.AdminApproveProduct)
+		products.Put("/:id/disapprove", approve, h.AdminDisapproveProduct)
 	}
+
+	categories := admins.Group("/categories")
+	categories.Use(m.JWTAuth.Require(domain.PermissionProductsEdit))
+	{
+		categories.Post("/create", h.AdminCreateCategory)
+		categories.Put("/reorder", h.AdminReorderCategories)
+		categories.Put("/:id/rename", h.AdminRenameCategory)
+		categories.Delete("/:id/delete", h.AdminDeleteCategory)
+	}
+
+	promoCodes := admins.Group("/promocodes")
+	promoCodes.Use(m.JWTAuth.Require(domain.PermissionPromoCodesEdit))
+	{
+		promoCodes.Get("/", h.AdminGetPromoCodes)
+		promoCodes.Get("/:id", h.AdminGetPromoCode)
+		promoCodes.Post("/create", h.AdminCreatePromoCode)
+		promoCodes.Put("/:id/update", h.AdminUpdatePromoCode)
+		promoCodes.Delete("/:id/delete", h.AdminDeletePromoCode)
+	}
+
+	deliveryZones := admins.Group("/delivery-zones")
+	deliveryZones.Use(m.JWTAuth.Require(domain.PermissionDeliveryZonesEdit))
+	{
+		deliveryZones.Get("/:id", h.AdminGetDeliveryZone)
+		deliveryZones.Post("/create", h.AdminCreateDeliveryZone)
+		deliveryZones.Put("/:id/update", h.AdminUpdateDeliveryZone)
+		deliveryZones.Delete("/:id/delete", h.AdminDeleteDeliveryZone)
+	}
+
+	revokeSessions := m.JWTAuth.Require(domain.PermissionSessionsRevoke)
+	workers := admins.Group("/workers")
+	{
+		manage := m.JWTAuth.Require(domain.PermissionWorkersManage)
+		workers.Get("/", manage, h.AdminGetWorkers)
+		workers.Post("/create", manage, h.AdminCreateWorker)
+		workers.Put("/:id/disable", manage, h.AdminDisableWorker)
+		workers.Put("/:id/password", manage, h.AdminResetWorkerPassword)
+		workers.Delete("/:id/sessions", revokeSessions, h.AdminRevokeWorkerSessions)
+	}
+
+	customers := admins.Group("/customers")
+	{
+		customers.Delete("/:id/sessions", revokeSessions, h.AdminRevokeCustomerSessions)
+	}
+
+	roles := admins.Group("/roles")
+	roles.Use(m.JWTAuth.Require(domain.PermissionRolesManage))
+	{
+		roles.Get("/", h.AdminGetRolePermissions)
+		roles.Put("/:role/permissions", h.AdminUpdateRolePermissions)
+	}
+
+	editMeta := m.JWTAuth.Require(domain.PermissionMetaEdit)
+	admins.Get("/meta", editMeta, h.AdminGetMeta)
+	admins.Put("/meta/schedule", editMeta, h.AdminUpdateSchedule)
 }
 
 func (h Handler) initOrdersAPI(api fiber.Router) {
 	m := h.middlewares
 
-	customerAuth := m.JWTAuth.Use(domain.RoleCustomer)
+	customerAuth := m.JWTAuth.Require(domain.PermissionOrdersOwn)
 	// Don't order.Use(customerAuth), because not all /order requests require auth.
 	order := api.Group("/order")
 	{
-		order.Post("/create", customerAuth, h.CreateUserOrder)
+		order.Post("/create", customerAuth, m.Idempotency.Use(), m.RateLimit.Use(middleware.RateLimitOrders), h.CreateUserOrder)
+		order.Get("/my", customerAuth, h.GetCustomerOrders)
+		order.Get("/my/current", customerAuth, h.GetCustomerCurrentOrder)
+		// Feed is shared by customers and workers, so permission depends on role. See subscribeOrderEvents
+		order.Get("/events", m.JWTAuth.Authenticate(), h.StreamOrderEvents)
 
 		{
 			worker := order.Group("/worker")
-			worker.Use(m.JWTAuth.Use(domain.RoleWorker))
+			require := m.JWTAuth.Require
 
-			worker.Post("/create", h.CreateWorkerOrder)
+			worker.Post("/create", require(domain.PermissionOrdersCreate), m.Idempotency.Use(), h.CreateWorkerOrder)
+			worker.Get("/list", require(domain.PermissionOrdersView), h.ListOrders)
+			worker.Put("/:id/verify", require(domain.PermissionOrdersVerify), h.VerifyOrder)
+			worker.Put("/:id/complete", require(domain.PermissionOrdersComplete), h.CompleteOrder)
+			worker.Put("/:id/cancel", require(domain.PermissionOrdersCancel), h.CancelOrder)
+			worker.Get("/kitchen", require(domain.PermissionOrdersView), RequireWebSocketUpgrade, websocket.New(h.KitchenFeed, websocket.Config{
+				// Browsers pass access token as subprotocol, see middleware.WebSocketTokenProtocol
+				Subprotocols: []string{middleware.WebSocketTokenProtocol},
+			}))
 		}
 	}
 }
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetSchedule returns working hours and whether store is open right now.
+// opensAt is in store's timezone and is omitted if store is open or doesn't open soon.
+func (h Handler) GetSchedule(c *fiber.Ctx) error {
+	var (
+		schedule = h.services.Meta.Schedule()
+		now      = time.Now().UTC()
+		isOpen   = schedule.IsOpenAt(now)
+		resp     = fiber.Map{
+			"schedule": schedule,
+			"isOpen":   isOpen,
+		}
+	)
+	if !isOpen {
+		if opensAt, ok := schedule.NextOpening(now); ok {
+			resp["opensAt"] = opensAt
+		}
+	}
+	return c.Status(http.StatusOK).JSON(resp)
+}
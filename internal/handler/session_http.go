@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/middleware"
+)
+
+func (h Handler) GetSessions(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromCtx(c)
+	if err != nil {
+		return err
+	}
+	role, err := middleware.GetRoleFromCtx(c)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := h.services.Session.GetByUser(c.Context(), role, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"sessions":         sessions,
+		"currentSessionId": middleware.GetSessionIDFromCtx(c),
+	})
+}
+
+func (h Handler) RevokeSession(c *fiber.Ctx) error {
+	sessionID := c.Params("id", "")
+	if sessionID == "" {
+		return c.Status(http.StatusBadRequest).SendString("empty id")
+	}
+	userID, err := middleware.GetUserIDFromCtx(c)
+	if err != nil {
+		return err
+	}
+	role, err := middleware.GetRoleFromCtx(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.services.Session.Revoke(c.Context(), role, userID, sessionID); err != nil {
+		return err
+	}
+	return c.SendStatus(http.StatusOK)
+}
@@ -2,45 +2,111 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
 	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
+	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
 )
 
 var (
 	ErrInvalidPassword = errors.New("password is invalid")
 )
 
+const (
+	DefaultOTPTTL                  = time.Minute * 5
+	DefaultOTPMaxAttempts    int64 = 5
+	DefaultOTPResendInterval       = time.Minute
+
+	otpText = "Your Sancho login code is %s"
+
+	// dummyPassword is hashed to be verified against when login isn't found
+	dummyPassword = "sancho-dummy-password"
+)
+
+type OTPConfig struct {
+	// TTL is how long code can be used after it's sent
+	TTL time.Duration
+	// MaxAttempts is number of times code can be checked before a new one should be requested
+	MaxAttempts int64
+	// ResendInterval is minimal time between codes sent to the same phone number
+	ResendInterval time.Duration
+}
+
 type TTLStrategy struct {
 	AccessTokenTTLs map[domain.Role]time.Duration
 	RefreshTokenTTL map[domain.Role]time.Duration
 }
 
 type authService struct {
-	tokenProvider  auth.TokenProvider
-	passwordHasher Hasher
-	userService    User
-	ttlStrategy    TTLStrategy
+	tokenProvider     auth.TokenProvider
+	passwordHasher    Hasher
+	userService       User
+	otpStorage        storage.OTP
+	sessionStorage    storage.Session
+	revocationService Revocation
+	smsSender         SMSSender
+	ttlStrategy       TTLStrategy
+	otpConfig         OTPConfig
+	dummyPasswordHash string
 }
 
-func NewAuthService(userService User, tokenProvider auth.TokenProvider, hasher Hasher, ttlStrategy TTLStrategy) Auth {
+func NewAuthService(userService User,
+	otpStorage storage.OTP,
+	sessionStorage storage.Session,
+	revocationService Revocation,
+	tokenProvider auth.TokenProvider,
+	hasher Hasher,
+	smsSender SMSSender,
+	ttlStrategy TTLStrategy,
+	otpConfig OTPConfig) Auth {
+	if otpConfig.TTL <= 0 {
+		otpConfig.TTL = DefaultOTPTTL
+	}
+	if otpConfig.MaxAttempts <= 0 {
+		otpConfig.MaxAttempts = DefaultOTPMaxAttempts
+	}
+	if otpConfig.ResendInterval <= 0 {
+		otpConfig.ResendInterval = DefaultOTPResendInterval
+	}
+	// Hashing fails only if system random source does. Verify rejects empty hash without hashing then.
+	dummyPasswordHash, _ := hasher.Hash(dummyPassword)
 	return &authService{
-		userService:    userService,
-		tokenProvider:  tokenProvider,
-		passwordHasher: hasher,
-		ttlStrategy:    ttlStrategy,
+		userService:       userService,
+		otpStorage:        otpStorage,
+		sessionStorage:    sessionStorage,
+		revocationService: revocationService,
+		tokenProvider:     tokenProvider,
+		passwordHasher:    hasher,
+		smsSender:         smsSender,
+		ttlStrategy:       ttlStrategy,
+		otpConfig:         otpConfig,
+		dummyPasswordHash: dummyPasswordHash,
 	}
 }
 
 func (a authService) RegisterAdmin(ctx context.Context, dto dto.RegisterAdminDTO) (string, error) {
-	// Create an admin without session. It's acquired via login.
+	passwordHash, err := a.passwordHasher.Hash(dto.Password)
+	if err != nil {
+		return "", err
+	}
+
+	// Create an admin without sessions. They're acquired via login.
 	admin := domain.Admin{
 		Login:    dto.Login,
 		Role:     domain.RoleAdmin,
-		Password: a.passwordHasher.Hash(dto.Password),
+		Password: passwordHash,
 	}
 
 	adminID, err := a.userService.SaveAdmin(ctx, admin)
@@ -51,11 +117,36 @@ func (a authService) RegisterAdmin(ctx context.Context, dto dto.RegisterAdminDTO
 	return adminID, nil
 }
 
+func (a authService) RegisterWorker(ctx context.Context, dto dto.RegisterWorkerDTO) (string, error) {
+	passwordHash, err := a.passwordHasher.Hash(dto.Password)
+	if err != nil {
+		return "", err
+	}
+
+	// Worker is created without sessions as well as admin
+	worker := domain.Worker{
+		Name:      dto.Name,
+		Login:     dto.Login,
+		Password:  passwordHash,
+		Role:      domain.RoleWorker,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	return a.userService.SaveWorker(ctx, worker)
+}
+
+func (a authService) ResetWorkerPassword(ctx context.Context, workerID, password string) error {
+	passwordHash, err := a.passwordHasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	return a.userService.UpdateWorkerPassword(ctx, workerID, passwordHash)
+}
+
 func (a authService) RegisterCustomer(ctx context.Context, dto dto.RegisterCustomerDTO) (string, error) {
 	customer := domain.Customer{
 		PhoneNumber: dto.PhoneNumber,
 		Role:        domain.RoleCustomer,
-		Session:     nil,
 	}
 	if dto.DeliveryAddress != nil {
 		customer.DeliveryAddress = dto.DeliveryAddress
@@ -67,37 +158,166 @@ func (a authService) RegisterCustomer(ctx context.Context, dto dto.RegisterCusto
 	return a.userService.SaveCustomer(ctx, customer)
 }
 
+func (a authService) RequestCustomerOTP(ctx context.Context, phoneNumber string) error {
+	code, err := newOTPCode()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	otp := domain.OTP{
+		PhoneNumber: phoneNumber,
+		CodeHash:    hashOTPCode(phoneNumber, code),
+		SentAt:      now,
+		ExpiresAt:   now.Add(a.otpConfig.TTL),
+	}
+	if err := a.otpStorage.Save(ctx, otp, now.Add(-a.otpConfig.ResendInterval)); err != nil {
+		return err
+	}
+
+	if err := a.smsSender.Send(ctx, phoneNumber, fmt.Sprintf(otpText, code)); err != nil {
+		// Code has never reached customer, so let them request another one right away
+		if deleteErr := a.otpStorage.Delete(ctx, phoneNumber, otp.CodeHash); deleteErr != nil {
+			logger.Get().Error("delete unsent otp",
+				zap.String("phoneNumber", phoneNumber),
+				zap.Error(deleteErr),
+			)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (a authService) LoginCustomer(ctx context.Context, loginDto dto.LoginCustomerDTO) (auth.Pair, error) {
+	otp, err := a.otpStorage.Get(ctx, loginDto.PhoneNumber)
+	if err != nil {
+		return auth.Pair{}, err
+	}
+	// Expired codes are removed by mongo with a delay
+	if otp.IsExpiredAt(time.Now().UTC()) {
+		return auth.Pair{}, domain.ErrOTPNotFound
+	}
+
+	// Attempt is counted before the code is checked, so that parallel guesses can't exceed the limit
+	if err := a.otpStorage.CountAttempt(ctx, otp.PhoneNumber, otp.CodeHash, a.otpConfig.MaxAttempts); err != nil {
+		return auth.Pair{}, err
+	}
+	codeHash := hashOTPCode(loginDto.PhoneNumber, loginDto.Code)
+	if subtle.ConstantTimeCompare([]byte(codeHash), []byte(otp.CodeHash)) != 1 {
+		return auth.Pair{}, domain.ErrInvalidOTP
+	}
+	// Code can be used only once
+	if err := a.otpStorage.Delete(ctx, otp.PhoneNumber, otp.CodeHash); err != nil {
+		return auth.Pair{}, err
+	}
+
+	var customerID string
+	customer, err := a.userService.GetCustomerByPhoneNumber(ctx, loginDto.PhoneNumber)
+	switch {
+	case err == nil:
+		customerID = customer.UserID.Hex()
+	case errors.Is(err, domain.ErrCustomerNotFound):
+		// Customer who has never ordered is registered on the first login
+		customerID, err = a.RegisterCustomer(ctx, dto.RegisterCustomerDTO{
+			PhoneNumber: loginDto.PhoneNumber,
+		})
+		if err != nil {
+			return auth.Pair{}, err
+		}
+	default:
+		return auth.Pair{}, err
+	}
+
+	return a.newSession(ctx, customerID, domain.RoleCustomer, loginDto.Client)
+}
+
 func (a authService) LoginAdmin(ctx context.Context, loginDto dto.LoginAdminDTO) (auth.Pair, error) {
-	var (
-		accessTokenTTL  = a.ttlStrategy.AccessTokenTTLs[domain.RoleAdmin]
-		refreshTokenTTL = a.ttlStrategy.RefreshTokenTTL[domain.RoleAdmin]
-	)
 	admin, err := a.userService.GetAdminByLogin(ctx, loginDto.Login)
 	if err != nil {
+		if errors.Is(err, domain.ErrAdminNotFound) {
+			return auth.Pair{}, a.rejectUnknownLogin(loginDto.Password)
+		}
 		return auth.Pair{}, err
 	}
 
-	hashedDTOPassword := a.passwordHasher.Hash(loginDto.Password)
-	if hashedDTOPassword != admin.Password {
-		return auth.Pair{}, ErrInvalidPassword
+	if err := a.checkPassword(ctx, admin.UserID.Hex(), domain.RoleAdmin, loginDto.Password, admin.Password); err != nil {
+		return auth.Pair{}, err
 	}
 
-	tokens, err := a.tokenProvider.GenerateNewPairWithTTL(auth.UserAuth{
-		Role:   domain.RoleAdmin,
-		UserID: admin.UserID.Hex(),
-	}, accessTokenTTL)
+	return a.newSession(ctx, admin.UserID.Hex(), domain.RoleAdmin, loginDto.Client)
+}
+
+func (a authService) LoginWorker(ctx context.Context, loginDto dto.LoginWorkerDTO) (auth.Pair, error) {
+	worker, err := a.userService.GetWorkerByLogin(ctx, loginDto.Login)
 	if err != nil {
+		if errors.Is(err, domain.ErrWorkerNotFound) {
+			return auth.Pair{}, a.rejectUnknownLogin(loginDto.Password)
+		}
 		return auth.Pair{}, err
 	}
 
-	session := domain.Session{
-		RefreshToken: tokens.RefreshToken,
-		ExpiresAt:    domain.NewExpiresAt(refreshTokenTTL),
+	if err := a.checkPassword(ctx, worker.UserID.Hex(), domain.RoleWorker, loginDto.Password, worker.Password); err != nil {
+		return auth.Pair{}, err
+	}
+	// Checked after password on purpose, so that only the owner learns that worker is disabled
+	if worker.IsDisabled {
+		return auth.Pair{}, domain.ErrWorkerDisabled
+	}
+
+	return a.newSession(ctx, worker.UserID.Hex(), domain.RoleWorker, loginDto.Client)
+}
+
+// rejectUnknownLogin answers the same way wrong password is answered. Password is verified
+// against dummy hash, so that response time doesn't tell whether login exists.
+func (a authService) rejectUnknownLogin(password string) error {
+	a.passwordHasher.Verify(password, a.dummyPasswordHash)
+	return ErrInvalidPassword
+}
+
+// checkPassword verifies password of admin or worker. Hash made with outdated algorithm
+// or parameters is replaced, failure to do so doesn't prevent login.
+func (a authService) checkPassword(ctx context.Context, userID string, role domain.Role, password, passwordHash string) error {
+	ok, needsRehash := a.passwordHasher.Verify(password, passwordHash)
+	if !ok {
+		return ErrInvalidPassword
+	}
+	if !needsRehash {
+		return nil
+	}
+
+	newPasswordHash, err := a.passwordHasher.Hash(password)
+	if err == nil {
+		err = a.userService.UpdatePasswordHash(ctx, userID, role, newPasswordHash)
 	}
-	err = a.userService.SaveSession(ctx, dto.SaveSessionDTO{
-		UserID:  admin.UserID.Hex(),
-		Role:    admin.Role,
-		Session: session,
+	if err != nil {
+		logger.Get().Error("rehash password",
+			zap.String("userID", userID),
+			zap.String("role", role.String()),
+			zap.Error(err),
+		)
+	}
+	return nil
+}
+
+func (a authService) RefreshTokens(ctx context.Context, refreshDto dto.RefreshTokensDTO) (auth.Pair, error) {
+	session, err := a.sessionStorage.GetByRefreshToken(ctx, refreshDto.Role, refreshDto.UserID, refreshDto.RefreshToken)
+	if err != nil {
+		return auth.Pair{}, err
+	}
+
+	tokens, expiresAt, err := a.generateTokens(session.UserID, session.Role, session.SessionID.Hex())
+	if err != nil {
+		return auth.Pair{}, err
+	}
+
+	// Session is updated only if it still holds the refresh token, so that it can't be used twice
+	err = a.sessionStorage.Rotate(ctx, dto.RotateSessionDTO{
+		SessionID:       session.SessionID.Hex(),
+		RefreshToken:    refreshDto.RefreshToken,
+		NewRefreshToken: tokens.RefreshToken,
+		ExpiresAt:       expiresAt,
+		Client:          refreshDto.Client,
 	})
 	if err != nil {
 		return auth.Pair{}, err
@@ -106,33 +326,45 @@ func (a authService) LoginAdmin(ctx context.Context, loginDto dto.LoginAdminDTO)
 	return tokens, nil
 }
 
-func (a authService) RefreshAdminToken(ctx context.Context, adminID, token string) (auth.Pair, error) {
-	var (
-		accessTokenTTL  = a.ttlStrategy.AccessTokenTTLs[domain.RoleAdmin]
-		refreshTokenTTL = a.ttlStrategy.RefreshTokenTTL[domain.RoleAdmin]
-	)
-	admin, err := a.userService.GetAdminByRefreshToken(ctx, adminID, token)
-	if err != nil {
-		return auth.Pair{}, err
+func (a authService) Logout(ctx context.Context, claims auth.Claims) error {
+	if err := a.revocationService.RevokeToken(ctx, claims); err != nil {
+		return err
+	}
+	// Token may have been issued before sessions were tracked per device
+	if claims.SessionID == "" {
+		return nil
+	}
+	err := a.sessionStorage.Delete(ctx, claims.Role, claims.UserID, claims.SessionID)
+	if err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+		return err
 	}
+	return nil
+}
 
-	tokens, err := a.tokenProvider.GenerateNewPairWithTTL(auth.UserAuth{
-		Role:   domain.RoleAdmin,
-		UserID: admin.UserID.Hex(),
-	}, accessTokenTTL)
+func (a authService) JWKS() auth.JWKS {
+	return a.tokenProvider.JWKS()
+}
+
+// newSession generates token pair and starts a session of user on the client's device
+func (a authService) newSession(ctx context.Context, userID string, role domain.Role, client domain.SessionClient) (auth.Pair, error) {
+	sessionID := primitive.NewObjectID()
+	tokens, expiresAt, err := a.generateTokens(userID, role, sessionID.Hex())
 	if err != nil {
 		return auth.Pair{}, err
 	}
 
-	session := domain.Session{
+	now := time.Now().UTC()
+	err = a.sessionStorage.Save(ctx, domain.Session{
+		SessionID:    sessionID,
+		UserID:       userID,
+		Role:         role,
 		RefreshToken: tokens.RefreshToken,
-		ExpiresAt:    domain.NewExpiresAt(refreshTokenTTL),
-	}
-
-	err = a.userService.SaveSession(ctx, dto.SaveSessionDTO{
-		UserID:  admin.UserID.Hex(),
-		Role:    admin.Role,
-		Session: session,
+		Device:       client.Device,
+		IP:           client.IP,
+		UserAgent:    client.UserAgent,
+		CreatedAt:    now,
+		LastUsedAt:   now,
+		ExpiresAt:    expiresAt,
 	})
 	if err != nil {
 		return auth.Pair{}, err
@@ -140,3 +372,36 @@ func (a authService) RefreshAdminToken(ctx context.Context, adminID, token strin
 
 	return tokens, nil
 }
+
+// generateTokens returns token pair of the session and expiration time of its refresh token with role's TTLs
+func (a authService) generateTokens(userID string, role domain.Role, sessionID string) (auth.Pair, time.Time, error) {
+	var (
+		accessTokenTTL  = a.ttlStrategy.AccessTokenTTLs[role]
+		refreshTokenTTL = a.ttlStrategy.RefreshTokenTTL[role]
+	)
+	tokens, err := a.tokenProvider.GenerateNewPairWithTTL(auth.UserAuth{
+		Role:      role,
+		UserID:    userID,
+		SessionID: sessionID,
+	}, accessTokenTTL)
+	if err != nil {
+		return auth.Pair{}, time.Time{}, err
+	}
+
+	return tokens, domain.NewExpiresAt(refreshTokenTTL), nil
+}
+
+func newOTPCode() (string, error) {
+	upperBound := new(big.Int).Exp(big.NewInt(10), big.NewInt(domain.OTPCodeLength), nil)
+	n, err := rand.Int(rand.Reader, upperBound)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", domain.OTPCodeLength, n), nil
+}
+
+// hashOTPCode salts code with phone number, so that the same code has different hashes for different customers
+func hashOTPCode(phoneNumber, code string) string {
+	sum := sha256.Sum256([]byte(phoneNumber + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,587 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	mock_service "github.com/sonyamoonglade/sancho-backend/internal/services/mocks"
+	mock_storage "github.com/sonyamoonglade/sancho-backend/internal/storages/mocks"
+	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
+	"github.com/sonyamoonglade/sancho-backend/pkg/hash"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const phoneNumber = "+79128557826"
+
+// Cheap parameters keep tests fast
+var testHasher = hash.NewArgon2idHasher(hash.Argon2idParams{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+})
+
+func TestRequestCustomerOTP(t *testing.T) {
+	t.Run("should save hashed code and send it", func(t *testing.T) {
+		authService, _, otpStorage, smsSender := getAuthService(t)
+		var saved domain.OTP
+
+		otpStorage.
+			EXPECT().
+			Save(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, otp domain.OTP, sentBefore time.Time) error {
+				saved = otp
+				require.True(t, otp.SentAt.Add(-DefaultOTPResendInterval).Equal(sentBefore))
+				require.True(t, otp.SentAt.Add(DefaultOTPTTL).Equal(otp.ExpiresAt))
+				return nil
+			})
+		smsSender.
+			EXPECT().
+			Send(gomock.Any(), phoneNumber, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, text string) error {
+				code := text[strings.LastIndex(text, " ")+1:]
+				require.Len(t, code, domain.OTPCodeLength)
+				require.Equal(t, hashOTPCode(phoneNumber, code), saved.CodeHash)
+				return nil
+			})
+
+		err := authService.RequestCustomerOTP(context.Background(), phoneNumber)
+		require.NoError(t, err)
+		require.Equal(t, phoneNumber, saved.PhoneNumber)
+		require.Zero(t, saved.Attempts)
+	})
+
+	t.Run("should not send code because previous one has been sent recently", func(t *testing.T) {
+		authService, _, otpStorage, _ := getAuthService(t)
+
+		otpStorage.
+			EXPECT().
+			Save(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(domain.ErrOTPResendTooSoon)
+
+		err := authService.RequestCustomerOTP(context.Background(), phoneNumber)
+		require.ErrorIs(t, err, domain.ErrOTPResendTooSoon)
+	})
+
+	t.Run("should delete code because sending failed", func(t *testing.T) {
+		authService, _, otpStorage, smsSender := getAuthService(t)
+		var saved domain.OTP
+		sendErr := errors.New("provider is down")
+
+		otpStorage.
+			EXPECT().
+			Save(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, otp domain.OTP, _ time.Time) error {
+				saved = otp
+				return nil
+			})
+		smsSender.
+			EXPECT().
+			Send(gomock.Any(), phoneNumber, gomock.Any()).
+			Return(sendErr)
+		otpStorage.
+			EXPECT().
+			Delete(gomock.Any(), phoneNumber, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, codeHash string) error {
+				require.Equal(t, saved.CodeHash, codeHash)
+				return nil
+			})
+
+		err := authService.RequestCustomerOTP(context.Background(), phoneNumber)
+		require.ErrorIs(t, err, sendErr)
+	})
+}
+
+func TestLoginCustomer(t *testing.T) {
+	const code = "012345"
+	client := domain.SessionClient{
+		Device:    "Pixel 7",
+		IP:        "10.0.0.1",
+		UserAgent: "okhttp/4.10.0",
+	}
+	getOTP := func() domain.OTP {
+		now := time.Now().UTC()
+		return domain.OTP{
+			PhoneNumber: phoneNumber,
+			CodeHash:    hashOTPCode(phoneNumber, code),
+			Attempts:    1,
+			SentAt:      now,
+			ExpiresAt:   now.Add(DefaultOTPTTL),
+		}
+	}
+
+	t.Run("should log existing customer in", func(t *testing.T) {
+		authService, userService, otpStorage, _ := getAuthService(t)
+		var (
+			otp      = getOTP()
+			customer = domain.Customer{UserID: primitive.NewObjectID(), PhoneNumber: phoneNumber, Role: domain.RoleCustomer}
+		)
+
+		otpStorage.EXPECT().Get(gomock.Any(), phoneNumber).Return(otp, nil)
+		otpStorage.EXPECT().CountAttempt(gomock.Any(), phoneNumber, otp.CodeHash, DefaultOTPMaxAttempts).Return(nil)
+		otpStorage.EXPECT().Delete(gomock.Any(), phoneNumber, otp.CodeHash).Return(nil)
+		userService.EXPECT().GetCustomerByPhoneNumber(gomock.Any(), phoneNumber).Return(customer, nil)
+		var saved domain.Session
+		getSessionStorage(authService).
+			EXPECT().
+			Save(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, session domain.Session) error {
+				saved = session
+				return nil
+			})
+
+		tokens, err := authService.LoginCustomer(context.Background(), dto.LoginCustomerDTO{
+			PhoneNumber: phoneNumber,
+			Code:        code,
+			Client:      client,
+		})
+		require.NoError(t, err)
+
+		userAuth, err := authService.tokenProvider.ParseAndValidate(tokens.AccessToken)
+		require.NoError(t, err)
+		require.Equal(t, domain.RoleCustomer, userAuth.Role)
+		require.Equal(t, customer.UserID.Hex(), userAuth.UserID)
+
+		require.Equal(t, saved.SessionID.Hex(), userAuth.SessionID)
+		require.Equal(t, customer.UserID.Hex(), saved.UserID)
+		require.Equal(t, domain.RoleCustomer, saved.Role)
+		require.Equal(t, tokens.RefreshToken, saved.RefreshToken)
+		require.Equal(t, client.Device, saved.Device)
+		require.Equal(t, client.IP, saved.IP)
+		require.Equal(t, client.UserAgent, saved.UserAgent)
+		require.Equal(t, saved.CreatedAt, saved.LastUsedAt)
+	})
+
+	t.Run("should register customer on the first login", func(t *testing.T) {
+		authService, userService, otpStorage, _ := getAuthService(t)
+		var (
+			otp        = getOTP()
+			customerID = primitive.NewObjectID().Hex()
+		)
+
+		otpStorage.EXPECT().Get(gomock.Any(), phoneNumber).Return(otp, nil)
+		otpStorage.EXPECT().CountAttempt(gomock.Any(), phoneNumber, otp.CodeHash, DefaultOTPMaxAttempts).Return(nil)
+		otpStorage.EXPECT().Delete(gomock.Any(), phoneNumber, otp.CodeHash).Return(nil)
+		userService.EXPECT().GetCustomerByPhoneNumber(gomock.Any(), phoneNumber).Return(domain.Customer{}, domain.ErrCustomerNotFound)
+		userService.
+			EXPECT().
+			SaveCustomer(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, customer domain.Customer) (string, error) {
+				require.Equal(t, phoneNumber, customer.PhoneNumber)
+				require.Equal(t, domain.RoleCustomer, customer.Role)
+				return customerID, nil
+			})
+		getSessionStorage(authService).EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+		tokens, err := authService.LoginCustomer(context.Background(), dto.LoginCustomerDTO{
+			PhoneNumber: phoneNumber,
+			Code:        code,
+		})
+		require.NoError(t, err)
+
+		userAuth, err := authService.tokenProvider.ParseAndValidate(tokens.AccessToken)
+		require.NoError(t, err)
+		require.Equal(t, customerID, userAuth.UserID)
+	})
+
+	t.Run("should return ErrInvalidOTP and count attempt", func(t *testing.T) {
+		authService, _, otpStorage, _ := getAuthService(t)
+		otp := getOTP()
+
+		otpStorage.EXPECT().Get(gomock.Any(), phoneNumber).Return(otp, nil)
+		otpStorage.EXPECT().CountAttempt(gomock.Any(), phoneNumber, otp.CodeHash, DefaultOTPMaxAttempts).Return(nil)
+
+		_, err := authService.LoginCustomer(context.Background(), dto.LoginCustomerDTO{
+			PhoneNumber: phoneNumber,
+			Code:        "543210",
+		})
+		require.ErrorIs(t, err, domain.ErrInvalidOTP)
+	})
+
+	t.Run("should return ErrOTPAttemptsExceeded", func(t *testing.T) {
+		authService, _, otpStorage, _ := getAuthService(t)
+		otp := getOTP()
+
+		otpStorage.EXPECT().Get(gomock.Any(), phoneNumber).Return(otp, nil)
+		otpStorage.
+			EXPECT().
+			CountAttempt(gomock.Any(), phoneNumber, otp.CodeHash, DefaultOTPMaxAttempts).
+			Return(domain.ErrOTPAttemptsExceeded)
+
+		// Even the right code is rejected
+		_, err := authService.LoginCustomer(context.Background(), dto.LoginCustomerDTO{
+			PhoneNumber: phoneNumber,
+			Code:        code,
+		})
+		require.ErrorIs(t, err, domain.ErrOTPAttemptsExceeded)
+	})
+
+	t.Run("should return ErrOTPNotFound because code has expired", func(t *testing.T) {
+		authService, _, otpStorage, _ := getAuthService(t)
+		otp := getOTP()
+		otp.ExpiresAt = time.Now().UTC().Add(-time.Second)
+
+		otpStorage.EXPECT().Get(gomock.Any(), phoneNumber).Return(otp, nil)
+
+		_, err := authService.LoginCustomer(context.Background(), dto.LoginCustomerDTO{
+			PhoneNumber: phoneNumber,
+			Code:        code,
+		})
+		require.ErrorIs(t, err, domain.ErrOTPNotFound)
+	})
+}
+
+func TestRefreshTokens(t *testing.T) {
+	client := domain.SessionClient{
+		IP:        "10.0.0.2",
+		UserAgent: "okhttp/4.10.0",
+	}
+
+	t.Run("should rotate session", func(t *testing.T) {
+		authService, _, _, _ := getAuthService(t)
+		sessionStorage := getSessionStorage(authService)
+		session := domain.Session{
+			SessionID:    primitive.NewObjectID(),
+			UserID:       primitive.NewObjectID().Hex(),
+			Role:         domain.RoleCustomer,
+			RefreshToken: "refresh token",
+		}
+
+		sessionStorage.
+			EXPECT().
+			GetByRefreshToken(gomock.Any(), domain.RoleCustomer, session.UserID, session.RefreshToken).
+			Return(session, nil)
+		sessionStorage.
+			EXPECT().
+			Rotate(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, rotateDTO dto.RotateSessionDTO) error {
+				require.Equal(t, session.SessionID.Hex(), rotateDTO.SessionID)
+				require.Equal(t, session.RefreshToken, rotateDTO.RefreshToken)
+				require.NotEqual(t, session.RefreshToken, rotateDTO.NewRefreshToken)
+				require.Equal(t, client, rotateDTO.Client)
+				require.WithinDuration(t, time.Now().UTC().Add(time.Hour), rotateDTO.ExpiresAt, time.Minute)
+				return nil
+			})
+
+		tokens, err := authService.RefreshTokens(context.Background(), dto.RefreshTokensDTO{
+			Role:         domain.RoleCustomer,
+			UserID:       session.UserID,
+			RefreshToken: session.RefreshToken,
+			Client:       client,
+		})
+		require.NoError(t, err)
+
+		userAuth, err := authService.tokenProvider.ParseAndValidate(tokens.AccessToken)
+		require.NoError(t, err)
+		require.Equal(t, session.UserID, userAuth.UserID)
+		require.Equal(t, domain.RoleCustomer, userAuth.Role)
+		require.Equal(t, session.SessionID.Hex(), userAuth.SessionID)
+	})
+
+	t.Run("should return ErrSessionNotFound because session has been revoked", func(t *testing.T) {
+		authService, _, _, _ := getAuthService(t)
+
+		getSessionStorage(authService).
+			EXPECT().
+			GetByRefreshToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(domain.Session{}, domain.ErrSessionNotFound)
+
+		_, err := authService.RefreshTokens(context.Background(), dto.RefreshTokensDTO{
+			Role:         domain.RoleCustomer,
+			UserID:       primitive.NewObjectID().Hex(),
+			RefreshToken: "refresh token",
+		})
+		require.ErrorIs(t, err, domain.ErrSessionNotFound)
+	})
+
+	t.Run("should return ErrSessionNotFound because token has been used concurrently", func(t *testing.T) {
+		authService, _, _, _ := getAuthService(t)
+		sessionStorage := getSessionStorage(authService)
+		session := domain.Session{
+			SessionID:    primitive.NewObjectID(),
+			UserID:       primitive.NewObjectID().Hex(),
+			Role:         domain.RoleCustomer,
+			RefreshToken: "refresh token",
+		}
+
+		sessionStorage.EXPECT().GetByRefreshToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(session, nil)
+		sessionStorage.EXPECT().Rotate(gomock.Any(), gomock.Any()).Return(domain.ErrSessionNotFound)
+
+		_, err := authService.RefreshTokens(context.Background(), dto.RefreshTokensDTO{
+			Role:         domain.RoleCustomer,
+			UserID:       session.UserID,
+			RefreshToken: session.RefreshToken,
+		})
+		require.ErrorIs(t, err, domain.ErrSessionNotFound)
+	})
+}
+
+func TestLogout(t *testing.T) {
+	claims := auth.Claims{
+		UserAuth: auth.UserAuth{
+			Role:      domain.RoleWorker,
+			UserID:    primitive.NewObjectID().Hex(),
+			SessionID: primitive.NewObjectID().Hex(),
+		},
+		TokenID:   "jti",
+		ExpiresAt: auth.NewNumericDate(time.Now().Add(time.Minute)),
+	}
+
+	t.Run("should revoke token and end its session", func(t *testing.T) {
+		authService, _, _, _ := getAuthService(t)
+
+		getRevocationService(authService).EXPECT().RevokeToken(gomock.Any(), claims).Return(nil)
+		getSessionStorage(authService).
+			EXPECT().
+			Delete(gomock.Any(), domain.RoleWorker, claims.UserID, claims.SessionID).
+			Return(nil)
+
+		require.NoError(t, authService.Logout(context.Background(), claims))
+	})
+
+	t.Run("should log out even though session has ended already", func(t *testing.T) {
+		authService, _, _, _ := getAuthService(t)
+
+		getRevocationService(authService).EXPECT().RevokeToken(gomock.Any(), claims).Return(nil)
+		getSessionStorage(authService).
+			EXPECT().
+			Delete(gomock.Any(), domain.RoleWorker, claims.UserID, claims.SessionID).
+			Return(domain.ErrSessionNotFound)
+
+		require.NoError(t, authService.Logout(context.Background(), claims))
+	})
+
+	t.Run("should only revoke token without session", func(t *testing.T) {
+		authService, _, _, _ := getAuthService(t)
+		withoutSession := claims
+		withoutSession.SessionID = ""
+
+		getRevocationService(authService).EXPECT().RevokeToken(gomock.Any(), withoutSession).Return(nil)
+
+		require.NoError(t, authService.Logout(context.Background(), withoutSession))
+	})
+}
+
+func TestLoginWorker(t *testing.T) {
+	const password = "kasdsjd*&1231mz"
+	getWorker := func() domain.Worker {
+		passwordHash, err := testHasher.Hash(password)
+		require.NoError(t, err)
+		return domain.Worker{
+			UserID:   primitive.NewObjectID(),
+			Login:    "jqkweixuch",
+			Password: passwordHash,
+			Role:     domain.RoleWorker,
+		}
+	}
+
+	t.Run("should log worker in", func(t *testing.T) {
+		authService, userService, _, _ := getAuthService(t)
+		worker := getWorker()
+
+		userService.EXPECT().GetWorkerByLogin(gomock.Any(), worker.Login).Return(worker, nil)
+		getSessionStorage(authService).
+			EXPECT().
+			Save(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, session domain.Session) error {
+				require.Equal(t, worker.UserID.Hex(), session.UserID)
+				require.Equal(t, domain.RoleWorker, session.Role)
+				// Worker's TTL from the strategy
+				require.WithinDuration(t, time.Now().UTC().Add(time.Hour*18), session.ExpiresAt, time.Minute)
+				return nil
+			})
+
+		tokens, err := authService.LoginWorker(context.Background(), dto.LoginWorkerDTO{
+			Login:    worker.Login,
+			Password: password,
+		})
+		require.NoError(t, err)
+
+		userAuth, err := authService.tokenProvider.ParseAndValidate(tokens.AccessToken)
+		require.NoError(t, err)
+		require.Equal(t, domain.RoleWorker, userAuth.Role)
+	})
+
+	t.Run("should rehash legacy SHA-1 password", func(t *testing.T) {
+		authService, userService, _, _ := getAuthService(t)
+		worker := getWorker()
+		legacyHash, err := hash.NewSHA1Hasher().Hash(password)
+		require.NoError(t, err)
+		worker.Password = legacyHash
+
+		userService.EXPECT().GetWorkerByLogin(gomock.Any(), worker.Login).Return(worker, nil)
+		userService.
+			EXPECT().
+			UpdatePasswordHash(gomock.Any(), worker.UserID.Hex(), domain.RoleWorker, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, _ domain.Role, passwordHash string) error {
+				ok, needsRehash := testHasher.Verify(password, passwordHash)
+				require.True(t, ok)
+				require.False(t, needsRehash)
+				return nil
+			})
+		getSessionStorage(authService).EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err = authService.LoginWorker(context.Background(), dto.LoginWorkerDTO{
+			Login:    worker.Login,
+			Password: password,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("should log in even though rehash has failed", func(t *testing.T) {
+		authService, userService, _, _ := getAuthService(t)
+		worker := getWorker()
+		legacyHash, err := hash.NewSHA1Hasher().Hash(password)
+		require.NoError(t, err)
+		worker.Password = legacyHash
+
+		userService.EXPECT().GetWorkerByLogin(gomock.Any(), worker.Login).Return(worker, nil)
+		userService.
+			EXPECT().
+			UpdatePasswordHash(gomock.Any(), worker.UserID.Hex(), domain.RoleWorker, gomock.Any()).
+			Return(errors.New("mongo is down"))
+		getSessionStorage(authService).EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err = authService.LoginWorker(context.Background(), dto.LoginWorkerDTO{
+			Login:    worker.Login,
+			Password: password,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("should return ErrInvalidPassword", func(t *testing.T) {
+		authService, userService, _, _ := getAuthService(t)
+		worker := getWorker()
+
+		userService.EXPECT().GetWorkerByLogin(gomock.Any(), worker.Login).Return(worker, nil)
+
+		_, err := authService.LoginWorker(context.Background(), dto.LoginWorkerDTO{
+			Login:    worker.Login,
+			Password: "wrong password",
+		})
+		require.ErrorIs(t, err, ErrInvalidPassword)
+	})
+
+	t.Run("should return ErrWorkerDisabled", func(t *testing.T) {
+		authService, userService, _, _ := getAuthService(t)
+		worker := getWorker()
+		worker.IsDisabled = true
+
+		userService.EXPECT().GetWorkerByLogin(gomock.Any(), worker.Login).Return(worker, nil)
+
+		_, err := authService.LoginWorker(context.Background(), dto.LoginWorkerDTO{
+			Login:    worker.Login,
+			Password: password,
+		})
+		require.ErrorIs(t, err, domain.ErrWorkerDisabled)
+	})
+
+	t.Run("should return ErrInvalidPassword instead of ErrWorkerDisabled for wrong password", func(t *testing.T) {
+		authService, userService, _, _ := getAuthService(t)
+		worker := getWorker()
+		worker.IsDisabled = true
+
+		userService.EXPECT().GetWorkerByLogin(gomock.Any(), worker.Login).Return(worker, nil)
+
+		_, err := authService.LoginWorker(context.Background(), dto.LoginWorkerDTO{
+			Login:    worker.Login,
+			Password: "wrong password",
+		})
+		require.ErrorIs(t, err, ErrInvalidPassword)
+	})
+
+	t.Run("should return ErrInvalidPassword because login is not found", func(t *testing.T) {
+		authService, userService, _, _ := getAuthService(t)
+
+		userService.EXPECT().GetWorkerByLogin(gomock.Any(), "unknown").Return(domain.Worker{}, domain.ErrWorkerNotFound)
+
+		_, err := authService.LoginWorker(context.Background(), dto.LoginWorkerDTO{
+			Login:    "unknown",
+			Password: password,
+		})
+		require.ErrorIs(t, err, ErrInvalidPassword)
+		require.NotErrorIs(t, err, domain.ErrWorkerNotFound)
+	})
+}
+
+func TestLoginAdmin(t *testing.T) {
+	t.Run("should return ErrInvalidPassword because login is not found", func(t *testing.T) {
+		authService, userService, _, _ := getAuthService(t)
+
+		userService.EXPECT().GetAdminByLogin(gomock.Any(), "unknown").Return(domain.Admin{}, domain.ErrAdminNotFound)
+
+		_, err := authService.LoginAdmin(context.Background(), dto.LoginAdminDTO{
+			Login:    "unknown",
+			Password: "kasdsjd*&1231mz",
+		})
+		require.ErrorIs(t, err, ErrInvalidPassword)
+		require.NotErrorIs(t, err, domain.ErrAdminNotFound)
+	})
+}
+
+func TestRegisterWorker(t *testing.T) {
+	authService, userService, _, _ := getAuthService(t)
+	var (
+		workerID = primitive.NewObjectID().Hex()
+		password = "kasdsjd*&1231mz"
+	)
+
+	userService.
+		EXPECT().
+		SaveWorker(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, worker domain.Worker) (string, error) {
+			require.Equal(t, "jqkweixuch", worker.Login)
+			require.Equal(t, domain.RoleWorker, worker.Role)
+			ok, _ := testHasher.Verify(password, worker.Password)
+			require.True(t, ok)
+			require.False(t, worker.IsDisabled)
+			return workerID, nil
+		})
+
+	createdID, err := authService.RegisterWorker(context.Background(), dto.RegisterWorkerDTO{
+		Name:     "George",
+		Login:    "jqkweixuch",
+		Password: password,
+	})
+	require.NoError(t, err)
+	require.Equal(t, workerID, createdID)
+}
+
+func getAuthService(t *testing.T) (*authService, *mock_service.MockUser, *mock_storage.MockOTP, *mock_service.MockSMSSender) {
+	ctrl := gomock.NewController(t)
+	userService := mock_service.NewMockUser(ctrl)
+	otpStorage := mock_storage.NewMockOTP(ctrl)
+	sessionStorage := mock_storage.NewMockSession(ctrl)
+	revocationService := mock_service.NewMockRevocation(ctrl)
+	smsSender := mock_service.NewMockSMSSender(ctrl)
+	tokenProvider, err := auth.NewProvider(time.Minute, []byte("signing key"), "test")
+	require.NoError(t, err)
+	ttlStrategy := TTLStrategy{
+		AccessTokenTTLs: map[domain.Role]time.Duration{
+			domain.RoleCustomer: time.Minute,
+			domain.RoleWorker:   time.Minute,
+		},
+		RefreshTokenTTL: map[domain.Role]time.Duration{
+			domain.RoleCustomer: time.Hour,
+			domain.RoleWorker:   time.Hour * 18,
+		},
+	}
+	authSvc := NewAuthService(userService, otpStorage, sessionStorage, revocationService, tokenProvider, testHasher, smsSender, ttlStrategy, OTPConfig{})
+	return authSvc.(*authService), userService, otpStorage, smsSender
+}
+
+func getSessionStorage(authService *authService) *mock_storage.MockSession {
+	return authService.sessionStorage.(*mock_storage.MockSession)
+}
+
+func getRevocationService(authService *authService) *mock_service.MockRevocation {
+	return authService.revocationService.(*mock_service.MockRevocation)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
+)
+
+const (
+	DefaultDeliverySlotDuration       = time.Minute * 30
+	DefaultDeliverySlotCapacity int64 = 10
+)
+
+type DeliverySlotConfig struct {
+	Duration time.Duration
+	// Capacity is maximum number of orders scheduled to a slot
+	Capacity int64
+}
+
+type deliverySlotService struct {
+	deliverySlotStorage  storage.DeliverySlot
+	businessMetaProvider domain.MetaProvider
+	deliverySlotConfig   DeliverySlotConfig
+}
+
+func NewDeliverySlotService(deliverySlotStorage storage.DeliverySlot,
+	metaProvider domain.MetaProvider,
+	deliverySlotConfig DeliverySlotConfig) DeliverySlot {
+	if deliverySlotConfig.Duration <= 0 {
+		deliverySlotConfig.Duration = DefaultDeliverySlotDuration
+	}
+	if deliverySlotConfig.Capacity <= 0 {
+		deliverySlotConfig.Capacity = DefaultDeliverySlotCapacity
+	}
+	return &deliverySlotService{
+		deliverySlotStorage:  deliverySlotStorage,
+		businessMetaProvider: metaProvider,
+		deliverySlotConfig:   deliverySlotConfig,
+	}
+}
+
+func (d *deliverySlotService) GetAvailable(ctx context.Context, date string) ([]domain.DeliverySlot, error) {
+	var (
+		schedule = d.businessMetaProvider.Get().Schedule
+		duration = d.deliverySlotConfig.Duration
+		now      = time.Now().UTC()
+	)
+	// Date is local to the store
+	day, err := time.ParseInLocation(domain.DateLayout, date, schedule.Location())
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make([]domain.DeliverySlot, 0)
+	starts := schedule.DeliverySlotsOn(day, duration)
+	if len(starts) == 0 {
+		return slots, nil
+	}
+
+	reservations, err := d.deliverySlotStorage.GetReservations(ctx, starts[0], starts[len(starts)-1])
+	if err != nil {
+		return nil, err
+	}
+	reserved := make(map[int64]int64, len(reservations))
+	for _, reservation := range reservations {
+		reserved[reservation.StartsAt.Unix()] = reservation.Reserved
+	}
+
+	for _, startsAt := range starts {
+		endsAt := startsAt.Add(duration)
+		// Slots that are over can't be chosen
+		if !endsAt.After(now) {
+			continue
+		}
+		available := d.deliverySlotConfig.Capacity - reserved[startsAt.Unix()]
+		if available <= 0 {
+			continue
+		}
+		slots = append(slots, domain.DeliverySlot{
+			StartsAt:  startsAt,
+			EndsAt:    endsAt,
+			Available: available,
+		})
+	}
+	return slots, nil
+}
+
+func (d *deliverySlotService) Reserve(ctx context.Context, at time.Time) (time.Time, error) {
+	schedule := d.businessMetaProvider.Get().Schedule
+	startsAt, ok := schedule.DeliverySlotAt(at, d.deliverySlotConfig.Duration)
+	if !ok {
+		return time.Time{}, domain.ErrDeliveryTimeOutsideHours
+	}
+	// Slots that are over can't be chosen, as they are not listed as available
+	if !startsAt.Add(d.deliverySlotConfig.Duration).After(time.Now().UTC()) {
+		return time.Time{}, domain.ErrDeliverySlotPassed
+	}
+	// Stored in UTC, so that the same slot is matched regardless of location
+	startsAt = startsAt.UTC()
+
+	if err := d.deliverySlotStorage.Reserve(ctx, startsAt, d.deliverySlotConfig.Capacity); err != nil {
+		return time.Time{}, err
+	}
+	return startsAt, nil
+}
+
+func (d *deliverySlotService) Release(ctx context.Context, startsAt time.Time) error {
+	return d.deliverySlotStorage.Release(ctx, startsAt)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	mock_storage "github.com/sonyamoonglade/sancho-backend/internal/storages/mocks"
+	"github.com/sonyamoonglade/sancho-backend/pkg/meta_cache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAvailableDeliverySlots(t *testing.T) {
+	t.Run("should skip full slots", func(t *testing.T) {
+		slotService, slotStorage := getDeliverySlotService(t, DeliverySlotConfig{Duration: time.Hour * 6, Capacity: 2})
+		var (
+			tomorrow = time.Now().UTC().AddDate(0, 0, 1)
+			date     = tomorrow.Format(domain.DateLayout)
+			midnight = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+		)
+
+		slotStorage.
+			EXPECT().
+			GetReservations(gomock.Any(), midnight, midnight.Add(time.Hour*18)).
+			Return([]domain.DeliverySlotReservation{
+				{StartsAt: midnight.Add(time.Hour * 6), Reserved: 2},
+				{StartsAt: midnight.Add(time.Hour * 12), Reserved: 1},
+			}, nil)
+
+		slots, err := slotService.GetAvailable(context.Background(), date)
+		require.NoError(t, err)
+		require.Len(t, slots, 3)
+		require.True(t, midnight.Equal(slots[0].StartsAt))
+		require.Equal(t, int64(2), slots[0].Available)
+		require.True(t, midnight.Add(time.Hour*12).Equal(slots[1].StartsAt))
+		require.Equal(t, int64(1), slots[1].Available)
+		require.True(t, midnight.Add(time.Hour*24).Equal(slots[2].EndsAt))
+	})
+
+	t.Run("should return no slots because store is closed", func(t *testing.T) {
+		slotService, _ := getDeliverySlotService(t, DeliverySlotConfig{})
+		date := time.Now().UTC().AddDate(0, 0, 1).Format(domain.DateLayout)
+		slotService.businessMetaProvider.Set(domain.BusinessMeta{
+			Schedule: domain.Schedule{
+				Timezone:  "UTC",
+				Overrides: []domain.ScheduleOverride{{Date: date, IsClosed: true}},
+			},
+		})
+
+		slots, err := slotService.GetAvailable(context.Background(), date)
+		require.NoError(t, err)
+		require.Empty(t, slots)
+	})
+}
+
+func TestReserveDeliverySlot(t *testing.T) {
+	t.Run("should reserve slot delivery time falls into", func(t *testing.T) {
+		slotService, slotStorage := getDeliverySlotService(t, DeliverySlotConfig{Duration: time.Minute * 30, Capacity: 5})
+		var (
+			tomorrow     = time.Now().UTC().AddDate(0, 0, 1)
+			deliveredAt  = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 12, 40, 0, 0, time.UTC)
+			slotStartsAt = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 12, 30, 0, 0, time.UTC)
+		)
+
+		slotStorage.EXPECT().Reserve(gomock.Any(), slotStartsAt, int64(5)).Return(nil)
+
+		startsAt, err := slotService.Reserve(context.Background(), deliveredAt)
+		require.NoError(t, err)
+		require.True(t, slotStartsAt.Equal(startsAt))
+	})
+
+	t.Run("should return ErrDeliveryTimeOutsideHours", func(t *testing.T) {
+		slotService, slotStorage := getDeliverySlotService(t, DeliverySlotConfig{})
+		slotService.businessMetaProvider.Set(domain.BusinessMeta{
+			Schedule: domain.Schedule{
+				Timezone: "UTC",
+				Weekly:   []domain.WorkingHours{{Weekday: time.Wednesday, Open: "10:00", Close: "22:00"}},
+			},
+		})
+
+		slotStorage.EXPECT().Reserve(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		// Wednesday night
+		_, err := slotService.Reserve(context.Background(), time.Date(2023, 2, 1, 23, 0, 0, 0, time.UTC))
+		require.ErrorIs(t, err, domain.ErrDeliveryTimeOutsideHours)
+	})
+
+	t.Run("should return ErrDeliverySlotPassed", func(t *testing.T) {
+		slotService, slotStorage := getDeliverySlotService(t, DeliverySlotConfig{Duration: time.Minute * 30, Capacity: 5})
+		yesterday := time.Now().UTC().AddDate(0, 0, -1)
+
+		slotStorage.EXPECT().Reserve(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := slotService.Reserve(context.Background(), time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 12, 15, 0, 0, time.UTC))
+		require.ErrorIs(t, err, domain.ErrDeliverySlotPassed)
+	})
+}
+
+func getDeliverySlotService(t *testing.T, slotConfig DeliverySlotConfig) (*deliverySlotService, *mock_storage.MockDeliverySlot) {
+	ctrl := gomock.NewController(t)
+	slotStorage := mock_storage.NewMockDeliverySlot(ctrl)
+	metaCache := meta_cache.NewMetaCache()
+	// Store without schedule is open all day long
+	metaCache.Set(domain.BusinessMeta{})
+	slotService := NewDeliverySlotService(slotStorage, metaCache, slotConfig)
+	return slotService.(*deliverySlotService), slotStorage
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
+)
+
+type deliveryZoneService struct {
+	deliveryZoneStorage storage.DeliveryZone
+}
+
+func NewDeliveryZoneService(deliveryZoneStorage storage.DeliveryZone) DeliveryZone {
+	return &deliveryZoneService{deliveryZoneStorage: deliveryZoneStorage}
+}
+
+func (d deliveryZoneService) GetByID(ctx context.Context, deliveryZoneID string) (domain.DeliveryZone, error) {
+	return d.deliveryZoneStorage.GetByID(ctx, deliveryZoneID)
+}
+
+func (d deliveryZoneService) GetAll(ctx context.Context) ([]domain.DeliveryZone, error) {
+	return d.deliveryZoneStorage.GetAll(ctx)
+}
+
+func (d deliveryZoneService) Create(ctx context.Context, dto dto.CreateDeliveryZoneDTO) (string, error) {
+	deliveryZone := dto.ToDomain()
+	deliveryZone.CreatedAt = time.Now().UTC()
+
+	deliveryZoneID, err := d.deliveryZoneStorage.Save(ctx, deliveryZone)
+	if err != nil {
+		return "", err
+	}
+	return deliveryZoneID.Hex(), nil
+}
+
+func (d deliveryZoneService) Update(ctx context.Context, dto dto.UpdateDeliveryZoneDTO) error {
+	return d.deliveryZoneStorage.Update(ctx, dto)
+}
+
+func (d deliveryZoneService) Delete(ctx context.Context, deliveryZoneID string) error {
+	return d.deliveryZoneStorage.Delete(ctx, deliveryZoneID)
+}
+
+func (d deliveryZoneService) Resolve(ctx context.Context, address domain.OrderDeliveryAddress) (domain.DeliveryZone, error) {
+	return d.deliveryZoneStorage.GetByLocation(ctx, address.Longitude, address.Latitude)
+}
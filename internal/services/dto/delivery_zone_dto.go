@@ -0,0 +1,33 @@
+package dto
+
+import "github.com/sonyamoonglade/sancho-backend/internal/domain"
+
+// DeliveryZoneTermsDTO holds everything about delivery zone that admin can change
+type DeliveryZoneTermsDTO struct {
+	Name                  string
+	Area                  domain.GeoPolygon
+	Fee                   int64
+	FreeDeliveryThreshold int64
+	MinOrderAmount        int64
+	ETAMinutes            int64
+}
+
+type CreateDeliveryZoneDTO struct {
+	DeliveryZoneTermsDTO
+}
+
+func (d CreateDeliveryZoneDTO) ToDomain() domain.DeliveryZone {
+	return domain.DeliveryZone{
+		Name:                  d.Name,
+		Area:                  d.Area,
+		Fee:                   d.Fee,
+		FreeDeliveryThreshold: d.FreeDeliveryThreshold,
+		MinOrderAmount:        d.MinOrderAmount,
+		ETAMinutes:            d.ETAMinutes,
+	}
+}
+
+type UpdateDeliveryZoneDTO struct {
+	DeliveryZoneID string
+	DeliveryZoneTermsDTO
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "github.com/sonyamoonglade/sancho-backend/internal/domain"
+
+type UpdateScheduleDTO struct {
+	Timezone  string
+	Weekly    []domain.WorkingHours
+	Overrides []domain.ScheduleOverride
+}
@@ -1,7 +1,10 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/pkg/cursor"
 )
 
 type CreateUserOrderDTO struct {
@@ -10,6 +13,7 @@ type CreateUserOrderDTO struct {
 	Cart            []CartProductDTO
 	IsDelivered     bool
 	DeliveryAddress *domain.OrderDeliveryAddress
+	PromoCode       *string
 }
 
 type CreateWorkerOrderDTO struct {
@@ -26,4 +30,44 @@ type CreateWorkerOrderDTO struct {
 type CartProductDTO struct {
 	ProductID string
 	Quantity  int32
+	OptionIDs []string
+	// Swaps are keyed by combo component id
+	Swaps map[string]string
+}
+
+type UpdateOrderStatusDTO struct {
+	OrderID string
+	// Status order is expected to have at the moment of update
+	From domain.OrderStatus
+	To   domain.OrderStatus
+	// Time of transition. Stored into verifiedAt, completedAt or cancelledAt depending on To
+	At                time.Time
+	CancelExplanation *string
+}
+
+type MarkCartReadyDTO struct {
+	OrderID string
+	// Indexes of order's cart lines to mark ready
+	Lines []int
+	At    time.Time
+}
+
+// ListOrdersDTO describes filters of the orders listing. Nil fields are not applied.
+type ListOrdersDTO struct {
+	Status       *domain.OrderStatus
+	Pay          *domain.Pay
+	IsDelivered  *bool
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	CustomerID   *string
+	NanoIDPrefix *string
+	// Cursor is the last order of the previous page
+	Cursor *cursor.Cursor
+	Limit  int64
+}
+
+type OrdersPageDTO struct {
+	Orders []domain.Order
+	// NextCursor is nil when there are no more orders
+	NextCursor *string
 }
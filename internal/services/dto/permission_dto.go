@@ -0,0 +1,8 @@
+package dto
+
+import "github.com/sonyamoonglade/sancho-backend/internal/domain"
+
+type UpdateRolePermissionsDTO struct {
+	Role        domain.Role
+	Permissions []domain.Permission
+}
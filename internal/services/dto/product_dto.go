@@ -9,6 +9,8 @@ type CreateProductDTO struct {
 	CategoryName string
 	Price        int64
 	Features     domain.Features
+	OptionGroups []domain.OptionGroup
+	Combo        *domain.Combo
 }
 
 func (d CreateProductDTO) ToDomain() domain.Product {
@@ -25,6 +27,8 @@ func (d CreateProductDTO) ToDomain() domain.Product {
 			EnergyValue: d.Features.EnergyValue,
 			Nutrients:   d.Features.Nutrients,
 		},
+		OptionGroups: d.OptionGroups,
+		Combo:        d.Combo,
 	}
 }
 
@@ -32,7 +36,16 @@ type UpdateProductDTO struct {
 	ProductID   string
 	Name        *string
 	TranslateRU *string
-	ImageURL    *string
 	Description *string
 	Price       *int64
 }
+
+type UpdateOptionGroupsDTO struct {
+	ProductID    string
+	OptionGroups []domain.OptionGroup
+}
+
+type RenameCategoryDTO struct {
+	CategoryID string
+	Name       string
+}
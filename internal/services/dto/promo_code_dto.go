@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PromoCodeTermsDTO holds everything about promo code that admin can change after it's created
+type PromoCodeTermsDTO struct {
+	Kind             domain.DiscountKind
+	Percent          float64
+	Amount           int64
+	MinCartAmount    int64
+	ValidFrom        *time.Time
+	ValidTo          *time.Time
+	UsageLimit       int64
+	PerCustomerLimit int64
+	CategoryIDs      []primitive.ObjectID
+	ProductIDs       []primitive.ObjectID
+}
+
+type CreatePromoCodeDTO struct {
+	Code string
+	PromoCodeTermsDTO
+}
+
+func (d CreatePromoCodeDTO) ToDomain() domain.PromoCode {
+	return domain.PromoCode{
+		Code:             strings.ToUpper(d.Code),
+		Kind:             d.Kind,
+		Percent:          d.Percent,
+		Amount:           d.Amount,
+		MinCartAmount:    d.MinCartAmount,
+		ValidFrom:        d.ValidFrom,
+		ValidTo:          d.ValidTo,
+		UsageLimit:       d.UsageLimit,
+		PerCustomerLimit: d.PerCustomerLimit,
+		UsedCount:        0,
+		CategoryIDs:      d.CategoryIDs,
+		ProductIDs:       d.ProductIDs,
+	}
+}
+
+type UpdatePromoCodeDTO struct {
+	PromoCodeID string
+	PromoCodeTermsDTO
+}
+
+type RedeemPromoCodeDTO struct {
+	PromoCodeID      string
+	CustomerID       string
+	PerCustomerLimit int64
+}
+
+type ReleasePromoCodeDTO struct {
+	PromoCodeID string
+	CustomerID  string
+}
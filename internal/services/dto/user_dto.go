@@ -1,18 +1,38 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 )
 
-type SaveSessionDTO struct {
-	UserID  string
-	Role    domain.Role
-	Session domain.Session
+type RotateSessionDTO struct {
+	SessionID string
+	// RefreshToken is the token session should hold
+	RefreshToken    string
+	NewRefreshToken string
+	ExpiresAt       time.Time
+	// Client is device session is refreshed from
+	Client domain.SessionClient
+}
+
+type RefreshTokensDTO struct {
+	Role         domain.Role
+	UserID       string
+	RefreshToken string
+	Client       domain.SessionClient
 }
 
 type LoginAdminDTO struct {
 	Login    string
 	Password string
+	Client   domain.SessionClient
+}
+
+type LoginCustomerDTO struct {
+	PhoneNumber string
+	Code        string
+	Client      domain.SessionClient
 }
 
 type RegisterCustomerDTO struct {
@@ -25,3 +45,15 @@ type RegisterAdminDTO struct {
 	Login    string
 	Password string
 }
+
+type RegisterWorkerDTO struct {
+	Name     string
+	Login    string
+	Password string
+}
+
+type LoginWorkerDTO struct {
+	Login    string
+	Password string
+	Client   domain.SessionClient
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
+)
+
+const (
+	DefaultIdempotencyTTL = time.Hour * 24
+	// DefaultIdempotencyLockTimeout is how long request is considered in progress.
+	// Record of request that has not completed by then (e.g. instance crashed) is taken over by retry.
+	DefaultIdempotencyLockTimeout = time.Minute
+)
+
+type IdempotencyConfig struct {
+	// How long response is replayed for
+	TTL         time.Duration
+	LockTimeout time.Duration
+}
+
+type idempotencyService struct {
+	idempotencyStorage storage.Idempotency
+	idempotencyConfig  IdempotencyConfig
+}
+
+func NewIdempotencyService(idempotencyStorage storage.Idempotency, idempotencyConfig IdempotencyConfig) Idempotency {
+	if idempotencyConfig.TTL <= 0 {
+		idempotencyConfig.TTL = DefaultIdempotencyTTL
+	}
+	if idempotencyConfig.LockTimeout <= 0 {
+		idempotencyConfig.LockTimeout = DefaultIdempotencyLockTimeout
+	}
+	return &idempotencyService{
+		idempotencyStorage: idempotencyStorage,
+		idempotencyConfig:  idempotencyConfig,
+	}
+}
+
+func (i idempotencyService) Begin(ctx context.Context, key, requestHash string) (*domain.IdempotentResponse, error) {
+	now := time.Now().UTC()
+	record := domain.IdempotencyRecord{
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(i.idempotencyConfig.TTL),
+	}
+	err := i.idempotencyStorage.Insert(ctx, record)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, domain.ErrIdempotencyKeyExists) {
+		return nil, err
+	}
+
+	existing, err := i.idempotencyStorage.Get(ctx, key)
+	if err != nil {
+		// Expired record has not been removed by TTL index yet
+		if errors.Is(err, domain.ErrIdempotencyKeyNotFound) {
+			return nil, i.idempotencyStorage.Replace(ctx, record, now)
+		}
+		return nil, err
+	}
+	if existing.RequestHash != requestHash {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	if existing.Response != nil {
+		return existing.Response, nil
+	}
+	return nil, i.idempotencyStorage.Replace(ctx, record, now.Add(-i.idempotencyConfig.LockTimeout))
+}
+
+func (i idempotencyService) Complete(ctx context.Context, key string, response domain.IdempotentResponse) error {
+	return i.idempotencyStorage.SaveResponse(ctx, key, response)
+}
+
+func (i idempotencyService) Release(ctx context.Context, key string) error {
+	return i.idempotencyStorage.Delete(ctx, key)
+}
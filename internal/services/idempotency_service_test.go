@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	mock_storage "github.com/sonyamoonglade/sancho-backend/internal/storages/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeginIdempotentRequest(t *testing.T) {
+	const (
+		key         = "/api/order/create:user:key"
+		requestHash = "hash"
+	)
+
+	t.Run("should reserve key that has not been used", func(t *testing.T) {
+		idempotencyService, idempotencyStorage := getIdempotencyService(t)
+
+		idempotencyStorage.EXPECT().Insert(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, record domain.IdempotencyRecord) error {
+				require.Equal(t, key, record.Key)
+				require.Equal(t, requestHash, record.RequestHash)
+				require.Nil(t, record.Response)
+				require.Equal(t, DefaultIdempotencyTTL, record.ExpiresAt.Sub(record.CreatedAt))
+				return nil
+			})
+
+		stored, err := idempotencyService.Begin(context.Background(), key, requestHash)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+	})
+
+	t.Run("should return stored response of the same request", func(t *testing.T) {
+		idempotencyService, idempotencyStorage := getIdempotencyService(t)
+		response := &domain.IdempotentResponse{StatusCode: 201, ContentType: "application/json", Body: []byte(`{}`)}
+
+		idempotencyStorage.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(domain.ErrIdempotencyKeyExists)
+		idempotencyStorage.EXPECT().Get(gomock.Any(), key).Return(domain.IdempotencyRecord{
+			Key:         key,
+			RequestHash: requestHash,
+			Response:    response,
+		}, nil)
+
+		stored, err := idempotencyService.Begin(context.Background(), key, requestHash)
+		require.NoError(t, err)
+		require.Equal(t, response, stored)
+	})
+
+	t.Run("should return ErrIdempotencyKeyReused because request differs", func(t *testing.T) {
+		idempotencyService, idempotencyStorage := getIdempotencyService(t)
+
+		idempotencyStorage.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(domain.ErrIdempotencyKeyExists)
+		idempotencyStorage.EXPECT().Get(gomock.Any(), key).Return(domain.IdempotencyRecord{
+			Key:         key,
+			RequestHash: "another hash",
+		}, nil)
+
+		_, err := idempotencyService.Begin(context.Background(), key, requestHash)
+		require.ErrorIs(t, err, domain.ErrIdempotencyKeyReused)
+	})
+
+	t.Run("should take over request only after lock timeout", func(t *testing.T) {
+		idempotencyService, idempotencyStorage := getIdempotencyService(t)
+
+		idempotencyStorage.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(domain.ErrIdempotencyKeyExists)
+		idempotencyStorage.EXPECT().Get(gomock.Any(), key).Return(domain.IdempotencyRecord{
+			Key:         key,
+			RequestHash: requestHash,
+			CreatedAt:   time.Now().UTC(),
+		}, nil)
+		idempotencyStorage.EXPECT().Replace(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, record domain.IdempotencyRecord, createdBefore time.Time) error {
+				require.WithinDuration(t, record.CreatedAt.Add(-DefaultIdempotencyLockTimeout), createdBefore, 0)
+				return domain.ErrIdempotencyKeyInProgress
+			})
+
+		_, err := idempotencyService.Begin(context.Background(), key, requestHash)
+		require.ErrorIs(t, err, domain.ErrIdempotencyKeyInProgress)
+	})
+}
+
+func getIdempotencyService(t *testing.T) (*idempotencyService, *mock_storage.MockIdempotency) {
+	ctrl := gomock.NewController(t)
+	idempotencyStorage := mock_storage.NewMockIdempotency(ctrl)
+	idService := NewIdempotencyService(idempotencyStorage, IdempotencyConfig{})
+	return idService.(*idempotencyService), idempotencyStorage
+}
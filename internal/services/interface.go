@@ -7,16 +7,36 @@ import (
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
 	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
+	"github.com/sonyamoonglade/sancho-backend/pkg/eventbus"
 )
 
 type Product interface {
 	GetByID(ctx context.Context, productID string) (domain.Product, error)
+	// GetAll returns every product including not approved ones
 	GetAll(ctx context.Context) ([]domain.Product, error)
+	// GetCatalog returns approved products grouped by category, categories with higher rank go first
+	GetCatalog(ctx context.Context) ([]domain.CatalogCategory, error)
 	GetProductsByIDs(ctx context.Context, ids []string) ([]domain.Product, error)
 	GetAllCategories(ctx context.Context, sorted bool) ([]domain.Category, error)
+	// CreateCategory puts new category last
+	CreateCategory(ctx context.Context, name string) (string, error)
+	RenameCategory(ctx context.Context, dto dto.RenameCategoryDTO) error
+	// ReorderCategories ranks categories in the given order, first goes first.
+	// Every category should be listed exactly once.
+	ReorderCategories(ctx context.Context, categoryIDs []string) error
+	DeleteCategory(ctx context.Context, categoryID string) error
+	// Create returns domain.ErrInvalidCombo if combo is made of products that don't exist or are combos
 	Create(ctx context.Context, dto dto.CreateProductDTO) (string, error)
+	// Delete deletes product along with its image
 	Delete(ctx context.Context, productID string) error
 	Update(ctx context.Context, dto dto.UpdateProductDTO) error
+	// UploadImage stores resized variants of the image and replaces product's previous image with them.
+	// It returns domain.ErrUnsupportedImage if data is not jpeg, png or gif and domain.ErrImageTooLarge
+	// if it exceeds ImageConfig limits.
+	UploadImage(ctx context.Context, productID string, data []byte) (domain.ImageURLs, error)
+	// UpdateOptionGroups replaces option groups of product. Groups and options without id get a new one.
+	UpdateOptionGroups(ctx context.Context, dto dto.UpdateOptionGroupsDTO) error
+	// Approve returns domain.ErrComboComponentNotApproved if combo has products that are not approved
 	Approve(ctx context.Context, productID string) error
 	Disapprove(ctx context.Context, productID string) error
 }
@@ -25,27 +45,177 @@ type Order interface {
 	GetOrderByID(ctx context.Context, orderID string) (domain.Order, error)
 	GetOrderByNanoIDAt(ctx context.Context, nanoID string, from, to time.Time) (domain.Order, error)
 	GetLastOrderByCustomerID(ctx context.Context, customerID string) (domain.Order, error)
+	GetCurrentOrderByCustomerID(ctx context.Context, customerID string) (domain.Order, error)
+	ListOrders(ctx context.Context, listDTO dto.ListOrdersDTO) (dto.OrdersPageDTO, error)
 
 	CreateUserOrder(ctx context.Context, dto dto.CreateUserOrderDTO) (string, error)
 	CreateWorkerOrder(ctx context.Context, orderDTO dto.CreateWorkerOrderDTO) (string, error)
 
+	VerifyOrder(ctx context.Context, orderID string) error
+	CompleteOrder(ctx context.Context, orderID string) error
+	CancelOrder(ctx context.Context, orderID string, explanation string) error
+
+	// MarkCartProductReady marks cart line of verified order as prepared.
+	// Order is completed as soon as every line is ready.
+	MarkCartProductReady(ctx context.Context, orderID string, line int) error
+	// MarkOrderReady marks all cart lines of verified order as prepared and completes it
+	MarkOrderReady(ctx context.Context, orderID string) error
+
 	CalculateDiscountedAmount(amount int64, discountPercent float64) int64
 	CalculateCartAmount(ctx context.Context, cart []dto.CartProductDTO) (int64, []domain.CartProduct, error)
 }
 
+type PromoCode interface {
+	GetByID(ctx context.Context, promoCodeID string) (domain.PromoCode, error)
+	GetAll(ctx context.Context) ([]domain.PromoCode, error)
+	Create(ctx context.Context, dto dto.CreatePromoCodeDTO) (string, error)
+	Update(ctx context.Context, dto dto.UpdatePromoCodeDTO) error
+	Delete(ctx context.Context, promoCodeID string) error
+	// Apply checks that promo code can be applied to the cart and returns discount as fraction of amount
+	Apply(ctx context.Context, code string, cart []domain.CartProduct, amount int64) (domain.PromoCode, float64, error)
+	// Redeem counts usage of promo code by customer. It fails if usage limits are reached.
+	Redeem(ctx context.Context, promoCode domain.PromoCode, customerID string) error
+	Release(ctx context.Context, promoCodeID, customerID string) error
+}
+
+type DeliveryZone interface {
+	GetByID(ctx context.Context, deliveryZoneID string) (domain.DeliveryZone, error)
+	GetAll(ctx context.Context) ([]domain.DeliveryZone, error)
+	Create(ctx context.Context, dto dto.CreateDeliveryZoneDTO) (string, error)
+	Update(ctx context.Context, dto dto.UpdateDeliveryZoneDTO) error
+	Delete(ctx context.Context, deliveryZoneID string) error
+	// Resolve returns delivery zone the address belongs to.
+	// It returns domain.ErrOutsideDeliveryZone if address is outside of every zone.
+	Resolve(ctx context.Context, address domain.OrderDeliveryAddress) (domain.DeliveryZone, error)
+}
+
+type DeliverySlot interface {
+	// GetAvailable returns slots of the date (YYYY-MM-DD in store's timezone) that are not over and have capacity left
+	GetAvailable(ctx context.Context, date string) ([]domain.DeliverySlot, error)
+	// Reserve counts order in the slot delivery time falls into and returns start of the slot.
+	// It fails if slot is full or has already passed.
+	Reserve(ctx context.Context, at time.Time) (time.Time, error)
+	Release(ctx context.Context, startsAt time.Time) error
+}
+
+type OrderEvents interface {
+	Publish(eventType string, event domain.OrderEvent)
+	// SubscribeCustomer returns subscription to events of customer's orders and
+	// buffered events that happened after lastEventID
+	SubscribeCustomer(customerID string, lastEventID uint64) (*eventbus.Subscription, []eventbus.Event)
+	// SubscribeWorkers returns subscription to events of all orders and
+	// buffered events that happened after lastEventID
+	SubscribeWorkers(lastEventID uint64) (*eventbus.Subscription, []eventbus.Event)
+}
+
+type Meta interface {
+	Get(ctx context.Context) (domain.BusinessMeta, error)
+	// Schedule returns store schedule from MetaProvider
+	Schedule() domain.Schedule
+	// UpdateSchedule replaces store schedule and sets meta to MetaProvider of current instance
+	UpdateSchedule(ctx context.Context, dto dto.UpdateScheduleDTO) error
+	// Load reads meta from storage into MetaProvider. Must be called before serving requests.
+	Load(ctx context.Context) error
+	// Watch blocks and keeps MetaProvider in sync with storage until ctx is done.
+	// It listens to change stream and falls back to polling when it's not available.
+	Watch(ctx context.Context)
+}
+
 type User interface {
 	GetAdminByLogin(ctx context.Context, login string) (domain.Admin, error)
-	GetAdminByRefreshToken(ctx context.Context, adminID, token string) (domain.Admin, error)
+	GetWorkerByLogin(ctx context.Context, login string) (domain.Worker, error)
+	GetWorkers(ctx context.Context) ([]domain.Worker, error)
 	GetCustomerByPhoneNumber(ctx context.Context, phoneNumber string) (domain.Customer, error)
 
 	SaveAdmin(ctx context.Context, admin domain.Admin) (string, error)
 	SaveCustomer(ctx context.Context, customer domain.Customer) (string, error)
-	SaveSession(ctx context.Context, dto dto.SaveSessionDTO) error
+	SaveWorker(ctx context.Context, worker domain.Worker) (string, error)
+	// DisableWorker and UpdateWorkerPassword log worker out of every device
+	DisableWorker(ctx context.Context, workerID string) error
+	UpdateWorkerPassword(ctx context.Context, workerID, password string) error
+	// UpdatePasswordHash replaces password hash of admin or worker keeping its sessions
+	UpdatePasswordHash(ctx context.Context, userID string, role domain.Role, passwordHash string) error
+}
+
+type Session interface {
+	GetByUser(ctx context.Context, role domain.Role, userID string) ([]domain.Session, error)
+	// Revoke logs user out of the session and revokes its access tokens.
+	// It returns domain.ErrSessionNotFound if user has no such session.
+	Revoke(ctx context.Context, role domain.Role, userID, sessionID string) error
+	// RevokeAll logs user out of every device and revokes all of its access tokens
+	RevokeAll(ctx context.Context, role domain.Role, userID string) error
 }
 
 type Auth interface {
 	RegisterCustomer(ctx context.Context, dto dto.RegisterCustomerDTO) (string, error)
 	RegisterAdmin(ctx context.Context, dto dto.RegisterAdminDTO) (string, error)
+	RegisterWorker(ctx context.Context, dto dto.RegisterWorkerDTO) (string, error)
 	LoginAdmin(ctx context.Context, dto dto.LoginAdminDTO) (auth.Pair, error)
-	RefreshAdminToken(ctx context.Context, adminID, token string) (auth.Pair, error)
+	// LoginWorker returns domain.ErrWorkerDisabled if worker has been disabled by admin
+	LoginWorker(ctx context.Context, dto dto.LoginWorkerDTO) (auth.Pair, error)
+	ResetWorkerPassword(ctx context.Context, workerID, password string) error
+	// RequestCustomerOTP sends one-time code to phone number.
+	// It returns domain.ErrOTPResendTooSoon if previous code has been sent less than resend interval ago.
+	RequestCustomerOTP(ctx context.Context, phoneNumber string) error
+	// LoginCustomer checks one-time code and issues tokens. Customer is registered if it does not exist yet.
+	LoginCustomer(ctx context.Context, dto dto.LoginCustomerDTO) (auth.Pair, error)
+	// RefreshTokens issues new token pair in exchange for the refresh token of user's session
+	RefreshTokens(ctx context.Context, dto dto.RefreshTokensDTO) (auth.Pair, error)
+	// Logout revokes the access token and ends session it has been issued for
+	Logout(ctx context.Context, claims auth.Claims) error
+	// JWKS returns public keys tokens can be verified with
+	JWKS() auth.JWKS
+}
+
+// Revocation makes access tokens invalid before they expire
+type Revocation interface {
+	// IsRevoked checks token against revocations in memory, so that it's cheap to call on every request
+	IsRevoked(claims auth.Claims) bool
+	RevokeToken(ctx context.Context, claims auth.Claims) error
+	// RevokeSession revokes access tokens issued for the session until now
+	RevokeSession(ctx context.Context, role domain.Role, sessionID string) error
+	// RevokeUser revokes every access token of user issued until now
+	RevokeUser(ctx context.Context, role domain.Role, userID string) error
+	// Load reads revocations from storage into RevocationProvider. Must be called before serving requests.
+	Load(ctx context.Context) error
+	// Watch blocks and periodically loads revocations made by other instances until ctx is done
+	Watch(ctx context.Context)
+}
+
+type Permission interface {
+	// Has checks if role has every one of permissions. It's checked in memory, so that it's cheap to call on every request.
+	Has(role domain.Role, permissions ...domain.Permission) bool
+	// GetAll returns permissions of every role. Roles that have not been saved have domain.DefaultRolePermissions.
+	GetAll(ctx context.Context) ([]domain.RolePermissions, error)
+	// Update replaces permissions of the role.
+	// It returns domain.ErrRolesManageRequired if admin role would lose domain.PermissionRolesManage.
+	Update(ctx context.Context, dto dto.UpdateRolePermissionsDTO) error
+	// Load reads permissions from storage into PermissionsProvider. Must be called before serving requests.
+	Load(ctx context.Context) error
+	// Watch blocks and keeps PermissionsProvider up to date with changes made by other instances until ctx is done
+	Watch(ctx context.Context)
+}
+
+type Idempotency interface {
+	// Begin reserves idempotency key for the request. It returns response stored for the key
+	// if the same request has already completed, nil if the request should be handled.
+	// It returns domain.ErrIdempotencyKeyReused if key has been used with another request and
+	// domain.ErrIdempotencyKeyInProgress if the same request is being handled meanwhile.
+	Begin(ctx context.Context, key, requestHash string) (*domain.IdempotentResponse, error)
+	// Complete stores response of the request, so that it's replayed on retries
+	Complete(ctx context.Context, key string, response domain.IdempotentResponse) error
+	// Release frees key of the request that has failed, so that it can be retried
+	Release(ctx context.Context, key string) error
+}
+
+// BlobStore keeps files such as product images
+type BlobStore interface {
+	// Put stores data under key and returns URL it's served at
+	Put(ctx context.Context, key string, data []byte) (string, error)
+	// Delete deletes data under key. Missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+type SMSSender interface {
+	Send(ctx context.Context, phoneNumber, text string) error
 }
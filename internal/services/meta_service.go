@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
+	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const DefaultMetaPollInterval = time.Second * 30
+
+type MetaConfig struct {
+	// Interval of reloading meta from storage when change streams are not available
+	PollInterval time.Duration
+}
+
+type metaService struct {
+	metaStorage  storage.Meta
+	metaProvider domain.MetaProvider
+	metaConfig   MetaConfig
+}
+
+func NewMetaService(metaStorage storage.Meta, metaProvider domain.MetaProvider, metaConfig MetaConfig) Meta {
+	if metaConfig.PollInterval <= 0 {
+		metaConfig.PollInterval = DefaultMetaPollInterval
+	}
+	return &metaService{
+		metaStorage:  metaStorage,
+		metaProvider: metaProvider,
+		metaConfig:   metaConfig,
+	}
+}
+
+func (m *metaService) Get(ctx context.Context) (domain.BusinessMeta, error) {
+	return m.metaStorage.Get(ctx)
+}
+
+func (m *metaService) Schedule() domain.Schedule {
+	return m.metaProvider.Get().Schedule
+}
+
+func (m *metaService) UpdateSchedule(ctx context.Context, dto dto.UpdateScheduleDTO) error {
+	meta, err := m.metaStorage.SaveSchedule(ctx, domain.Schedule{
+		Timezone:  dto.Timezone,
+		Weekly:    dto.Weekly,
+		Overrides: dto.Overrides,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Other instances receive it through Watch
+	m.metaProvider.Set(meta)
+	return nil
+}
+
+func (m *metaService) Load(ctx context.Context) error {
+	meta, err := m.metaStorage.Get(ctx)
+	if err != nil {
+		if !errors.Is(err, domain.ErrMetaNotFound) {
+			return err
+		}
+		// Fresh database. Zero meta has no schedule, so store is open all day long until admin sets it.
+		logger.Get().Warn("business meta is not set, using zero values")
+	}
+
+	m.metaProvider.Set(meta)
+	return nil
+}
+
+func (m *metaService) Watch(ctx context.Context) {
+	err := m.metaStorage.Watch(ctx, m.metaProvider.Set)
+	if ctx.Err() != nil {
+		return
+	}
+	logger.Get().Warn("business meta change stream is unavailable, falling back to polling",
+		zap.Error(err),
+		zap.Duration("interval", m.metaConfig.PollInterval),
+	)
+
+	ticker := time.NewTicker(m.metaConfig.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			meta, err := m.metaStorage.Get(ctx)
+			if err != nil {
+				if ctx.Err() == nil && !errors.Is(err, domain.ErrMetaNotFound) {
+					logger.Get().Error("reload business meta", zap.Error(err))
+				}
+				continue
+			}
+			m.metaProvider.Set(meta)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	mock_storage "github.com/sonyamoonglade/sancho-backend/internal/storages/mocks"
+	"github.com/sonyamoonglade/sancho-backend/pkg/meta_cache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateSchedule(t *testing.T) {
+	t.Run("should save schedule and set meta returned by storage to provider", func(t *testing.T) {
+		metaService, metaStorage, metaCache := getMetaService(t, MetaConfig{})
+		scheduleDTO := dto.UpdateScheduleDTO{
+			Timezone:  "Asia/Yekaterinburg",
+			Weekly:    []domain.WorkingHours{{Weekday: time.Tuesday, Open: "10:00", Close: "22:00"}},
+			Overrides: []domain.ScheduleOverride{{Date: "2023-02-23", IsClosed: true}},
+		}
+		schedule := domain.Schedule{
+			Timezone:  scheduleDTO.Timezone,
+			Weekly:    scheduleDTO.Weekly,
+			Overrides: scheduleDTO.Overrides,
+		}
+		// Other fields are kept by storage
+		saved := domain.BusinessMeta{
+			DeliveryPunishmentThreshold: 500,
+			DeliveryPunishmentValue:     150,
+			Schedule:                    schedule,
+		}
+
+		metaStorage.EXPECT().Get(gomock.Any()).Times(0)
+		metaStorage.EXPECT().SaveSchedule(gomock.Any(), schedule).Return(saved, nil)
+
+		require.NoError(t, metaService.UpdateSchedule(context.Background(), scheduleDTO))
+		require.Equal(t, saved, *metaCache.Get())
+		require.Equal(t, schedule, metaService.Schedule())
+	})
+
+	t.Run("should not set meta to provider because storage failed", func(t *testing.T) {
+		metaService, metaStorage, metaCache := getMetaService(t, MetaConfig{})
+
+		metaStorage.EXPECT().SaveSchedule(gomock.Any(), gomock.Any()).Return(domain.BusinessMeta{}, errors.New("mongo is down"))
+
+		require.Error(t, metaService.UpdateSchedule(context.Background(), dto.UpdateScheduleDTO{Timezone: "UTC"}))
+		require.Panics(t, func() {
+			metaCache.Get()
+		})
+	})
+}
+
+func TestLoadMeta(t *testing.T) {
+	t.Run("should load meta into provider", func(t *testing.T) {
+		metaService, metaStorage, metaCache := getMetaService(t, MetaConfig{})
+		meta := domain.BusinessMeta{
+			DeliveryPunishmentThreshold: 400,
+			DeliveryPunishmentValue:     100,
+		}
+
+		metaStorage.EXPECT().Get(gomock.Any()).Return(meta, nil)
+
+		require.NoError(t, metaService.Load(context.Background()))
+		require.Equal(t, meta, *metaCache.Get())
+	})
+
+	t.Run("should set zero meta because it's not saved yet", func(t *testing.T) {
+		metaService, metaStorage, metaCache := getMetaService(t, MetaConfig{})
+
+		metaStorage.EXPECT().Get(gomock.Any()).Return(domain.BusinessMeta{}, domain.ErrMetaNotFound)
+
+		require.NoError(t, metaService.Load(context.Background()))
+		require.Equal(t, domain.BusinessMeta{}, *metaCache.Get())
+	})
+}
+
+func TestWatchMeta(t *testing.T) {
+	t.Run("should set meta received from change stream", func(t *testing.T) {
+		metaService, metaStorage, metaCache := getMetaService(t, MetaConfig{})
+		meta := domain.BusinessMeta{DeliveryPunishmentValue: 200}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		metaStorage.
+			EXPECT().
+			Watch(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, onChange func(domain.BusinessMeta)) error {
+				onChange(meta)
+				cancel()
+				return ctx.Err()
+			})
+
+		metaService.Watch(ctx)
+		require.Equal(t, meta, *metaCache.Get())
+	})
+
+	t.Run("should poll meta because change stream is not supported", func(t *testing.T) {
+		metaService, metaStorage, metaCache := getMetaService(t, MetaConfig{PollInterval: time.Millisecond})
+		meta := domain.BusinessMeta{DeliveryPunishmentThreshold: 1000}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		metaStorage.EXPECT().Watch(gomock.Any(), gomock.Any()).Return(errors.New("not a replica set"))
+		metaStorage.
+			EXPECT().
+			Get(gomock.Any()).
+			DoAndReturn(func(ctx context.Context) (domain.BusinessMeta, error) {
+				cancel()
+				return meta, nil
+			}).
+			MinTimes(1)
+
+		metaService.Watch(ctx)
+		require.Equal(t, meta, *metaCache.Get())
+	})
+}
+
+func getMetaService(t *testing.T, metaConfig MetaConfig) (*metaService, *mock_storage.MockMeta, *meta_cache.MetaCache) {
+	ctrl := gomock.NewController(t)
+	metaStorage := mock_storage.NewMockMeta(ctrl)
+	metaCache := meta_cache.NewMetaCache()
+	mtService := NewMetaService(metaStorage, metaCache, metaConfig)
+	return mtService.(*metaService), metaStorage, metaCache
+}
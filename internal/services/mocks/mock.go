@@ -13,6 +13,7 @@ import (
 	domain "github.com/sonyamoonglade/sancho-backend/internal/domain"
 	dto "github.com/sonyamoonglade/sancho-backend/internal/services/dto"
 	auth "github.com/sonyamoonglade/sancho-backend/pkg/auth"
+	eventbus "github.com/sonyamoonglade/sancho-backend/pkg/eventbus"
 )
 
 // MockProduct is a mock of Product interface.
@@ -67,6 +68,21 @@ func (mr *MockProductMockRecorder) Create(ctx, dto interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProduct)(nil).Create), ctx, dto)
 }
 
+// CreateCategory mocks base method.
+func (m *MockProduct) CreateCategory(ctx context.Context, name string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCategory", ctx, name)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCategory indicates an expected call of CreateCategory.
+func (mr *MockProductMockRecorder) CreateCategory(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockProduct)(nil).CreateCategory), ctx, name)
+}
+
 // Delete mocks base method.
 func (m *MockProduct) Delete(ctx context.Context, productID string) error {
 	m.ctrl.T.Helper()
@@ -81,6 +97,20 @@ func (mr *MockProductMockRecorder) Delete(ctx, productID interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProduct)(nil).Delete), ctx, productID)
 }
 
+// DeleteCategory mocks base method.
+func (m *MockProduct) DeleteCategory(ctx context.Context, categoryID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategory", ctx, categoryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCategory indicates an expected call of DeleteCategory.
+func (mr *MockProductMockRecorder) DeleteCategory(ctx, categoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockProduct)(nil).DeleteCategory), ctx, categoryID)
+}
+
 // Disapprove mocks base method.
 func (m *MockProduct) Disapprove(ctx context.Context, productID string) error {
 	m.ctrl.T.Helper()
@@ -140,6 +170,21 @@ func (mr *MockProductMockRecorder) GetByID(ctx, productID interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockProduct)(nil).GetByID), ctx, productID)
 }
 
+// GetCatalog mocks base method.
+func (m *MockProduct) GetCatalog(ctx context.Context) ([]domain.CatalogCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCatalog", ctx)
+	ret0, _ := ret[0].([]domain.CatalogCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCatalog indicates an expected call of GetCatalog.
+func (mr *MockProductMockRecorder) GetCatalog(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalog", reflect.TypeOf((*MockProduct)(nil).GetCatalog), ctx)
+}
+
 // GetProductsByIDs mocks base method.
 func (m *MockProduct) GetProductsByIDs(ctx context.Context, ids []string) ([]domain.Product, error) {
 	m.ctrl.T.Helper()
@@ -155,6 +200,34 @@ func (mr *MockProductMockRecorder) GetProductsByIDs(ctx, ids interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductsByIDs", reflect.TypeOf((*MockProduct)(nil).GetProductsByIDs), ctx, ids)
 }
 
+// RenameCategory mocks base method.
+func (m *MockProduct) RenameCategory(ctx context.Context, dto dto.RenameCategoryDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCategory", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameCategory indicates an expected call of RenameCategory.
+func (mr *MockProductMockRecorder) RenameCategory(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCategory", reflect.TypeOf((*MockProduct)(nil).RenameCategory), ctx, dto)
+}
+
+// ReorderCategories mocks base method.
+func (m *MockProduct) ReorderCategories(ctx context.Context, categoryIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderCategories", ctx, categoryIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderCategories indicates an expected call of ReorderCategories.
+func (mr *MockProductMockRecorder) ReorderCategories(ctx, categoryIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderCategories", reflect.TypeOf((*MockProduct)(nil).ReorderCategories), ctx, categoryIDs)
+}
+
 // Update mocks base method.
 func (m *MockProduct) Update(ctx context.Context, dto dto.UpdateProductDTO) error {
 	m.ctrl.T.Helper()
@@ -169,6 +242,35 @@ func (mr *MockProductMockRecorder) Update(ctx, dto interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProduct)(nil).Update), ctx, dto)
 }
 
+// UpdateOptionGroups mocks base method.
+func (m *MockProduct) UpdateOptionGroups(ctx context.Context, dto dto.UpdateOptionGroupsDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOptionGroups", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOptionGroups indicates an expected call of UpdateOptionGroups.
+func (mr *MockProductMockRecorder) UpdateOptionGroups(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOptionGroups", reflect.TypeOf((*MockProduct)(nil).UpdateOptionGroups), ctx, dto)
+}
+
+// UploadImage mocks base method.
+func (m *MockProduct) UploadImage(ctx context.Context, productID string, data []byte) (domain.ImageURLs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadImage", ctx, productID, data)
+	ret0, _ := ret[0].(domain.ImageURLs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadImage indicates an expected call of UploadImage.
+func (mr *MockProductMockRecorder) UploadImage(ctx, productID, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadImage", reflect.TypeOf((*MockProduct)(nil).UploadImage), ctx, productID, data)
+}
+
 // MockOrder is a mock of Order interface.
 type MockOrder struct {
 	ctrl     *gomock.Controller
@@ -222,6 +324,34 @@ func (mr *MockOrderMockRecorder) CalculateDiscountedAmount(amount, discountPerce
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateDiscountedAmount", reflect.TypeOf((*MockOrder)(nil).CalculateDiscountedAmount), amount, discountPercent)
 }
 
+// CancelOrder mocks base method.
+func (m *MockOrder) CancelOrder(ctx context.Context, orderID, explanation string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", ctx, orderID, explanation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockOrderMockRecorder) CancelOrder(ctx, orderID, explanation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockOrder)(nil).CancelOrder), ctx, orderID, explanation)
+}
+
+// CompleteOrder mocks base method.
+func (m *MockOrder) CompleteOrder(ctx context.Context, orderID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteOrder", ctx, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteOrder indicates an expected call of CompleteOrder.
+func (mr *MockOrderMockRecorder) CompleteOrder(ctx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteOrder", reflect.TypeOf((*MockOrder)(nil).CompleteOrder), ctx, orderID)
+}
+
 // CreateUserOrder mocks base method.
 func (m *MockOrder) CreateUserOrder(ctx context.Context, dto dto.CreateUserOrderDTO) (string, error) {
 	m.ctrl.T.Helper()
@@ -252,6 +382,21 @@ func (mr *MockOrderMockRecorder) CreateWorkerOrder(ctx, orderDTO interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkerOrder", reflect.TypeOf((*MockOrder)(nil).CreateWorkerOrder), ctx, orderDTO)
 }
 
+// GetCurrentOrderByCustomerID mocks base method.
+func (m *MockOrder) GetCurrentOrderByCustomerID(ctx context.Context, customerID string) (domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentOrderByCustomerID", ctx, customerID)
+	ret0, _ := ret[0].(domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentOrderByCustomerID indicates an expected call of GetCurrentOrderByCustomerID.
+func (mr *MockOrderMockRecorder) GetCurrentOrderByCustomerID(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentOrderByCustomerID", reflect.TypeOf((*MockOrder)(nil).GetCurrentOrderByCustomerID), ctx, customerID)
+}
+
 // GetLastOrderByCustomerID mocks base method.
 func (m *MockOrder) GetLastOrderByCustomerID(ctx context.Context, customerID string) (domain.Order, error) {
 	m.ctrl.T.Helper()
@@ -297,197 +442,1311 @@ func (mr *MockOrderMockRecorder) GetOrderByNanoIDAt(ctx, nanoID, from, to interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByNanoIDAt", reflect.TypeOf((*MockOrder)(nil).GetOrderByNanoIDAt), ctx, nanoID, from, to)
 }
 
-// MockUser is a mock of User interface.
-type MockUser struct {
+// ListOrders mocks base method.
+func (m *MockOrder) ListOrders(ctx context.Context, listDTO dto.ListOrdersDTO) (dto.OrdersPageDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", ctx, listDTO)
+	ret0, _ := ret[0].(dto.OrdersPageDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockOrderMockRecorder) ListOrders(ctx, listDTO interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrder)(nil).ListOrders), ctx, listDTO)
+}
+
+// MarkCartProductReady mocks base method.
+func (m *MockOrder) MarkCartProductReady(ctx context.Context, orderID string, line int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkCartProductReady", ctx, orderID, line)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkCartProductReady indicates an expected call of MarkCartProductReady.
+func (mr *MockOrderMockRecorder) MarkCartProductReady(ctx, orderID, line interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkCartProductReady", reflect.TypeOf((*MockOrder)(nil).MarkCartProductReady), ctx, orderID, line)
+}
+
+// MarkOrderReady mocks base method.
+func (m *MockOrder) MarkOrderReady(ctx context.Context, orderID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOrderReady", ctx, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOrderReady indicates an expected call of MarkOrderReady.
+func (mr *MockOrderMockRecorder) MarkOrderReady(ctx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOrderReady", reflect.TypeOf((*MockOrder)(nil).MarkOrderReady), ctx, orderID)
+}
+
+// VerifyOrder mocks base method.
+func (m *MockOrder) VerifyOrder(ctx context.Context, orderID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyOrder", ctx, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyOrder indicates an expected call of VerifyOrder.
+func (mr *MockOrderMockRecorder) VerifyOrder(ctx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyOrder", reflect.TypeOf((*MockOrder)(nil).VerifyOrder), ctx, orderID)
+}
+
+// MockPromoCode is a mock of PromoCode interface.
+type MockPromoCode struct {
 	ctrl     *gomock.Controller
-	recorder *MockUserMockRecorder
+	recorder *MockPromoCodeMockRecorder
 }
 
-// MockUserMockRecorder is the mock recorder for MockUser.
-type MockUserMockRecorder struct {
-	mock *MockUser
+// MockPromoCodeMockRecorder is the mock recorder for MockPromoCode.
+type MockPromoCodeMockRecorder struct {
+	mock *MockPromoCode
 }
 
-// NewMockUser creates a new mock instance.
-func NewMockUser(ctrl *gomock.Controller) *MockUser {
-	mock := &MockUser{ctrl: ctrl}
-	mock.recorder = &MockUserMockRecorder{mock}
+// NewMockPromoCode creates a new mock instance.
+func NewMockPromoCode(ctrl *gomock.Controller) *MockPromoCode {
+	mock := &MockPromoCode{ctrl: ctrl}
+	mock.recorder = &MockPromoCodeMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockUser) EXPECT() *MockUserMockRecorder {
+func (m *MockPromoCode) EXPECT() *MockPromoCodeMockRecorder {
 	return m.recorder
 }
 
-// GetAdminByLogin mocks base method.
-func (m *MockUser) GetAdminByLogin(ctx context.Context, login string) (domain.Admin, error) {
+// Apply mocks base method.
+func (m *MockPromoCode) Apply(ctx context.Context, code string, cart []domain.CartProduct, amount int64) (domain.PromoCode, float64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAdminByLogin", ctx, login)
-	ret0, _ := ret[0].(domain.Admin)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Apply", ctx, code, cart, amount)
+	ret0, _ := ret[0].(domain.PromoCode)
+	ret1, _ := ret[1].(float64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
-// GetAdminByLogin indicates an expected call of GetAdminByLogin.
-func (mr *MockUserMockRecorder) GetAdminByLogin(ctx, login interface{}) *gomock.Call {
+// Apply indicates an expected call of Apply.
+func (mr *MockPromoCodeMockRecorder) Apply(ctx, code, cart, amount interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminByLogin", reflect.TypeOf((*MockUser)(nil).GetAdminByLogin), ctx, login)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockPromoCode)(nil).Apply), ctx, code, cart, amount)
 }
 
-// GetAdminByRefreshToken mocks base method.
-func (m *MockUser) GetAdminByRefreshToken(ctx context.Context, adminID, token string) (domain.Admin, error) {
+// Create mocks base method.
+func (m *MockPromoCode) Create(ctx context.Context, dto dto.CreatePromoCodeDTO) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAdminByRefreshToken", ctx, adminID, token)
-	ret0, _ := ret[0].(domain.Admin)
+	ret := m.ctrl.Call(m, "Create", ctx, dto)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAdminByRefreshToken indicates an expected call of GetAdminByRefreshToken.
-func (mr *MockUserMockRecorder) GetAdminByRefreshToken(ctx, adminID, token interface{}) *gomock.Call {
+// Create indicates an expected call of Create.
+func (mr *MockPromoCodeMockRecorder) Create(ctx, dto interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminByRefreshToken", reflect.TypeOf((*MockUser)(nil).GetAdminByRefreshToken), ctx, adminID, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPromoCode)(nil).Create), ctx, dto)
 }
 
-// GetCustomerByPhoneNumber mocks base method.
-func (m *MockUser) GetCustomerByPhoneNumber(ctx context.Context, phoneNumber string) (domain.Customer, error) {
+// Delete mocks base method.
+func (m *MockPromoCode) Delete(ctx context.Context, promoCodeID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCustomerByPhoneNumber", ctx, phoneNumber)
-	ret0, _ := ret[0].(domain.Customer)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Delete", ctx, promoCodeID)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetCustomerByPhoneNumber indicates an expected call of GetCustomerByPhoneNumber.
-func (mr *MockUserMockRecorder) GetCustomerByPhoneNumber(ctx, phoneNumber interface{}) *gomock.Call {
+// Delete indicates an expected call of Delete.
+func (mr *MockPromoCodeMockRecorder) Delete(ctx, promoCodeID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerByPhoneNumber", reflect.TypeOf((*MockUser)(nil).GetCustomerByPhoneNumber), ctx, phoneNumber)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPromoCode)(nil).Delete), ctx, promoCodeID)
 }
 
-// SaveAdmin mocks base method.
-func (m *MockUser) SaveAdmin(ctx context.Context, admin domain.Admin) (string, error) {
+// GetAll mocks base method.
+func (m *MockPromoCode) GetAll(ctx context.Context) ([]domain.PromoCode, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveAdmin", ctx, admin)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]domain.PromoCode)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SaveAdmin indicates an expected call of SaveAdmin.
-func (mr *MockUserMockRecorder) SaveAdmin(ctx, admin interface{}) *gomock.Call {
+// GetAll indicates an expected call of GetAll.
+func (mr *MockPromoCodeMockRecorder) GetAll(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAdmin", reflect.TypeOf((*MockUser)(nil).SaveAdmin), ctx, admin)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPromoCode)(nil).GetAll), ctx)
 }
 
-// SaveCustomer mocks base method.
-func (m *MockUser) SaveCustomer(ctx context.Context, customer domain.Customer) (string, error) {
+// GetByID mocks base method.
+func (m *MockPromoCode) GetByID(ctx context.Context, promoCodeID string) (domain.PromoCode, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveCustomer", ctx, customer)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetByID", ctx, promoCodeID)
+	ret0, _ := ret[0].(domain.PromoCode)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SaveCustomer indicates an expected call of SaveCustomer.
-func (mr *MockUserMockRecorder) SaveCustomer(ctx, customer interface{}) *gomock.Call {
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPromoCodeMockRecorder) GetByID(ctx, promoCodeID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCustomer", reflect.TypeOf((*MockUser)(nil).SaveCustomer), ctx, customer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPromoCode)(nil).GetByID), ctx, promoCodeID)
 }
 
-// SaveSession mocks base method.
-func (m *MockUser) SaveSession(ctx context.Context, dto dto.SaveSessionDTO) error {
+// Redeem mocks base method.
+func (m *MockPromoCode) Redeem(ctx context.Context, promoCode domain.PromoCode, customerID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveSession", ctx, dto)
+	ret := m.ctrl.Call(m, "Redeem", ctx, promoCode, customerID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SaveSession indicates an expected call of SaveSession.
-func (mr *MockUserMockRecorder) SaveSession(ctx, dto interface{}) *gomock.Call {
+// Redeem indicates an expected call of Redeem.
+func (mr *MockPromoCodeMockRecorder) Redeem(ctx, promoCode, customerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSession", reflect.TypeOf((*MockUser)(nil).SaveSession), ctx, dto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockPromoCode)(nil).Redeem), ctx, promoCode, customerID)
 }
 
-// MockAuth is a mock of Auth interface.
-type MockAuth struct {
+// Release mocks base method.
+func (m *MockPromoCode) Release(ctx context.Context, promoCodeID, customerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, promoCodeID, customerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockPromoCodeMockRecorder) Release(ctx, promoCodeID, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockPromoCode)(nil).Release), ctx, promoCodeID, customerID)
+}
+
+// Update mocks base method.
+func (m *MockPromoCode) Update(ctx context.Context, dto dto.UpdatePromoCodeDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPromoCodeMockRecorder) Update(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPromoCode)(nil).Update), ctx, dto)
+}
+
+// MockDeliveryZone is a mock of DeliveryZone interface.
+type MockDeliveryZone struct {
 	ctrl     *gomock.Controller
-	recorder *MockAuthMockRecorder
+	recorder *MockDeliveryZoneMockRecorder
 }
 
-// MockAuthMockRecorder is the mock recorder for MockAuth.
-type MockAuthMockRecorder struct {
-	mock *MockAuth
+// MockDeliveryZoneMockRecorder is the mock recorder for MockDeliveryZone.
+type MockDeliveryZoneMockRecorder struct {
+	mock *MockDeliveryZone
 }
 
-// NewMockAuth creates a new mock instance.
-func NewMockAuth(ctrl *gomock.Controller) *MockAuth {
-	mock := &MockAuth{ctrl: ctrl}
-	mock.recorder = &MockAuthMockRecorder{mock}
+// NewMockDeliveryZone creates a new mock instance.
+func NewMockDeliveryZone(ctrl *gomock.Controller) *MockDeliveryZone {
+	mock := &MockDeliveryZone{ctrl: ctrl}
+	mock.recorder = &MockDeliveryZoneMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockAuth) EXPECT() *MockAuthMockRecorder {
+func (m *MockDeliveryZone) EXPECT() *MockDeliveryZoneMockRecorder {
 	return m.recorder
 }
 
-// LoginAdmin mocks base method.
-func (m *MockAuth) LoginAdmin(ctx context.Context, dto dto.LoginAdminDTO) (auth.Pair, error) {
+// Create mocks base method.
+func (m *MockDeliveryZone) Create(ctx context.Context, dto dto.CreateDeliveryZoneDTO) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoginAdmin", ctx, dto)
-	ret0, _ := ret[0].(auth.Pair)
+	ret := m.ctrl.Call(m, "Create", ctx, dto)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// LoginAdmin indicates an expected call of LoginAdmin.
-func (mr *MockAuthMockRecorder) LoginAdmin(ctx, dto interface{}) *gomock.Call {
+// Create indicates an expected call of Create.
+func (mr *MockDeliveryZoneMockRecorder) Create(ctx, dto interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginAdmin", reflect.TypeOf((*MockAuth)(nil).LoginAdmin), ctx, dto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeliveryZone)(nil).Create), ctx, dto)
 }
 
-// RefreshAdminToken mocks base method.
-func (m *MockAuth) RefreshAdminToken(ctx context.Context, adminID, token string) (auth.Pair, error) {
+// Delete mocks base method.
+func (m *MockDeliveryZone) Delete(ctx context.Context, deliveryZoneID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RefreshAdminToken", ctx, adminID, token)
-	ret0, _ := ret[0].(auth.Pair)
+	ret := m.ctrl.Call(m, "Delete", ctx, deliveryZoneID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDeliveryZoneMockRecorder) Delete(ctx, deliveryZoneID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDeliveryZone)(nil).Delete), ctx, deliveryZoneID)
+}
+
+// GetAll mocks base method.
+func (m *MockDeliveryZone) GetAll(ctx context.Context) ([]domain.DeliveryZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]domain.DeliveryZone)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// RefreshAdminToken indicates an expected call of RefreshAdminToken.
-func (mr *MockAuthMockRecorder) RefreshAdminToken(ctx, adminID, token interface{}) *gomock.Call {
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDeliveryZoneMockRecorder) GetAll(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshAdminToken", reflect.TypeOf((*MockAuth)(nil).RefreshAdminToken), ctx, adminID, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDeliveryZone)(nil).GetAll), ctx)
 }
 
-// RegisterAdmin mocks base method.
-func (m *MockAuth) RegisterAdmin(ctx context.Context, dto dto.RegisterAdminDTO) (string, error) {
+// GetByID mocks base method.
+func (m *MockDeliveryZone) GetByID(ctx context.Context, deliveryZoneID string) (domain.DeliveryZone, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterAdmin", ctx, dto)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetByID", ctx, deliveryZoneID)
+	ret0, _ := ret[0].(domain.DeliveryZone)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// RegisterAdmin indicates an expected call of RegisterAdmin.
-func (mr *MockAuthMockRecorder) RegisterAdmin(ctx, dto interface{}) *gomock.Call {
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDeliveryZoneMockRecorder) GetByID(ctx, deliveryZoneID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAdmin", reflect.TypeOf((*MockAuth)(nil).RegisterAdmin), ctx, dto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDeliveryZone)(nil).GetByID), ctx, deliveryZoneID)
 }
 
-// RegisterCustomer mocks base method.
-func (m *MockAuth) RegisterCustomer(ctx context.Context, dto dto.RegisterCustomerDTO) (string, error) {
+// Resolve mocks base method.
+func (m *MockDeliveryZone) Resolve(ctx context.Context, address domain.OrderDeliveryAddress) (domain.DeliveryZone, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterCustomer", ctx, dto)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Resolve", ctx, address)
+	ret0, _ := ret[0].(domain.DeliveryZone)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// RegisterCustomer indicates an expected call of RegisterCustomer.
-func (mr *MockAuthMockRecorder) RegisterCustomer(ctx, dto interface{}) *gomock.Call {
+// Resolve indicates an expected call of Resolve.
+func (mr *MockDeliveryZoneMockRecorder) Resolve(ctx, address interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterCustomer", reflect.TypeOf((*MockAuth)(nil).RegisterCustomer), ctx, dto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockDeliveryZone)(nil).Resolve), ctx, address)
+}
+
+// Update mocks base method.
+func (m *MockDeliveryZone) Update(ctx context.Context, dto dto.UpdateDeliveryZoneDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDeliveryZoneMockRecorder) Update(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeliveryZone)(nil).Update), ctx, dto)
+}
+
+// MockDeliverySlot is a mock of DeliverySlot interface.
+type MockDeliverySlot struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliverySlotMockRecorder
+}
+
+// MockDeliverySlotMockRecorder is the mock recorder for MockDeliverySlot.
+type MockDeliverySlotMockRecorder struct {
+	mock *MockDeliverySlot
+}
+
+// NewMockDeliverySlot creates a new mock instance.
+func NewMockDeliverySlot(ctrl *gomock.Controller) *MockDeliverySlot {
+	mock := &MockDeliverySlot{ctrl: ctrl}
+	mock.recorder = &MockDeliverySlotMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliverySlot) EXPECT() *MockDeliverySlotMockRecorder {
+	return m.recorder
+}
+
+// GetAvailable mocks base method.
+func (m *MockDeliverySlot) GetAvailable(ctx context.Context, date string) ([]domain.DeliverySlot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailable", ctx, date)
+	ret0, _ := ret[0].([]domain.DeliverySlot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailable indicates an expected call of GetAvailable.
+func (mr *MockDeliverySlotMockRecorder) GetAvailable(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailable", reflect.TypeOf((*MockDeliverySlot)(nil).GetAvailable), ctx, date)
+}
+
+// Release mocks base method.
+func (m *MockDeliverySlot) Release(ctx context.Context, startsAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, startsAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockDeliverySlotMockRecorder) Release(ctx, startsAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockDeliverySlot)(nil).Release), ctx, startsAt)
+}
+
+// Reserve mocks base method.
+func (m *MockDeliverySlot) Reserve(ctx context.Context, at time.Time) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, at)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockDeliverySlotMockRecorder) Reserve(ctx, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockDeliverySlot)(nil).Reserve), ctx, at)
+}
+
+// MockOrderEvents is a mock of OrderEvents interface.
+type MockOrderEvents struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderEventsMockRecorder
+}
+
+// MockOrderEventsMockRecorder is the mock recorder for MockOrderEvents.
+type MockOrderEventsMockRecorder struct {
+	mock *MockOrderEvents
+}
+
+// NewMockOrderEvents creates a new mock instance.
+func NewMockOrderEvents(ctrl *gomock.Controller) *MockOrderEvents {
+	mock := &MockOrderEvents{ctrl: ctrl}
+	mock.recorder = &MockOrderEventsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderEvents) EXPECT() *MockOrderEventsMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockOrderEvents) Publish(eventType string, event domain.OrderEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", eventType, event)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockOrderEventsMockRecorder) Publish(eventType, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockOrderEvents)(nil).Publish), eventType, event)
+}
+
+// SubscribeCustomer mocks base method.
+func (m *MockOrderEvents) SubscribeCustomer(customerID string, lastEventID uint64) (*eventbus.Subscription, []eventbus.Event) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeCustomer", customerID, lastEventID)
+	ret0, _ := ret[0].(*eventbus.Subscription)
+	ret1, _ := ret[1].([]eventbus.Event)
+	return ret0, ret1
+}
+
+// SubscribeCustomer indicates an expected call of SubscribeCustomer.
+func (mr *MockOrderEventsMockRecorder) SubscribeCustomer(customerID, lastEventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeCustomer", reflect.TypeOf((*MockOrderEvents)(nil).SubscribeCustomer), customerID, lastEventID)
+}
+
+// SubscribeWorkers mocks base method.
+func (m *MockOrderEvents) SubscribeWorkers(lastEventID uint64) (*eventbus.Subscription, []eventbus.Event) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeWorkers", lastEventID)
+	ret0, _ := ret[0].(*eventbus.Subscription)
+	ret1, _ := ret[1].([]eventbus.Event)
+	return ret0, ret1
+}
+
+// SubscribeWorkers indicates an expected call of SubscribeWorkers.
+func (mr *MockOrderEventsMockRecorder) SubscribeWorkers(lastEventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeWorkers", reflect.TypeOf((*MockOrderEvents)(nil).SubscribeWorkers), lastEventID)
+}
+
+// MockMeta is a mock of Meta interface.
+type MockMeta struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetaMockRecorder
+}
+
+// MockMetaMockRecorder is the mock recorder for MockMeta.
+type MockMetaMockRecorder struct {
+	mock *MockMeta
+}
+
+// NewMockMeta creates a new mock instance.
+func NewMockMeta(ctrl *gomock.Controller) *MockMeta {
+	mock := &MockMeta{ctrl: ctrl}
+	mock.recorder = &MockMetaMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMeta) EXPECT() *MockMetaMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockMeta) Get(ctx context.Context) (domain.BusinessMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx)
+	ret0, _ := ret[0].(domain.BusinessMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockMetaMockRecorder) Get(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMeta)(nil).Get), ctx)
+}
+
+// Load mocks base method.
+func (m *MockMeta) Load(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockMetaMockRecorder) Load(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockMeta)(nil).Load), ctx)
+}
+
+// Schedule mocks base method.
+func (m *MockMeta) Schedule() domain.Schedule {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule")
+	ret0, _ := ret[0].(domain.Schedule)
+	return ret0
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockMetaMockRecorder) Schedule() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockMeta)(nil).Schedule))
+}
+
+// UpdateSchedule mocks base method.
+func (m *MockMeta) UpdateSchedule(ctx context.Context, dto dto.UpdateScheduleDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSchedule", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSchedule indicates an expected call of UpdateSchedule.
+func (mr *MockMetaMockRecorder) UpdateSchedule(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSchedule", reflect.TypeOf((*MockMeta)(nil).UpdateSchedule), ctx, dto)
+}
+
+// Watch mocks base method.
+func (m *MockMeta) Watch(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Watch", ctx)
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockMetaMockRecorder) Watch(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockMeta)(nil).Watch), ctx)
+}
+
+// MockUser is a mock of User interface.
+type MockUser struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserMockRecorder
+}
+
+// MockUserMockRecorder is the mock recorder for MockUser.
+type MockUserMockRecorder struct {
+	mock *MockUser
+}
+
+// NewMockUser creates a new mock instance.
+func NewMockUser(ctrl *gomock.Controller) *MockUser {
+	mock := &MockUser{ctrl: ctrl}
+	mock.recorder = &MockUserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUser) EXPECT() *MockUserMockRecorder {
+	return m.recorder
+}
+
+// DisableWorker mocks base method.
+func (m *MockUser) DisableWorker(ctx context.Context, workerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableWorker", ctx, workerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableWorker indicates an expected call of DisableWorker.
+func (mr *MockUserMockRecorder) DisableWorker(ctx, workerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableWorker", reflect.TypeOf((*MockUser)(nil).DisableWorker), ctx, workerID)
+}
+
+// GetAdminByLogin mocks base method.
+func (m *MockUser) GetAdminByLogin(ctx context.Context, login string) (domain.Admin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdminByLogin", ctx, login)
+	ret0, _ := ret[0].(domain.Admin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdminByLogin indicates an expected call of GetAdminByLogin.
+func (mr *MockUserMockRecorder) GetAdminByLogin(ctx, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminByLogin", reflect.TypeOf((*MockUser)(nil).GetAdminByLogin), ctx, login)
+}
+
+// GetCustomerByPhoneNumber mocks base method.
+func (m *MockUser) GetCustomerByPhoneNumber(ctx context.Context, phoneNumber string) (domain.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomerByPhoneNumber", ctx, phoneNumber)
+	ret0, _ := ret[0].(domain.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomerByPhoneNumber indicates an expected call of GetCustomerByPhoneNumber.
+func (mr *MockUserMockRecorder) GetCustomerByPhoneNumber(ctx, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerByPhoneNumber", reflect.TypeOf((*MockUser)(nil).GetCustomerByPhoneNumber), ctx, phoneNumber)
+}
+
+// GetWorkerByLogin mocks base method.
+func (m *MockUser) GetWorkerByLogin(ctx context.Context, login string) (domain.Worker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkerByLogin", ctx, login)
+	ret0, _ := ret[0].(domain.Worker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkerByLogin indicates an expected call of GetWorkerByLogin.
+func (mr *MockUserMockRecorder) GetWorkerByLogin(ctx, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkerByLogin", reflect.TypeOf((*MockUser)(nil).GetWorkerByLogin), ctx, login)
+}
+
+// GetWorkers mocks base method.
+func (m *MockUser) GetWorkers(ctx context.Context) ([]domain.Worker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkers", ctx)
+	ret0, _ := ret[0].([]domain.Worker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkers indicates an expected call of GetWorkers.
+func (mr *MockUserMockRecorder) GetWorkers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkers", reflect.TypeOf((*MockUser)(nil).GetWorkers), ctx)
+}
+
+// SaveAdmin mocks base method.
+func (m *MockUser) SaveAdmin(ctx context.Context, admin domain.Admin) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAdmin", ctx, admin)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveAdmin indicates an expected call of SaveAdmin.
+func (mr *MockUserMockRecorder) SaveAdmin(ctx, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAdmin", reflect.TypeOf((*MockUser)(nil).SaveAdmin), ctx, admin)
+}
+
+// SaveCustomer mocks base method.
+func (m *MockUser) SaveCustomer(ctx context.Context, customer domain.Customer) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCustomer", ctx, customer)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveCustomer indicates an expected call of SaveCustomer.
+func (mr *MockUserMockRecorder) SaveCustomer(ctx, customer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCustomer", reflect.TypeOf((*MockUser)(nil).SaveCustomer), ctx, customer)
+}
+
+// SaveWorker mocks base method.
+func (m *MockUser) SaveWorker(ctx context.Context, worker domain.Worker) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWorker", ctx, worker)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveWorker indicates an expected call of SaveWorker.
+func (mr *MockUserMockRecorder) SaveWorker(ctx, worker interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWorker", reflect.TypeOf((*MockUser)(nil).SaveWorker), ctx, worker)
+}
+
+// UpdatePasswordHash mocks base method.
+func (m *MockUser) UpdatePasswordHash(ctx context.Context, userID string, role domain.Role, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasswordHash", ctx, userID, role, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasswordHash indicates an expected call of UpdatePasswordHash.
+func (mr *MockUserMockRecorder) UpdatePasswordHash(ctx, userID, role, passwordHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordHash", reflect.TypeOf((*MockUser)(nil).UpdatePasswordHash), ctx, userID, role, passwordHash)
+}
+
+// UpdateWorkerPassword mocks base method.
+func (m *MockUser) UpdateWorkerPassword(ctx context.Context, workerID, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkerPassword", ctx, workerID, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkerPassword indicates an expected call of UpdateWorkerPassword.
+func (mr *MockUserMockRecorder) UpdateWorkerPassword(ctx, workerID, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkerPassword", reflect.TypeOf((*MockUser)(nil).UpdateWorkerPassword), ctx, workerID, password)
+}
+
+// MockSession is a mock of Session interface.
+type MockSession struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionMockRecorder
+}
+
+// MockSessionMockRecorder is the mock recorder for MockSession.
+type MockSessionMockRecorder struct {
+	mock *MockSession
+}
+
+// NewMockSession creates a new mock instance.
+func NewMockSession(ctrl *gomock.Controller) *MockSession {
+	mock := &MockSession{ctrl: ctrl}
+	mock.recorder = &MockSessionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSession) EXPECT() *MockSessionMockRecorder {
+	return m.recorder
+}
+
+// GetByUser mocks base method.
+func (m *MockSession) GetByUser(ctx context.Context, role domain.Role, userID string) ([]domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, role, userID)
+	ret0, _ := ret[0].([]domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockSessionMockRecorder) GetByUser(ctx, role, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockSession)(nil).GetByUser), ctx, role, userID)
+}
+
+// Revoke mocks base method.
+func (m *MockSession) Revoke(ctx context.Context, role domain.Role, userID, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, role, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockSessionMockRecorder) Revoke(ctx, role, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockSession)(nil).Revoke), ctx, role, userID, sessionID)
+}
+
+// RevokeAll mocks base method.
+func (m *MockSession) RevokeAll(ctx context.Context, role domain.Role, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAll", ctx, role, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAll indicates an expected call of RevokeAll.
+func (mr *MockSessionMockRecorder) RevokeAll(ctx, role, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAll", reflect.TypeOf((*MockSession)(nil).RevokeAll), ctx, role, userID)
+}
+
+// MockAuth is a mock of Auth interface.
+type MockAuth struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthMockRecorder
+}
+
+// MockAuthMockRecorder is the mock recorder for MockAuth.
+type MockAuthMockRecorder struct {
+	mock *MockAuth
+}
+
+// NewMockAuth creates a new mock instance.
+func NewMockAuth(ctrl *gomock.Controller) *MockAuth {
+	mock := &MockAuth{ctrl: ctrl}
+	mock.recorder = &MockAuthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuth) EXPECT() *MockAuthMockRecorder {
+	return m.recorder
+}
+
+// JWKS mocks base method.
+func (m *MockAuth) JWKS() auth.JWKS {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(auth.JWKS)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockAuthMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockAuth)(nil).JWKS))
+}
+
+// LoginAdmin mocks base method.
+func (m *MockAuth) LoginAdmin(ctx context.Context, dto dto.LoginAdminDTO) (auth.Pair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginAdmin", ctx, dto)
+	ret0, _ := ret[0].(auth.Pair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginAdmin indicates an expected call of LoginAdmin.
+func (mr *MockAuthMockRecorder) LoginAdmin(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginAdmin", reflect.TypeOf((*MockAuth)(nil).LoginAdmin), ctx, dto)
+}
+
+// LoginCustomer mocks base method.
+func (m *MockAuth) LoginCustomer(ctx context.Context, dto dto.LoginCustomerDTO) (auth.Pair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginCustomer", ctx, dto)
+	ret0, _ := ret[0].(auth.Pair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginCustomer indicates an expected call of LoginCustomer.
+func (mr *MockAuthMockRecorder) LoginCustomer(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginCustomer", reflect.TypeOf((*MockAuth)(nil).LoginCustomer), ctx, dto)
+}
+
+// LoginWorker mocks base method.
+func (m *MockAuth) LoginWorker(ctx context.Context, dto dto.LoginWorkerDTO) (auth.Pair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginWorker", ctx, dto)
+	ret0, _ := ret[0].(auth.Pair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginWorker indicates an expected call of LoginWorker.
+func (mr *MockAuthMockRecorder) LoginWorker(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginWorker", reflect.TypeOf((*MockAuth)(nil).LoginWorker), ctx, dto)
+}
+
+// Logout mocks base method.
+func (m *MockAuth) Logout(ctx context.Context, claims auth.Claims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthMockRecorder) Logout(ctx, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuth)(nil).Logout), ctx, claims)
+}
+
+// RefreshTokens mocks base method.
+func (m *MockAuth) RefreshTokens(ctx context.Context, dto dto.RefreshTokensDTO) (auth.Pair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTokens", ctx, dto)
+	ret0, _ := ret[0].(auth.Pair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshTokens indicates an expected call of RefreshTokens.
+func (mr *MockAuthMockRecorder) RefreshTokens(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokens", reflect.TypeOf((*MockAuth)(nil).RefreshTokens), ctx, dto)
+}
+
+// RegisterAdmin mocks base method.
+func (m *MockAuth) RegisterAdmin(ctx context.Context, dto dto.RegisterAdminDTO) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterAdmin", ctx, dto)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterAdmin indicates an expected call of RegisterAdmin.
+func (mr *MockAuthMockRecorder) RegisterAdmin(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAdmin", reflect.TypeOf((*MockAuth)(nil).RegisterAdmin), ctx, dto)
+}
+
+// RegisterCustomer mocks base method.
+func (m *MockAuth) RegisterCustomer(ctx context.Context, dto dto.RegisterCustomerDTO) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterCustomer", ctx, dto)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterCustomer indicates an expected call of RegisterCustomer.
+func (mr *MockAuthMockRecorder) RegisterCustomer(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterCustomer", reflect.TypeOf((*MockAuth)(nil).RegisterCustomer), ctx, dto)
+}
+
+// RegisterWorker mocks base method.
+func (m *MockAuth) RegisterWorker(ctx context.Context, dto dto.RegisterWorkerDTO) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterWorker", ctx, dto)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterWorker indicates an expected call of RegisterWorker.
+func (mr *MockAuthMockRecorder) RegisterWorker(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterWorker", reflect.TypeOf((*MockAuth)(nil).RegisterWorker), ctx, dto)
+}
+
+// RequestCustomerOTP mocks base method.
+func (m *MockAuth) RequestCustomerOTP(ctx context.Context, phoneNumber string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestCustomerOTP", ctx, phoneNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestCustomerOTP indicates an expected call of RequestCustomerOTP.
+func (mr *MockAuthMockRecorder) RequestCustomerOTP(ctx, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestCustomerOTP", reflect.TypeOf((*MockAuth)(nil).RequestCustomerOTP), ctx, phoneNumber)
+}
+
+// ResetWorkerPassword mocks base method.
+func (m *MockAuth) ResetWorkerPassword(ctx context.Context, workerID, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetWorkerPassword", ctx, workerID, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetWorkerPassword indicates an expected call of ResetWorkerPassword.
+func (mr *MockAuthMockRecorder) ResetWorkerPassword(ctx, workerID, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetWorkerPassword", reflect.TypeOf((*MockAuth)(nil).ResetWorkerPassword), ctx, workerID, password)
+}
+
+// MockRevocation is a mock of Revocation interface.
+type MockRevocation struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevocationMockRecorder
+}
+
+// MockRevocationMockRecorder is the mock recorder for MockRevocation.
+type MockRevocationMockRecorder struct {
+	mock *MockRevocation
+}
+
+// NewMockRevocation creates a new mock instance.
+func NewMockRevocation(ctrl *gomock.Controller) *MockRevocation {
+	mock := &MockRevocation{ctrl: ctrl}
+	mock.recorder = &MockRevocationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevocation) EXPECT() *MockRevocationMockRecorder {
+	return m.recorder
+}
+
+// IsRevoked mocks base method.
+func (m *MockRevocation) IsRevoked(claims auth.Claims) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", claims)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockRevocationMockRecorder) IsRevoked(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockRevocation)(nil).IsRevoked), claims)
+}
+
+// Load mocks base method.
+func (m *MockRevocation) Load(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockRevocationMockRecorder) Load(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockRevocation)(nil).Load), ctx)
+}
+
+// RevokeSession mocks base method.
+func (m *MockRevocation) RevokeSession(ctx context.Context, role domain.Role, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, role, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockRevocationMockRecorder) RevokeSession(ctx, role, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockRevocation)(nil).RevokeSession), ctx, role, sessionID)
+}
+
+// RevokeToken mocks base method.
+func (m *MockRevocation) RevokeToken(ctx context.Context, claims auth.Claims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockRevocationMockRecorder) RevokeToken(ctx, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockRevocation)(nil).RevokeToken), ctx, claims)
+}
+
+// RevokeUser mocks base method.
+func (m *MockRevocation) RevokeUser(ctx context.Context, role domain.Role, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUser", ctx, role, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUser indicates an expected call of RevokeUser.
+func (mr *MockRevocationMockRecorder) RevokeUser(ctx, role, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUser", reflect.TypeOf((*MockRevocation)(nil).RevokeUser), ctx, role, userID)
+}
+
+// Watch mocks base method.
+func (m *MockRevocation) Watch(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Watch", ctx)
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockRevocationMockRecorder) Watch(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockRevocation)(nil).Watch), ctx)
+}
+
+// MockPermission is a mock of Permission interface.
+type MockPermission struct {
+	ctrl     *gomock.Controller
+	recorder *MockPermissionMockRecorder
+}
+
+// MockPermissionMockRecorder is the mock recorder for MockPermission.
+type MockPermissionMockRecorder struct {
+	mock *MockPermission
+}
+
+// NewMockPermission creates a new mock instance.
+func NewMockPermission(ctrl *gomock.Controller) *MockPermission {
+	mock := &MockPermission{ctrl: ctrl}
+	mock.recorder = &MockPermissionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPermission) EXPECT() *MockPermissionMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockPermission) GetAll(ctx context.Context) ([]domain.RolePermissions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]domain.RolePermissions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockPermissionMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPermission)(nil).GetAll), ctx)
+}
+
+// Has mocks base method.
+func (m *MockPermission) Has(role domain.Role, permissions ...domain.Permission) bool {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{role}
+	for _, a := range permissions {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Has", varargs...)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Has indicates an expected call of Has.
+func (mr *MockPermissionMockRecorder) Has(role interface{}, permissions ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{role}, permissions...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Has", reflect.TypeOf((*MockPermission)(nil).Has), varargs...)
+}
+
+// Load mocks base method.
+func (m *MockPermission) Load(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockPermissionMockRecorder) Load(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockPermission)(nil).Load), ctx)
+}
+
+// Update mocks base method.
+func (m *MockPermission) Update(ctx context.Context, dto dto.UpdateRolePermissionsDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPermissionMockRecorder) Update(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPermission)(nil).Update), ctx, dto)
+}
+
+// Watch mocks base method.
+func (m *MockPermission) Watch(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Watch", ctx)
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockPermissionMockRecorder) Watch(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockPermission)(nil).Watch), ctx)
+}
+
+// MockIdempotency is a mock of Idempotency interface.
+type MockIdempotency struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyMockRecorder
+}
+
+// MockIdempotencyMockRecorder is the mock recorder for MockIdempotency.
+type MockIdempotencyMockRecorder struct {
+	mock *MockIdempotency
+}
+
+// NewMockIdempotency creates a new mock instance.
+func NewMockIdempotency(ctrl *gomock.Controller) *MockIdempotency {
+	mock := &MockIdempotency{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotency) EXPECT() *MockIdempotencyMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockIdempotency) Begin(ctx context.Context, key, requestHash string) (*domain.IdempotentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, key, requestHash)
+	ret0, _ := ret[0].(*domain.IdempotentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockIdempotencyMockRecorder) Begin(ctx, key, requestHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockIdempotency)(nil).Begin), ctx, key, requestHash)
+}
+
+// Complete mocks base method.
+func (m *MockIdempotency) Complete(ctx context.Context, key string, response domain.IdempotentResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, key, response)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyMockRecorder) Complete(ctx, key, response interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotency)(nil).Complete), ctx, key, response)
+}
+
+// Release mocks base method.
+func (m *MockIdempotency) Release(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyMockRecorder) Release(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotency)(nil).Release), ctx, key)
+}
+
+// MockBlobStore is a mock of BlobStore interface.
+type MockBlobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobStoreMockRecorder
+}
+
+// MockBlobStoreMockRecorder is the mock recorder for MockBlobStore.
+type MockBlobStoreMockRecorder struct {
+	mock *MockBlobStore
+}
+
+// NewMockBlobStore creates a new mock instance.
+func NewMockBlobStore(ctrl *gomock.Controller) *MockBlobStore {
+	mock := &MockBlobStore{ctrl: ctrl}
+	mock.recorder = &MockBlobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobStore) EXPECT() *MockBlobStoreMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockBlobStore) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBlobStoreMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlobStore)(nil).Delete), ctx, key)
+}
+
+// Put mocks base method.
+func (m *MockBlobStore) Put(ctx context.Context, key string, data []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, data)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockBlobStoreMockRecorder) Put(ctx, key, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockBlobStore)(nil).Put), ctx, key, data)
+}
+
+// MockSMSSender is a mock of SMSSender interface.
+type MockSMSSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockSMSSenderMockRecorder
+}
+
+// MockSMSSenderMockRecorder is the mock recorder for MockSMSSender.
+type MockSMSSenderMockRecorder struct {
+	mock *MockSMSSender
+}
+
+// NewMockSMSSender creates a new mock instance.
+func NewMockSMSSender(ctrl *gomock.Controller) *MockSMSSender {
+	mock := &MockSMSSender{ctrl: ctrl}
+	mock.recorder = &MockSMSSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSMSSender) EXPECT() *MockSMSSenderMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockSMSSender) Send(ctx context.Context, phoneNumber, text string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, phoneNumber, text)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockSMSSenderMockRecorder) Send(ctx, phoneNumber, text interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockSMSSender)(nil).Send), ctx, phoneNumber, text)
 }
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"encoding/json"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/pkg/eventbus"
+	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	customerTopicPrefix = "customer:"
+	// Workers and admins share the same channel
+	workersTopic = "role:worker"
+)
+
+type orderEventsService struct {
+	bus *eventbus.Bus
+}
+
+func NewOrderEventsService(bus *eventbus.Bus) OrderEvents {
+	return &orderEventsService{bus: bus}
+}
+
+func (o orderEventsService) Publish(eventType string, event domain.OrderEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		logger.Get().Error("could not marshal order event", zap.Error(err))
+		return
+	}
+	o.bus.Publish(customerTopicPrefix+event.CustomerID, eventType, data)
+	o.bus.Publish(workersTopic, eventType, data)
+}
+
+func (o orderEventsService) SubscribeCustomer(customerID string, lastEventID uint64) (*eventbus.Subscription, []eventbus.Event) {
+	return o.bus.Subscribe(customerTopicPrefix+customerID, lastEventID)
+}
+
+func (o orderEventsService) SubscribeWorkers(lastEventID uint64) (*eventbus.Subscription, []eventbus.Event) {
+	return o.bus.Subscribe(workersTopic, lastEventID)
+}
@@ -9,7 +9,15 @@ import (
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
 	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
+	"github.com/sonyamoonglade/sancho-backend/pkg/cursor"
+	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
 	"github.com/sonyamoonglade/sancho-backend/pkg/nanoid"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultOrdersPageLimit int64 = 20
+	MaxOrdersPageLimit     int64 = 100
 )
 
 type OrderConfig struct {
@@ -21,17 +29,29 @@ type OrderConfig struct {
 type orderService struct {
 	orderStorage         storage.Order
 	productService       Product
+	promoCodeService     PromoCode
+	deliveryZoneService  DeliveryZone
+	deliverySlotService  DeliverySlot
+	orderEvents          OrderEvents
 	orderConfig          OrderConfig
 	businessMetaProvider domain.MetaProvider
 }
 
 func NewOrderService(orderStorage storage.Order,
 	productService Product,
+	promoCodeService PromoCode,
+	deliveryZoneService DeliveryZone,
+	deliverySlotService DeliverySlot,
+	orderEvents OrderEvents,
 	orderConfig OrderConfig,
 	metaProvider domain.MetaProvider) Order {
 	return &orderService{
 		orderStorage:         orderStorage,
 		productService:       productService,
+		promoCodeService:     promoCodeService,
+		deliveryZoneService:  deliveryZoneService,
+		deliverySlotService:  deliverySlotService,
+		orderEvents:          orderEvents,
 		orderConfig:          orderConfig,
 		businessMetaProvider: metaProvider,
 	}
@@ -45,10 +65,40 @@ func (o *orderService) GetLastOrderByCustomerID(ctx context.Context, customerID
 	return o.orderStorage.GetLastOrderByCustomerID(ctx, customerID)
 }
 
+func (o *orderService) GetCurrentOrderByCustomerID(ctx context.Context, customerID string) (domain.Order, error) {
+	return o.orderStorage.GetCurrentOrderByCustomerID(ctx, customerID)
+}
+
 func (o *orderService) GetOrderByNanoIDAt(ctx context.Context, nanoID string, from, to time.Time) (domain.Order, error) {
 	return o.orderStorage.GetOrderByNanoIDAt(ctx, nanoID, from, to)
 }
 
+func (o *orderService) ListOrders(ctx context.Context, listDTO dto.ListOrdersDTO) (dto.OrdersPageDTO, error) {
+	limit := listDTO.Limit
+	if limit <= 0 {
+		limit = DefaultOrdersPageLimit
+	}
+	if limit > MaxOrdersPageLimit {
+		limit = MaxOrdersPageLimit
+	}
+	// Ask for one extra order to know if there's a next page
+	listDTO.Limit = limit + 1
+
+	orders, err := o.orderStorage.GetOrders(ctx, listDTO)
+	if err != nil {
+		return dto.OrdersPageDTO{}, err
+	}
+
+	page := dto.OrdersPageDTO{Orders: orders}
+	if int64(len(orders)) > limit {
+		page.Orders = orders[:limit]
+		last := page.Orders[limit-1]
+		nextCursor := cursor.New(last.CreatedAt, last.OrderID).Encode()
+		page.NextCursor = &nextCursor
+	}
+	return page, nil
+}
+
 // todo: test
 func (o *orderService) CreateWorkerOrder(ctx context.Context, dto dto.CreateWorkerOrderDTO) (string, error) {
 	amount, cartProducts, err := o.CalculateCartAmount(ctx, dto.Cart)
@@ -78,17 +128,23 @@ func (o *orderService) CreateWorkerOrder(ctx context.Context, dto dto.CreateWork
 	}
 
 	if dto.IsDelivered && dto.DeliveryAddress != nil {
-		meta := o.businessMetaProvider.Get()
-		if order.Amount > meta.DeliveryPunishmentThreshold {
-			order.DiscountedAmount = o.applyPunishment(order.DiscountedAmount, meta.DeliveryPunishmentValue)
+		if err := o.applyDelivery(ctx, &order); err != nil {
+			return "", err
+		}
+		if err := o.reserveDeliverySlot(ctx, &order); err != nil {
+			return "", err
 		}
 	}
 
 	orderID, err := o.orderStorage.SaveOrder(ctx, order)
 	if err != nil {
+		o.releaseResources(ctx, order)
 		return "", err
 	}
 
+	order.OrderID = orderID
+	o.orderEvents.Publish(domain.OrderEventCreated, domain.NewOrderEvent(order, now))
+
 	return orderID.Hex(), nil
 }
 
@@ -109,51 +165,270 @@ func (o *orderService) CreateUserOrder(ctx context.Context, dto dto.CreateUserOr
 		return "", domain.ErrHavePendingOrder
 	}
 
+	schedule := o.businessMetaProvider.Get().Schedule
+	if !schedule.IsOpenAt(now) {
+		return "", domain.ErrStoreClosed
+	}
+	// Delivery time of asap orders is only an estimate
+	if dto.IsDelivered && dto.DeliveryAddress != nil && !dto.DeliveryAddress.IsAsap {
+		if !schedule.IsOpenAt(dto.DeliveryAddress.DeliveredAt) {
+			return "", domain.ErrDeliveryTimeOutsideHours
+		}
+	}
+
 	amount, cartProducts, err := o.CalculateCartAmount(ctx, dto.Cart)
 	if err != nil {
 		return "", err
 	}
 
+	// Customer can get a discount only with promo code
+	var (
+		discount  float64
+		promoCode *domain.PromoCode
+	)
+	if dto.PromoCode != nil {
+		appliedPromoCode, promoCodeDiscount, err := o.promoCodeService.Apply(ctx, *dto.PromoCode, cartProducts, amount)
+		if err != nil {
+			return "", err
+		}
+		promoCode, discount = &appliedPromoCode, promoCodeDiscount
+	}
+
 	nanoID, err := o.findNanoID(ctx)
 	if err != nil {
 		return "", err
 	}
 
 	order := domain.Order{
-		NanoID:     nanoID,
-		CustomerID: dto.CustomerID,
-		Cart:       cartProducts,
-		Pay:        dto.Pay,
-		Amount:     amount,
-		// If customer creates an order it can't get any discount
-		Discount:         0,
-		DiscountedAmount: amount,
+		NanoID:           nanoID,
+		CustomerID:       dto.CustomerID,
+		Cart:             cartProducts,
+		Pay:              dto.Pay,
+		Amount:           amount,
+		Discount:         discount,
+		DiscountedAmount: o.CalculateDiscountedAmount(amount, discount),
 		Status:           domain.StatusWaitingForVerification,
 		IsDelivered:      dto.IsDelivered,
 		DeliveryAddress:  dto.DeliveryAddress,
 		CreatedAt:        now,
 	}
 
+	// Slot is reserved and promo code is redeemed as late as possible,
+	// so that nothing is taken by orders failed to be created
 	if dto.IsDelivered && dto.DeliveryAddress != nil {
-		meta := o.businessMetaProvider.Get()
-		if order.Amount > meta.DeliveryPunishmentThreshold {
-			order.DiscountedAmount = o.applyPunishment(order.DiscountedAmount, meta.DeliveryPunishmentValue)
+		if err := o.applyDelivery(ctx, &order); err != nil {
+			return "", err
+		}
+		if err := o.reserveDeliverySlot(ctx, &order); err != nil {
+			return "", err
+		}
+	}
+	if promoCode != nil {
+		if err := o.promoCodeService.Redeem(ctx, *promoCode, dto.CustomerID); err != nil {
+			o.releaseResources(ctx, order)
+			return "", err
+		}
+		order.PromoCode = &domain.OrderPromoCode{
+			PromoCodeID: promoCode.PromoCodeID,
+			Code:        promoCode.Code,
 		}
 	}
 
 	orderID, err := o.orderStorage.SaveOrder(ctx, order)
 	if err != nil {
+		o.releaseResources(ctx, order)
 		return "", err
 	}
 
+	order.OrderID = orderID
+	o.orderEvents.Publish(domain.OrderEventCreated, domain.NewOrderEvent(order, now))
+
 	return orderID.Hex(), nil
 }
 
+// applyDelivery resolves delivery zone of order's address and charges delivery on top of discounted amount
+func (o *orderService) applyDelivery(ctx context.Context, order *domain.Order) error {
+	zone, err := o.deliveryZoneService.Resolve(ctx, *order.DeliveryAddress)
+	if err != nil {
+		return err
+	}
+	delivery, err := zone.Deliver(order.Amount)
+	if err != nil {
+		return err
+	}
+	// Zone fee is the only delivery charge
+	order.Delivery = &delivery
+	order.DiscountedAmount += delivery.Fee
+	return nil
+}
+
+// reserveDeliverySlot takes capacity of the slot order is scheduled to. Asap orders are not scheduled.
+func (o *orderService) reserveDeliverySlot(ctx context.Context, order *domain.Order) error {
+	if order.DeliveryAddress.IsAsap {
+		return nil
+	}
+	slotStartsAt, err := o.deliverySlotService.Reserve(ctx, order.DeliveryAddress.DeliveredAt)
+	if err != nil {
+		return err
+	}
+	order.Delivery.SlotStartsAt = &slotStartsAt
+	return nil
+}
+
+func (o *orderService) VerifyOrder(ctx context.Context, orderID string) error {
+	return o.changeStatus(ctx, orderID, domain.StatusVerified, nil)
+}
+
+func (o *orderService) CompleteOrder(ctx context.Context, orderID string) error {
+	return o.changeStatus(ctx, orderID, domain.StatusCompleted, nil)
+}
+
+func (o *orderService) CancelOrder(ctx context.Context, orderID string, explanation string) error {
+	return o.changeStatus(ctx, orderID, domain.StatusCancelled, &explanation)
+}
+
+func (o *orderService) changeStatus(ctx context.Context, orderID string, to domain.OrderStatus, cancelExplanation *string) error {
+	order, err := o.orderStorage.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	if err := order.Status.TransitionTo(to); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	err = o.orderStorage.UpdateOrderStatus(ctx, dto.UpdateOrderStatusDTO{
+		OrderID:           orderID,
+		From:              order.Status,
+		To:                to,
+		At:                now,
+		CancelExplanation: cancelExplanation,
+	})
+	if err != nil {
+		return err
+	}
+
+	order.Status = to
+	o.orderEvents.Publish(domain.OrderEventStatusChanged, domain.NewOrderEvent(order, now))
+
+	if to == domain.StatusCancelled {
+		o.releaseResources(ctx, order)
+	}
+	return nil
+}
+
+// releaseResources gives back everything order has taken at creation. Errors are only
+// logged because order is already cancelled or has failed to be saved at this point.
+func (o *orderService) releaseResources(ctx context.Context, order domain.Order) {
+	if order.PromoCode != nil {
+		err := o.promoCodeService.Release(ctx, order.PromoCode.PromoCodeID.Hex(), order.CustomerID)
+		if err != nil {
+			logger.Get().Error("release promo code",
+				zap.String("promoCode", order.PromoCode.Code),
+				zap.String("customerID", order.CustomerID),
+				zap.Error(err),
+			)
+		}
+	}
+	if order.Delivery != nil && order.Delivery.SlotStartsAt != nil {
+		err := o.deliverySlotService.Release(ctx, *order.Delivery.SlotStartsAt)
+		if err != nil {
+			logger.Get().Error("release delivery slot",
+				zap.Time("slotStartsAt", *order.Delivery.SlotStartsAt),
+				zap.String("orderID", order.OrderID.Hex()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func (o *orderService) MarkCartProductReady(ctx context.Context, orderID string, line int) error {
+	order, err := o.getOrderInKitchen(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	if line < 0 || line >= len(order.Cart) {
+		return domain.ErrCartLineNotFound
+	}
+	if order.Cart[line].IsReady {
+		return nil
+	}
+
+	return o.markCartReady(ctx, order, []int{line})
+}
+
+func (o *orderService) MarkOrderReady(ctx context.Context, orderID string) error {
+	order, err := o.getOrderInKitchen(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	lines := make([]int, 0, len(order.Cart))
+	for line, cartProduct := range order.Cart {
+		if !cartProduct.IsReady {
+			lines = append(lines, line)
+		}
+	}
+
+	return o.markCartReady(ctx, order, lines)
+}
+
+func (o *orderService) getOrderInKitchen(ctx context.Context, orderID string) (domain.Order, error) {
+	order, err := o.orderStorage.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if order.Status != domain.StatusVerified {
+		return domain.Order{}, domain.ErrOrderNotInKitchen
+	}
+	return order, nil
+}
+
+func (o *orderService) markCartReady(ctx context.Context, order domain.Order, lines []int) error {
+	var (
+		now     = time.Now().UTC()
+		orderID = order.OrderID.Hex()
+	)
+	if len(lines) > 0 {
+		updated, err := o.orderStorage.MarkCartReady(ctx, dto.MarkCartReadyDTO{
+			OrderID: orderID,
+			Lines:   lines,
+			At:      now,
+		})
+		if err != nil {
+			return err
+		}
+		// Other cooks could have marked lines in the meantime, so it's decided on the stored cart
+		order = updated
+	}
+
+	// Whole cart is prepared, so order moves forward
+	if order.IsCartReady() {
+		err := o.CompleteOrder(ctx, orderID)
+		if errors.Is(err, domain.ErrOrderStatusChanged) || errors.Is(err, domain.ErrInvalidStatusTransition) {
+			// Cook that marked the other last line could have completed it already
+			if current, getErr := o.orderStorage.GetOrderByID(ctx, orderID); getErr == nil && current.Status == domain.StatusCompleted {
+				return nil
+			}
+		}
+		return err
+	}
+
+	o.orderEvents.Publish(domain.OrderEventCartChanged, domain.NewOrderEvent(order, now))
+	return nil
+}
+
 func (o *orderService) CalculateDiscountedAmount(amount int64, discountPercent float64) int64 {
+	// Avoid float rounding when there's nothing to discount
+	if discountPercent == 0 {
+		return amount
+	}
 	return int64(math.Round((1 - discountPercent) * float64(amount)))
 }
 
-//todo: test
+// CalculateCartAmount returns domain.CartError listing every line with missing or not approved product
 func (o *orderService) CalculateCartAmount(ctx context.Context, cart []dto.CartProductDTO) (int64, []domain.CartProduct, error) {
 	productIDs := make([]string, 0, len(cart))
 	for _, product := range cart {
@@ -164,25 +439,118 @@ func (o *orderService) CalculateCartAmount(ctx context.Context, cart []dto.CartP
 	if err != nil {
 		return 0, nil, err
 	}
+	productByID := make(map[string]domain.Product, len(products))
+	for _, product := range products {
+		productByID[product.ProductID.Hex()] = product
+	}
+	// Combos are expanded into their components, which are needed to check that they can be ordered
+	var (
+		componentIDs []string
+		seen         = make(map[string]struct{})
+	)
+	for _, product := range products {
+		if !product.IsCombo() {
+			continue
+		}
+		for _, productID := range product.Combo.ProductIDs() {
+			if _, ok := productByID[productID]; ok {
+				continue
+			}
+			if _, ok := seen[productID]; !ok {
+				seen[productID] = struct{}{}
+				componentIDs = append(componentIDs, productID)
+			}
+		}
+	}
+	if len(componentIDs) > 0 {
+		components, err := o.productService.GetProductsByIDs(ctx, componentIDs)
+		if err != nil {
+			return 0, nil, err
+		}
+		for _, component := range components {
+			productByID[component.ProductID.Hex()] = component
+		}
+	}
 
 	var (
 		total        int64
 		cartProducts = make([]domain.CartProduct, 0, len(cart))
+		cartErr      domain.CartError
 	)
-	for _, cartProduct := range cart {
-		for _, product := range products {
-			if cartProduct.ProductID == product.ProductID.Hex() {
-				total += product.Price * int64(cartProduct.Quantity)
-				cartProducts = append(cartProducts, domain.CartProduct{
-					Product:  product,
-					Quantity: cartProduct.Quantity,
-				})
+	for line, cartProduct := range cart {
+		product, ok := productByID[cartProduct.ProductID]
+		switch {
+		case !ok:
+			cartErr.Lines = append(cartErr.Lines, domain.CartLineError{
+				Line:      line,
+				ProductID: cartProduct.ProductID,
+				Reason:    domain.CartLineProductNotFound,
+			})
+		case !product.IsApproved:
+			cartErr.Lines = append(cartErr.Lines, domain.CartLineError{
+				Line:      line,
+				ProductID: cartProduct.ProductID,
+				Reason:    domain.CartLineProductNotApproved,
+			})
+		default:
+			ordered, err := newCartProduct(product, cartProduct, productByID)
+			if err != nil {
+				lineErr, ok := cartLineError(line, cartProduct.ProductID, err)
+				if !ok {
+					return 0, nil, err
+				}
+				cartErr.Lines = append(cartErr.Lines, lineErr)
+				continue
 			}
+			// Combo is priced as one line
+			total += ordered.Amount()
+			cartProducts = append(cartProducts, ordered)
 		}
 	}
+	if len(cartErr.Lines) > 0 {
+		return 0, nil, cartErr
+	}
 	return total, cartProducts, nil
 }
 
+// newCartProduct copies product into cart line along with chosen options and combo components
+func newCartProduct(product domain.Product, cartProduct dto.CartProductDTO, products map[string]domain.Product) (domain.CartProduct, error) {
+	options, err := product.ChooseOptions(cartProduct.OptionIDs)
+	if err != nil {
+		return domain.CartProduct{}, err
+	}
+	components, err := product.ChooseComboItems(cartProduct.Swaps, products)
+	if err != nil {
+		return domain.CartProduct{}, err
+	}
+	// Order keeps what is chosen only, not every choice the product has
+	product.OptionGroups, product.Combo = nil, nil
+	return domain.CartProduct{
+		Product:    product,
+		Quantity:   cartProduct.Quantity,
+		Options:    options,
+		Components: components,
+	}, nil
+}
+
+// cartLineError turns error of choosing options or combo swaps into error of the cart line
+func cartLineError(line int, productID string, err error) (domain.CartLineError, bool) {
+	var (
+		lineErr   = domain.CartLineError{Line: line, ProductID: productID}
+		optionErr domain.OptionChoiceError
+		comboErr  domain.ComboChoiceError
+	)
+	switch {
+	case errors.As(err, &optionErr):
+		lineErr.GroupID, lineErr.Reason = optionErr.GroupID, optionErr.Reason
+	case errors.As(err, &comboErr):
+		lineErr.ComponentID, lineErr.Reason = comboErr.ComponentID, comboErr.Reason
+	default:
+		return domain.CartLineError{}, false
+	}
+	return lineErr, true
+}
+
 func (o *orderService) findNanoID(ctx context.Context) (string, error) {
 	var (
 		day       = time.Hour * 24
@@ -211,7 +579,3 @@ func (o *orderService) findNanoID(ctx context.Context) (string, error) {
 
 	return nanoID, nil
 }
-
-func (o *orderService) applyPunishment(origin, punishment int64) int64 {
-	return origin + punishment
-}
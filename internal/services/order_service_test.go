@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"math"
 	"testing"
 	"time"
@@ -13,6 +15,8 @@ import (
 	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
 	mock_service "github.com/sonyamoonglade/sancho-backend/internal/services/mocks"
 	mock_storage "github.com/sonyamoonglade/sancho-backend/internal/storages/mocks"
+	"github.com/sonyamoonglade/sancho-backend/pkg/cursor"
+	"github.com/sonyamoonglade/sancho-backend/pkg/eventbus"
 	"github.com/sonyamoonglade/sancho-backend/pkg/meta_cache"
 	"github.com/sonyamoonglade/sancho-backend/pkg/nanoid"
 	"github.com/stretchr/testify/require"
@@ -110,7 +114,7 @@ func TestCreateOrder(t *testing.T) {
 				require.Equal(t, d.Pay, order.Pay)
 				require.EqualValues(t, d.DeliveryAddress, order.DeliveryAddress)
 				require.Equal(t, d.IsDelivered, order.IsDelivered)
-				// Users cant get discount on an order when created by themselves
+				// Users cant get discount on an order without promo code
 				require.True(t, order.Discount == 0)
 				require.True(t, order.Amount == order.DiscountedAmount)
 				return primitive.NewObjectID(), nil
@@ -121,6 +125,387 @@ func TestCreateOrder(t *testing.T) {
 		require.NoError(t, err)
 		require.NotZero(t, orderID)
 	})
+
+	t.Run("should create order with promo code discount", func(t *testing.T) {
+		orderService, productService, orderStorage := getServices(t, OrderConfig{})
+		promoCodeService := orderService.promoCodeService.(*mock_service.MockPromoCode)
+		mockProduct := getProduct()
+		mockProduct.Price = 500
+		d := dto.CreateUserOrderDTO{
+			CustomerID: primitive.NewObjectID().Hex(),
+			Pay:        domain.PayOnPickup,
+			Cart: []dto.CartProductDTO{
+				{ProductID: mockProduct.ProductID.Hex(), Quantity: 2},
+			},
+			PromoCode: stringPtr("summer10"),
+		}
+		promoCode := domain.PromoCode{
+			PromoCodeID: primitive.NewObjectID(),
+			Code:        "SUMMER10",
+			Kind:        domain.DiscountPercent,
+			Percent:     0.1,
+		}
+
+		orderStorage.EXPECT().GetLastOrderByCustomerID(gomock.Any(), d.CustomerID).Return(domain.Order{}, domain.ErrOrderNotFound)
+		productService.EXPECT().GetProductsByIDs(gomock.Any(), gomock.Any()).Return([]domain.Product{mockProduct}, nil)
+		promoCodeService.
+			EXPECT().
+			Apply(gomock.Any(), *d.PromoCode, gomock.Any(), int64(1000)).
+			Return(promoCode, 0.1, nil)
+		orderStorage.
+			EXPECT().
+			GetOrderByNanoIDAt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(domain.Order{}, domain.ErrOrderNotFound)
+		promoCodeService.EXPECT().Redeem(gomock.Any(), promoCode, d.CustomerID).Return(nil).Times(1)
+		orderStorage.
+			EXPECT().
+			SaveOrder(gomock.Any(), gomock.AssignableToTypeOf(domain.Order{})).
+			DoAndReturn(func(ctx context.Context, order domain.Order) (primitive.ObjectID, error) {
+				require.Equal(t, 0.1, order.Discount)
+				require.Equal(t, int64(1000), order.Amount)
+				require.Equal(t, int64(900), order.DiscountedAmount)
+				require.NotNil(t, order.PromoCode)
+				require.Equal(t, promoCode.PromoCodeID, order.PromoCode.PromoCodeID)
+				return primitive.NewObjectID(), nil
+			}).
+			Times(1)
+
+		orderID, err := orderService.CreateUserOrder(context.Background(), d)
+		require.NoError(t, err)
+		require.NotZero(t, orderID)
+	})
+
+	t.Run("should not create order because promo code usage limit is reached", func(t *testing.T) {
+		orderService, productService, orderStorage := getServices(t, OrderConfig{})
+		promoCodeService := orderService.promoCodeService.(*mock_service.MockPromoCode)
+		mockProduct := getProduct()
+		d := dto.CreateUserOrderDTO{
+			CustomerID: primitive.NewObjectID().Hex(),
+			Pay:        domain.PayOnPickup,
+			Cart: []dto.CartProductDTO{
+				{ProductID: mockProduct.ProductID.Hex(), Quantity: 1},
+			},
+			PromoCode: stringPtr("ONCE"),
+		}
+
+		orderStorage.EXPECT().GetLastOrderByCustomerID(gomock.Any(), d.CustomerID).Return(domain.Order{}, domain.ErrOrderNotFound)
+		productService.EXPECT().GetProductsByIDs(gomock.Any(), gomock.Any()).Return([]domain.Product{mockProduct}, nil)
+		promoCodeService.EXPECT().Apply(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(domain.PromoCode{}, 0.5, nil)
+		orderStorage.
+			EXPECT().
+			GetOrderByNanoIDAt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(domain.Order{}, domain.ErrOrderNotFound)
+		promoCodeService.EXPECT().Redeem(gomock.Any(), gomock.Any(), d.CustomerID).Return(domain.ErrPromoCodeUsageLimit)
+		orderStorage.EXPECT().SaveOrder(gomock.Any(), gomock.Any()).Times(0)
+
+		orderID, err := orderService.CreateUserOrder(context.Background(), d)
+		require.ErrorIs(t, err, domain.ErrPromoCodeUsageLimit)
+		require.Zero(t, orderID)
+	})
+
+	t.Run("should release promo code because order failed to be saved", func(t *testing.T) {
+		orderService, productService, orderStorage := getServices(t, OrderConfig{})
+		promoCodeService := orderService.promoCodeService.(*mock_service.MockPromoCode)
+		mockProduct := getProduct()
+		d := dto.CreateUserOrderDTO{
+			CustomerID: primitive.NewObjectID().Hex(),
+			Pay:        domain.PayOnPickup,
+			Cart: []dto.CartProductDTO{
+				{ProductID: mockProduct.ProductID.Hex(), Quantity: 1},
+			},
+			PromoCode: stringPtr("SUMMER10"),
+		}
+		promoCode := domain.PromoCode{PromoCodeID: primitive.NewObjectID(), Code: "SUMMER10"}
+
+		orderStorage.EXPECT().GetLastOrderByCustomerID(gomock.Any(), d.CustomerID).Return(domain.Order{}, domain.ErrOrderNotFound)
+		productService.EXPECT().GetProductsByIDs(gomock.Any(), gomock.Any()).Return([]domain.Product{mockProduct}, nil)
+		promoCodeService.EXPECT().Apply(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(promoCode, 0.1, nil)
+		orderStorage.
+			EXPECT().
+			GetOrderByNanoIDAt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(domain.Order{}, domain.ErrOrderNotFound)
+		promoCodeService.EXPECT().Redeem(gomock.Any(), promoCode, d.CustomerID).Return(nil)
+		orderStorage.EXPECT().SaveOrder(gomock.Any(), gomock.Any()).Return(primitive.ObjectID{}, errors.New("mongo is down"))
+		promoCodeService.EXPECT().Release(gomock.Any(), promoCode.PromoCodeID.Hex(), d.CustomerID).Return(nil).Times(1)
+
+		_, err := orderService.CreateUserOrder(context.Background(), d)
+		require.Error(t, err)
+	})
+
+	t.Run("should create delivered order with zone fee", func(t *testing.T) {
+		orderService, productService, orderStorage := getServices(t, OrderConfig{})
+		deliveryZoneService := orderService.deliveryZoneService.(*mock_service.MockDeliveryZone)
+		mockProduct := getProduct()
+		mockProduct.Price = 300
+		d := dto.CreateUserOrderDTO{
+			CustomerID: primitive.NewObjectID().Hex(),
+			Pay:        domain.PayOnPickup,
+			Cart: []dto.CartProductDTO{
+				{ProductID: mockProduct.ProductID.Hex(), Quantity: 1},
+			},
+			IsDelivered:     true,
+			DeliveryAddress: getDeliveryAddress(),
+		}
+		zone := domain.DeliveryZone{
+			DeliveryZoneID: primitive.NewObjectID(),
+			Name:           "center",
+			Fee:            150,
+			MinOrderAmount: 200,
+			ETAMinutes:     45,
+		}
+
+		orderStorage.EXPECT().GetLastOrderByCustomerID(gomock.Any(), d.CustomerID).Return(domain.Order{}, domain.ErrOrderNotFound)
+		productService.EXPECT().GetProductsByIDs(gomock.Any(), gomock.Any()).Return([]domain.Product{mockProduct}, nil)
+		orderStorage.
+			EXPECT().
+			GetOrderByNanoIDAt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(domain.Order{}, domain.ErrOrderNotFound)
+		deliveryZoneService.EXPECT().Resolve(gomock.Any(), *d.DeliveryAddress).Return(zone, nil)
+		orderStorage.
+			EXPECT().
+			SaveOrder(gomock.Any(), gomock.AssignableToTypeOf(domain.Order{})).
+			DoAndReturn(func(ctx context.Context, order domain.Order) (primitive.ObjectID, error) {
+				require.Equal(t, int64(300), order.Amount)
+				require.Equal(t, int64(450), order.DiscountedAmount)
+				require.NotNil(t, order.Delivery)
+				require.Equal(t, zone.DeliveryZoneID, order.Delivery.DeliveryZoneID)
+				require.Equal(t, zone.Fee, order.Delivery.Fee)
+				require.Equal(t, zone.ETAMinutes, order.Delivery.ETAMinutes)
+				return primitive.NewObjectID(), nil
+			}).
+			Times(1)
+
+		orderID, err := orderService.CreateUserOrder(context.Background(), d)
+		require.NoError(t, err)
+		require.NotZero(t, orderID)
+	})
+
+	t.Run("should charge only zone fee for order above delivery punishment threshold", func(t *testing.T) {
+		orderService, productService, orderStorage := getServices(t, OrderConfig{})
+		deliveryZoneService := orderService.deliveryZoneService.(*mock_service.MockDeliveryZone)
+		mockProduct := getProduct()
+		mockProduct.Price = 300
+		d := dto.CreateUserOrderDTO{
+			CustomerID: primitive.NewObjectID().Hex(),
+			Pay:        domain.PayOnPickup,
+			Cart: []dto.CartProductDTO{
+				{ProductID: mockProduct.ProductID.Hex(), Quantity: 3},
+			},
+			IsDelivered:     true,
+			DeliveryAddress: getDeliveryAddress(),
+		}
+
+		orderStorage.EXPECT().GetLastOrderByCustomerID(gomock.Any(), d.CustomerID).Return(domain.Order{}, domain.ErrOrderNotFound)
+		productService.EXPECT().GetProductsByIDs(gomock.Any(), gomock.Any()).Return([]domain.Product{mockProduct}, nil)
+		orderStorage.
+			EXPECT().
+			GetOrderByNanoIDAt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(domain.Order{}, domain.ErrOrderNotFound)
+		deliveryZoneService.EXPECT().Resolve(gomock.Any(), gomock.Any()).Return(domain.DeliveryZone{Fee: 150}, nil)
+		orderStorage.
+			EXPECT().
+			SaveOrder(gomock.Any(), gomock.AssignableToTypeOf(domain.Order{})).
+			DoAndReturn(func(ctx context.Context, order domain.Order) (primitive.ObjectID, error) {
+				// Amount is above threshold of meta (400), but it's not charged on top of zone fee
+				require.Equal(t, int64(900), order.Amount)
+				require.Equal(t, int64(1050), order.DiscountedAmount)
+				return primitive.NewObjectID(), nil
+			})
+
+		_, err := orderService.CreateUserOrder(context.Background(), d)
+		require.NoError(t, err)
+	})
+
+	t.Run("should reserve delivery slot and release it because order failed to be saved", func(t *testing.T) {
+		orderService, productService, orderStorage := getServices(t, OrderConfig{})
+		deliveryZoneService := orderService.deliveryZoneService.(*mock_service.MockDeliveryZone)
+		deliverySlotService := orderService.deliverySlotService.(*mock_service.MockDeliverySlot)
+		mockProduct := getProduct()
+		mockProduct.Price = 300
+		d := dto.CreateUserOrderDTO{
+			CustomerID: primitive.NewObjectID().Hex(),
+			Pay:        domain.PayOnPickup,
+			Cart: []dto.CartProductDTO{
+				{ProductID: mockProduct.ProductID.Hex(), Quantity: 1},
+			},
+			IsDelivered:     true,
+			DeliveryAddress: getDeliveryAddress(),
+		}
+		d.DeliveryAddress.IsAsap = false
+		slotStartsAt := d.DeliveryAddress.DeliveredAt.Truncate(time.Minute * 30)
+
+		orderStorage.EXPECT().GetLastOrderByCustomerID(gomock.Any(), d.CustomerID).Return(domain.Order{}, domain.ErrOrderNotFound)
+		productService.EXPECT().GetProductsByIDs(gomock.Any(), gomock.Any()).Return([]domain.Product{mockProduct}, nil)
+		orderStorage.
+			EXPECT().
+			GetOrderByNanoIDAt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(domain.Order{}, domain.ErrOrderNotFound)
+		deliveryZoneService.EXPECT().Resolve(gomock.Any(), gomock.Any()).Return(domain.DeliveryZone{Fee: 100}, nil)
+		deliverySlotService.EXPECT().Reserve(gomock.Any(), d.DeliveryAddress.DeliveredAt).Return(slotStartsAt, nil)
+		orderStorage.
+			EXPECT().
+			SaveOrder(gomock.Any(), gomock.AssignableToTypeOf(domain.Order{})).
+			DoAndReturn(func(ctx context.Context, order domain.Order) (primitive.ObjectID, error) {
+				require.NotNil(t, order.Delivery.SlotStartsAt)
+				require.True(t, slotStartsAt.Equal(*order.Delivery.SlotStartsAt))
+				return primitive.ObjectID{}, errors.New("mongo is down")
+			})
+		deliverySlotService.EXPECT().Release(gomock.Any(), slotStartsAt).Return(nil).Times(1)
+
+		_, err := orderService.CreateUserOrder(context.Background(), d)
+		require.Error(t, err)
+	})
+
+	t.Run("should not create order because delivery slot is full", func(t *testing.T) {
+		orderService, productService, orderStorage := getServices(t, OrderConfig{})
+		deliveryZoneService := orderService.deliveryZoneService.(*mock_service.MockDeliveryZone)
+		deliverySlotService := orderService.deliverySlotService.(*mock_service.MockDeliverySlot)
+		mockProduct := getProduct()
+		mockProduct.Price = 300
+		d := dto.CreateUserOrderDTO{
+			CustomerID: primitive.NewObjectID().Hex(),
+			Pay:        domain.PayOnPickup,
+			Cart: []dto.CartProductDTO{
+				{ProductID: mockProduct.ProductID.Hex(), Quantity: 1},
+			},
+			IsDelivered:     true,
+			DeliveryAddress: getDeliveryAddress(),
+		}
+		d.DeliveryAddress.IsAsap = false
+
+		orderStorage.EXPECT().GetLastOrderByCustomerID(gomock.Any(), d.CustomerID).Return(domain.Order{}, domain.ErrOrderNotFound)
+		productService.EXPECT().GetProductsByIDs(gomock.Any(), gomock.Any()).Return([]domain.Product{mockProduct}, nil)
+		orderStorage.
+			EXPECT().
+			GetOrderByNanoIDAt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(domain.Order{}, domain.ErrOrderNotFound)
+		deliveryZoneService.EXPECT().Resolve(gomock.Any(), gomock.Any()).Return(domain.DeliveryZone{}, nil)
+		deliverySlotService.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(time.Time{}, domain.ErrDeliverySlotFull)
+		orderStorage.EXPECT().SaveOrder(gomock.Any(), gomock.Any()).Times(0)
+
+		orderID, err := orderService.CreateUserOrder(context.Background(), d)
+		require.ErrorIs(t, err, domain.ErrDeliverySlotFull)
+		require.Zero(t, orderID)
+	})
+
+	t.Run("should not create order because address is outside of delivery zones", func(t *testing.T) {
+		orderService, productService, orderStorage := getServices(t, OrderConfig{})
+		deliveryZoneService := orderService.deliveryZoneService.(*mock_service.MockDeliveryZone)
+		mockProduct := getProduct()
+		d := dto.CreateUserOrderDTO{
+			CustomerID: primitive.NewObjectID().Hex(),
+			Pay:        domain.PayOnPickup,
+			Cart: []dto.CartProductDTO{
+				{ProductID: mockProduct.ProductID.Hex(), Quantity: 1},
+			},
+			IsDelivered:     true,
+			DeliveryAddress: getDeliveryAddress(),
+		}
+
+		orderStorage.EXPECT().GetLastOrderByCustomerID(gomock.Any(), d.CustomerID).Return(domain.Order{}, domain.ErrOrderNotFound)
+		productService.EXPECT().GetProductsByIDs(gomock.Any(), gomock.Any()).Return([]domain.Product{mockProduct}, nil)
+		orderStorage.
+			EXPECT().
+			GetOrderByNanoIDAt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(domain.Order{}, domain.ErrOrderNotFound)
+		deliveryZoneService.EXPECT().Resolve(gomock.Any(), gomock.Any()).Return(domain.DeliveryZone{}, domain.ErrOutsideDeliveryZone)
+		orderStorage.EXPECT().SaveOrder(gomock.Any(), gomock.Any()).Times(0)
+
+		orderID, err := orderService.CreateUserOrder(context.Background(), d)
+		require.ErrorIs(t, err, domain.ErrOutsideDeliveryZone)
+		require.Zero(t, orderID)
+	})
+
+	t.Run("should not create order because store is closed", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		now := time.Now().UTC()
+		orderService.businessMetaProvider.Set(domain.BusinessMeta{
+			Schedule: domain.Schedule{
+				Timezone: "UTC",
+				// No weekly hours, so store is closed on any other day as well
+				Overrides: []domain.ScheduleOverride{{Date: now.Format(domain.DateLayout), IsClosed: true}},
+			},
+		})
+		d := dto.CreateUserOrderDTO{
+			CustomerID: primitive.NewObjectID().Hex(),
+			Pay:        domain.PayOnPickup,
+			Cart:       []dto.CartProductDTO{{ProductID: primitive.NewObjectID().Hex(), Quantity: 1}},
+		}
+
+		orderStorage.EXPECT().GetLastOrderByCustomerID(gomock.Any(), d.CustomerID).Return(domain.Order{}, domain.ErrOrderNotFound)
+		orderStorage.EXPECT().SaveOrder(gomock.Any(), gomock.Any()).Times(0)
+
+		orderID, err := orderService.CreateUserOrder(context.Background(), d)
+		require.ErrorIs(t, err, domain.ErrStoreClosed)
+		require.Zero(t, orderID)
+	})
+
+	t.Run("should not create order because delivery time is outside of working hours", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		var (
+			deliveredAt = time.Now().UTC().Add(time.Hour * 24 * 3)
+			schedule    = domain.Schedule{
+				Timezone:  "UTC",
+				Overrides: []domain.ScheduleOverride{{Date: deliveredAt.Format(domain.DateLayout), IsClosed: true}},
+			}
+		)
+		for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+			schedule.Weekly = append(schedule.Weekly, domain.WorkingHours{Weekday: weekday, Open: "00:00", Close: "24:00"})
+		}
+		orderService.businessMetaProvider.Set(domain.BusinessMeta{Schedule: schedule})
+		d := dto.CreateUserOrderDTO{
+			CustomerID:      primitive.NewObjectID().Hex(),
+			Pay:             domain.PayOnPickup,
+			Cart:            []dto.CartProductDTO{{ProductID: primitive.NewObjectID().Hex(), Quantity: 1}},
+			IsDelivered:     true,
+			DeliveryAddress: getDeliveryAddress(),
+		}
+		d.DeliveryAddress.IsAsap = false
+		d.DeliveryAddress.DeliveredAt = deliveredAt
+
+		orderStorage.EXPECT().GetLastOrderByCustomerID(gomock.Any(), d.CustomerID).Return(domain.Order{}, domain.ErrOrderNotFound)
+		orderStorage.EXPECT().SaveOrder(gomock.Any(), gomock.Any()).Times(0)
+
+		orderID, err := orderService.CreateUserOrder(context.Background(), d)
+		require.ErrorIs(t, err, domain.ErrDeliveryTimeOutsideHours)
+		require.Zero(t, orderID)
+	})
+
+	t.Run("should accept delivery at 01:00 because store is open overnight", func(t *testing.T) {
+		orderService, productService, orderStorage := getServices(t, OrderConfig{})
+		var (
+			now         = time.Now().UTC()
+			deliveredAt = time.Date(now.Year(), now.Month(), now.Day()+2, 1, 0, 0, 0, time.UTC)
+			schedule    = domain.Schedule{
+				Timezone: "UTC",
+				// Store is open now whatever time it is
+				Overrides: []domain.ScheduleOverride{{Date: now.Format(domain.DateLayout), Open: "00:00", Close: "24:00"}},
+			}
+			errProducts = errors.New("products are not available")
+		)
+		for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+			schedule.Weekly = append(schedule.Weekly, domain.WorkingHours{Weekday: weekday, Open: "18:00", Close: "02:00"})
+		}
+		orderService.businessMetaProvider.Set(domain.BusinessMeta{Schedule: schedule})
+		d := dto.CreateUserOrderDTO{
+			CustomerID:      primitive.NewObjectID().Hex(),
+			Pay:             domain.PayOnPickup,
+			Cart:            []dto.CartProductDTO{{ProductID: primitive.NewObjectID().Hex(), Quantity: 1}},
+			IsDelivered:     true,
+			DeliveryAddress: getDeliveryAddress(),
+		}
+		d.DeliveryAddress.IsAsap = false
+		d.DeliveryAddress.DeliveredAt = deliveredAt
+
+		orderStorage.EXPECT().GetLastOrderByCustomerID(gomock.Any(), d.CustomerID).Return(domain.Order{}, domain.ErrOrderNotFound)
+		// Order gets past schedule checks up to cart calculation
+		productService.EXPECT().GetProductsByIDs(gomock.Any(), gomock.Any()).Return(nil, errProducts)
+		orderStorage.EXPECT().SaveOrder(gomock.Any(), gomock.Any()).Times(0)
+
+		orderID, err := orderService.CreateUserOrder(context.Background(), d)
+		require.ErrorIs(t, err, errProducts)
+		require.Zero(t, orderID)
+	})
 }
 
 func TestCalculateCartAmount(t *testing.T) {
@@ -220,6 +605,533 @@ func TestCalculateCartAmount(t *testing.T) {
 		require.Nil(t, cartProducts)
 		require.Zero(t, amount)
 	})
+
+	t.Run("should return CartError listing missing and not approved products", func(t *testing.T) {
+		orderService, productService, _ := getServices(t, OrderConfig{})
+
+		var (
+			approved    = getProduct()
+			notApproved = getProduct()
+			missingID   = primitive.NewObjectID().Hex()
+		)
+		notApproved.IsApproved = false
+		cart := []dto.CartProductDTO{
+			{ProductID: approved.ProductID.Hex(), Quantity: 1},
+			{ProductID: notApproved.ProductID.Hex(), Quantity: 1},
+			{ProductID: missingID, Quantity: 2},
+		}
+
+		productService.
+			EXPECT().
+			GetProductsByIDs(gomock.Any(), []string{approved.ProductID.Hex(), notApproved.ProductID.Hex(), missingID}).
+			Return([]domain.Product{approved, notApproved}, nil)
+
+		amount, cartProducts, err := orderService.CalculateCartAmount(context.Background(), cart)
+		require.ErrorIs(t, err, domain.ErrInvalidCart)
+		require.Nil(t, cartProducts)
+		require.Zero(t, amount)
+
+		var cartErr domain.CartError
+		require.ErrorAs(t, err, &cartErr)
+		require.Equal(t, []domain.CartLineError{
+			{Line: 1, ProductID: notApproved.ProductID.Hex(), Reason: domain.CartLineProductNotApproved},
+			{Line: 2, ProductID: missingID, Reason: domain.CartLineProductNotFound},
+		}, cartErr.Lines)
+	})
+
+	t.Run("should price chosen options in and keep them in cart", func(t *testing.T) {
+		orderService, productService, _ := getServices(t, OrderConfig{})
+
+		product, size, toppings := getProductWithOptions()
+		var (
+			large  = size.Options[1]
+			cheese = toppings.Options[0]
+			bacon  = toppings.Options[1]
+		)
+		cart := []dto.CartProductDTO{
+			{
+				ProductID: product.ProductID.Hex(),
+				Quantity:  2,
+				OptionIDs: []string{bacon.OptionID.Hex(), large.OptionID.Hex(), cheese.OptionID.Hex()},
+			},
+		}
+
+		productService.
+			EXPECT().
+			GetProductsByIDs(gomock.Any(), []string{product.ProductID.Hex()}).
+			Return([]domain.Product{product}, nil)
+
+		amount, cartProducts, err := orderService.CalculateCartAmount(context.Background(), cart)
+		require.NoError(t, err)
+		require.Equal(t, (product.Price+large.PriceDelta+cheese.PriceDelta+bacon.PriceDelta)*2, amount)
+		require.Len(t, cartProducts, 1)
+		// Options are in the order of the product, not the order they're chosen in
+		require.Equal(t, []domain.ChosenOption{
+			{GroupID: size.GroupID, GroupName: size.Name, OptionID: large.OptionID, Name: large.Name, PriceDelta: large.PriceDelta},
+			{GroupID: toppings.GroupID, GroupName: toppings.Name, OptionID: cheese.OptionID, Name: cheese.Name, PriceDelta: cheese.PriceDelta},
+			{GroupID: toppings.GroupID, GroupName: toppings.Name, OptionID: bacon.OptionID, Name: bacon.Name, PriceDelta: bacon.PriceDelta},
+		}, cartProducts[0].Options)
+		require.Nil(t, cartProducts[0].OptionGroups)
+	})
+
+	t.Run("should price combo as one line and expand it into components", func(t *testing.T) {
+		orderService, productService, _ := getServices(t, OrderConfig{})
+
+		var (
+			burger = getProduct()
+			cola   = getProduct()
+			juice  = getProduct()
+			drink  = domain.ComboComponent{
+				ComponentID: primitive.NewObjectID(),
+				ProductID:   cola.ProductID,
+				Quantity:    1,
+				Swaps:       []domain.ComboSwap{{ProductID: juice.ProductID, PriceDelta: 30}},
+			}
+			combo = getProduct()
+		)
+		combo.Price = 499
+		combo.Combo = &domain.Combo{Components: []domain.ComboComponent{
+			{ComponentID: primitive.NewObjectID(), ProductID: burger.ProductID, Quantity: 1},
+			drink,
+		}}
+		cart := []dto.CartProductDTO{
+			{ProductID: combo.ProductID.Hex(), Quantity: 2, Swaps: map[string]string{drink.ComponentID.Hex(): juice.ProductID.Hex()}},
+			{ProductID: burger.ProductID.Hex(), Quantity: 1},
+		}
+
+		gomock.InOrder(
+			productService.
+				EXPECT().
+				GetProductsByIDs(gomock.Any(), []string{combo.ProductID.Hex(), burger.ProductID.Hex()}).
+				Return([]domain.Product{combo, burger}, nil),
+			// Burger is in cart already
+			productService.
+				EXPECT().
+				GetProductsByIDs(gomock.Any(), []string{cola.ProductID.Hex(), juice.ProductID.Hex()}).
+				Return([]domain.Product{cola, juice}, nil),
+		)
+
+		amount, cartProducts, err := orderService.CalculateCartAmount(context.Background(), cart)
+		require.NoError(t, err)
+		require.Equal(t, (499+30)*2+burger.Price, amount)
+		require.Len(t, cartProducts, 2)
+		require.Nil(t, cartProducts[0].Combo)
+		require.Equal(t, []domain.ComboItem{
+			{ComponentID: combo.Combo.Components[0].ComponentID, ProductID: burger.ProductID, Name: burger.Name, TranslateRU: burger.TranslateRU, Quantity: 1},
+			{ComponentID: drink.ComponentID, ProductID: juice.ProductID, Name: juice.Name, TranslateRU: juice.TranslateRU, Quantity: 1, IsSwapped: true, PriceDelta: 30},
+		}, cartProducts[0].Components)
+		require.Empty(t, cartProducts[1].Components)
+	})
+
+	t.Run("should return CartError for combo with unavailable component", func(t *testing.T) {
+		orderService, productService, _ := getServices(t, OrderConfig{})
+
+		var (
+			cola      = getProduct()
+			combo     = getProduct()
+			component = domain.ComboComponent{ComponentID: primitive.NewObjectID(), ProductID: cola.ProductID, Quantity: 1}
+		)
+		cola.IsApproved = false
+		combo.Combo = &domain.Combo{Components: []domain.ComboComponent{component}}
+
+		productService.EXPECT().GetProductsByIDs(gomock.Any(), []string{combo.ProductID.Hex()}).Return([]domain.Product{combo}, nil)
+		productService.EXPECT().GetProductsByIDs(gomock.Any(), []string{cola.ProductID.Hex()}).Return([]domain.Product{cola}, nil)
+
+		_, _, err := orderService.CalculateCartAmount(context.Background(), []dto.CartProductDTO{
+			{ProductID: combo.ProductID.Hex(), Quantity: 1},
+		})
+		var cartErr domain.CartError
+		require.ErrorAs(t, err, &cartErr)
+		require.Equal(t, []domain.CartLineError{
+			{
+				Line:        0,
+				ProductID:   combo.ProductID.Hex(),
+				ComponentID: component.ComponentID.Hex(),
+				Reason:      domain.CartLineComponentNotAvailable,
+			},
+		}, cartErr.Lines)
+	})
+
+	t.Run("should return CartError listing lines with invalid options", func(t *testing.T) {
+		orderService, productService, _ := getServices(t, OrderConfig{})
+
+		product, size, toppings := getProductWithOptions()
+		var (
+			small    = size.Options[0].OptionID.Hex()
+			unknown  = primitive.NewObjectID().Hex()
+			toppingA = toppings.Options[0].OptionID.Hex()
+			toppingB = toppings.Options[1].OptionID.Hex()
+			toppingC = toppings.Options[2].OptionID.Hex()
+		)
+		cart := []dto.CartProductDTO{
+			{ProductID: product.ProductID.Hex(), Quantity: 1, OptionIDs: []string{small}},
+			{ProductID: product.ProductID.Hex(), Quantity: 1},
+			{ProductID: product.ProductID.Hex(), Quantity: 1, OptionIDs: []string{small, toppingA, toppingB, toppingC}},
+			{ProductID: product.ProductID.Hex(), Quantity: 1, OptionIDs: []string{small, unknown}},
+			{ProductID: product.ProductID.Hex(), Quantity: 1, OptionIDs: []string{small, small}},
+		}
+
+		productService.
+			EXPECT().
+			GetProductsByIDs(gomock.Any(), gomock.Any()).
+			Return([]domain.Product{product}, nil)
+
+		amount, cartProducts, err := orderService.CalculateCartAmount(context.Background(), cart)
+		require.ErrorIs(t, err, domain.ErrInvalidCart)
+		require.Nil(t, cartProducts)
+		require.Zero(t, amount)
+
+		var cartErr domain.CartError
+		require.ErrorAs(t, err, &cartErr)
+		productID := product.ProductID.Hex()
+		require.Equal(t, []domain.CartLineError{
+			{Line: 1, ProductID: productID, GroupID: size.GroupID.Hex(), Reason: domain.CartLineTooFewOptions},
+			{Line: 2, ProductID: productID, GroupID: toppings.GroupID.Hex(), Reason: domain.CartLineTooManyOptions},
+			{Line: 3, ProductID: productID, Reason: domain.CartLineUnknownOption},
+			{Line: 4, ProductID: productID, Reason: domain.CartLineDuplicateOption},
+		}, cartErr.Lines)
+	})
+}
+
+func TestChangeOrderStatus(t *testing.T) {
+	t.Run("should verify order that is waiting for verification", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		order := getOrder(primitive.NewObjectID().Hex(), time.Now().UTC(), nil, domain.StatusWaitingForVerification)
+		orderID := order.OrderID.Hex()
+
+		orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order, nil)
+		orderStorage.
+			EXPECT().
+			UpdateOrderStatus(gomock.Any(), gomock.AssignableToTypeOf(dto.UpdateOrderStatusDTO{})).
+			DoAndReturn(func(ctx context.Context, d dto.UpdateOrderStatusDTO) error {
+				require.Equal(t, orderID, d.OrderID)
+				require.Equal(t, domain.StatusWaitingForVerification, d.From)
+				require.Equal(t, domain.StatusVerified, d.To)
+				require.NotZero(t, d.At)
+				require.Nil(t, d.CancelExplanation)
+				return nil
+			}).
+			Times(1)
+
+		sub, _ := orderService.orderEvents.SubscribeCustomer(order.CustomerID, 0)
+		defer sub.Close()
+
+		err := orderService.VerifyOrder(context.Background(), orderID)
+		require.NoError(t, err)
+
+		// Customer should be notified about status change
+		event := <-sub.Events()
+		require.Equal(t, domain.OrderEventStatusChanged, event.Type)
+		var orderEvent domain.OrderEvent
+		require.NoError(t, json.Unmarshal(event.Data, &orderEvent))
+		require.Equal(t, orderID, orderEvent.OrderID)
+		require.Equal(t, domain.StatusVerified, orderEvent.Status)
+	})
+
+	t.Run("should cancel verified order with explanation", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		order := getOrder(primitive.NewObjectID().Hex(), time.Now().UTC(), nil, domain.StatusVerified)
+		orderID := order.OrderID.Hex()
+		explanation := "customer did not answer the phone"
+
+		orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order, nil)
+		orderStorage.
+			EXPECT().
+			UpdateOrderStatus(gomock.Any(), gomock.AssignableToTypeOf(dto.UpdateOrderStatusDTO{})).
+			DoAndReturn(func(ctx context.Context, d dto.UpdateOrderStatusDTO) error {
+				require.Equal(t, domain.StatusVerified, d.From)
+				require.Equal(t, domain.StatusCancelled, d.To)
+				require.NotNil(t, d.CancelExplanation)
+				require.Equal(t, explanation, *d.CancelExplanation)
+				return nil
+			}).
+			Times(1)
+
+		err := orderService.CancelOrder(context.Background(), orderID, explanation)
+		require.NoError(t, err)
+	})
+
+	t.Run("should release promo code of cancelled order", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		promoCodeService := orderService.promoCodeService.(*mock_service.MockPromoCode)
+		order := getOrder(primitive.NewObjectID().Hex(), time.Now().UTC(), nil, domain.StatusWaitingForVerification)
+		order.PromoCode = &domain.OrderPromoCode{PromoCodeID: primitive.NewObjectID(), Code: "SUMMER10"}
+		orderID := order.OrderID.Hex()
+
+		orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order, nil)
+		orderStorage.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).Return(nil)
+		promoCodeService.
+			EXPECT().
+			Release(gomock.Any(), order.PromoCode.PromoCodeID.Hex(), order.CustomerID).
+			Return(nil).
+			Times(1)
+
+		err := orderService.CancelOrder(context.Background(), orderID, "customer changed mind")
+		require.NoError(t, err)
+	})
+
+	t.Run("should release delivery slot of cancelled order", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		deliverySlotService := orderService.deliverySlotService.(*mock_service.MockDeliverySlot)
+		slotStartsAt := time.Now().UTC().Truncate(time.Minute * 30).Add(time.Hour)
+		order := getOrder(primitive.NewObjectID().Hex(), time.Now().UTC(), nil, domain.StatusVerified)
+		order.IsDelivered = true
+		order.DeliveryAddress = getDeliveryAddress()
+		order.Delivery = &domain.OrderDelivery{SlotStartsAt: &slotStartsAt}
+		orderID := order.OrderID.Hex()
+
+		orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order, nil)
+		orderStorage.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).Return(nil)
+		deliverySlotService.EXPECT().Release(gomock.Any(), slotStartsAt).Return(nil).Times(1)
+
+		err := orderService.CancelOrder(context.Background(), orderID, "courier is sick")
+		require.NoError(t, err)
+	})
+
+	t.Run("should not verify completed order", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		order := getOrder(primitive.NewObjectID().Hex(), time.Now().UTC(), nil, domain.StatusCompleted)
+		orderID := order.OrderID.Hex()
+
+		orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order, nil)
+		// Storage must not be touched because transition is illegal
+		orderStorage.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).Times(0)
+
+		err := orderService.VerifyOrder(context.Background(), orderID)
+		require.Error(t, err)
+		require.ErrorIs(t, err, domain.ErrInvalidStatusTransition)
+	})
+
+	t.Run("should return ErrOrderNotFound because order does not exist", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		orderID := primitive.NewObjectID().Hex()
+
+		orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(domain.Order{}, domain.ErrOrderNotFound)
+
+		err := orderService.CompleteOrder(context.Background(), orderID)
+		require.Error(t, err)
+		require.Equal(t, domain.ErrOrderNotFound, err)
+	})
+}
+
+func TestMarkCartReady(t *testing.T) {
+	getCart := func() []domain.CartProduct {
+		return []domain.CartProduct{
+			{Product: getProduct(), Quantity: 1},
+			{Product: getProduct(), Quantity: 2},
+		}
+	}
+
+	t.Run("should mark cart line ready and notify workers", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		order := getOrder(primitive.NewObjectID().Hex(), time.Now().UTC(), getCart(), domain.StatusVerified)
+		orderID := order.OrderID.Hex()
+
+		orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order, nil)
+		orderStorage.
+			EXPECT().
+			MarkCartReady(gomock.Any(), gomock.AssignableToTypeOf(dto.MarkCartReadyDTO{})).
+			DoAndReturn(func(ctx context.Context, d dto.MarkCartReadyDTO) (domain.Order, error) {
+				require.Equal(t, orderID, d.OrderID)
+				require.Equal(t, []int{1}, d.Lines)
+				require.NotZero(t, d.At)
+				return withReadyLines(order, d), nil
+			}).
+			Times(1)
+		// Order is not complete while first line is not ready
+		orderStorage.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).Times(0)
+
+		sub, _ := orderService.orderEvents.SubscribeWorkers(0)
+		defer sub.Close()
+
+		err := orderService.MarkCartProductReady(context.Background(), orderID, 1)
+		require.NoError(t, err)
+
+		event := <-sub.Events()
+		require.Equal(t, domain.OrderEventCartChanged, event.Type)
+	})
+
+	t.Run("should complete order because last cart line is ready", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		cart := getCart()
+		cart[0].IsReady = true
+		order := getOrder(primitive.NewObjectID().Hex(), time.Now().UTC(), cart, domain.StatusVerified)
+		orderID := order.OrderID.Hex()
+
+		orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order, nil).Times(2)
+		orderStorage.
+			EXPECT().
+			MarkCartReady(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, d dto.MarkCartReadyDTO) (domain.Order, error) {
+				return withReadyLines(order, d), nil
+			}).
+			Times(1)
+		orderStorage.
+			EXPECT().
+			UpdateOrderStatus(gomock.Any(), gomock.AssignableToTypeOf(dto.UpdateOrderStatusDTO{})).
+			DoAndReturn(func(ctx context.Context, d dto.UpdateOrderStatusDTO) error {
+				require.Equal(t, domain.StatusVerified, d.From)
+				require.Equal(t, domain.StatusCompleted, d.To)
+				return nil
+			}).
+			Times(1)
+
+		err := orderService.MarkCartProductReady(context.Background(), orderID, 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("should mark every line that is not ready and complete order", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		cart := getCart()
+		cart = append(cart, domain.CartProduct{Product: getProduct(), Quantity: 1, IsReady: true})
+		order := getOrder(primitive.NewObjectID().Hex(), time.Now().UTC(), cart, domain.StatusVerified)
+		orderID := order.OrderID.Hex()
+
+		orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order, nil).Times(2)
+		orderStorage.
+			EXPECT().
+			MarkCartReady(gomock.Any(), gomock.AssignableToTypeOf(dto.MarkCartReadyDTO{})).
+			DoAndReturn(func(ctx context.Context, d dto.MarkCartReadyDTO) (domain.Order, error) {
+				require.Equal(t, []int{0, 1}, d.Lines)
+				return withReadyLines(order, d), nil
+			}).
+			Times(1)
+		orderStorage.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+		err := orderService.MarkOrderReady(context.Background(), orderID)
+		require.NoError(t, err)
+	})
+
+	t.Run("should complete order because other line was marked ready concurrently", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		order := getOrder(primitive.NewObjectID().Hex(), time.Now().UTC(), getCart(), domain.StatusVerified)
+		orderID := order.OrderID.Hex()
+
+		// Order is read before the other cook marks line 0
+		orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order, nil).Times(2)
+		orderStorage.
+			EXPECT().
+			MarkCartReady(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, d dto.MarkCartReadyDTO) (domain.Order, error) {
+				return withReadyLines(order, dto.MarkCartReadyDTO{Lines: []int{0, 1}, At: d.At}), nil
+			})
+		orderStorage.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+		err := orderService.MarkCartProductReady(context.Background(), orderID, 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("should not fail because other cook has completed order", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		order := getOrder(primitive.NewObjectID().Hex(), time.Now().UTC(), getCart(), domain.StatusVerified)
+		orderID := order.OrderID.Hex()
+		completed := withReadyLines(order, dto.MarkCartReadyDTO{Lines: []int{0, 1}})
+		completed.Status = domain.StatusCompleted
+
+		gomock.InOrder(
+			orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order, nil),
+			orderStorage.
+				EXPECT().
+				MarkCartReady(gomock.Any(), gomock.Any()).
+				Return(withReadyLines(order, dto.MarkCartReadyDTO{Lines: []int{0, 1}}), nil),
+			// CompleteOrder still sees it verified, but the other cook wins the update
+			orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order, nil),
+			orderStorage.EXPECT().UpdateOrderStatus(gomock.Any(), gomock.Any()).Return(domain.ErrOrderStatusChanged),
+			orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(completed, nil),
+		)
+
+		err := orderService.MarkCartProductReady(context.Background(), orderID, 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("should return ErrOrderNotInKitchen because order is not verified", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		order := getOrder(primitive.NewObjectID().Hex(), time.Now().UTC(), getCart(), domain.StatusWaitingForVerification)
+		orderID := order.OrderID.Hex()
+
+		orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order, nil)
+		orderStorage.EXPECT().MarkCartReady(gomock.Any(), gomock.Any()).Times(0)
+
+		err := orderService.MarkCartProductReady(context.Background(), orderID, 0)
+		require.ErrorIs(t, err, domain.ErrOrderNotInKitchen)
+	})
+
+	t.Run("should return ErrCartLineNotFound because line is out of cart", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		order := getOrder(primitive.NewObjectID().Hex(), time.Now().UTC(), getCart(), domain.StatusVerified)
+		orderID := order.OrderID.Hex()
+
+		orderStorage.EXPECT().GetOrderByID(gomock.Any(), orderID).Return(order, nil)
+		orderStorage.EXPECT().MarkCartReady(gomock.Any(), gomock.Any()).Times(0)
+
+		err := orderService.MarkCartProductReady(context.Background(), orderID, 2)
+		require.ErrorIs(t, err, domain.ErrCartLineNotFound)
+	})
+}
+
+// withReadyLines returns copy of order with lines marked ready, as storage returns it
+func withReadyLines(order domain.Order, d dto.MarkCartReadyDTO) domain.Order {
+	cart := make([]domain.CartProduct, len(order.Cart))
+	copy(cart, order.Cart)
+	for _, line := range d.Lines {
+		cart[line].IsReady = true
+		cart[line].ReadyAt = &d.At
+	}
+	order.Cart = cart
+	return order
+}
+
+func TestListOrders(t *testing.T) {
+	t.Run("should return page with next cursor because storage has more orders", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		var (
+			limit  int64 = 3
+			now          = time.Now().UTC()
+			orders []domain.Order
+		)
+		// limit+1 orders are returned by storage, the last one signals that there's next page
+		for i := int64(0); i < limit+1; i++ {
+			orders = append(orders, getOrder(primitive.NewObjectID().Hex(), now.Add(time.Duration(-i)*time.Minute), nil, domain.StatusVerified))
+		}
+
+		orderStorage.
+			EXPECT().
+			GetOrders(gomock.Any(), gomock.AssignableToTypeOf(dto.ListOrdersDTO{})).
+			DoAndReturn(func(ctx context.Context, d dto.ListOrdersDTO) ([]domain.Order, error) {
+				require.Equal(t, limit+1, d.Limit)
+				return orders, nil
+			})
+
+		page, err := orderService.ListOrders(context.Background(), dto.ListOrdersDTO{Limit: limit})
+		require.NoError(t, err)
+		require.Len(t, page.Orders, int(limit))
+		require.NotNil(t, page.NextCursor)
+
+		c, err := cursor.Decode(*page.NextCursor)
+		require.NoError(t, err)
+		require.Equal(t, orders[limit-1].OrderID, c.ID)
+		require.True(t, orders[limit-1].CreatedAt.Equal(c.CreatedAt))
+	})
+
+	t.Run("should return last page without cursor and apply default limit", func(t *testing.T) {
+		orderService, _, orderStorage := getServices(t, OrderConfig{})
+		orders := []domain.Order{
+			getOrder(primitive.NewObjectID().Hex(), time.Now().UTC(), nil, domain.StatusVerified),
+		}
+
+		orderStorage.
+			EXPECT().
+			GetOrders(gomock.Any(), gomock.AssignableToTypeOf(dto.ListOrdersDTO{})).
+			DoAndReturn(func(ctx context.Context, d dto.ListOrdersDTO) ([]domain.Order, error) {
+				require.Equal(t, DefaultOrdersPageLimit+1, d.Limit)
+				return orders, nil
+			})
+
+		page, err := orderService.ListOrders(context.Background(), dto.ListOrdersDTO{})
+		require.NoError(t, err)
+		require.Len(t, page.Orders, 1)
+		require.Nil(t, page.NextCursor)
+	})
 }
 
 func TestCalculateDiscountedAmount(t *testing.T) {
@@ -256,7 +1168,19 @@ func getServices(t *testing.T, orderConfig OrderConfig) (*orderService, *mock_se
 		DeliveryPunishmentThreshold: 400,
 		DeliveryPunishmentValue:     100,
 	})
-	ordService := NewOrderService(orderStorage, productService, orderConfig, metaCache)
+	orderEvents := NewOrderEventsService(eventbus.New(eventbus.DefaultBufferSize))
+	// Tests that need promo codes or delivery zones take the mocks from orderService
+	promoCodeService := mock_service.NewMockPromoCode(ctrl)
+	deliveryZoneService := mock_service.NewMockDeliveryZone(ctrl)
+	deliverySlotService := mock_service.NewMockDeliverySlot(ctrl)
+	ordService := NewOrderService(orderStorage,
+		productService,
+		promoCodeService,
+		deliveryZoneService,
+		deliverySlotService,
+		orderEvents,
+		orderConfig,
+		metaCache)
 	return ordService.(*orderService), productService, orderStorage
 }
 
@@ -286,15 +1210,32 @@ func getOrder(customerID string, createdAt time.Time, cart []domain.CartProduct,
 	}
 }
 
+func getDeliveryAddress() *domain.OrderDeliveryAddress {
+	return &domain.OrderDeliveryAddress{
+		IsAsap:      true,
+		Address:     f.Street(),
+		Entrance:    1,
+		Floor:       2,
+		Apartment:   3,
+		DeliveredAt: time.Now().UTC().Add(time.Hour),
+		Latitude:    56.8519,
+		Longitude:   60.6122,
+	}
+}
+
 func getProduct() domain.Product {
 	return domain.Product{
 		ProductID:   primitive.NewObjectID(),
 		Name:        f.BeerName(),
 		TranslateRU: f.HipsterWord(),
 		Description: f.LoremIpsumSentence(10),
-		ImageURL:    stringPtr(f.ImageURL(200, 300)),
-		IsApproved:  f.Bool(),
-		Price:       f.Int64(),
+		ImageURL: &domain.ImageURLs{
+			Thumbnail: f.ImageURL(200, 150),
+			Medium:    f.ImageURL(800, 600),
+		},
+		// Only approved products can be ordered
+		IsApproved: true,
+		Price:      f.Int64(),
 		Category: domain.Category{
 			CategoryID: primitive.NewObjectID(),
 			Rank:       int32(f.IntRange(1, 10)),
@@ -317,3 +1258,34 @@ func getProduct() domain.Product {
 func stringPtr(s string) *string {
 	return &s
 }
+
+// getProductWithOptions returns product with required size and up to 2 toppings
+func getProductWithOptions() (domain.Product, domain.OptionGroup, domain.OptionGroup) {
+	var (
+		size = domain.OptionGroup{
+			GroupID:    primitive.NewObjectID(),
+			Name:       "size",
+			MinChoices: 1,
+			MaxChoices: 1,
+			Options: []domain.Option{
+				{OptionID: primitive.NewObjectID(), Name: "small"},
+				{OptionID: primitive.NewObjectID(), Name: "large", PriceDelta: 200},
+			},
+		}
+		toppings = domain.OptionGroup{
+			GroupID:    primitive.NewObjectID(),
+			Name:       "toppings",
+			Multiple:   true,
+			MaxChoices: 2,
+			Options: []domain.Option{
+				{OptionID: primitive.NewObjectID(), Name: "cheese", PriceDelta: 50},
+				{OptionID: primitive.NewObjectID(), Name: "bacon", PriceDelta: 70},
+				{OptionID: primitive.NewObjectID(), Name: "olives", PriceDelta: 30},
+			},
+		}
+	)
+	product := getProduct()
+	product.Price = 500
+	product.OptionGroups = []domain.OptionGroup{size, toppings}
+	return product, size, toppings
+}
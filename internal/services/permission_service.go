@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
+	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const DefaultPermissionPollInterval = time.Second * 30
+
+type PermissionConfig struct {
+	// Interval of reloading permissions from storage when change streams are not available
+	PollInterval time.Duration
+}
+
+type permissionService struct {
+	permissionStorage   storage.Permission
+	permissionsProvider domain.PermissionsProvider
+	permissionConfig    PermissionConfig
+}
+
+func NewPermissionService(permissionStorage storage.Permission,
+	permissionsProvider domain.PermissionsProvider,
+	permissionConfig PermissionConfig) Permission {
+	if permissionConfig.PollInterval <= 0 {
+		permissionConfig.PollInterval = DefaultPermissionPollInterval
+	}
+	return &permissionService{
+		permissionStorage:   permissionStorage,
+		permissionsProvider: permissionsProvider,
+		permissionConfig:    permissionConfig,
+	}
+}
+
+func (p permissionService) Has(role domain.Role, permissions ...domain.Permission) bool {
+	return p.permissionsProvider.Has(role, permissions...)
+}
+
+func (p permissionService) GetAll(ctx context.Context) ([]domain.RolePermissions, error) {
+	saved, err := p.permissionStorage.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return withDefaultPermissions(saved), nil
+}
+
+func (p permissionService) Update(ctx context.Context, dto dto.UpdateRolePermissionsDTO) error {
+	if !dto.Role.IsKnown() {
+		return domain.ErrUnknownRole
+	}
+	// Duplicates are dropped, so that stored set reads the same as it's checked
+	permissions := make([]domain.Permission, 0, len(dto.Permissions))
+	seen := make(map[domain.Permission]struct{}, len(dto.Permissions))
+	for _, permission := range dto.Permissions {
+		if !permission.IsKnown() {
+			return domain.ErrUnknownPermission
+		}
+		if _, ok := seen[permission]; ok {
+			continue
+		}
+		seen[permission] = struct{}{}
+		permissions = append(permissions, permission)
+	}
+
+	rolePermissions := domain.RolePermissions{
+		Role:        dto.Role,
+		Permissions: permissions,
+		UpdatedAt:   time.Now().UTC(),
+	}
+	// Otherwise nobody would be able to grant it back
+	if dto.Role == domain.RoleAdmin && !rolePermissions.Has(domain.PermissionRolesManage) {
+		return domain.ErrRolesManageRequired
+	}
+
+	if err := p.permissionStorage.Save(ctx, rolePermissions); err != nil {
+		return err
+	}
+
+	// Other instances receive it through Watch
+	p.permissionsProvider.Set(rolePermissions)
+	return nil
+}
+
+func (p permissionService) Load(ctx context.Context) error {
+	saved, err := p.permissionStorage.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	p.permissionsProvider.Set(withDefaultPermissions(saved)...)
+	return nil
+}
+
+func (p permissionService) Watch(ctx context.Context) {
+	err := p.permissionStorage.Watch(ctx, func(rolePermissions domain.RolePermissions) {
+		p.permissionsProvider.Set(rolePermissions)
+	})
+	if ctx.Err() != nil {
+		return
+	}
+	logger.Get().Warn("role permissions change stream is unavailable, falling back to polling",
+		zap.Error(err),
+		zap.Duration("interval", p.permissionConfig.PollInterval),
+	)
+
+	ticker := time.NewTicker(p.permissionConfig.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Load(ctx); err != nil && ctx.Err() == nil {
+				logger.Get().Error("reload role permissions", zap.Error(err))
+			}
+		}
+	}
+}
+
+// withDefaultPermissions adds domain.DefaultRolePermissions of roles that have not been saved
+func withDefaultPermissions(saved []domain.RolePermissions) []domain.RolePermissions {
+	rolePermissions := make([]domain.RolePermissions, 0, len(domain.Roles))
+	for _, role := range domain.Roles {
+		found := false
+		for _, rp := range saved {
+			if rp.Role == role {
+				rolePermissions = append(rolePermissions, rp)
+				found = true
+				break
+			}
+		}
+		if !found {
+			rolePermissions = append(rolePermissions, domain.RolePermissions{
+				Role:        role,
+				Permissions: domain.DefaultRolePermissions[role],
+			})
+		}
+	}
+	return rolePermissions
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	mock_storage "github.com/sonyamoonglade/sancho-backend/internal/storages/mocks"
+	"github.com/sonyamoonglade/sancho-backend/pkg/permission_cache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateRolePermissions(t *testing.T) {
+	t.Run("should save permissions without duplicates and set them to provider", func(t *testing.T) {
+		permissionService, permissionStorage, permissionCache := getPermissionService(t)
+
+		permissionStorage.EXPECT().Save(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, rolePermissions domain.RolePermissions) error {
+				require.Equal(t, domain.RoleWorker, rolePermissions.Role)
+				require.Equal(t, []domain.Permission{domain.PermissionOrdersView, domain.PermissionMetaEdit}, rolePermissions.Permissions)
+				require.NotZero(t, rolePermissions.UpdatedAt)
+				return nil
+			})
+
+		err := permissionService.Update(context.Background(), dto.UpdateRolePermissionsDTO{
+			Role:        domain.RoleWorker,
+			Permissions: []domain.Permission{domain.PermissionOrdersView, domain.PermissionMetaEdit, domain.PermissionOrdersView},
+		})
+		require.NoError(t, err)
+		require.True(t, permissionCache.Has(domain.RoleWorker, domain.PermissionOrdersView, domain.PermissionMetaEdit))
+	})
+
+	t.Run("should reject update that takes roles management away from admin", func(t *testing.T) {
+		permissionService, _, _ := getPermissionService(t)
+
+		err := permissionService.Update(context.Background(), dto.UpdateRolePermissionsDTO{
+			Role:        domain.RoleAdmin,
+			Permissions: []domain.Permission{domain.PermissionMetaEdit},
+		})
+		require.ErrorIs(t, err, domain.ErrRolesManageRequired)
+	})
+
+	t.Run("should reject unknown role and permission", func(t *testing.T) {
+		permissionService, _, _ := getPermissionService(t)
+
+		err := permissionService.Update(context.Background(), dto.UpdateRolePermissionsDTO{
+			Role: domain.RoleUnknown,
+		})
+		require.ErrorIs(t, err, domain.ErrUnknownRole)
+
+		err = permissionService.Update(context.Background(), dto.UpdateRolePermissionsDTO{
+			Role:        domain.RoleWorker,
+			Permissions: []domain.Permission{"orders.delete"},
+		})
+		require.ErrorIs(t, err, domain.ErrUnknownPermission)
+	})
+
+	t.Run("should not set permissions to provider because storage failed", func(t *testing.T) {
+		permissionService, permissionStorage, permissionCache := getPermissionService(t)
+
+		permissionStorage.EXPECT().Save(gomock.Any(), gomock.Any()).Return(errors.New("mongo is down"))
+
+		err := permissionService.Update(context.Background(), dto.UpdateRolePermissionsDTO{
+			Role:        domain.RoleWorker,
+			Permissions: []domain.Permission{domain.PermissionOrdersView},
+		})
+		require.Error(t, err)
+		require.False(t, permissionCache.Has(domain.RoleWorker, domain.PermissionOrdersView))
+	})
+}
+
+func TestLoadRolePermissions(t *testing.T) {
+	t.Run("should use default permissions of roles that have not been saved", func(t *testing.T) {
+		permissionService, permissionStorage, permissionCache := getPermissionService(t)
+
+		permissionStorage.EXPECT().GetAll(gomock.Any()).Return([]domain.RolePermissions{
+			{Role: domain.RoleWorker, Permissions: []domain.Permission{domain.PermissionOrdersView}},
+		}, nil)
+
+		require.NoError(t, permissionService.Load(context.Background()))
+		require.True(t, permissionCache.Has(domain.RoleWorker, domain.PermissionOrdersView))
+		require.False(t, permissionCache.Has(domain.RoleWorker, domain.PermissionOrdersCancel))
+		require.True(t, permissionCache.Has(domain.RoleAdmin, domain.Permissions...))
+		require.False(t, permissionCache.Has(domain.RoleCustomer, domain.PermissionOrdersView))
+	})
+}
+
+func getPermissionService(t *testing.T) (*permissionService, *mock_storage.MockPermission, *permission_cache.PermissionCache) {
+	ctrl := gomock.NewController(t)
+	permissionStorage := mock_storage.NewMockPermission(ctrl)
+	permissionCache := permission_cache.NewPermissionCache()
+	pmService := NewPermissionService(permissionStorage, permissionCache, PermissionConfig{})
+	return pmService.(*permissionService), permissionStorage, permissionCache
+}
@@ -2,19 +2,64 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"image"
+	"net/http"
 
 	"github.com/sonyamoonglade/sancho-backend/internal/appErrors"
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
 	"github.com/sonyamoonglade/sancho-backend/internal/storages"
+	"github.com/sonyamoonglade/sancho-backend/pkg/imaging"
+	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
 )
 
+const (
+	DefaultImageMaxSize       = 5 << 20
+	DefaultImageMaxPixels     = imaging.DefaultMaxPixels
+	DefaultImageThumbnailSize = 200
+	DefaultImageMediumSize    = 800
+
+	imageQuality = 85
+)
+
+type ImageConfig struct {
+	// Bytes
+	MaxSize int64
+	// Width * height of uploaded image
+	MaxPixels int
+	// Longest side of variants in pixels
+	ThumbnailSize int
+	MediumSize    int
+}
+
 type productService struct {
 	productStorage storage.Product
+	blobStore      BlobStore
+	imageConfig    ImageConfig
 }
 
-func NewProductService(productStorage storage.Product) Product {
-	return &productService{productStorage: productStorage}
+func NewProductService(productStorage storage.Product, blobStore BlobStore, imageConfig ImageConfig) Product {
+	if imageConfig.MaxSize <= 0 {
+		imageConfig.MaxSize = DefaultImageMaxSize
+	}
+	if imageConfig.MaxPixels <= 0 {
+		imageConfig.MaxPixels = DefaultImageMaxPixels
+	}
+	if imageConfig.ThumbnailSize <= 0 {
+		imageConfig.ThumbnailSize = DefaultImageThumbnailSize
+	}
+	if imageConfig.MediumSize <= 0 {
+		imageConfig.MediumSize = DefaultImageMediumSize
+	}
+	return &productService{
+		productStorage: productStorage,
+		blobStore:      blobStore,
+		imageConfig:    imageConfig,
+	}
 }
 
 func (p productService) GetByID(ctx context.Context, productID string) (domain.Product, error) {
@@ -33,6 +78,24 @@ func (p productService) GetAll(ctx context.Context) ([]domain.Product, error) {
 	return catalog, nil
 }
 
+func (p productService) GetCatalog(ctx context.Context) ([]domain.CatalogCategory, error) {
+	products, err := p.productStorage.GetApproved(ctx)
+	if err != nil {
+		return nil, appErrors.WithContext("productStorage.GetApproved", err)
+	}
+
+	catalog := make([]domain.CatalogCategory, 0)
+	for _, product := range products {
+		last := len(catalog) - 1
+		if last < 0 || catalog[last].CategoryID != product.Category.CategoryID {
+			catalog = append(catalog, domain.CatalogCategory{Category: product.Category})
+			last++
+		}
+		catalog[last].Products = append(catalog[last].Products, product)
+	}
+	return catalog, nil
+}
+
 func (p productService) GetProductsByIDs(ctx context.Context, ids []string) ([]domain.Product, error) {
 	return p.productStorage.GetByIDs(ctx, ids)
 }
@@ -45,6 +108,55 @@ func (p productService) GetAllCategories(ctx context.Context, sorted bool) ([]do
 	return categories, nil
 }
 
+func (p productService) CreateCategory(ctx context.Context, name string) (string, error) {
+	// Rank is given by storage, so that concurrent creations don't share it
+	categoryID, err := p.productStorage.SaveCategory(ctx, domain.Category{
+		CategoryID: primitive.NewObjectID(),
+		Name:       name,
+	})
+	if err != nil {
+		return "", err
+	}
+	return categoryID.Hex(), nil
+}
+
+func (p productService) RenameCategory(ctx context.Context, dto dto.RenameCategoryDTO) error {
+	return p.productStorage.RenameCategory(ctx, dto.CategoryID, dto.Name)
+}
+
+func (p productService) ReorderCategories(ctx context.Context, categoryIDs []string) error {
+	categories, err := p.productStorage.GetAllCategories(ctx, false)
+	if err != nil {
+		return err
+	}
+	if len(categoryIDs) != len(categories) {
+		return domain.ErrInvalidCategoryOrder
+	}
+
+	known := make(map[string]struct{}, len(categories))
+	for _, category := range categories {
+		known[category.CategoryID.Hex()] = struct{}{}
+	}
+
+	ranks := make(map[string]int32, len(categoryIDs))
+	for i, categoryID := range categoryIDs {
+		if _, ok := known[categoryID]; !ok {
+			return domain.ErrInvalidCategoryOrder
+		}
+		if _, ok := ranks[categoryID]; ok {
+			return domain.ErrInvalidCategoryOrder
+		}
+		// Higher rank goes first
+		ranks[categoryID] = int32(len(categoryIDs) - i)
+	}
+
+	return p.productStorage.RankCategories(ctx, ranks)
+}
+
+func (p productService) DeleteCategory(ctx context.Context, categoryID string) error {
+	return p.productStorage.DeleteCategory(ctx, categoryID)
+}
+
 func (p productService) Create(ctx context.Context, dto dto.CreateProductDTO) (string, error) {
 	category, err := p.productStorage.GetCategoryByName(ctx, dto.CategoryName)
 	if err != nil {
@@ -53,6 +165,15 @@ func (p productService) Create(ctx context.Context, dto dto.CreateProductDTO) (s
 
 	product := dto.ToDomain()
 	product.Category = category
+	assignOptionIDs(product.OptionGroups)
+	if product.IsCombo() {
+		if err := p.checkComboProducts(ctx, *product.Combo); err != nil {
+			return "", err
+		}
+		for i := range product.Combo.Components {
+			product.Combo.Components[i].ComponentID = primitive.NewObjectID()
+		}
+	}
 
 	productID, err := p.productStorage.Save(ctx, product)
 	if err != nil {
@@ -63,7 +184,139 @@ func (p productService) Create(ctx context.Context, dto dto.CreateProductDTO) (s
 }
 
 func (p productService) Delete(ctx context.Context, productID string) error {
-	return p.productStorage.Delete(ctx, productID)
+	product, err := p.productStorage.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if err := p.productStorage.Delete(ctx, productID); err != nil {
+		return err
+	}
+	if product.ImageURL != nil {
+		p.deleteBlobs(ctx, product.ImageURL.Keys)
+	}
+	return nil
+}
+
+func (p productService) UploadImage(ctx context.Context, productID string, data []byte) (domain.ImageURLs, error) {
+	if int64(len(data)) > p.imageConfig.MaxSize {
+		return domain.ImageURLs{}, domain.ErrImageTooLarge
+	}
+	switch http.DetectContentType(data) {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return domain.ImageURLs{}, domain.ErrUnsupportedImage
+	}
+
+	// Don't process image of product that does not exist
+	if _, err := p.productStorage.GetByID(ctx, productID); err != nil {
+		return domain.ImageURLs{}, err
+	}
+
+	img, err := imaging.Decode(data, p.imageConfig.MaxPixels)
+	if err != nil {
+		if errors.Is(err, imaging.ErrTooManyPixels) {
+			return domain.ImageURLs{}, domain.ErrImageTooLarge
+		}
+		return domain.ImageURLs{}, domain.ErrUnsupportedImage
+	}
+	// Thumbnail is made of medium variant, it's faster and looks the same
+	medium := imaging.Fit(img, p.imageConfig.MediumSize)
+	thumbnail := imaging.Fit(medium, p.imageConfig.ThumbnailSize)
+
+	// Every upload gets new keys, so that cached previous image is never served instead of the new one
+	var (
+		prefix = fmt.Sprintf("products/%s/%s", productID, primitive.NewObjectID().Hex())
+		urls   domain.ImageURLs
+	)
+	for _, variant := range []struct {
+		name string
+		img  *image.RGBA
+		url  *string
+	}{
+		{name: "medium", img: medium, url: &urls.Medium},
+		{name: "thumbnail", img: thumbnail, url: &urls.Thumbnail},
+	} {
+		encoded, err := imaging.EncodeJPEG(variant.img, imageQuality)
+		if err != nil {
+			p.deleteBlobs(ctx, urls.Keys)
+			return domain.ImageURLs{}, appErrors.WithContext("imaging.EncodeJPEG", err)
+		}
+		key := prefix + "-" + variant.name + ".jpg"
+		url, err := p.blobStore.Put(ctx, key, encoded)
+		if err != nil {
+			p.deleteBlobs(ctx, urls.Keys)
+			return domain.ImageURLs{}, appErrors.WithContext("blobStore.Put", err)
+		}
+		*variant.url = url
+		urls.Keys = append(urls.Keys, key)
+	}
+
+	previous, err := p.productStorage.SetImage(ctx, productID, urls)
+	if err != nil {
+		p.deleteBlobs(ctx, urls.Keys)
+		return domain.ImageURLs{}, err
+	}
+	if previous != nil {
+		p.deleteBlobs(ctx, previous.Keys)
+	}
+	return urls, nil
+}
+
+// deleteBlobs deletes blobs that are no longer referenced. Failure leaves garbage
+// in blob store but does not affect products, so it's only logged.
+func (p productService) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := p.blobStore.Delete(ctx, key); err != nil {
+			logger.Get().Error("delete blob",
+				zap.String("key", key),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func (p productService) UpdateOptionGroups(ctx context.Context, dto dto.UpdateOptionGroupsDTO) error {
+	assignOptionIDs(dto.OptionGroups)
+	return p.productStorage.SetOptionGroups(ctx, dto.ProductID, dto.OptionGroups)
+}
+
+// checkComboProducts returns domain.ErrInvalidCombo if combo has products that don't exist or are combos themselves
+func (p productService) checkComboProducts(ctx context.Context, combo domain.Combo) error {
+	productIDs := combo.ProductIDs()
+	products, err := p.productStorage.GetByIDs(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+	if len(products) != countUnique(productIDs) {
+		return domain.ErrInvalidCombo
+	}
+	for _, product := range products {
+		if product.IsCombo() {
+			return domain.ErrInvalidCombo
+		}
+	}
+	return nil
+}
+
+func countUnique(ids []string) int {
+	unique := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+	return len(unique)
+}
+
+func assignOptionIDs(groups []domain.OptionGroup) {
+	for i := range groups {
+		if groups[i].GroupID.IsZero() {
+			groups[i].GroupID = primitive.NewObjectID()
+		}
+		for j := range groups[i].Options {
+			if groups[i].Options[j].OptionID.IsZero() {
+				groups[i].Options[j].OptionID = primitive.NewObjectID()
+			}
+		}
+	}
 }
 
 func (p productService) Update(ctx context.Context, dto dto.UpdateProductDTO) error {
@@ -78,6 +331,23 @@ func (p productService) Approve(ctx context.Context, productID string) error {
 	if product.IsApproved {
 		return domain.ErrProductAlreadyApproved
 	}
+	// Combo can't be ordered until every product it can be made of is available
+	if product.IsCombo() {
+		productIDs := product.Combo.ProductIDs()
+		components, err := p.productStorage.GetByIDs(ctx, productIDs)
+		if err != nil {
+			return err
+		}
+		// Deleted components are missing
+		if len(components) != countUnique(productIDs) {
+			return domain.ErrComboComponentNotApproved
+		}
+		for _, component := range components {
+			if !component.IsApproved {
+				return domain.ErrComboComponentNotApproved
+			}
+		}
+	}
 	if err := p.productStorage.Approve(ctx, productID); err != nil {
 		return err
 	}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	mock_service "github.com/sonyamoonglade/sancho-backend/internal/services/mocks"
+	mock_storage "github.com/sonyamoonglade/sancho-backend/internal/storages/mocks"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetCatalog(t *testing.T) {
+	t.Run("should group products by category keeping order", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+
+		var (
+			drinks = domain.Category{CategoryID: primitive.NewObjectID(), Rank: 2, Name: "drinks"}
+			pizza  = domain.Category{CategoryID: primitive.NewObjectID(), Rank: 1, Name: "pizza"}
+			cola   = domain.Product{ProductID: primitive.NewObjectID(), Category: drinks, IsApproved: true}
+			juice  = domain.Product{ProductID: primitive.NewObjectID(), Category: drinks, IsApproved: true}
+			pepper = domain.Product{ProductID: primitive.NewObjectID(), Category: pizza, IsApproved: true}
+		)
+		productStorage.EXPECT().GetApproved(gomock.Any()).Return([]domain.Product{cola, juice, pepper}, nil)
+
+		catalog, err := productService.GetCatalog(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []domain.CatalogCategory{
+			{Category: drinks, Products: []domain.Product{cola, juice}},
+			{Category: pizza, Products: []domain.Product{pepper}},
+		}, catalog)
+	})
+
+	t.Run("should return empty catalog", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+
+		productStorage.EXPECT().GetApproved(gomock.Any()).Return(nil, nil)
+
+		catalog, err := productService.GetCatalog(context.Background())
+		require.NoError(t, err)
+		require.NotNil(t, catalog)
+		require.Empty(t, catalog)
+	})
+}
+
+func TestCreateCategory(t *testing.T) {
+	t.Run("should save category leaving rank to storage", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+
+		productStorage.EXPECT().SaveCategory(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, category domain.Category) (primitive.ObjectID, error) {
+				require.False(t, category.CategoryID.IsZero())
+				require.Zero(t, category.Rank)
+				require.Equal(t, "desserts", category.Name)
+				return category.CategoryID, nil
+			})
+
+		categoryID, err := productService.CreateCategory(context.Background(), "desserts")
+		require.NoError(t, err)
+		require.NotZero(t, categoryID)
+	})
+
+	t.Run("should return ErrCategoryAlreadyExists", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+
+		productStorage.EXPECT().SaveCategory(gomock.Any(), gomock.Any()).Return(primitive.ObjectID{}, domain.ErrCategoryAlreadyExists)
+
+		_, err := productService.CreateCategory(context.Background(), "pizza")
+		require.ErrorIs(t, err, domain.ErrCategoryAlreadyExists)
+	})
+}
+
+func TestReorderCategories(t *testing.T) {
+	drinks := domain.Category{CategoryID: primitive.NewObjectID(), Rank: 2, Name: "drinks"}
+	pizza := domain.Category{CategoryID: primitive.NewObjectID(), Rank: 1, Name: "pizza"}
+	desserts := domain.Category{CategoryID: primitive.NewObjectID(), Rank: 0, Name: "desserts"}
+	categories := []domain.Category{drinks, pizza, desserts}
+
+	t.Run("should rank categories in the given order", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+
+		productStorage.EXPECT().GetAllCategories(gomock.Any(), false).Return(categories, nil)
+		productStorage.EXPECT().RankCategories(gomock.Any(), map[string]int32{
+			pizza.CategoryID.Hex():    3,
+			desserts.CategoryID.Hex(): 2,
+			drinks.CategoryID.Hex():   1,
+		}).Return(nil)
+
+		err := productService.ReorderCategories(context.Background(), []string{
+			pizza.CategoryID.Hex(),
+			desserts.CategoryID.Hex(),
+			drinks.CategoryID.Hex(),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject order that does not list every category once", func(t *testing.T) {
+		for _, categoryIDs := range [][]string{
+			{pizza.CategoryID.Hex(), drinks.CategoryID.Hex()},
+			{pizza.CategoryID.Hex(), drinks.CategoryID.Hex(), drinks.CategoryID.Hex()},
+			{pizza.CategoryID.Hex(), drinks.CategoryID.Hex(), primitive.NewObjectID().Hex()},
+		} {
+			productService, productStorage, _ := getProductService(t)
+
+			productStorage.EXPECT().GetAllCategories(gomock.Any(), false).Return(categories, nil)
+
+			err := productService.ReorderCategories(context.Background(), categoryIDs)
+			require.ErrorIs(t, err, domain.ErrInvalidCategoryOrder)
+		}
+	})
+}
+
+func TestUploadImage(t *testing.T) {
+	encodePNG := func(w, h int) []byte {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))))
+		return buf.Bytes()
+	}
+	productID := primitive.NewObjectID().Hex()
+
+	t.Run("should store variants and delete previous image", func(t *testing.T) {
+		productService, productStorage, blobStore := getProductService(t)
+
+		productStorage.EXPECT().GetByID(gomock.Any(), productID).Return(domain.Product{}, nil)
+		var keys []string
+		blobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+			DoAndReturn(func(ctx context.Context, key string, data []byte) (string, error) {
+				require.True(t, strings.HasPrefix(key, "products/"+productID+"/"))
+
+				img, err := jpeg.Decode(bytes.NewReader(data))
+				require.NoError(t, err)
+				size := DefaultImageMediumSize
+				if strings.HasSuffix(key, "-thumbnail.jpg") {
+					size = DefaultImageThumbnailSize
+				}
+				require.Equal(t, size, img.Bounds().Dx())
+				require.Equal(t, size/2, img.Bounds().Dy())
+
+				keys = append(keys, key)
+				return "/static/" + key, nil
+			})
+		productStorage.EXPECT().SetImage(gomock.Any(), productID, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, productID string, image domain.ImageURLs) (*domain.ImageURLs, error) {
+				require.Equal(t, keys, image.Keys)
+				return &domain.ImageURLs{Keys: []string{"old-medium.jpg", "old-thumbnail.jpg"}}, nil
+			})
+		blobStore.EXPECT().Delete(gomock.Any(), "old-medium.jpg").Return(nil)
+		blobStore.EXPECT().Delete(gomock.Any(), "old-thumbnail.jpg").Return(errors.New("disk is gone"))
+
+		urls, err := productService.UploadImage(context.Background(), productID, encodePNG(1600, 800))
+		require.NoError(t, err)
+		require.Equal(t, "/static/"+keys[0], urls.Medium)
+		require.Equal(t, "/static/"+keys[1], urls.Thumbnail)
+	})
+
+	t.Run("should delete stored variants if product is deleted meanwhile", func(t *testing.T) {
+		productService, productStorage, blobStore := getProductService(t)
+
+		productStorage.EXPECT().GetByID(gomock.Any(), productID).Return(domain.Product{}, nil)
+		blobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return("/static/key", nil)
+		productStorage.EXPECT().SetImage(gomock.Any(), productID, gomock.Any()).Return(nil, domain.ErrProductNotFound)
+		blobStore.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(2).Return(nil)
+
+		_, err := productService.UploadImage(context.Background(), productID, encodePNG(10, 10))
+		require.ErrorIs(t, err, domain.ErrProductNotFound)
+	})
+
+	t.Run("should reject image before touching storage", func(t *testing.T) {
+		productService, _, _ := getProductService(t)
+		productService.imageConfig.MaxSize = 1024
+
+		_, err := productService.UploadImage(context.Background(), productID, []byte("%PDF-1.4"))
+		require.ErrorIs(t, err, domain.ErrUnsupportedImage)
+
+		_, err = productService.UploadImage(context.Background(), productID, make([]byte, 1025))
+		require.ErrorIs(t, err, domain.ErrImageTooLarge)
+	})
+
+	t.Run("should reject image with too many pixels", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+		productService.imageConfig.MaxPixels = 100 * 100
+
+		productStorage.EXPECT().GetByID(gomock.Any(), productID).Return(domain.Product{}, nil)
+
+		_, err := productService.UploadImage(context.Background(), productID, encodePNG(101, 100))
+		require.ErrorIs(t, err, domain.ErrImageTooLarge)
+	})
+}
+
+func TestDeleteProduct(t *testing.T) {
+	t.Run("should delete image of product", func(t *testing.T) {
+		productService, productStorage, blobStore := getProductService(t)
+		productID := primitive.NewObjectID().Hex()
+
+		productStorage.EXPECT().GetByID(gomock.Any(), productID).Return(domain.Product{
+			ImageURL: &domain.ImageURLs{Keys: []string{"medium.jpg", "thumbnail.jpg"}},
+		}, nil)
+		productStorage.EXPECT().Delete(gomock.Any(), productID).Return(nil)
+		blobStore.EXPECT().Delete(gomock.Any(), "medium.jpg").Return(nil)
+		blobStore.EXPECT().Delete(gomock.Any(), "thumbnail.jpg").Return(nil)
+
+		require.NoError(t, productService.Delete(context.Background(), productID))
+	})
+
+	t.Run("should return ErrProductNotFound", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+
+		productStorage.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(domain.Product{}, domain.ErrProductNotFound)
+
+		err := productService.Delete(context.Background(), primitive.NewObjectID().Hex())
+		require.ErrorIs(t, err, domain.ErrProductNotFound)
+	})
+}
+
+func TestUpdateOptionGroups(t *testing.T) {
+	t.Run("should keep existing ids and assign new ones", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+		var (
+			productID = primitive.NewObjectID().Hex()
+			groupID   = primitive.NewObjectID()
+			optionID  = primitive.NewObjectID()
+		)
+		groups := []domain.OptionGroup{
+			{
+				GroupID: groupID,
+				Name:    "size",
+				Options: []domain.Option{{OptionID: optionID, Name: "small"}, {Name: "large"}},
+			},
+			{Name: "sauce", Options: []domain.Option{{Name: "garlic"}}},
+		}
+
+		productStorage.
+			EXPECT().
+			SetOptionGroups(gomock.Any(), productID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, saved []domain.OptionGroup) error {
+				require.Equal(t, groupID, saved[0].GroupID)
+				require.Equal(t, optionID, saved[0].Options[0].OptionID)
+				require.False(t, saved[0].Options[1].OptionID.IsZero())
+				require.False(t, saved[1].GroupID.IsZero())
+				require.False(t, saved[1].Options[0].OptionID.IsZero())
+				return nil
+			})
+
+		err := productService.UpdateOptionGroups(context.Background(), dto.UpdateOptionGroupsDTO{
+			ProductID:    productID,
+			OptionGroups: groups,
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestCreateCombo(t *testing.T) {
+	var (
+		burger = domain.Product{ProductID: primitive.NewObjectID()}
+		cola   = domain.Product{ProductID: primitive.NewObjectID()}
+		combo  = &domain.Combo{Components: []domain.ComboComponent{
+			{ProductID: burger.ProductID, Quantity: 1},
+			{ProductID: cola.ProductID, Quantity: 1},
+		}}
+		productIDs = []string{burger.ProductID.Hex(), cola.ProductID.Hex()}
+	)
+
+	t.Run("should assign ids to components", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+
+		productStorage.EXPECT().GetCategoryByName(gomock.Any(), "combos").Return(domain.Category{}, nil)
+		productStorage.EXPECT().GetByIDs(gomock.Any(), productIDs).Return([]domain.Product{burger, cola}, nil)
+		productStorage.
+			EXPECT().
+			Save(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, product domain.Product) (primitive.ObjectID, error) {
+				for _, component := range product.Combo.Components {
+					require.False(t, component.ComponentID.IsZero())
+				}
+				return primitive.NewObjectID(), nil
+			})
+
+		_, err := productService.Create(context.Background(), dto.CreateProductDTO{CategoryName: "combos", Combo: combo})
+		require.NoError(t, err)
+	})
+
+	t.Run("should return ErrInvalidCombo because component does not exist", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+
+		productStorage.EXPECT().GetCategoryByName(gomock.Any(), "combos").Return(domain.Category{}, nil)
+		productStorage.EXPECT().GetByIDs(gomock.Any(), productIDs).Return([]domain.Product{burger}, nil)
+
+		_, err := productService.Create(context.Background(), dto.CreateProductDTO{CategoryName: "combos", Combo: combo})
+		require.ErrorIs(t, err, domain.ErrInvalidCombo)
+	})
+
+	t.Run("should return ErrInvalidCombo because component is a combo", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+		nested := cola
+		nested.Combo = &domain.Combo{}
+
+		productStorage.EXPECT().GetCategoryByName(gomock.Any(), "combos").Return(domain.Category{}, nil)
+		productStorage.EXPECT().GetByIDs(gomock.Any(), productIDs).Return([]domain.Product{burger, nested}, nil)
+
+		_, err := productService.Create(context.Background(), dto.CreateProductDTO{CategoryName: "combos", Combo: combo})
+		require.ErrorIs(t, err, domain.ErrInvalidCombo)
+	})
+}
+
+func TestApproveCombo(t *testing.T) {
+	var (
+		burger = domain.Product{ProductID: primitive.NewObjectID(), IsApproved: true}
+		cola   = domain.Product{ProductID: primitive.NewObjectID(), IsApproved: true}
+		juice  = domain.Product{ProductID: primitive.NewObjectID()}
+		combo  = domain.Product{
+			ProductID: primitive.NewObjectID(),
+			Combo: &domain.Combo{Components: []domain.ComboComponent{
+				{ProductID: burger.ProductID, Quantity: 1},
+				{ProductID: cola.ProductID, Quantity: 1, Swaps: []domain.ComboSwap{{ProductID: juice.ProductID}}},
+			}},
+		}
+		comboID    = combo.ProductID.Hex()
+		productIDs = []string{burger.ProductID.Hex(), cola.ProductID.Hex(), juice.ProductID.Hex()}
+	)
+
+	t.Run("should return ErrComboComponentNotApproved because swap is not approved", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+
+		productStorage.EXPECT().GetByID(gomock.Any(), comboID).Return(combo, nil)
+		productStorage.EXPECT().GetByIDs(gomock.Any(), productIDs).Return([]domain.Product{burger, cola, juice}, nil)
+
+		err := productService.Approve(context.Background(), comboID)
+		require.ErrorIs(t, err, domain.ErrComboComponentNotApproved)
+	})
+
+	t.Run("should return ErrComboComponentNotApproved because component is deleted", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+		approvedJuice := juice
+		approvedJuice.IsApproved = true
+
+		productStorage.EXPECT().GetByID(gomock.Any(), comboID).Return(combo, nil)
+		productStorage.EXPECT().GetByIDs(gomock.Any(), productIDs).Return([]domain.Product{burger, approvedJuice}, nil)
+
+		err := productService.Approve(context.Background(), comboID)
+		require.ErrorIs(t, err, domain.ErrComboComponentNotApproved)
+	})
+
+	t.Run("should approve combo", func(t *testing.T) {
+		productService, productStorage, _ := getProductService(t)
+		approvedJuice := juice
+		approvedJuice.IsApproved = true
+
+		productStorage.EXPECT().GetByID(gomock.Any(), comboID).Return(combo, nil)
+		productStorage.EXPECT().GetByIDs(gomock.Any(), productIDs).Return([]domain.Product{burger, cola, approvedJuice}, nil)
+		productStorage.EXPECT().Approve(gomock.Any(), comboID).Return(nil)
+
+		require.NoError(t, productService.Approve(context.Background(), comboID))
+	})
+}
+
+func getProductService(t *testing.T) (*productService, *mock_storage.MockProduct, *mock_service.MockBlobStore) {
+	ctrl := gomock.NewController(t)
+	productStorage := mock_storage.NewMockProduct(ctrl)
+	blobStore := mock_service.NewMockBlobStore(ctrl)
+	return NewProductService(productStorage, blobStore, ImageConfig{}).(*productService), productStorage, blobStore
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
+)
+
+type promoCodeService struct {
+	promoCodeStorage storage.PromoCode
+}
+
+func NewPromoCodeService(promoCodeStorage storage.PromoCode) PromoCode {
+	return &promoCodeService{promoCodeStorage: promoCodeStorage}
+}
+
+func (p promoCodeService) GetByID(ctx context.Context, promoCodeID string) (domain.PromoCode, error) {
+	return p.promoCodeStorage.GetByID(ctx, promoCodeID)
+}
+
+func (p promoCodeService) GetAll(ctx context.Context) ([]domain.PromoCode, error) {
+	return p.promoCodeStorage.GetAll(ctx)
+}
+
+func (p promoCodeService) Create(ctx context.Context, dto dto.CreatePromoCodeDTO) (string, error) {
+	promoCode := dto.ToDomain()
+	promoCode.CreatedAt = time.Now().UTC()
+
+	promoCodeID, err := p.promoCodeStorage.Save(ctx, promoCode)
+	if err != nil {
+		return "", err
+	}
+	return promoCodeID.Hex(), nil
+}
+
+func (p promoCodeService) Update(ctx context.Context, dto dto.UpdatePromoCodeDTO) error {
+	return p.promoCodeStorage.Update(ctx, dto)
+}
+
+func (p promoCodeService) Delete(ctx context.Context, promoCodeID string) error {
+	return p.promoCodeStorage.Delete(ctx, promoCodeID)
+}
+
+func (p promoCodeService) Apply(ctx context.Context, code string, cart []domain.CartProduct, amount int64) (domain.PromoCode, float64, error) {
+	promoCode, err := p.promoCodeStorage.GetByCode(ctx, strings.ToUpper(code))
+	if err != nil {
+		return domain.PromoCode{}, 0, err
+	}
+	if !promoCode.IsActiveAt(time.Now().UTC()) {
+		return domain.PromoCode{}, 0, domain.ErrPromoCodeNotActive
+	}
+
+	discount, err := promoCode.Discount(cart, amount)
+	if err != nil {
+		return domain.PromoCode{}, 0, err
+	}
+	return promoCode, discount, nil
+}
+
+func (p promoCodeService) Redeem(ctx context.Context, promoCode domain.PromoCode, customerID string) error {
+	return p.promoCodeStorage.Redeem(ctx, dto.RedeemPromoCodeDTO{
+		PromoCodeID:      promoCode.PromoCodeID.Hex(),
+		CustomerID:       customerID,
+		PerCustomerLimit: promoCode.PerCustomerLimit,
+	})
+}
+
+func (p promoCodeService) Release(ctx context.Context, promoCodeID, customerID string) error {
+	return p.promoCodeStorage.Release(ctx, dto.ReleasePromoCodeDTO{
+		PromoCodeID: promoCodeID,
+		CustomerID:  customerID,
+	})
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
+	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
+	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultRevocationSyncInterval = time.Second * 10
+	// DefaultRevocationTTL is used for roles missing from TTLStrategy
+	DefaultRevocationTTL = time.Hour * 24
+)
+
+type RevocationConfig struct {
+	// Interval of reloading revocations made by other instances
+	SyncInterval time.Duration
+}
+
+type revocationService struct {
+	revocationStorage  storage.Revocation
+	revocationProvider domain.RevocationProvider
+	ttlStrategy        TTLStrategy
+	revocationConfig   RevocationConfig
+}
+
+func NewRevocationService(revocationStorage storage.Revocation,
+	revocationProvider domain.RevocationProvider,
+	ttlStrategy TTLStrategy,
+	revocationConfig RevocationConfig) Revocation {
+	if revocationConfig.SyncInterval <= 0 {
+		revocationConfig.SyncInterval = DefaultRevocationSyncInterval
+	}
+	return &revocationService{
+		revocationStorage:  revocationStorage,
+		revocationProvider: revocationProvider,
+		ttlStrategy:        ttlStrategy,
+		revocationConfig:   revocationConfig,
+	}
+}
+
+func (r revocationService) IsRevoked(claims auth.Claims) bool {
+	if _, ok := r.revocationProvider.Get(domain.TokenRevocationKey(claims.TokenID)); ok {
+		return true
+	}
+	if claims.SessionID != "" {
+		revocation, ok := r.revocationProvider.Get(domain.SessionRevocationKey(claims.SessionID))
+		if ok && revocation.Revokes(claims.IssuedAt.Time) {
+			return true
+		}
+	}
+	revocation, ok := r.revocationProvider.Get(domain.UserRevocationKey(claims.Role, claims.UserID))
+	return ok && revocation.Revokes(claims.IssuedAt.Time)
+}
+
+func (r revocationService) RevokeToken(ctx context.Context, claims auth.Claims) error {
+	return r.save(ctx, domain.Revocation{
+		Key:       domain.TokenRevocationKey(claims.TokenID),
+		RevokedAt: time.Now().UTC(),
+		ExpiresAt: claims.ExpiresAt.UTC(),
+	})
+}
+
+func (r revocationService) RevokeSession(ctx context.Context, role domain.Role, sessionID string) error {
+	now := time.Now().UTC()
+	return r.save(ctx, domain.Revocation{
+		Key:       domain.SessionRevocationKey(sessionID),
+		RevokedAt: now,
+		ExpiresAt: now.Add(r.accessTokenTTL(role)),
+	})
+}
+
+func (r revocationService) RevokeUser(ctx context.Context, role domain.Role, userID string) error {
+	now := time.Now().UTC()
+	return r.save(ctx, domain.Revocation{
+		Key:       domain.UserRevocationKey(role, userID),
+		RevokedAt: now,
+		ExpiresAt: now.Add(r.accessTokenTTL(role)),
+	})
+}
+
+func (r revocationService) Load(ctx context.Context) error {
+	revocations, err := r.revocationStorage.GetActive(ctx)
+	if err != nil {
+		return err
+	}
+	r.revocationProvider.Set(revocations...)
+	return nil
+}
+
+func (r revocationService) Watch(ctx context.Context) {
+	ticker := time.NewTicker(r.revocationConfig.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Revocations are only added, so cache is merged with storage and expired ones are dropped
+			if err := r.Load(ctx); err != nil {
+				if ctx.Err() == nil {
+					logger.Get().Error("reload revocations", zap.Error(err))
+				}
+				continue
+			}
+			r.revocationProvider.Prune(time.Now().UTC())
+		}
+	}
+}
+
+// save persists revocation for other instances and applies it to this one right away
+func (r revocationService) save(ctx context.Context, revocation domain.Revocation) error {
+	if err := r.revocationStorage.Save(ctx, revocation); err != nil {
+		return err
+	}
+	r.revocationProvider.Set(revocation)
+	return nil
+}
+
+// accessTokenTTL is the longest time access token of the role issued before now remains valid
+func (r revocationService) accessTokenTTL(role domain.Role) time.Duration {
+	if ttl, ok := r.ttlStrategy.AccessTokenTTLs[role]; ok && ttl > 0 {
+		return ttl
+	}
+	return DefaultRevocationTTL
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	mock_storage "github.com/sonyamoonglade/sancho-backend/internal/storages/mocks"
+	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
+	"github.com/sonyamoonglade/sancho-backend/pkg/revocation_cache"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRevocation(t *testing.T) {
+	newClaims := func(issuedAt time.Time) auth.Claims {
+		return auth.Claims{
+			UserAuth: auth.UserAuth{
+				Role:      domain.RoleWorker,
+				UserID:    primitive.NewObjectID().Hex(),
+				SessionID: primitive.NewObjectID().Hex(),
+			},
+			TokenID:   primitive.NewObjectID().Hex(),
+			IssuedAt:  auth.NewNumericDate(issuedAt),
+			ExpiresAt: auth.NewNumericDate(issuedAt.Add(time.Minute)),
+		}
+	}
+
+	t.Run("should revoke single token", func(t *testing.T) {
+		revocationService, revocationStorage := newTestRevocationService(t)
+		var (
+			claims = newClaims(time.Now())
+			other  = claims
+		)
+		other.TokenID = primitive.NewObjectID().Hex()
+
+		revocationStorage.
+			EXPECT().
+			Save(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, revocation domain.Revocation) error {
+				require.Equal(t, domain.TokenRevocationKey(claims.TokenID), revocation.Key)
+				require.True(t, claims.ExpiresAt.Equal(revocation.ExpiresAt))
+				return nil
+			})
+
+		require.False(t, revocationService.IsRevoked(claims))
+		require.NoError(t, revocationService.RevokeToken(context.Background(), claims))
+		require.True(t, revocationService.IsRevoked(claims))
+		// Another token of the same session
+		require.False(t, revocationService.IsRevoked(other))
+	})
+
+	t.Run("should revoke tokens of user issued before revocation", func(t *testing.T) {
+		revocationService, revocationStorage := newTestRevocationService(t)
+		claims := newClaims(time.Now().Add(-time.Second))
+
+		revocationStorage.
+			EXPECT().
+			Save(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, revocation domain.Revocation) error {
+				require.Equal(t, domain.UserRevocationKey(domain.RoleWorker, claims.UserID), revocation.Key)
+				// Worker's access token TTL
+				require.Equal(t, time.Minute*5, revocation.ExpiresAt.Sub(revocation.RevokedAt))
+				return nil
+			})
+
+		require.NoError(t, revocationService.RevokeUser(context.Background(), domain.RoleWorker, claims.UserID))
+		require.True(t, revocationService.IsRevoked(claims))
+
+		// User logs in again
+		newToken := claims
+		newToken.TokenID = primitive.NewObjectID().Hex()
+		newToken.IssuedAt = auth.NewNumericDate(time.Now().Add(time.Millisecond))
+		require.False(t, revocationService.IsRevoked(newToken))
+
+		// The same id of a user of another role
+		customerToken := claims
+		customerToken.Role = domain.RoleCustomer
+		require.False(t, revocationService.IsRevoked(customerToken))
+	})
+
+	t.Run("should revoke tokens of session", func(t *testing.T) {
+		revocationService, revocationStorage := newTestRevocationService(t)
+		var (
+			claims       = newClaims(time.Now().Add(-time.Second))
+			otherSession = claims
+		)
+		otherSession.SessionID = primitive.NewObjectID().Hex()
+
+		revocationStorage.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+		require.NoError(t, revocationService.RevokeSession(context.Background(), domain.RoleWorker, claims.SessionID))
+		require.True(t, revocationService.IsRevoked(claims))
+		require.False(t, revocationService.IsRevoked(otherSession))
+	})
+
+	t.Run("should load revocations made by other instances", func(t *testing.T) {
+		revocationService, revocationStorage := newTestRevocationService(t)
+		claims := newClaims(time.Now().Add(-time.Second))
+
+		revocationStorage.
+			EXPECT().
+			GetActive(gomock.Any()).
+			Return([]domain.Revocation{{
+				Key:       domain.UserRevocationKey(claims.Role, claims.UserID),
+				RevokedAt: time.Now().UTC(),
+				ExpiresAt: time.Now().UTC().Add(time.Minute),
+			}}, nil)
+
+		require.False(t, revocationService.IsRevoked(claims))
+		require.NoError(t, revocationService.Load(context.Background()))
+		require.True(t, revocationService.IsRevoked(claims))
+	})
+}
+
+func newTestRevocationService(t *testing.T) (*revocationService, *mock_storage.MockRevocation) {
+	ctrl := gomock.NewController(t)
+	revocationStorage := mock_storage.NewMockRevocation(ctrl)
+	ttlStrategy := TTLStrategy{
+		AccessTokenTTLs: map[domain.Role]time.Duration{
+			domain.RoleCustomer: time.Minute,
+			domain.RoleWorker:   time.Minute * 5,
+		},
+	}
+	revocationSvc := NewRevocationService(revocationStorage, revocation_cache.NewRevocationCache(), ttlStrategy, RevocationConfig{})
+	return revocationSvc.(*revocationService), revocationStorage
+}
@@ -4,32 +4,69 @@ import (
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
 	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
+	"github.com/sonyamoonglade/sancho-backend/pkg/eventbus"
 )
 
 type Services struct {
-	Product Product
-	Auth    Auth
-	User    User
-	Order   Order
+	Product      Product
+	Auth         Auth
+	User         User
+	Session      Session
+	Revocation   Revocation
+	Permission   Permission
+	Idempotency  Idempotency
+	Order        Order
+	OrderEvents  OrderEvents
+	Meta         Meta
+	PromoCode    PromoCode
+	DeliveryZone DeliveryZone
+	DeliverySlot DeliverySlot
 }
 
 type Deps struct {
-	Storages      *storage.Storages
-	TokenProvider auth.TokenProvider
-	MetaProvider  domain.MetaProvider
-	Hasher        Hasher
-	TTLStrategy   TTLStrategy
-	OrderConfig   OrderConfig
+	Storages            *storage.Storages
+	TokenProvider       auth.TokenProvider
+	MetaProvider        domain.MetaProvider
+	RevocationProvider  domain.RevocationProvider
+	PermissionsProvider domain.PermissionsProvider
+	Hasher              Hasher
+	TTLStrategy         TTLStrategy
+	OrderConfig         OrderConfig
+	MetaConfig          MetaConfig
+	DeliverySlotConfig  DeliverySlotConfig
+	OTPConfig           OTPConfig
+	RevocationConfig    RevocationConfig
+	PermissionConfig    PermissionConfig
+	IdempotencyConfig   IdempotencyConfig
+	ImageConfig         ImageConfig
+	SMSSender           SMSSender
+	BlobStore           BlobStore
+	EventBus            *eventbus.Bus
 }
 
 func NewServices(deps Deps) *Services {
 	stg := deps.Storages
-	userService := NewUserService(stg.User, deps.Hasher)
-	productService := NewProductService(stg.Product)
+	revocationService := NewRevocationService(stg.Revocation, deps.RevocationProvider, deps.TTLStrategy, deps.RevocationConfig)
+	sessionService := NewSessionService(stg.Session, revocationService)
+	userService := NewUserService(stg.User, sessionService, deps.Hasher)
+	productService := NewProductService(stg.Product, deps.BlobStore, deps.ImageConfig)
+	orderEventsService := NewOrderEventsService(deps.EventBus)
+	promoCodeService := NewPromoCodeService(stg.PromoCode)
+	deliveryZoneService := NewDeliveryZoneService(stg.DeliveryZone)
+	deliverySlotService := NewDeliverySlotService(stg.DeliverySlot, deps.MetaProvider, deps.DeliverySlotConfig)
 	return &Services{
-		Product: productService,
-		User:    userService,
-		Auth:    NewAuthService(userService, deps.TokenProvider, deps.Hasher, deps.TTLStrategy),
-		Order:   NewOrderService(stg.Order, productService, deps.OrderConfig, deps.MetaProvider),
+		Product:      productService,
+		User:         userService,
+		Session:      sessionService,
+		Revocation:   revocationService,
+		Permission:   NewPermissionService(stg.Permission, deps.PermissionsProvider, deps.PermissionConfig),
+		Idempotency:  NewIdempotencyService(stg.Idempotency, deps.IdempotencyConfig),
+		Auth:         NewAuthService(userService, stg.OTP, stg.Session, revocationService, deps.TokenProvider, deps.Hasher, deps.SMSSender, deps.TTLStrategy, deps.OTPConfig),
+		Order:        NewOrderService(stg.Order, productService, promoCodeService, deliveryZoneService, deliverySlotService, orderEventsService, deps.OrderConfig, deps.MetaProvider),
+		OrderEvents:  orderEventsService,
+		Meta:         NewMetaService(stg.Meta, deps.MetaProvider, deps.MetaConfig),
+		PromoCode:    promoCodeService,
+		DeliveryZone: deliveryZoneService,
+		DeliverySlot: deliverySlotService,
 	}
 }
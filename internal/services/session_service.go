@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
+)
+
+type sessionService struct {
+	sessionStorage    storage.Session
+	revocationService Revocation
+}
+
+func NewSessionService(sessionStorage storage.Session, revocationService Revocation) Session {
+	return &sessionService{
+		sessionStorage:    sessionStorage,
+		revocationService: revocationService,
+	}
+}
+
+func (s sessionService) GetByUser(ctx context.Context, role domain.Role, userID string) ([]domain.Session, error) {
+	return s.sessionStorage.GetByUser(ctx, role, userID)
+}
+
+func (s sessionService) Revoke(ctx context.Context, role domain.Role, userID, sessionID string) error {
+	if err := s.sessionStorage.Delete(ctx, role, userID, sessionID); err != nil {
+		return err
+	}
+	// Session can't be refreshed anymore, but its access token is valid until it expires
+	return s.revocationService.RevokeSession(ctx, role, sessionID)
+}
+
+func (s sessionService) RevokeAll(ctx context.Context, role domain.Role, userID string) error {
+	if err := s.sessionStorage.DeleteByUser(ctx, role, userID); err != nil {
+		return err
+	}
+	return s.revocationService.RevokeUser(ctx, role, userID)
+}
@@ -4,34 +4,40 @@ import (
 	"context"
 
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
-	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
 	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
 )
 
 type Hasher interface {
-	Hash(password string) string
+	Hash(password string) (string, error)
+	// Verify compares password with hash in constant time. needsRehash is true if hash
+	// has been made with outdated algorithm or parameters and should be replaced.
+	Verify(password, passwordHash string) (ok bool, needsRehash bool)
 }
 
 type userService struct {
 	passwordHasher Hasher
 	userStorage    storage.User
+	sessionService Session
 }
 
-func NewUserService(userStorage storage.User, hasher Hasher) User {
+func NewUserService(userStorage storage.User, sessionService Session, hasher Hasher) User {
 	return &userService{
 		userStorage:    userStorage,
+		sessionService: sessionService,
 		passwordHasher: hasher,
 	}
 }
 
 func (u userService) GetAdminByLogin(ctx context.Context, login string) (domain.Admin, error) {
-	//TODO implement me
-	panic("implement me")
+	return u.userStorage.GetAdminByLogin(ctx, login)
 }
 
-func (u userService) GetAdminByRefreshToken(ctx context.Context, adminID, token string) (domain.Admin, error) {
-	//TODO implement me
-	panic("implement me")
+func (u userService) GetWorkerByLogin(ctx context.Context, login string) (domain.Worker, error) {
+	return u.userStorage.GetWorkerByLogin(ctx, login)
+}
+
+func (u userService) GetWorkers(ctx context.Context) ([]domain.Worker, error) {
+	return u.userStorage.GetWorkers(ctx)
 }
 
 func (u userService) SaveAdmin(ctx context.Context, admin domain.Admin) (string, error) {
@@ -54,6 +60,28 @@ func (u userService) SaveCustomer(ctx context.Context, customer domain.Customer)
 	return customerID.Hex(), err
 }
 
-func (u userService) SaveSession(ctx context.Context, dto dto.SaveSessionDTO) error {
-	return u.userStorage.SaveSession(ctx, dto)
+func (u userService) SaveWorker(ctx context.Context, worker domain.Worker) (string, error) {
+	workerID, err := u.userStorage.SaveWorker(ctx, worker)
+	if err != nil {
+		return "", err
+	}
+	return workerID.Hex(), nil
+}
+
+func (u userService) DisableWorker(ctx context.Context, workerID string) error {
+	if err := u.userStorage.DisableWorker(ctx, workerID); err != nil {
+		return err
+	}
+	return u.sessionService.RevokeAll(ctx, domain.RoleWorker, workerID)
+}
+
+func (u userService) UpdateWorkerPassword(ctx context.Context, workerID, password string) error {
+	if err := u.userStorage.UpdateWorkerPassword(ctx, workerID, password); err != nil {
+		return err
+	}
+	return u.sessionService.RevokeAll(ctx, domain.RoleWorker, workerID)
+}
+
+func (u userService) UpdatePasswordHash(ctx context.Context, userID string, role domain.Role, passwordHash string) error {
+	return u.userStorage.UpdatePasswordHash(ctx, userID, role, passwordHash)
 }
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type deliverySlotStorage struct {
+	// slots holds number of orders reserved in the slot. Document is keyed by start of the slot,
+	// which is copied to startsAt for TTL index, as _id can't have one.
+	slots *mongo.Collection
+}
+
+func NewDeliverySlotStorage(slots *mongo.Collection) DeliverySlot {
+	return &deliverySlotStorage{slots: slots}
+}
+
+func (d deliverySlotStorage) GetReservations(ctx context.Context, from, to time.Time) ([]domain.DeliverySlotReservation, error) {
+	query := bson.M{
+		"_id": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+
+	cur, err := d.slots.Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations := make([]domain.DeliverySlotReservation, 0)
+	if err := cur.All(ctx, &reservations); err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+func (d deliverySlotStorage) Reserve(ctx context.Context, startsAt time.Time, capacity int64) error {
+	// When slot is full, filter does not match the existing document and upsert fails on duplicate _id
+	filter := bson.M{
+		"_id":      startsAt,
+		"reserved": bson.M{"$lt": capacity},
+	}
+	update := bson.D{
+		bson.E{
+			Key:   "$inc",
+			Value: bson.M{"reserved": 1},
+		},
+		bson.E{
+			Key:   "$setOnInsert",
+			Value: bson.M{"startsAt": startsAt},
+		},
+	}
+	_, err := d.slots.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	// Concurrent first reservations both try to insert the slot and the one that loses fails
+	// on duplicate _id, though slot may have room. Slot exists now, so retry decides it.
+	if mongo.IsDuplicateKeyError(err) {
+		_, err = d.slots.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	}
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrDeliverySlotFull
+		}
+		return err
+	}
+	return nil
+}
+
+func (d deliverySlotStorage) Release(ctx context.Context, startsAt time.Time) error {
+	_, err := d.slots.UpdateOne(ctx, bson.M{
+		"_id":      startsAt,
+		"reserved": bson.M{"$gt": 0},
+	}, bson.D{bson.E{
+		Key:   "$inc",
+		Value: bson.M{"reserved": -1},
+	}})
+	return err
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Mongo error code of a document which geometry can't be indexed by 2dsphere index
+const codeCantExtractGeoKeys = 16755
+
+type deliveryZoneStorage struct {
+	deliveryZones *mongo.Collection
+}
+
+func NewDeliveryZoneStorage(deliveryZones *mongo.Collection) DeliveryZone {
+	return &deliveryZoneStorage{deliveryZones: deliveryZones}
+}
+
+func (d deliveryZoneStorage) GetByID(ctx context.Context, deliveryZoneID string) (domain.DeliveryZone, error) {
+	result := d.deliveryZones.FindOne(ctx, bson.M{"_id": ToObjectID(deliveryZoneID)})
+	if err := result.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.DeliveryZone{}, domain.ErrDeliveryZoneNotFound
+		}
+		return domain.DeliveryZone{}, err
+	}
+
+	var deliveryZone domain.DeliveryZone
+	if err := result.Decode(&deliveryZone); err != nil {
+		return domain.DeliveryZone{}, err
+	}
+	return deliveryZone, nil
+}
+
+func (d deliveryZoneStorage) GetByLocation(ctx context.Context, longitude, latitude float64) (domain.DeliveryZone, error) {
+	query := bson.M{
+		"area": bson.M{
+			"$geoIntersects": bson.M{
+				"$geometry": bson.M{
+					"type":        "Point",
+					"coordinates": bson.A{longitude, latitude},
+				},
+			},
+		},
+	}
+	// Zones may overlap, customer gets the cheapest one
+	opts := options.FindOne()
+	opts.SetSort(bson.D{
+		bson.E{Key: "fee", Value: 1},
+		bson.E{Key: "_id", Value: 1},
+	})
+
+	result := d.deliveryZones.FindOne(ctx, query, opts)
+	if err := result.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.DeliveryZone{}, domain.ErrOutsideDeliveryZone
+		}
+		return domain.DeliveryZone{}, err
+	}
+
+	var deliveryZone domain.DeliveryZone
+	if err := result.Decode(&deliveryZone); err != nil {
+		return domain.DeliveryZone{}, err
+	}
+	return deliveryZone, nil
+}
+
+func (d deliveryZoneStorage) GetAll(ctx context.Context) ([]domain.DeliveryZone, error) {
+	opts := options.Find()
+	opts.SetSort(bson.M{"name": 1})
+
+	cur, err := d.deliveryZones.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveryZones := make([]domain.DeliveryZone, 0)
+	if err := cur.All(ctx, &deliveryZones); err != nil {
+		return nil, err
+	}
+	return deliveryZones, nil
+}
+
+func (d deliveryZoneStorage) Save(ctx context.Context, deliveryZone domain.DeliveryZone) (primitive.ObjectID, error) {
+	result, err := d.deliveryZones.InsertOne(ctx, deliveryZone)
+	if err != nil {
+		if isInvalidGeometryError(err) {
+			return primitive.ObjectID{}, domain.ErrInvalidDeliveryZoneArea
+		}
+		return primitive.ObjectID{}, err
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+func (d deliveryZoneStorage) Update(ctx context.Context, dto dto.UpdateDeliveryZoneDTO) error {
+	updateQuery := bson.D{bson.E{
+		Key: "$set",
+		Value: bson.M{
+			"name":                  dto.Name,
+			"area":                  dto.Area,
+			"fee":                   dto.Fee,
+			"freeDeliveryThreshold": dto.FreeDeliveryThreshold,
+			"minOrderAmount":        dto.MinOrderAmount,
+			"etaMinutes":            dto.ETAMinutes,
+		},
+	}}
+
+	result, err := d.deliveryZones.UpdateOne(ctx, bson.M{"_id": ToObjectID(dto.DeliveryZoneID)}, updateQuery)
+	if err != nil {
+		if isInvalidGeometryError(err) {
+			return domain.ErrInvalidDeliveryZoneArea
+		}
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrDeliveryZoneNotFound
+	}
+	return nil
+}
+
+func (d deliveryZoneStorage) Delete(ctx context.Context, deliveryZoneID string) error {
+	result, err := d.deliveryZones.DeleteOne(ctx, bson.M{"_id": ToObjectID(deliveryZoneID)})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrDeliveryZoneNotFound
+	}
+	return nil
+}
+
+// isInvalidGeometryError reports whether write is rejected by 2dsphere index, e.g. because polygon has self-intersections
+func isInvalidGeometryError(err error) bool {
+	var writeException mongo.WriteException
+	if !errors.As(err, &writeException) {
+		return false
+	}
+	for _, writeError := range writeException.WriteErrors {
+		if writeError.Code == codeCantExtractGeoKeys {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type idempotencyStorage struct {
+	// Expired documents are removed by TTL index on expiresAt
+	idempotencyKeys *mongo.Collection
+}
+
+func NewIdempotencyStorage(idempotencyKeys *mongo.Collection) Idempotency {
+	return &idempotencyStorage{idempotencyKeys: idempotencyKeys}
+}
+
+func (i idempotencyStorage) Get(ctx context.Context, key string) (domain.IdempotencyRecord, error) {
+	// TTL monitor runs once a minute, so expired record may still be there
+	result := i.idempotencyKeys.FindOne(ctx, bson.M{
+		"_id":       key,
+		"expiresAt": bson.M{"$gt": time.Now().UTC()},
+	})
+	if err := result.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.IdempotencyRecord{}, domain.ErrIdempotencyKeyNotFound
+		}
+		return domain.IdempotencyRecord{}, err
+	}
+
+	var record domain.IdempotencyRecord
+	if err := result.Decode(&record); err != nil {
+		return domain.IdempotencyRecord{}, err
+	}
+	return record, nil
+}
+
+func (i idempotencyStorage) Insert(ctx context.Context, record domain.IdempotencyRecord) error {
+	_, err := i.idempotencyKeys.InsertOne(ctx, record)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrIdempotencyKeyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (i idempotencyStorage) Replace(ctx context.Context, record domain.IdempotencyRecord, createdBefore time.Time) error {
+	// Record that has a response or has been replaced by another instance meanwhile does not match unless it has expired
+	filter := bson.M{
+		"_id": record.Key,
+		"$or": bson.A{
+			bson.M{"response": nil, "createdAt": bson.M{"$lt": createdBefore}},
+			bson.M{"expiresAt": bson.M{"$lte": time.Now().UTC()}},
+		},
+	}
+	res, err := i.idempotencyKeys.ReplaceOne(ctx, filter, record)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrIdempotencyKeyInProgress
+	}
+	return nil
+}
+
+func (i idempotencyStorage) SaveResponse(ctx context.Context, key string, response domain.IdempotentResponse) error {
+	res, err := i.idempotencyKeys.UpdateOne(ctx, bson.M{"_id": key}, bson.M{
+		"$set": bson.M{"response": response},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrIdempotencyKeyNotFound
+	}
+	return nil
+}
+
+func (i idempotencyStorage) Delete(ctx context.Context, key string) error {
+	_, err := i.idempotencyKeys.DeleteOne(ctx, bson.M{"_id": key, "response": nil})
+	return err
+}
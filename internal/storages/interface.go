@@ -12,31 +12,156 @@ import (
 type Product interface {
 	GetByID(ctx context.Context, productID string) (domain.Product, error)
 	GetAll(ctx context.Context) ([]domain.Product, error)
+	// GetApproved returns approved products sorted by category rank, products of a category go in a row
+	GetApproved(ctx context.Context) ([]domain.Product, error)
 	GetByIDs(ctx context.Context, ids []string) ([]domain.Product, error)
 	Save(ctx context.Context, product domain.Product) (primitive.ObjectID, error)
 	Update(ctx context.Context, dto dto.UpdateProductDTO) error
+	// SetImage replaces image of product and returns the previous one, nil if product had none
+	SetImage(ctx context.Context, productID string, image domain.ImageURLs) (*domain.ImageURLs, error)
+	// SetOptionGroups replaces every option group of product
+	SetOptionGroups(ctx context.Context, productID string, groups []domain.OptionGroup) error
 	Delete(ctx context.Context, productID string) error
 	Approve(ctx context.Context, productID string) error
 	Disapprove(ctx context.Context, productID string) error
 	GetCategoryByName(ctx context.Context, categoryName string) (domain.Category, error)
 	GetAllCategories(ctx context.Context, sorted bool) ([]domain.Category, error)
+	// SaveCategory puts category last. Category gets rank 1 and ranks of the others and their copies
+	// in products are raised by one in a transaction. It returns domain.ErrCategoryAlreadyExists
+	// if category with the same name exists.
+	SaveCategory(ctx context.Context, category domain.Category) (primitive.ObjectID, error)
+	// RenameCategory renames category and its copy in every product of it in a transaction.
+	// It returns domain.ErrCategoryAlreadyExists if category with the same name exists.
+	RenameCategory(ctx context.Context, categoryID, name string) error
+	// RankCategories sets ranks of categories keyed by id and their copies in products in a transaction
+	RankCategories(ctx context.Context, ranks map[string]int32) error
+	// DeleteCategory returns domain.ErrCategoryNotEmpty if category has products
+	DeleteCategory(ctx context.Context, categoryID string) error
 }
 
 type User interface {
 	GetAdminByLogin(ctx context.Context, login string) (domain.Admin, error)
-	GetAdminByRefreshToken(ctx context.Context, adminID, token string) (domain.Admin, error)
+	GetWorkerByLogin(ctx context.Context, login string) (domain.Worker, error)
+	GetWorkers(ctx context.Context) ([]domain.Worker, error)
 	GetCustomerByPhoneNumber(ctx context.Context, phoneNumber string) (domain.Customer, error)
 
 	SaveAdmin(ctx context.Context, admin domain.Admin) (primitive.ObjectID, error)
 	SaveCustomer(ctx context.Context, customer domain.Customer) (primitive.ObjectID, error)
 	SaveWorker(ctx context.Context, worker domain.Worker) (primitive.ObjectID, error)
-
-	SaveSession(ctx context.Context, dto dto.SaveSessionDTO) error
+	DisableWorker(ctx context.Context, workerID string) error
+	UpdateWorkerPassword(ctx context.Context, workerID, password string) error
+	UpdatePasswordHash(ctx context.Context, userID string, role domain.Role, passwordHash string) error
 }
 
 type Order interface {
 	GetOrderByID(ctx context.Context, orderID string) (domain.Order, error)
 	GetOrderByNanoIDAt(ctx context.Context, nanoID string, from, to time.Time) (domain.Order, error)
 	GetLastOrderByCustomerID(ctx context.Context, customerID string) (domain.Order, error)
+	// GetCurrentOrderByCustomerID returns the latest order that is either waiting for verification or verified
+	GetCurrentOrderByCustomerID(ctx context.Context, customerID string) (domain.Order, error)
+	GetOrders(ctx context.Context, dto dto.ListOrdersDTO) ([]domain.Order, error)
 	SaveOrder(ctx context.Context, order domain.Order) (primitive.ObjectID, error)
+	UpdateOrderStatus(ctx context.Context, dto dto.UpdateOrderStatusDTO) error
+	// MarkCartReady marks cart lines of verified order as prepared and returns the updated order
+	MarkCartReady(ctx context.Context, dto dto.MarkCartReadyDTO) (domain.Order, error)
+}
+
+type Meta interface {
+	Get(ctx context.Context) (domain.BusinessMeta, error)
+	// SaveSchedule sets schedule keeping other fields of meta as they are and returns the updated meta
+	SaveSchedule(ctx context.Context, schedule domain.Schedule) (domain.BusinessMeta, error)
+	// Watch blocks and calls onChange with the new meta every time it's saved until ctx is done.
+	// It returns an error if change streams are not supported by the deployment (standalone mongod).
+	Watch(ctx context.Context, onChange func(meta domain.BusinessMeta)) error
+}
+
+type PromoCode interface {
+	GetByID(ctx context.Context, promoCodeID string) (domain.PromoCode, error)
+	GetByCode(ctx context.Context, code string) (domain.PromoCode, error)
+	GetAll(ctx context.Context) ([]domain.PromoCode, error)
+	Save(ctx context.Context, promoCode domain.PromoCode) (primitive.ObjectID, error)
+	Update(ctx context.Context, dto dto.UpdatePromoCodeDTO) error
+	Delete(ctx context.Context, promoCodeID string) error
+	// Redeem atomically counts usage of promo code by customer.
+	// It returns domain.ErrPromoCodeUsageLimit if either global or per-customer limit is reached.
+	Redeem(ctx context.Context, dto dto.RedeemPromoCodeDTO) error
+	// Release reverts Redeem
+	Release(ctx context.Context, dto dto.ReleasePromoCodeDTO) error
+}
+
+type DeliveryZone interface {
+	GetByID(ctx context.Context, deliveryZoneID string) (domain.DeliveryZone, error)
+	// GetByLocation returns zone containing the point. If zones overlap, the one with the lowest fee is returned.
+	// It returns domain.ErrOutsideDeliveryZone if point is outside of every zone.
+	GetByLocation(ctx context.Context, longitude, latitude float64) (domain.DeliveryZone, error)
+	GetAll(ctx context.Context) ([]domain.DeliveryZone, error)
+	Save(ctx context.Context, deliveryZone domain.DeliveryZone) (primitive.ObjectID, error)
+	Update(ctx context.Context, dto dto.UpdateDeliveryZoneDTO) error
+	Delete(ctx context.Context, deliveryZoneID string) error
+}
+
+type DeliverySlot interface {
+	// GetReservations returns reservations of slots starting within [from, to]
+	GetReservations(ctx context.Context, from, to time.Time) ([]domain.DeliverySlotReservation, error)
+	// Reserve atomically counts order in the slot. It returns domain.ErrDeliverySlotFull if capacity is reached.
+	Reserve(ctx context.Context, startsAt time.Time, capacity int64) error
+	// Release reverts Reserve
+	Release(ctx context.Context, startsAt time.Time) error
+}
+
+type OTP interface {
+	Get(ctx context.Context, phoneNumber string) (domain.OTP, error)
+	// Save replaces code of the phone number unless previous one has been sent after sentBefore,
+	// in which case it returns domain.ErrOTPResendTooSoon
+	Save(ctx context.Context, otp domain.OTP, sentBefore time.Time) error
+	// CountAttempt atomically counts check of the code.
+	// It returns domain.ErrOTPAttemptsExceeded if code has been checked maxAttempts times.
+	CountAttempt(ctx context.Context, phoneNumber, codeHash string, maxAttempts int64) error
+	// Delete removes used code. It returns domain.ErrOTPNotFound if code has been used or replaced.
+	Delete(ctx context.Context, phoneNumber, codeHash string) error
+}
+
+type Session interface {
+	// GetByRefreshToken returns session of user holding the refresh token.
+	// It returns domain.ErrSessionNotFound if there is no such session or it has expired.
+	GetByRefreshToken(ctx context.Context, role domain.Role, userID, refreshToken string) (domain.Session, error)
+	// GetByUser returns sessions of user that have not expired, the most recently used first
+	GetByUser(ctx context.Context, role domain.Role, userID string) ([]domain.Session, error)
+	Save(ctx context.Context, session domain.Session) error
+	// Rotate atomically replaces refresh token of the session, so that the old one can't be used twice.
+	// It returns domain.ErrSessionNotFound if session no longer holds the old token.
+	Rotate(ctx context.Context, dto dto.RotateSessionDTO) error
+	// Delete returns domain.ErrSessionNotFound if user has no such session
+	Delete(ctx context.Context, role domain.Role, userID, sessionID string) error
+	DeleteByUser(ctx context.Context, role domain.Role, userID string) error
+}
+
+type Revocation interface {
+	// GetActive returns revocations that have not expired yet
+	GetActive(ctx context.Context) ([]domain.Revocation, error)
+	// Save replaces revocation of the same key
+	Save(ctx context.Context, revocation domain.Revocation) error
+}
+
+type Permission interface {
+	// GetAll returns permissions of roles that have been saved
+	GetAll(ctx context.Context) ([]domain.RolePermissions, error)
+	// Save replaces permissions of the role
+	Save(ctx context.Context, rolePermissions domain.RolePermissions) error
+	// Watch blocks and calls onChange with permissions of the role every time they're saved until ctx is done.
+	// It returns an error if change streams are not supported by the deployment (standalone mongod).
+	Watch(ctx context.Context, onChange func(rolePermissions domain.RolePermissions)) error
+}
+
+type Idempotency interface {
+	// Get returns domain.ErrIdempotencyKeyNotFound if key has not been used or has expired
+	Get(ctx context.Context, key string) (domain.IdempotencyRecord, error)
+	// Insert returns domain.ErrIdempotencyKeyExists if key has been used
+	Insert(ctx context.Context, record domain.IdempotencyRecord) error
+	// Replace takes over record of the key that has expired or has been created before createdBefore and still has no response.
+	// It returns domain.ErrIdempotencyKeyInProgress otherwise.
+	Replace(ctx context.Context, record domain.IdempotencyRecord, createdBefore time.Time) error
+	SaveResponse(ctx context.Context, key string, response domain.IdempotentResponse) error
+	// Delete removes record of the key unless it has a response
+	Delete(ctx context.Context, key string) error
 }
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// metaDocumentID is _id of the only document in meta collection
+const metaDocumentID = "business"
+
+type metaStorage struct {
+	meta *mongo.Collection
+}
+
+func NewMetaStorage(meta *mongo.Collection) Meta {
+	return &metaStorage{meta: meta}
+}
+
+func (m metaStorage) Get(ctx context.Context) (domain.BusinessMeta, error) {
+	result := m.meta.FindOne(ctx, bson.M{"_id": metaDocumentID})
+	if err := result.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.BusinessMeta{}, domain.ErrMetaNotFound
+		}
+		return domain.BusinessMeta{}, err
+	}
+
+	var meta domain.BusinessMeta
+	if err := result.Decode(&meta); err != nil {
+		return domain.BusinessMeta{}, err
+	}
+	return meta, nil
+}
+
+func (m metaStorage) SaveSchedule(ctx context.Context, schedule domain.Schedule) (domain.BusinessMeta, error) {
+	opts := options.FindOneAndUpdate()
+	opts.SetUpsert(true)
+	opts.SetReturnDocument(options.After)
+
+	result := m.meta.FindOneAndUpdate(ctx,
+		bson.M{"_id": metaDocumentID},
+		bson.M{"$set": bson.M{"schedule": schedule}},
+		opts,
+	)
+	if err := result.Err(); err != nil {
+		return domain.BusinessMeta{}, err
+	}
+
+	var meta domain.BusinessMeta
+	if err := result.Decode(&meta); err != nil {
+		return domain.BusinessMeta{}, err
+	}
+	return meta, nil
+}
+
+func (m metaStorage) Watch(ctx context.Context, onChange func(meta domain.BusinessMeta)) error {
+	pipeline := mongo.Pipeline{bson.D{bson.E{
+		Key:   "$match",
+		Value: bson.M{"documentKey._id": metaDocumentID},
+	}}}
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+
+	stream, err := m.meta.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.Background()) //nolint:errcheck
+
+	for stream.Next(ctx) {
+		var event struct {
+			FullDocument *domain.BusinessMeta `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&event); err != nil {
+			return err
+		}
+		// Document has been deleted
+		if event.FullDocument == nil {
+			continue
+		}
+		onChange(*event.FullDocument)
+	}
+	return stream.Err()
+}
@@ -66,6 +66,20 @@ func (mr *MockProductMockRecorder) Delete(ctx, productID interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProduct)(nil).Delete), ctx, productID)
 }
 
+// DeleteCategory mocks base method.
+func (m *MockProduct) DeleteCategory(ctx context.Context, categoryID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategory", ctx, categoryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCategory indicates an expected call of DeleteCategory.
+func (mr *MockProductMockRecorder) DeleteCategory(ctx, categoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockProduct)(nil).DeleteCategory), ctx, categoryID)
+}
+
 // Disapprove mocks base method.
 func (m *MockProduct) Disapprove(ctx context.Context, productID string) error {
 	m.ctrl.T.Helper()
@@ -110,6 +124,21 @@ func (mr *MockProductMockRecorder) GetAllCategories(ctx, sorted interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCategories", reflect.TypeOf((*MockProduct)(nil).GetAllCategories), ctx, sorted)
 }
 
+// GetApproved mocks base method.
+func (m *MockProduct) GetApproved(ctx context.Context) ([]domain.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApproved", ctx)
+	ret0, _ := ret[0].([]domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApproved indicates an expected call of GetApproved.
+func (mr *MockProductMockRecorder) GetApproved(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApproved", reflect.TypeOf((*MockProduct)(nil).GetApproved), ctx)
+}
+
 // GetByID mocks base method.
 func (m *MockProduct) GetByID(ctx context.Context, productID string) (domain.Product, error) {
 	m.ctrl.T.Helper()
@@ -155,6 +184,34 @@ func (mr *MockProductMockRecorder) GetCategoryByName(ctx, categoryName interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryByName", reflect.TypeOf((*MockProduct)(nil).GetCategoryByName), ctx, categoryName)
 }
 
+// RankCategories mocks base method.
+func (m *MockProduct) RankCategories(ctx context.Context, ranks map[string]int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RankCategories", ctx, ranks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RankCategories indicates an expected call of RankCategories.
+func (mr *MockProductMockRecorder) RankCategories(ctx, ranks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RankCategories", reflect.TypeOf((*MockProduct)(nil).RankCategories), ctx, ranks)
+}
+
+// RenameCategory mocks base method.
+func (m *MockProduct) RenameCategory(ctx context.Context, categoryID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCategory", ctx, categoryID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameCategory indicates an expected call of RenameCategory.
+func (mr *MockProductMockRecorder) RenameCategory(ctx, categoryID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCategory", reflect.TypeOf((*MockProduct)(nil).RenameCategory), ctx, categoryID, name)
+}
+
 // Save mocks base method.
 func (m *MockProduct) Save(ctx context.Context, product domain.Product) (primitive.ObjectID, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +227,50 @@ func (mr *MockProductMockRecorder) Save(ctx, product interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockProduct)(nil).Save), ctx, product)
 }
 
+// SaveCategory mocks base method.
+func (m *MockProduct) SaveCategory(ctx context.Context, category domain.Category) (primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCategory", ctx, category)
+	ret0, _ := ret[0].(primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveCategory indicates an expected call of SaveCategory.
+func (mr *MockProductMockRecorder) SaveCategory(ctx, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCategory", reflect.TypeOf((*MockProduct)(nil).SaveCategory), ctx, category)
+}
+
+// SetImage mocks base method.
+func (m *MockProduct) SetImage(ctx context.Context, productID string, image domain.ImageURLs) (*domain.ImageURLs, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetImage", ctx, productID, image)
+	ret0, _ := ret[0].(*domain.ImageURLs)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetImage indicates an expected call of SetImage.
+func (mr *MockProductMockRecorder) SetImage(ctx, productID, image interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImage", reflect.TypeOf((*MockProduct)(nil).SetImage), ctx, productID, image)
+}
+
+// SetOptionGroups mocks base method.
+func (m *MockProduct) SetOptionGroups(ctx context.Context, productID string, groups []domain.OptionGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOptionGroups", ctx, productID, groups)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOptionGroups indicates an expected call of SetOptionGroups.
+func (mr *MockProductMockRecorder) SetOptionGroups(ctx, productID, groups interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOptionGroups", reflect.TypeOf((*MockProduct)(nil).SetOptionGroups), ctx, productID, groups)
+}
+
 // Update mocks base method.
 func (m *MockProduct) Update(ctx context.Context, dto dto.UpdateProductDTO) error {
 	m.ctrl.T.Helper()
@@ -207,6 +308,20 @@ func (m *MockUser) EXPECT() *MockUserMockRecorder {
 	return m.recorder
 }
 
+// DisableWorker mocks base method.
+func (m *MockUser) DisableWorker(ctx context.Context, workerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableWorker", ctx, workerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableWorker indicates an expected call of DisableWorker.
+func (mr *MockUserMockRecorder) DisableWorker(ctx, workerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableWorker", reflect.TypeOf((*MockUser)(nil).DisableWorker), ctx, workerID)
+}
+
 // GetAdminByLogin mocks base method.
 func (m *MockUser) GetAdminByLogin(ctx context.Context, login string) (domain.Admin, error) {
 	m.ctrl.T.Helper()
@@ -222,34 +337,49 @@ func (mr *MockUserMockRecorder) GetAdminByLogin(ctx, login interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminByLogin", reflect.TypeOf((*MockUser)(nil).GetAdminByLogin), ctx, login)
 }
 
-// GetAdminByRefreshToken mocks base method.
-func (m *MockUser) GetAdminByRefreshToken(ctx context.Context, adminID, token string) (domain.Admin, error) {
+// GetCustomerByPhoneNumber mocks base method.
+func (m *MockUser) GetCustomerByPhoneNumber(ctx context.Context, phoneNumber string) (domain.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAdminByRefreshToken", ctx, adminID, token)
-	ret0, _ := ret[0].(domain.Admin)
+	ret := m.ctrl.Call(m, "GetCustomerByPhoneNumber", ctx, phoneNumber)
+	ret0, _ := ret[0].(domain.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAdminByRefreshToken indicates an expected call of GetAdminByRefreshToken.
-func (mr *MockUserMockRecorder) GetAdminByRefreshToken(ctx, adminID, token interface{}) *gomock.Call {
+// GetCustomerByPhoneNumber indicates an expected call of GetCustomerByPhoneNumber.
+func (mr *MockUserMockRecorder) GetCustomerByPhoneNumber(ctx, phoneNumber interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminByRefreshToken", reflect.TypeOf((*MockUser)(nil).GetAdminByRefreshToken), ctx, adminID, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerByPhoneNumber", reflect.TypeOf((*MockUser)(nil).GetCustomerByPhoneNumber), ctx, phoneNumber)
 }
 
-// GetCustomerByPhoneNumber mocks base method.
-func (m *MockUser) GetCustomerByPhoneNumber(ctx context.Context, phoneNumber string) (domain.Customer, error) {
+// GetWorkerByLogin mocks base method.
+func (m *MockUser) GetWorkerByLogin(ctx context.Context, login string) (domain.Worker, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCustomerByPhoneNumber", ctx, phoneNumber)
-	ret0, _ := ret[0].(domain.Customer)
+	ret := m.ctrl.Call(m, "GetWorkerByLogin", ctx, login)
+	ret0, _ := ret[0].(domain.Worker)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCustomerByPhoneNumber indicates an expected call of GetCustomerByPhoneNumber.
-func (mr *MockUserMockRecorder) GetCustomerByPhoneNumber(ctx, phoneNumber interface{}) *gomock.Call {
+// GetWorkerByLogin indicates an expected call of GetWorkerByLogin.
+func (mr *MockUserMockRecorder) GetWorkerByLogin(ctx, login interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerByPhoneNumber", reflect.TypeOf((*MockUser)(nil).GetCustomerByPhoneNumber), ctx, phoneNumber)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkerByLogin", reflect.TypeOf((*MockUser)(nil).GetWorkerByLogin), ctx, login)
+}
+
+// GetWorkers mocks base method.
+func (m *MockUser) GetWorkers(ctx context.Context) ([]domain.Worker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkers", ctx)
+	ret0, _ := ret[0].([]domain.Worker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkers indicates an expected call of GetWorkers.
+func (mr *MockUserMockRecorder) GetWorkers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkers", reflect.TypeOf((*MockUser)(nil).GetWorkers), ctx)
 }
 
 // SaveAdmin mocks base method.
@@ -282,20 +412,6 @@ func (mr *MockUserMockRecorder) SaveCustomer(ctx, customer interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCustomer", reflect.TypeOf((*MockUser)(nil).SaveCustomer), ctx, customer)
 }
 
-// SaveSession mocks base method.
-func (m *MockUser) SaveSession(ctx context.Context, dto dto.SaveSessionDTO) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveSession", ctx, dto)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// SaveSession indicates an expected call of SaveSession.
-func (mr *MockUserMockRecorder) SaveSession(ctx, dto interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSession", reflect.TypeOf((*MockUser)(nil).SaveSession), ctx, dto)
-}
-
 // SaveWorker mocks base method.
 func (m *MockUser) SaveWorker(ctx context.Context, worker domain.Worker) (primitive.ObjectID, error) {
 	m.ctrl.T.Helper()
@@ -311,6 +427,34 @@ func (mr *MockUserMockRecorder) SaveWorker(ctx, worker interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWorker", reflect.TypeOf((*MockUser)(nil).SaveWorker), ctx, worker)
 }
 
+// UpdatePasswordHash mocks base method.
+func (m *MockUser) UpdatePasswordHash(ctx context.Context, userID string, role domain.Role, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasswordHash", ctx, userID, role, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasswordHash indicates an expected call of UpdatePasswordHash.
+func (mr *MockUserMockRecorder) UpdatePasswordHash(ctx, userID, role, passwordHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordHash", reflect.TypeOf((*MockUser)(nil).UpdatePasswordHash), ctx, userID, role, passwordHash)
+}
+
+// UpdateWorkerPassword mocks base method.
+func (m *MockUser) UpdateWorkerPassword(ctx context.Context, workerID, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkerPassword", ctx, workerID, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkerPassword indicates an expected call of UpdateWorkerPassword.
+func (mr *MockUserMockRecorder) UpdateWorkerPassword(ctx, workerID, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkerPassword", reflect.TypeOf((*MockUser)(nil).UpdateWorkerPassword), ctx, workerID, password)
+}
+
 // MockOrder is a mock of Order interface.
 type MockOrder struct {
 	ctrl     *gomock.Controller
@@ -334,6 +478,21 @@ func (m *MockOrder) EXPECT() *MockOrderMockRecorder {
 	return m.recorder
 }
 
+// GetCurrentOrderByCustomerID mocks base method.
+func (m *MockOrder) GetCurrentOrderByCustomerID(ctx context.Context, customerID string) (domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentOrderByCustomerID", ctx, customerID)
+	ret0, _ := ret[0].(domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentOrderByCustomerID indicates an expected call of GetCurrentOrderByCustomerID.
+func (mr *MockOrderMockRecorder) GetCurrentOrderByCustomerID(ctx, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentOrderByCustomerID", reflect.TypeOf((*MockOrder)(nil).GetCurrentOrderByCustomerID), ctx, customerID)
+}
+
 // GetLastOrderByCustomerID mocks base method.
 func (m *MockOrder) GetLastOrderByCustomerID(ctx context.Context, customerID string) (domain.Order, error) {
 	m.ctrl.T.Helper()
@@ -379,6 +538,36 @@ func (mr *MockOrderMockRecorder) GetOrderByNanoIDAt(ctx, nanoID, from, to interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByNanoIDAt", reflect.TypeOf((*MockOrder)(nil).GetOrderByNanoIDAt), ctx, nanoID, from, to)
 }
 
+// GetOrders mocks base method.
+func (m *MockOrder) GetOrders(ctx context.Context, dto dto.ListOrdersDTO) ([]domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrders", ctx, dto)
+	ret0, _ := ret[0].([]domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrders indicates an expected call of GetOrders.
+func (mr *MockOrderMockRecorder) GetOrders(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockOrder)(nil).GetOrders), ctx, dto)
+}
+
+// MarkCartReady mocks base method.
+func (m *MockOrder) MarkCartReady(ctx context.Context, dto dto.MarkCartReadyDTO) (domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkCartReady", ctx, dto)
+	ret0, _ := ret[0].(domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkCartReady indicates an expected call of MarkCartReady.
+func (mr *MockOrderMockRecorder) MarkCartReady(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkCartReady", reflect.TypeOf((*MockOrder)(nil).MarkCartReady), ctx, dto)
+}
+
 // SaveOrder mocks base method.
 func (m *MockOrder) SaveOrder(ctx context.Context, order domain.Order) (primitive.ObjectID, error) {
 	m.ctrl.T.Helper()
@@ -393,3 +582,801 @@ func (mr *MockOrderMockRecorder) SaveOrder(ctx, order interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOrder", reflect.TypeOf((*MockOrder)(nil).SaveOrder), ctx, order)
 }
+
+// UpdateOrderStatus mocks base method.
+func (m *MockOrder) UpdateOrderStatus(ctx context.Context, dto dto.UpdateOrderStatusDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockOrderMockRecorder) UpdateOrderStatus(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrder)(nil).UpdateOrderStatus), ctx, dto)
+}
+
+// MockMeta is a mock of Meta interface.
+type MockMeta struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetaMockRecorder
+}
+
+// MockMetaMockRecorder is the mock recorder for MockMeta.
+type MockMetaMockRecorder struct {
+	mock *MockMeta
+}
+
+// NewMockMeta creates a new mock instance.
+func NewMockMeta(ctrl *gomock.Controller) *MockMeta {
+	mock := &MockMeta{ctrl: ctrl}
+	mock.recorder = &MockMetaMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMeta) EXPECT() *MockMetaMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockMeta) Get(ctx context.Context) (domain.BusinessMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx)
+	ret0, _ := ret[0].(domain.BusinessMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockMetaMockRecorder) Get(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMeta)(nil).Get), ctx)
+}
+
+// SaveSchedule mocks base method.
+func (m *MockMeta) SaveSchedule(ctx context.Context, schedule domain.Schedule) (domain.BusinessMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSchedule", ctx, schedule)
+	ret0, _ := ret[0].(domain.BusinessMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveSchedule indicates an expected call of SaveSchedule.
+func (mr *MockMetaMockRecorder) SaveSchedule(ctx, schedule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSchedule", reflect.TypeOf((*MockMeta)(nil).SaveSchedule), ctx, schedule)
+}
+
+// Watch mocks base method.
+func (m *MockMeta) Watch(ctx context.Context, onChange func(domain.BusinessMeta)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", ctx, onChange)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockMetaMockRecorder) Watch(ctx, onChange interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockMeta)(nil).Watch), ctx, onChange)
+}
+
+// MockPromoCode is a mock of PromoCode interface.
+type MockPromoCode struct {
+	ctrl     *gomock.Controller
+	recorder *MockPromoCodeMockRecorder
+}
+
+// MockPromoCodeMockRecorder is the mock recorder for MockPromoCode.
+type MockPromoCodeMockRecorder struct {
+	mock *MockPromoCode
+}
+
+// NewMockPromoCode creates a new mock instance.
+func NewMockPromoCode(ctrl *gomock.Controller) *MockPromoCode {
+	mock := &MockPromoCode{ctrl: ctrl}
+	mock.recorder = &MockPromoCodeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPromoCode) EXPECT() *MockPromoCodeMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockPromoCode) Delete(ctx context.Context, promoCodeID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, promoCodeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPromoCodeMockRecorder) Delete(ctx, promoCodeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPromoCode)(nil).Delete), ctx, promoCodeID)
+}
+
+// GetAll mocks base method.
+func (m *MockPromoCode) GetAll(ctx context.Context) ([]domain.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]domain.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockPromoCodeMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPromoCode)(nil).GetAll), ctx)
+}
+
+// GetByCode mocks base method.
+func (m *MockPromoCode) GetByCode(ctx context.Context, code string) (domain.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCode", ctx, code)
+	ret0, _ := ret[0].(domain.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCode indicates an expected call of GetByCode.
+func (mr *MockPromoCodeMockRecorder) GetByCode(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCode", reflect.TypeOf((*MockPromoCode)(nil).GetByCode), ctx, code)
+}
+
+// GetByID mocks base method.
+func (m *MockPromoCode) GetByID(ctx context.Context, promoCodeID string) (domain.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, promoCodeID)
+	ret0, _ := ret[0].(domain.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPromoCodeMockRecorder) GetByID(ctx, promoCodeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPromoCode)(nil).GetByID), ctx, promoCodeID)
+}
+
+// Redeem mocks base method.
+func (m *MockPromoCode) Redeem(ctx context.Context, dto dto.RedeemPromoCodeDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeem", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Redeem indicates an expected call of Redeem.
+func (mr *MockPromoCodeMockRecorder) Redeem(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockPromoCode)(nil).Redeem), ctx, dto)
+}
+
+// Release mocks base method.
+func (m *MockPromoCode) Release(ctx context.Context, dto dto.ReleasePromoCodeDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockPromoCodeMockRecorder) Release(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockPromoCode)(nil).Release), ctx, dto)
+}
+
+// Save mocks base method.
+func (m *MockPromoCode) Save(ctx context.Context, promoCode domain.PromoCode) (primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, promoCode)
+	ret0, _ := ret[0].(primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockPromoCodeMockRecorder) Save(ctx, promoCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPromoCode)(nil).Save), ctx, promoCode)
+}
+
+// Update mocks base method.
+func (m *MockPromoCode) Update(ctx context.Context, dto dto.UpdatePromoCodeDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPromoCodeMockRecorder) Update(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPromoCode)(nil).Update), ctx, dto)
+}
+
+// MockDeliveryZone is a mock of DeliveryZone interface.
+type MockDeliveryZone struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliveryZoneMockRecorder
+}
+
+// MockDeliveryZoneMockRecorder is the mock recorder for MockDeliveryZone.
+type MockDeliveryZoneMockRecorder struct {
+	mock *MockDeliveryZone
+}
+
+// NewMockDeliveryZone creates a new mock instance.
+func NewMockDeliveryZone(ctrl *gomock.Controller) *MockDeliveryZone {
+	mock := &MockDeliveryZone{ctrl: ctrl}
+	mock.recorder = &MockDeliveryZoneMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliveryZone) EXPECT() *MockDeliveryZoneMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockDeliveryZone) Delete(ctx context.Context, deliveryZoneID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, deliveryZoneID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDeliveryZoneMockRecorder) Delete(ctx, deliveryZoneID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDeliveryZone)(nil).Delete), ctx, deliveryZoneID)
+}
+
+// GetAll mocks base method.
+func (m *MockDeliveryZone) GetAll(ctx context.Context) ([]domain.DeliveryZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]domain.DeliveryZone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDeliveryZoneMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDeliveryZone)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockDeliveryZone) GetByID(ctx context.Context, deliveryZoneID string) (domain.DeliveryZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, deliveryZoneID)
+	ret0, _ := ret[0].(domain.DeliveryZone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDeliveryZoneMockRecorder) GetByID(ctx, deliveryZoneID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDeliveryZone)(nil).GetByID), ctx, deliveryZoneID)
+}
+
+// GetByLocation mocks base method.
+func (m *MockDeliveryZone) GetByLocation(ctx context.Context, longitude, latitude float64) (domain.DeliveryZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByLocation", ctx, longitude, latitude)
+	ret0, _ := ret[0].(domain.DeliveryZone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByLocation indicates an expected call of GetByLocation.
+func (mr *MockDeliveryZoneMockRecorder) GetByLocation(ctx, longitude, latitude interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByLocation", reflect.TypeOf((*MockDeliveryZone)(nil).GetByLocation), ctx, longitude, latitude)
+}
+
+// Save mocks base method.
+func (m *MockDeliveryZone) Save(ctx context.Context, deliveryZone domain.DeliveryZone) (primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, deliveryZone)
+	ret0, _ := ret[0].(primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockDeliveryZoneMockRecorder) Save(ctx, deliveryZone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockDeliveryZone)(nil).Save), ctx, deliveryZone)
+}
+
+// Update mocks base method.
+func (m *MockDeliveryZone) Update(ctx context.Context, dto dto.UpdateDeliveryZoneDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDeliveryZoneMockRecorder) Update(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeliveryZone)(nil).Update), ctx, dto)
+}
+
+// MockDeliverySlot is a mock of DeliverySlot interface.
+type MockDeliverySlot struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliverySlotMockRecorder
+}
+
+// MockDeliverySlotMockRecorder is the mock recorder for MockDeliverySlot.
+type MockDeliverySlotMockRecorder struct {
+	mock *MockDeliverySlot
+}
+
+// NewMockDeliverySlot creates a new mock instance.
+func NewMockDeliverySlot(ctrl *gomock.Controller) *MockDeliverySlot {
+	mock := &MockDeliverySlot{ctrl: ctrl}
+	mock.recorder = &MockDeliverySlotMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliverySlot) EXPECT() *MockDeliverySlotMockRecorder {
+	return m.recorder
+}
+
+// GetReservations mocks base method.
+func (m *MockDeliverySlot) GetReservations(ctx context.Context, from, to time.Time) ([]domain.DeliverySlotReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReservations", ctx, from, to)
+	ret0, _ := ret[0].([]domain.DeliverySlotReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReservations indicates an expected call of GetReservations.
+func (mr *MockDeliverySlotMockRecorder) GetReservations(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReservations", reflect.TypeOf((*MockDeliverySlot)(nil).GetReservations), ctx, from, to)
+}
+
+// Release mocks base method.
+func (m *MockDeliverySlot) Release(ctx context.Context, startsAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, startsAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockDeliverySlotMockRecorder) Release(ctx, startsAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockDeliverySlot)(nil).Release), ctx, startsAt)
+}
+
+// Reserve mocks base method.
+func (m *MockDeliverySlot) Reserve(ctx context.Context, startsAt time.Time, capacity int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, startsAt, capacity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockDeliverySlotMockRecorder) Reserve(ctx, startsAt, capacity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockDeliverySlot)(nil).Reserve), ctx, startsAt, capacity)
+}
+
+// MockOTP is a mock of OTP interface.
+type MockOTP struct {
+	ctrl     *gomock.Controller
+	recorder *MockOTPMockRecorder
+}
+
+// MockOTPMockRecorder is the mock recorder for MockOTP.
+type MockOTPMockRecorder struct {
+	mock *MockOTP
+}
+
+// NewMockOTP creates a new mock instance.
+func NewMockOTP(ctrl *gomock.Controller) *MockOTP {
+	mock := &MockOTP{ctrl: ctrl}
+	mock.recorder = &MockOTPMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOTP) EXPECT() *MockOTPMockRecorder {
+	return m.recorder
+}
+
+// CountAttempt mocks base method.
+func (m *MockOTP) CountAttempt(ctx context.Context, phoneNumber, codeHash string, maxAttempts int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAttempt", ctx, phoneNumber, codeHash, maxAttempts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CountAttempt indicates an expected call of CountAttempt.
+func (mr *MockOTPMockRecorder) CountAttempt(ctx, phoneNumber, codeHash, maxAttempts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAttempt", reflect.TypeOf((*MockOTP)(nil).CountAttempt), ctx, phoneNumber, codeHash, maxAttempts)
+}
+
+// Delete mocks base method.
+func (m *MockOTP) Delete(ctx context.Context, phoneNumber, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, phoneNumber, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockOTPMockRecorder) Delete(ctx, phoneNumber, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockOTP)(nil).Delete), ctx, phoneNumber, codeHash)
+}
+
+// Get mocks base method.
+func (m *MockOTP) Get(ctx context.Context, phoneNumber string) (domain.OTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, phoneNumber)
+	ret0, _ := ret[0].(domain.OTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockOTPMockRecorder) Get(ctx, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockOTP)(nil).Get), ctx, phoneNumber)
+}
+
+// Save mocks base method.
+func (m *MockOTP) Save(ctx context.Context, otp domain.OTP, sentBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, otp, sentBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockOTPMockRecorder) Save(ctx, otp, sentBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockOTP)(nil).Save), ctx, otp, sentBefore)
+}
+
+// MockSession is a mock of Session interface.
+type MockSession struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionMockRecorder
+}
+
+// MockSessionMockRecorder is the mock recorder for MockSession.
+type MockSessionMockRecorder struct {
+	mock *MockSession
+}
+
+// NewMockSession creates a new mock instance.
+func NewMockSession(ctrl *gomock.Controller) *MockSession {
+	mock := &MockSession{ctrl: ctrl}
+	mock.recorder = &MockSessionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSession) EXPECT() *MockSessionMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockSession) Delete(ctx context.Context, role domain.Role, userID, sessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, role, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSessionMockRecorder) Delete(ctx, role, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSession)(nil).Delete), ctx, role, userID, sessionID)
+}
+
+// DeleteByUser mocks base method.
+func (m *MockSession) DeleteByUser(ctx context.Context, role domain.Role, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", ctx, role, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockSessionMockRecorder) DeleteByUser(ctx, role, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockSession)(nil).DeleteByUser), ctx, role, userID)
+}
+
+// GetByRefreshToken mocks base method.
+func (m *MockSession) GetByRefreshToken(ctx context.Context, role domain.Role, userID, refreshToken string) (domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByRefreshToken", ctx, role, userID, refreshToken)
+	ret0, _ := ret[0].(domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByRefreshToken indicates an expected call of GetByRefreshToken.
+func (mr *MockSessionMockRecorder) GetByRefreshToken(ctx, role, userID, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByRefreshToken", reflect.TypeOf((*MockSession)(nil).GetByRefreshToken), ctx, role, userID, refreshToken)
+}
+
+// GetByUser mocks base method.
+func (m *MockSession) GetByUser(ctx context.Context, role domain.Role, userID string) ([]domain.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, role, userID)
+	ret0, _ := ret[0].([]domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockSessionMockRecorder) GetByUser(ctx, role, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockSession)(nil).GetByUser), ctx, role, userID)
+}
+
+// Rotate mocks base method.
+func (m *MockSession) Rotate(ctx context.Context, dto dto.RotateSessionDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockSessionMockRecorder) Rotate(ctx, dto interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockSession)(nil).Rotate), ctx, dto)
+}
+
+// Save mocks base method.
+func (m *MockSession) Save(ctx context.Context, session domain.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockSessionMockRecorder) Save(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockSession)(nil).Save), ctx, session)
+}
+
+// MockRevocation is a mock of Revocation interface.
+type MockRevocation struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevocationMockRecorder
+}
+
+// MockRevocationMockRecorder is the mock recorder for MockRevocation.
+type MockRevocationMockRecorder struct {
+	mock *MockRevocation
+}
+
+// NewMockRevocation creates a new mock instance.
+func NewMockRevocation(ctrl *gomock.Controller) *MockRevocation {
+	mock := &MockRevocation{ctrl: ctrl}
+	mock.recorder = &MockRevocationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevocation) EXPECT() *MockRevocationMockRecorder {
+	return m.recorder
+}
+
+// GetActive mocks base method.
+func (m *MockRevocation) GetActive(ctx context.Context) ([]domain.Revocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActive", ctx)
+	ret0, _ := ret[0].([]domain.Revocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActive indicates an expected call of GetActive.
+func (mr *MockRevocationMockRecorder) GetActive(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActive", reflect.TypeOf((*MockRevocation)(nil).GetActive), ctx)
+}
+
+// Save mocks base method.
+func (m *MockRevocation) Save(ctx context.Context, revocation domain.Revocation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, revocation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockRevocationMockRecorder) Save(ctx, revocation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRevocation)(nil).Save), ctx, revocation)
+}
+
+// MockPermission is a mock of Permission interface.
+type MockPermission struct {
+	ctrl     *gomock.Controller
+	recorder *MockPermissionMockRecorder
+}
+
+// MockPermissionMockRecorder is the mock recorder for MockPermission.
+type MockPermissionMockRecorder struct {
+	mock *MockPermission
+}
+
+// NewMockPermission creates a new mock instance.
+func NewMockPermission(ctrl *gomock.Controller) *MockPermission {
+	mock := &MockPermission{ctrl: ctrl}
+	mock.recorder = &MockPermissionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPermission) EXPECT() *MockPermissionMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockPermission) GetAll(ctx context.Context) ([]domain.RolePermissions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]domain.RolePermissions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockPermissionMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPermission)(nil).GetAll), ctx)
+}
+
+// Save mocks base method.
+func (m *MockPermission) Save(ctx context.Context, rolePermissions domain.RolePermissions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, rolePermissions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockPermissionMockRecorder) Save(ctx, rolePermissions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPermission)(nil).Save), ctx, rolePermissions)
+}
+
+// Watch mocks base method.
+func (m *MockPermission) Watch(ctx context.Context, onChange func(domain.RolePermissions)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", ctx, onChange)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockPermissionMockRecorder) Watch(ctx, onChange interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockPermission)(nil).Watch), ctx, onChange)
+}
+
+// MockIdempotency is a mock of Idempotency interface.
+type MockIdempotency struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyMockRecorder
+}
+
+// MockIdempotencyMockRecorder is the mock recorder for MockIdempotency.
+type MockIdempotencyMockRecorder struct {
+	mock *MockIdempotency
+}
+
+// NewMockIdempotency creates a new mock instance.
+func NewMockIdempotency(ctrl *gomock.Controller) *MockIdempotency {
+	mock := &MockIdempotency{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotency) EXPECT() *MockIdempotencyMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockIdempotency) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIdempotencyMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIdempotency)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockIdempotency) Get(ctx context.Context, key string) (domain.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(domain.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockIdempotencyMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIdempotency)(nil).Get), ctx, key)
+}
+
+// Insert mocks base method.
+func (m *MockIdempotency) Insert(ctx context.Context, record domain.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockIdempotencyMockRecorder) Insert(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockIdempotency)(nil).Insert), ctx, record)
+}
+
+// Replace mocks base method.
+func (m *MockIdempotency) Replace(ctx context.Context, record domain.IdempotencyRecord, createdBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, record, createdBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockIdempotencyMockRecorder) Replace(ctx, record, createdBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockIdempotency)(nil).Replace), ctx, record, createdBefore)
+}
+
+// SaveResponse mocks base method.
+func (m *MockIdempotency) SaveResponse(ctx context.Context, key string, response domain.IdempotentResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveResponse", ctx, key, response)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveResponse indicates an expected call of SaveResponse.
+func (mr *MockIdempotencyMockRecorder) SaveResponse(ctx, key, response interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveResponse", reflect.TypeOf((*MockIdempotency)(nil).SaveResponse), ctx, key, response)
+}
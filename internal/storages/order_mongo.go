@@ -3,10 +3,13 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/sonyamoonglade/sancho-backend/internal/appErrors"
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
 	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -26,6 +29,9 @@ func NewOrderStorage(orders *mongo.Collection) Order {
 func (o orderStorage) GetOrderByID(ctx context.Context, orderID string) (domain.Order, error) {
 	result := o.orders.FindOne(ctx, bson.M{"_id": ToObjectID(orderID)}, nil)
 	if err := result.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Order{}, domain.ErrOrderNotFound
+		}
 		return domain.Order{}, err
 	}
 	var order domain.Order
@@ -37,14 +43,52 @@ func (o orderStorage) GetOrderByID(ctx context.Context, orderID string) (domain.
 
 func (o orderStorage) GetLastOrderByCustomerID(ctx context.Context, customerID string) (domain.Order, error) {
 	opts := options.FindOne()
-	opts.SetSort(bson.M{"createdAt": -1})
+	opts.SetSort(bson.D{
+		bson.E{Key: "createdAt", Value: -1},
+		bson.E{Key: "_id", Value: -1},
+	})
 
+	// Order.CustomerID is stored as string, see domain.Order
 	query := bson.D{bson.E{
 		Key:   "customerId",
-		Value: ToObjectID(customerID),
+		Value: customerID,
 	}}
 
-	result := o.orders.FindOne(ctx, query)
+	result := o.orders.FindOne(ctx, query, opts)
+	if err := result.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Order{}, domain.ErrOrderNotFound
+		}
+		return domain.Order{}, err
+	}
+
+	var order domain.Order
+	if err := result.Decode(&order); err != nil {
+		return domain.Order{}, err
+	}
+
+	return order, nil
+}
+
+func (o orderStorage) GetCurrentOrderByCustomerID(ctx context.Context, customerID string) (domain.Order, error) {
+	opts := options.FindOne()
+	opts.SetSort(bson.D{
+		bson.E{Key: "createdAt", Value: -1},
+		bson.E{Key: "_id", Value: -1},
+	})
+
+	query := bson.D{bson.E{
+		Key:   "customerId",
+		Value: customerID,
+	}, bson.E{
+		Key: "status",
+		Value: bson.M{"$in": bson.A{
+			domain.StatusWaitingForVerification,
+			domain.StatusVerified,
+		}},
+	}}
+
+	result := o.orders.FindOne(ctx, query, opts)
 	if err := result.Err(); err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return domain.Order{}, domain.ErrOrderNotFound
@@ -94,6 +138,68 @@ func (o orderStorage) GetOrderByNanoIDAt(ctx context.Context, nanoID string, fro
 	return order, nil
 }
 
+func (o orderStorage) GetOrders(ctx context.Context, dto dto.ListOrdersDTO) ([]domain.Order, error) {
+	query := bson.D{}
+	if dto.Status != nil {
+		query = append(query, bson.E{Key: "status", Value: *dto.Status})
+	}
+	if dto.Pay != nil {
+		query = append(query, bson.E{Key: "pay", Value: *dto.Pay})
+	}
+	if dto.IsDelivered != nil {
+		query = append(query, bson.E{Key: "isDelivered", Value: *dto.IsDelivered})
+	}
+	if dto.CustomerID != nil {
+		query = append(query, bson.E{Key: "customerId", Value: *dto.CustomerID})
+	}
+	if dto.NanoIDPrefix != nil {
+		query = append(query, bson.E{
+			Key:   "nanoId",
+			Value: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(*dto.NanoIDPrefix)},
+		})
+	}
+	if dto.CreatedFrom != nil || dto.CreatedTo != nil {
+		createdAt := bson.M{}
+		if dto.CreatedFrom != nil {
+			createdAt["$gte"] = *dto.CreatedFrom
+		}
+		if dto.CreatedTo != nil {
+			createdAt["$lte"] = *dto.CreatedTo
+		}
+		query = append(query, bson.E{Key: "createdAt", Value: createdAt})
+	}
+	if dto.Cursor != nil {
+		// Orders are sorted by (createdAt, _id) descending, so the next page
+		// consists of orders that are strictly "older" than the cursor.
+		query = append(query, bson.E{Key: "$or", Value: bson.A{
+			bson.M{"createdAt": bson.M{"$lt": dto.Cursor.CreatedAt}},
+			bson.M{
+				"createdAt": dto.Cursor.CreatedAt,
+				"_id":       bson.M{"$lt": dto.Cursor.ID},
+			},
+		}})
+	}
+
+	opts := options.Find()
+	opts.SetSort(bson.D{
+		bson.E{Key: "createdAt", Value: -1},
+		bson.E{Key: "_id", Value: -1},
+	})
+	opts.SetLimit(dto.Limit)
+
+	cur, err := o.orders.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]domain.Order, 0, dto.Limit)
+	if err := cur.All(ctx, &orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
 func (o orderStorage) SaveOrder(ctx context.Context, order domain.Order) (primitive.ObjectID, error) {
 	logger.Get().Debug("saved nano id", zap.String("nanoID", order.NanoID))
 	result, err := o.orders.InsertOne(ctx, order)
@@ -106,3 +212,85 @@ func (o orderStorage) SaveOrder(ctx context.Context, order domain.Order) (primit
 	}
 	return result.InsertedID.(primitive.ObjectID), nil
 }
+
+func (o orderStorage) UpdateOrderStatus(ctx context.Context, dto dto.UpdateOrderStatusDTO) error {
+	updateQuery := bson.M{"status": dto.To}
+	switch dto.To {
+	case domain.StatusVerified:
+		updateQuery["verifiedAt"] = dto.At
+	case domain.StatusCompleted:
+		updateQuery["completedAt"] = dto.At
+	case domain.StatusCancelled:
+		updateQuery["cancelledAt"] = dto.At
+		if dto.CancelExplanation != nil {
+			updateQuery["cancelExplanation"] = *dto.CancelExplanation
+		}
+	}
+
+	// Order is updated only if it still has the status transition was validated against.
+	// It protects from two workers moving the same order concurrently.
+	filter := bson.D{bson.E{
+		Key:   "_id",
+		Value: ToObjectID(dto.OrderID),
+	}, bson.E{
+		Key:   "status",
+		Value: dto.From,
+	}}
+	setQuery := bson.D{bson.E{
+		Key:   "$set",
+		Value: updateQuery,
+	}}
+
+	result, err := o.orders.UpdateOne(ctx, filter, setQuery)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		// Distinguish between missing order and order which status has changed in between
+		if _, err := o.GetOrderByID(ctx, dto.OrderID); err != nil {
+			return err
+		}
+		return domain.ErrOrderStatusChanged
+	}
+	return nil
+}
+
+func (o orderStorage) MarkCartReady(ctx context.Context, dto dto.MarkCartReadyDTO) (domain.Order, error) {
+	updateQuery := bson.M{}
+	for _, line := range dto.Lines {
+		updateQuery[fmt.Sprintf("cart.%d.isReady", line)] = true
+		updateQuery[fmt.Sprintf("cart.%d.readyAt", line)] = dto.At
+	}
+
+	// Only cart of verified order is being prepared
+	filter := bson.D{bson.E{
+		Key:   "_id",
+		Value: ToObjectID(dto.OrderID),
+	}, bson.E{
+		Key:   "status",
+		Value: domain.StatusVerified,
+	}}
+	setQuery := bson.D{bson.E{
+		Key:   "$set",
+		Value: updateQuery,
+	}}
+
+	opts := options.FindOneAndUpdate()
+	opts.SetReturnDocument(options.After)
+
+	result := o.orders.FindOneAndUpdate(ctx, filter, setQuery, opts)
+	if err := result.Err(); err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Order{}, err
+		}
+		if _, err := o.GetOrderByID(ctx, dto.OrderID); err != nil {
+			return domain.Order{}, err
+		}
+		return domain.Order{}, domain.ErrOrderStatusChanged
+	}
+	var order domain.Order
+	if err := result.Decode(&order); err != nil {
+		return domain.Order{}, err
+	}
+	return order, nil
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type otpStorage struct {
+	// otps is keyed by phone number. Expired documents are removed by TTL index on expiresAt.
+	otps *mongo.Collection
+}
+
+func NewOTPStorage(otps *mongo.Collection) OTP {
+	return &otpStorage{otps: otps}
+}
+
+func (o otpStorage) Get(ctx context.Context, phoneNumber string) (domain.OTP, error) {
+	result := o.otps.FindOne(ctx, bson.M{"_id": phoneNumber})
+	if err := result.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.OTP{}, domain.ErrOTPNotFound
+		}
+		return domain.OTP{}, err
+	}
+
+	var otp domain.OTP
+	if err := result.Decode(&otp); err != nil {
+		return domain.OTP{}, err
+	}
+	return otp, nil
+}
+
+func (o otpStorage) Save(ctx context.Context, otp domain.OTP, sentBefore time.Time) error {
+	// When code has been sent after sentBefore, filter does not match the existing
+	// document and upsert fails on duplicate _id
+	filter := bson.M{
+		"_id":    otp.PhoneNumber,
+		"sentAt": bson.M{"$lt": sentBefore},
+	}
+	_, err := o.otps.ReplaceOne(ctx, filter, otp, options.Replace().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrOTPResendTooSoon
+		}
+		return err
+	}
+	return nil
+}
+
+func (o otpStorage) CountAttempt(ctx context.Context, phoneNumber, codeHash string, maxAttempts int64) error {
+	// Code hash is matched, so that attempt is not counted against the code that has been resent meanwhile
+	filter := bson.M{
+		"_id":      phoneNumber,
+		"codeHash": codeHash,
+		"attempts": bson.M{"$lt": maxAttempts},
+	}
+	result, err := o.otps.UpdateOne(ctx, filter, bson.D{bson.E{
+		Key:   "$inc",
+		Value: bson.M{"attempts": 1},
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrOTPAttemptsExceeded
+	}
+	return nil
+}
+
+func (o otpStorage) Delete(ctx context.Context, phoneNumber, codeHash string) error {
+	result, err := o.otps.DeleteOne(ctx, bson.M{
+		"_id":      phoneNumber,
+		"codeHash": codeHash,
+	})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrOTPNotFound
+	}
+	return nil
+}
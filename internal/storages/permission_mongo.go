@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type permissionStorage struct {
+	rolePermissions *mongo.Collection
+}
+
+func NewPermissionStorage(rolePermissions *mongo.Collection) Permission {
+	return &permissionStorage{rolePermissions: rolePermissions}
+}
+
+func (p permissionStorage) GetAll(ctx context.Context) ([]domain.RolePermissions, error) {
+	cur, err := p.rolePermissions.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	rolePermissions := make([]domain.RolePermissions, 0)
+	if err := cur.All(ctx, &rolePermissions); err != nil {
+		return nil, err
+	}
+	return rolePermissions, nil
+}
+
+func (p permissionStorage) Save(ctx context.Context, rolePermissions domain.RolePermissions) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := p.rolePermissions.ReplaceOne(ctx, bson.M{"role.role": rolePermissions.Role.String()}, rolePermissions, opts)
+	return err
+}
+
+func (p permissionStorage) Watch(ctx context.Context, onChange func(rolePermissions domain.RolePermissions)) error {
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	stream, err := p.rolePermissions.Watch(ctx, mongo.Pipeline{}, opts)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.Background()) //nolint:errcheck
+
+	for stream.Next(ctx) {
+		var event struct {
+			FullDocument *domain.RolePermissions `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&event); err != nil {
+			return err
+		}
+		// Document has been deleted
+		if event.FullDocument == nil {
+			continue
+		}
+		onChange(*event.FullDocument)
+	}
+	return stream.Err()
+}
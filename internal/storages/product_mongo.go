@@ -57,6 +57,28 @@ func (p productStorage) GetAll(ctx context.Context) ([]domain.Product, error) {
 
 	return products, nil
 }
+func (p productStorage) GetApproved(ctx context.Context) ([]domain.Product, error) {
+	opts := options.Find()
+	// Categories may share rank, so products are also sorted by category to keep them in a row
+	opts.SetSort(bson.D{
+		{Key: "category.rank", Value: -1},
+		{Key: "category._id", Value: 1},
+		{Key: "_id", Value: 1},
+	})
+
+	cur, err := p.products.Find(ctx, bson.M{"isApproved": true}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var products []domain.Product
+	if err := cur.All(ctx, &products); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
 func (p productStorage) GetByIDs(ctx context.Context, ids []string) ([]domain.Product, error) {
 	objectIDs := make([]primitive.ObjectID, 0, len(ids))
 	for _, id := range ids {
@@ -120,9 +142,6 @@ func (p productStorage) Update(ctx context.Context, dto dto.UpdateProductDTO) er
 	if dto.TranslateRU != nil {
 		updateQuery["translateRu"] = *dto.TranslateRU
 	}
-	if dto.ImageURL != nil {
-		updateQuery["imageUrl"] = *dto.ImageURL
-	}
 	setQuery := bson.D{
 		bson.E{
 			Key:   "$set",
@@ -143,6 +162,46 @@ func (p productStorage) Update(ctx context.Context, dto dto.UpdateProductDTO) er
 	return nil
 }
 
+func (p productStorage) SetImage(ctx context.Context, productID string, image domain.ImageURLs) (*domain.ImageURLs, error) {
+	opts := options.FindOneAndUpdate()
+	opts.SetReturnDocument(options.Before)
+	opts.SetProjection(bson.M{"imageUrl": 1})
+
+	result := p.products.FindOneAndUpdate(ctx,
+		bson.M{"_id": ToObjectID(productID)},
+		bson.M{"$set": bson.M{"imageUrl": image}},
+		opts,
+	)
+	if err := result.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	var previous struct {
+		ImageURL *domain.ImageURLs `bson:"imageUrl"`
+	}
+	if err := result.Decode(&previous); err != nil {
+		return nil, err
+	}
+	return previous.ImageURL, nil
+}
+
+func (p productStorage) SetOptionGroups(ctx context.Context, productID string, groups []domain.OptionGroup) error {
+	result, err := p.products.UpdateOne(ctx,
+		bson.M{"_id": ToObjectID(productID)},
+		bson.M{"$set": bson.M{"optionGroups": groups}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrProductNotFound
+	}
+	return nil
+}
+
 func (p productStorage) Approve(ctx context.Context, productID string) error {
 	query := bson.D{
 		bson.E{Key: "$set", Value: bson.D{
@@ -219,3 +278,88 @@ func (p productStorage) GetCategoryByName(ctx context.Context, categoryName stri
 	}
 	return category, nil
 }
+
+func (p productStorage) SaveCategory(ctx context.Context, category domain.Category) (primitive.ObjectID, error) {
+	category.Rank = 1
+	err := withTransaction(ctx, p.categories.Database().Client(), func(ctx mongo.SessionContext) error {
+		// Concurrent saves conflict on raising the same ranks, so the one that loses is retried
+		if _, err := p.categories.UpdateMany(ctx, bson.M{}, bson.M{"$inc": bson.M{"rank": 1}}); err != nil {
+			return err
+		}
+		// Catalog is sorted by rank of the copy
+		if _, err := p.products.UpdateMany(ctx, bson.M{}, bson.M{"$inc": bson.M{"category.rank": 1}}); err != nil {
+			return err
+		}
+		if _, err := p.categories.InsertOne(ctx, category); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return domain.ErrCategoryAlreadyExists
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return primitive.ObjectID{}, err
+	}
+	return category.CategoryID, nil
+}
+
+func (p productStorage) RenameCategory(ctx context.Context, categoryID, name string) error {
+	id := ToObjectID(categoryID)
+	return withTransaction(ctx, p.categories.Database().Client(), func(ctx mongo.SessionContext) error {
+		result, err := p.categories.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"name": name}})
+		if err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return domain.ErrCategoryAlreadyExists
+			}
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return domain.ErrCategoryNotFound
+		}
+		_, err = p.products.UpdateMany(ctx, bson.M{"category._id": id}, bson.M{"$set": bson.M{"category.name": name}})
+		return err
+	})
+}
+
+func (p productStorage) RankCategories(ctx context.Context, ranks map[string]int32) error {
+	return withTransaction(ctx, p.categories.Database().Client(), func(ctx mongo.SessionContext) error {
+		for categoryID, rank := range ranks {
+			id := ToObjectID(categoryID)
+			result, err := p.categories.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"rank": rank}})
+			if err != nil {
+				return err
+			}
+			if result.MatchedCount == 0 {
+				return domain.ErrCategoryNotFound
+			}
+			// Catalog is sorted by rank of the copy
+			_, err = p.products.UpdateMany(ctx, bson.M{"category._id": id}, bson.M{"$set": bson.M{"category.rank": rank}})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (p productStorage) DeleteCategory(ctx context.Context, categoryID string) error {
+	id := ToObjectID(categoryID)
+	return withTransaction(ctx, p.categories.Database().Client(), func(ctx mongo.SessionContext) error {
+		count, err := p.products.CountDocuments(ctx, bson.M{"category._id": id})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return domain.ErrCategoryNotEmpty
+		}
+		result, err := p.categories.DeleteOne(ctx, bson.M{"_id": id})
+		if err != nil {
+			return err
+		}
+		if result.DeletedCount == 0 {
+			return domain.ErrCategoryNotFound
+		}
+		return nil
+	})
+}
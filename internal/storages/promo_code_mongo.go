@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type promoCodeStorage struct {
+	promoCodes *mongo.Collection
+	// usages holds number of redemptions of promo code by customer.
+	// Document is keyed by {promoCodeId, customerId}.
+	usages *mongo.Collection
+}
+
+func NewPromoCodeStorage(promoCodes *mongo.Collection, usages *mongo.Collection) PromoCode {
+	return &promoCodeStorage{
+		promoCodes: promoCodes,
+		usages:     usages,
+	}
+}
+
+func (p promoCodeStorage) GetByID(ctx context.Context, promoCodeID string) (domain.PromoCode, error) {
+	return p.findOne(ctx, bson.M{"_id": ToObjectID(promoCodeID)})
+}
+
+func (p promoCodeStorage) GetByCode(ctx context.Context, code string) (domain.PromoCode, error) {
+	return p.findOne(ctx, bson.M{"code": code})
+}
+
+func (p promoCodeStorage) findOne(ctx context.Context, query bson.M) (domain.PromoCode, error) {
+	result := p.promoCodes.FindOne(ctx, query)
+	if err := result.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.PromoCode{}, domain.ErrPromoCodeNotFound
+		}
+		return domain.PromoCode{}, err
+	}
+
+	var promoCode domain.PromoCode
+	if err := result.Decode(&promoCode); err != nil {
+		return domain.PromoCode{}, err
+	}
+	return promoCode, nil
+}
+
+func (p promoCodeStorage) GetAll(ctx context.Context) ([]domain.PromoCode, error) {
+	opts := options.Find()
+	opts.SetSort(bson.M{"createdAt": -1})
+
+	cur, err := p.promoCodes.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	promoCodes := make([]domain.PromoCode, 0)
+	if err := cur.All(ctx, &promoCodes); err != nil {
+		return nil, err
+	}
+	return promoCodes, nil
+}
+
+func (p promoCodeStorage) Save(ctx context.Context, promoCode domain.PromoCode) (primitive.ObjectID, error) {
+	result, err := p.promoCodes.InsertOne(ctx, promoCode)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return primitive.ObjectID{}, domain.ErrPromoCodeAlreadyExists
+		}
+		return primitive.ObjectID{}, err
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+func (p promoCodeStorage) Update(ctx context.Context, dto dto.UpdatePromoCodeDTO) error {
+	updateQuery := bson.M{
+		"kind":             dto.Kind,
+		"minCartAmount":    dto.MinCartAmount,
+		"usageLimit":       dto.UsageLimit,
+		"perCustomerLimit": dto.PerCustomerLimit,
+		"categoryIds":      dto.CategoryIDs,
+		"productIds":       dto.ProductIDs,
+	}
+	unsetQuery := bson.M{}
+	// Optional fields are removed if not set, the same way they're omitted on insert
+	setOrUnset := func(key string, value interface{}, isSet bool) {
+		if isSet {
+			updateQuery[key] = value
+			return
+		}
+		unsetQuery[key] = ""
+	}
+	setOrUnset("percent", dto.Percent, dto.Percent != 0)
+	setOrUnset("amount", dto.Amount, dto.Amount != 0)
+	setOrUnset("validFrom", dto.ValidFrom, dto.ValidFrom != nil)
+	setOrUnset("validTo", dto.ValidTo, dto.ValidTo != nil)
+
+	query := bson.D{bson.E{
+		Key:   "$set",
+		Value: updateQuery,
+	}}
+	if len(unsetQuery) > 0 {
+		query = append(query, bson.E{Key: "$unset", Value: unsetQuery})
+	}
+
+	result, err := p.promoCodes.UpdateOne(ctx, bson.M{"_id": ToObjectID(dto.PromoCodeID)}, query)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrPromoCodeNotFound
+	}
+	return nil
+}
+
+func (p promoCodeStorage) Delete(ctx context.Context, promoCodeID string) error {
+	result, err := p.promoCodes.DeleteOne(ctx, bson.M{"_id": ToObjectID(promoCodeID)})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrPromoCodeNotFound
+	}
+
+	_, err = p.usages.DeleteMany(ctx, bson.M{"_id.promoCodeId": ToObjectID(promoCodeID)})
+	return err
+}
+
+func (p promoCodeStorage) Redeem(ctx context.Context, dto dto.RedeemPromoCodeDTO) error {
+	promoCodeID := ToObjectID(dto.PromoCodeID)
+
+	// Per-customer usage is counted by upsert. When customer has reached the limit, filter
+	// does not match the existing document and upsert fails on duplicate _id.
+	usageFilter := bson.D{bson.E{
+		Key:   "_id",
+		Value: usageKey(promoCodeID, dto.CustomerID),
+	}}
+	if dto.PerCustomerLimit > 0 {
+		usageFilter = append(usageFilter, bson.E{
+			Key:   "count",
+			Value: bson.M{"$lt": dto.PerCustomerLimit},
+		})
+	}
+	incQuery := bson.D{bson.E{
+		Key:   "$inc",
+		Value: bson.M{"count": 1},
+	}}
+	_, err := p.usages.UpdateOne(ctx, usageFilter, incQuery, options.Update().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrPromoCodeUsageLimit
+		}
+		return err
+	}
+
+	// Global usage is compared to the limit stored in promo code itself, so that
+	// the check and the increment are a single atomic operation
+	filter := bson.D{bson.E{
+		Key:   "_id",
+		Value: promoCodeID,
+	}, bson.E{
+		Key: "$or",
+		Value: bson.A{
+			bson.M{"usageLimit": 0},
+			bson.M{"$expr": bson.M{"$lt": bson.A{"$usedCount", "$usageLimit"}}},
+		},
+	}}
+	result, err := p.promoCodes.UpdateOne(ctx, filter, bson.D{bson.E{
+		Key:   "$inc",
+		Value: bson.M{"usedCount": 1},
+	}})
+	if err == nil && result.MatchedCount == 0 {
+		err = domain.ErrPromoCodeUsageLimit
+	}
+	if err != nil {
+		// Give customer's usage back
+		if _, rollbackErr := p.usages.UpdateOne(ctx, bson.M{"_id": usageKey(promoCodeID, dto.CustomerID)}, bson.D{bson.E{
+			Key:   "$inc",
+			Value: bson.M{"count": -1},
+		}}); rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+	return nil
+}
+
+func (p promoCodeStorage) Release(ctx context.Context, dto dto.ReleasePromoCodeDTO) error {
+	promoCodeID := ToObjectID(dto.PromoCodeID)
+
+	_, err := p.promoCodes.UpdateOne(ctx, bson.M{
+		"_id":       promoCodeID,
+		"usedCount": bson.M{"$gt": 0},
+	}, bson.D{bson.E{
+		Key:   "$inc",
+		Value: bson.M{"usedCount": -1},
+	}})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.usages.UpdateOne(ctx, bson.M{
+		"_id":   usageKey(promoCodeID, dto.CustomerID),
+		"count": bson.M{"$gt": 0},
+	}, bson.D{bson.E{
+		Key:   "$inc",
+		Value: bson.M{"count": -1},
+	}})
+	return err
+}
+
+func usageKey(promoCodeID primitive.ObjectID, customerID string) bson.D {
+	// Field order matters for _id equality, hence bson.D
+	return bson.D{
+		bson.E{Key: "promoCodeId", Value: promoCodeID},
+		bson.E{Key: "customerId", Value: customerID},
+	}
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type revocationStorage struct {
+	// revocations is keyed by revocation key. Expired documents are removed by TTL index on expiresAt.
+	revocations *mongo.Collection
+}
+
+func NewRevocationStorage(revocations *mongo.Collection) Revocation {
+	return &revocationStorage{revocations: revocations}
+}
+
+func (r revocationStorage) GetActive(ctx context.Context) ([]domain.Revocation, error) {
+	cur, err := r.revocations.Find(ctx, bson.M{
+		"expiresAt": bson.M{"$gt": time.Now().UTC()},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revocations := make([]domain.Revocation, 0)
+	if err := cur.All(ctx, &revocations); err != nil {
+		return nil, err
+	}
+	return revocations, nil
+}
+
+func (r revocationStorage) Save(ctx context.Context, revocation domain.Revocation) error {
+	_, err := r.revocations.ReplaceOne(ctx,
+		bson.M{"_id": revocation.Key},
+		revocation,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
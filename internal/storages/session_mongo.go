@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type sessionStorage struct {
+	// sessions of every role. Expired documents are removed by TTL index on expiresAt.
+	sessions *mongo.Collection
+}
+
+func NewSessionStorage(sessions *mongo.Collection) Session {
+	return &sessionStorage{sessions: sessions}
+}
+
+func (s sessionStorage) GetByRefreshToken(ctx context.Context, role domain.Role, userID, refreshToken string) (domain.Session, error) {
+	query := s.userQuery(role, userID)
+	query = append(query, bson.E{
+		Key:   "refreshToken",
+		Value: refreshToken,
+	})
+
+	result := s.sessions.FindOne(ctx, query)
+	if err := result.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Session{}, domain.ErrSessionNotFound
+		}
+		return domain.Session{}, err
+	}
+
+	var session domain.Session
+	if err := result.Decode(&session); err != nil {
+		return domain.Session{}, err
+	}
+	return session, nil
+}
+
+func (s sessionStorage) GetByUser(ctx context.Context, role domain.Role, userID string) ([]domain.Session, error) {
+	opts := options.Find()
+	opts.SetSort(bson.M{"lastUsedAt": -1})
+
+	cur, err := s.sessions.Find(ctx, s.userQuery(role, userID), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]domain.Session, 0)
+	if err := cur.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (s sessionStorage) Save(ctx context.Context, session domain.Session) error {
+	_, err := s.sessions.InsertOne(ctx, session)
+	return err
+}
+
+func (s sessionStorage) Rotate(ctx context.Context, dto dto.RotateSessionDTO) error {
+	query := bson.M{
+		"_id":          ToObjectID(dto.SessionID),
+		"refreshToken": dto.RefreshToken,
+		"expiresAt":    bson.M{"$gt": time.Now().UTC()},
+	}
+	// Device label is given on login only, so it's kept
+	updateQuery := bson.D{bson.E{
+		Key: "$set",
+		Value: bson.M{
+			"refreshToken": dto.NewRefreshToken,
+			"expiresAt":    dto.ExpiresAt,
+			"lastUsedAt":   time.Now().UTC(),
+			"ip":           dto.Client.IP,
+			"userAgent":    dto.Client.UserAgent,
+		},
+	}}
+
+	result, err := s.sessions.UpdateOne(ctx, query, updateQuery)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (s sessionStorage) Delete(ctx context.Context, role domain.Role, userID, sessionID string) error {
+	query := s.userQuery(role, userID)
+	query = append(query, bson.E{
+		Key:   "_id",
+		Value: ToObjectID(sessionID),
+	})
+
+	result, err := s.sessions.DeleteOne(ctx, query)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (s sessionStorage) DeleteByUser(ctx context.Context, role domain.Role, userID string) error {
+	_, err := s.sessions.DeleteMany(ctx, s.userQuery(role, userID))
+	return err
+}
+
+// userQuery matches sessions of user that have not expired yet. Expired ones are removed by mongo with a delay.
+func (s sessionStorage) userQuery(role domain.Role, userID string) bson.D {
+	return bson.D{bson.E{
+		Key:   "userId",
+		Value: userID,
+	}, bson.E{
+		Key:   "role.role",
+		Value: role.String(),
+	}, bson.E{
+		Key:   "expiresAt",
+		Value: bson.M{"$gt": time.Now().UTC()},
+	}}
+}
@@ -1,10 +1,12 @@
 package storage
 
 import (
+	"context"
 	"strings"
 
 	"github.com/sonyamoonglade/sancho-backend/pkg/database"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 const (
@@ -13,12 +15,31 @@ const (
 	CollectionCategory         = "categories"
 	CollectionCustomers        = "customers"
 	CollectionAdminsAndWorkers = "adminsAndWorkers"
+	CollectionMeta             = "meta"
+	CollectionPromoCodes       = "promoCodes"
+	CollectionPromoCodeUsages  = "promoCodeUsages"
+	CollectionDeliveryZones    = "deliveryZones"
+	CollectionDeliverySlots    = "deliverySlots"
+	CollectionOTPs             = "otps"
+	CollectionSessions         = "sessions"
+	CollectionRevocations      = "revocations"
+	CollectionRolePermissions  = "rolePermissions"
+	CollectionIdempotencyKeys  = "idempotencyKeys"
 )
 
 type Storages struct {
-	Product Product
-	User    User
-	Order   Order
+	Product      Product
+	User         User
+	Order        Order
+	Meta         Meta
+	PromoCode    PromoCode
+	DeliveryZone DeliveryZone
+	DeliverySlot DeliverySlot
+	OTP          OTP
+	Session      Session
+	Revocation   Revocation
+	Permission   Permission
+	Idempotency  Idempotency
 }
 
 func NewStorages(db *database.Mongo) *Storages {
@@ -26,9 +47,36 @@ func NewStorages(db *database.Mongo) *Storages {
 		Product: NewProductStorage(db.Collection(CollectionProduct), db.Collection(CollectionCategory)),
 		User:    NewUserStorage(db.Collection(CollectionCustomers), db.Collection(CollectionAdminsAndWorkers)),
 		Order:   NewOrderStorage(db.Collection(CollectionOrders)),
+		Meta:    NewMetaStorage(db.Collection(CollectionMeta)),
+		PromoCode: NewPromoCodeStorage(
+			db.Collection(CollectionPromoCodes),
+			db.Collection(CollectionPromoCodeUsages),
+		),
+		DeliveryZone: NewDeliveryZoneStorage(db.Collection(CollectionDeliveryZones)),
+		DeliverySlot: NewDeliverySlotStorage(db.Collection(CollectionDeliverySlots)),
+		OTP:          NewOTPStorage(db.Collection(CollectionOTPs)),
+		Session:      NewSessionStorage(db.Collection(CollectionSessions)),
+		Revocation:   NewRevocationStorage(db.Collection(CollectionRevocations)),
+		Permission:   NewPermissionStorage(db.Collection(CollectionRolePermissions)),
+		Idempotency:  NewIdempotencyStorage(db.Collection(CollectionIdempotencyKeys)),
 	}
 }
 
+// withTransaction runs fn in a transaction, retrying it on transient errors.
+// Transactions require replica set, standalone mongod does not support them.
+func withTransaction(ctx context.Context, client *mongo.Client, fn func(ctx mongo.SessionContext) error) error {
+	session, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
 func ToObjectID(s string) primitive.ObjectID {
 	id, _ := primitive.ObjectIDFromHex(s)
 	return id
@@ -3,10 +3,8 @@ package storage
 import (
 	"context"
 	"errors"
-	"time"
 
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
-	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -26,23 +24,10 @@ func NewUserStorage(customers *mongo.Collection, adminsAndWorkers *mongo.Collect
 }
 
 func (u userStorage) GetAdminByLogin(ctx context.Context, login string) (domain.Admin, error) {
-	//TODO implement me
-	panic("implement me")
-}
-
-func (u userStorage) GetAdminByRefreshToken(ctx context.Context, adminID, token string) (domain.Admin, error) {
-	query := bson.D{bson.E{
-		Key:   "_id",
-		Value: adminID,
-	}, bson.E{
-		Key:   "session.refreshToken",
-		Value: token,
-	}, bson.E{
-		Key:   "session.expiresAt",
-		Value: bson.M{"$gt": time.Now().UTC()},
-	}}
-
-	result := u.adminsAndWorkers.FindOne(ctx, query)
+	result := u.adminsAndWorkers.FindOne(ctx, bson.M{
+		"login":     login,
+		"role.role": domain.RoleAdmin.String(),
+	})
 	if err := result.Err(); err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return domain.Admin{}, domain.ErrAdminNotFound
@@ -58,6 +43,42 @@ func (u userStorage) GetAdminByRefreshToken(ctx context.Context, adminID, token
 	return admin, nil
 }
 
+func (u userStorage) GetWorkerByLogin(ctx context.Context, login string) (domain.Worker, error) {
+	result := u.adminsAndWorkers.FindOne(ctx, bson.M{
+		"login":     login,
+		"role.role": domain.RoleWorker.String(),
+	})
+	if err := result.Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Worker{}, domain.ErrWorkerNotFound
+		}
+		return domain.Worker{}, err
+	}
+
+	var worker domain.Worker
+	if err := result.Decode(&worker); err != nil {
+		return domain.Worker{}, err
+	}
+
+	return worker, nil
+}
+
+func (u userStorage) GetWorkers(ctx context.Context) ([]domain.Worker, error) {
+	opts := options.Find()
+	opts.SetSort(bson.M{"name": 1})
+
+	cur, err := u.adminsAndWorkers.Find(ctx, bson.M{"role.role": domain.RoleWorker.String()}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := make([]domain.Worker, 0)
+	if err := cur.All(ctx, &workers); err != nil {
+		return nil, err
+	}
+	return workers, nil
+}
+
 func (u userStorage) GetCustomerByPhoneNumber(ctx context.Context, phoneNumber string) (domain.Customer, error) {
 	result := u.customers.FindOne(ctx, bson.M{"phoneNumber": phoneNumber})
 	if err := result.Err(); err != nil {
@@ -98,33 +119,63 @@ func (u userStorage) SaveCustomer(ctx context.Context, customer domain.Customer)
 }
 
 func (u userStorage) SaveWorker(ctx context.Context, worker domain.Worker) (primitive.ObjectID, error) {
-	//TODO implement me
-	panic("implement me")
+	res, err := u.adminsAndWorkers.InsertOne(ctx, worker)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return primitive.ObjectID{}, domain.ErrWorkerAlreadyExists
+		}
+		return primitive.ObjectID{}, err
+	}
+	return res.InsertedID.(primitive.ObjectID), nil
 }
 
-func (u userStorage) SaveSession(ctx context.Context, dto dto.SaveSessionDTO) error {
-
-	var collection = u.customers
-	if dto.Role == domain.RoleAdmin || dto.Role == domain.RoleWorker {
-		collection = u.adminsAndWorkers
-	}
+func (u userStorage) DisableWorker(ctx context.Context, workerID string) error {
+	return u.updateWorker(ctx, workerID, bson.M{"isDisabled": true})
+}
 
-	opts := options.Update()
-	opts.SetUpsert(true)
+func (u userStorage) UpdateWorkerPassword(ctx context.Context, workerID, password string) error {
+	return u.updateWorker(ctx, workerID, bson.M{"password": password})
+}
 
+func (u userStorage) UpdatePasswordHash(ctx context.Context, userID string, role domain.Role, passwordHash string) error {
 	updateQuery := bson.D{bson.E{
 		Key:   "$set",
-		Value: dto.Session,
+		Value: bson.M{"password": passwordHash},
 	}}
 
-	result, err := collection.UpdateOne(ctx, bson.M{"_id": ToObjectID(dto.UserID)}, updateQuery, opts)
+	result, err := u.adminsAndWorkers.UpdateOne(ctx, u.userQuery(userID, role), updateQuery)
 	if err != nil {
 		return err
 	}
-
 	if result.MatchedCount == 0 {
 		return domain.ErrUserNotFound
 	}
+	return nil
+}
 
+func (u userStorage) updateWorker(ctx context.Context, workerID string, setQuery bson.M) error {
+	updateQuery := bson.D{bson.E{
+		Key:   "$set",
+		Value: setQuery,
+	}}
+
+	result, err := u.adminsAndWorkers.UpdateOne(ctx, u.userQuery(workerID, domain.RoleWorker), updateQuery)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrWorkerNotFound
+	}
 	return nil
 }
+
+// userQuery matches user by id and role, so that admin and worker sharing the collection can't be mixed up
+func (u userStorage) userQuery(userID string, role domain.Role) bson.D {
+	return bson.D{bson.E{
+		Key:   "_id",
+		Value: ToObjectID(userID),
+	}, bson.E{
+		Key:   "role.role",
+		Value: role.String(),
+	}}
+}
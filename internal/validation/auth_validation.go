@@ -0,0 +1,43 @@
+package validation
+
+import (
+	"regexp"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+)
+
+const (
+	invalidPhoneNumber = "phoneNumber should be 10 to 15 digits with optional leading +"
+	invalidOTPCode     = "code should be 6 digits"
+)
+
+var phoneNumberRegexp = regexp.MustCompile(`^\+?[0-9]{10,15}$`)
+
+func ValidateRequestOTPInput(r input.RequestOTPInput) (ok bool, msg string) {
+	if ok, msg := ValidateStruct(r); !ok {
+		return false, msg
+	}
+	if !phoneNumberRegexp.MatchString(r.PhoneNumber) {
+		return false, invalidPhoneNumber
+	}
+	return true, ""
+}
+
+func ValidateLoginCustomerInput(l input.LoginCustomerInput) (ok bool, msg string) {
+	if ok, msg := ValidateStruct(l); !ok {
+		return false, msg
+	}
+	if !phoneNumberRegexp.MatchString(l.PhoneNumber) {
+		return false, invalidPhoneNumber
+	}
+	if len(l.Code) != domain.OTPCodeLength {
+		return false, invalidOTPCode
+	}
+	for _, ch := range l.Code {
+		if ch < '0' || ch > '9' {
+			return false, invalidOTPCode
+		}
+	}
+	return true, ""
+}
@@ -0,0 +1,40 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRequestOTPInput(t *testing.T) {
+	t.Run("should return ok", func(t *testing.T) {
+		ok, msg := ValidateRequestOTPInput(input.RequestOTPInput{PhoneNumber: "+79128557826"})
+		require.True(t, ok)
+		require.Zero(t, msg)
+	})
+
+	t.Run("should return invalidPhoneNumber", func(t *testing.T) {
+		for _, phoneNumber := range []string{"+7912", "8 912 855 78 26", "+7912855782a"} {
+			ok, msg := ValidateRequestOTPInput(input.RequestOTPInput{PhoneNumber: phoneNumber})
+			require.False(t, ok)
+			require.Equal(t, invalidPhoneNumber, msg)
+		}
+	})
+}
+
+func TestValidateLoginCustomerInput(t *testing.T) {
+	t.Run("should return ok", func(t *testing.T) {
+		ok, msg := ValidateLoginCustomerInput(input.LoginCustomerInput{PhoneNumber: "+79128557826", Code: "012345"})
+		require.True(t, ok)
+		require.Zero(t, msg)
+	})
+
+	t.Run("should return invalidOTPCode", func(t *testing.T) {
+		for _, code := range []string{"12345", "1234567", "12345a"} {
+			ok, msg := ValidateLoginCustomerInput(input.LoginCustomerInput{PhoneNumber: "+79128557826", Code: code})
+			require.False(t, ok)
+			require.Equal(t, invalidOTPCode, msg)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package validation
+
+import (
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+)
+
+const (
+	invalidSlotsDate = "date should be YYYY-MM-DD"
+)
+
+func ValidateDeliverySlotsInput(d input.DeliverySlotsInput) (ok bool, msg string) {
+	if ok, msg := ValidateStruct(d); !ok {
+		return false, msg
+	}
+	if _, err := time.Parse(domain.DateLayout, d.Date); err != nil {
+		return false, invalidSlotsDate
+	}
+	return true, ""
+}
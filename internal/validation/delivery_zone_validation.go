@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+)
+
+const (
+	invalidAreaType              = "area should be a GeoJSON Polygon"
+	invalidAreaRing              = "area ring should be closed and have at least 4 points"
+	invalidAreaPoint             = "area point should be [longitude, latitude]"
+	invalidDeliveryFee           = "fee can not be negative"
+	invalidFreeDeliveryThreshold = "freeDeliveryThreshold can not be negative"
+	invalidMinOrderAmount        = "minOrderAmount can not be negative"
+	invalidETAMinutes            = "etaMinutes should be positive"
+)
+
+func ValidateDeliveryZoneInput(d input.DeliveryZoneInput) (ok bool, msg string) {
+	if ok, msg := ValidateStruct(d); !ok {
+		return false, msg
+	}
+	if ok, msg := validateGeoPolygon(d.Area); !ok {
+		return false, msg
+	}
+	if d.Fee < 0 {
+		return false, invalidDeliveryFee
+	}
+	if d.FreeDeliveryThreshold < 0 {
+		return false, invalidFreeDeliveryThreshold
+	}
+	if d.MinOrderAmount < 0 {
+		return false, invalidMinOrderAmount
+	}
+	if d.ETAMinutes <= 0 {
+		return false, invalidETAMinutes
+	}
+	return true, ""
+}
+
+// validateGeoPolygon checks the shape of polygon. Self-intersections are detected by mongo on write.
+func validateGeoPolygon(p domain.GeoPolygon) (ok bool, msg string) {
+	if p.Type != domain.GeoPolygonType || len(p.Coordinates) == 0 {
+		return false, invalidAreaType
+	}
+	for _, ring := range p.Coordinates {
+		if len(ring) < 4 {
+			return false, invalidAreaRing
+		}
+		for _, point := range ring {
+			if len(point) != 2 {
+				return false, invalidAreaPoint
+			}
+			longitude, latitude := point[0], point[1]
+			if longitude < -180 || longitude > 180 || latitude < -90 || latitude > 90 {
+				return false, invalidAreaPoint
+			}
+		}
+		first, last := ring[0], ring[len(ring)-1]
+		if first[0] != last[0] || first[1] != last[1] {
+			return false, invalidAreaRing
+		}
+	}
+	return true, ""
+}
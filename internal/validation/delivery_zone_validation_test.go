@@ -0,0 +1,76 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDeliveryZoneInput(t *testing.T) {
+	getInput := func() input.DeliveryZoneInput {
+		return input.DeliveryZoneInput{
+			Name: "center",
+			Area: domain.NewGeoPolygon([][]float64{
+				{60.55, 56.80},
+				{60.70, 56.80},
+				{60.70, 56.90},
+				{60.55, 56.90},
+				{60.55, 56.80},
+			}),
+			Fee:                   150,
+			FreeDeliveryThreshold: 2000,
+			MinOrderAmount:        500,
+			ETAMinutes:            40,
+		}
+	}
+
+	t.Run("should return ok", func(t *testing.T) {
+		ok, msg := ValidateDeliveryZoneInput(getInput())
+		require.True(t, ok)
+		require.Zero(t, msg)
+	})
+
+	t.Run("should return invalidAreaType", func(t *testing.T) {
+		inp := getInput()
+		inp.Area.Type = "Point"
+		ok, msg := ValidateDeliveryZoneInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidAreaType, msg)
+	})
+
+	t.Run("should return invalidAreaRing because ring is not closed", func(t *testing.T) {
+		inp := getInput()
+		ring := inp.Area.Coordinates[0]
+		inp.Area.Coordinates[0] = ring[:len(ring)-1]
+		ok, msg := ValidateDeliveryZoneInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidAreaRing, msg)
+	})
+
+	t.Run("should return invalidAreaPoint", func(t *testing.T) {
+		inp := getInput()
+		// Latitude and longitude are swapped
+		inp.Area.Coordinates[0][1] = []float64{56.80, 160.70}
+		ok, msg := ValidateDeliveryZoneInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidAreaPoint, msg)
+	})
+
+	t.Run("should return invalidDeliveryFee", func(t *testing.T) {
+		inp := getInput()
+		inp.Fee = -1
+		ok, msg := ValidateDeliveryZoneInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidDeliveryFee, msg)
+	})
+
+	t.Run("should return invalidETAMinutes", func(t *testing.T) {
+		inp := getInput()
+		inp.ETAMinutes = -10
+		ok, msg := ValidateDeliveryZoneInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidETAMinutes, msg)
+	})
+}
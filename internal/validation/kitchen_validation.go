@@ -0,0 +1,28 @@
+package validation
+
+import "github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+
+const (
+	invalidKitchenMessageType = "invalid message type"
+	missingCartLine           = "line is required for item_ready"
+	invalidCartLine           = "line can not be negative"
+)
+
+func ValidateKitchenMessageInput(m input.KitchenMessageInput) (ok bool, msg string) {
+	if ok, msg := ValidateStruct(m); !ok {
+		return false, msg
+	}
+	switch m.Type {
+	case input.KitchenMessageItemReady:
+		if m.Line == nil {
+			return false, missingCartLine
+		}
+		if *m.Line < 0 {
+			return false, invalidCartLine
+		}
+	case input.KitchenMessageOrderReady:
+	default:
+		return false, invalidKitchenMessageType
+	}
+	return true, ""
+}
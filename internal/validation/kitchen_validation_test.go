@@ -0,0 +1,67 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateKitchenMessageInput(t *testing.T) {
+	line := func(v int) *int { return &v }
+
+	t.Run("should return ok for item_ready", func(t *testing.T) {
+		ok, msg := ValidateKitchenMessageInput(input.KitchenMessageInput{
+			Type:    input.KitchenMessageItemReady,
+			OrderID: "63c7bd6fbd4fa6b5ba0e5f1a",
+			Line:    line(0),
+		})
+		require.True(t, ok)
+		require.Zero(t, msg)
+	})
+
+	t.Run("should return ok for order_ready without line", func(t *testing.T) {
+		ok, msg := ValidateKitchenMessageInput(input.KitchenMessageInput{
+			Type:    input.KitchenMessageOrderReady,
+			OrderID: "63c7bd6fbd4fa6b5ba0e5f1a",
+		})
+		require.True(t, ok)
+		require.Zero(t, msg)
+	})
+
+	t.Run("should return err because item_ready has no line", func(t *testing.T) {
+		ok, msg := ValidateKitchenMessageInput(input.KitchenMessageInput{
+			Type:    input.KitchenMessageItemReady,
+			OrderID: "63c7bd6fbd4fa6b5ba0e5f1a",
+		})
+		require.False(t, ok)
+		require.Equal(t, missingCartLine, msg)
+	})
+
+	t.Run("should return err because line is negative", func(t *testing.T) {
+		ok, msg := ValidateKitchenMessageInput(input.KitchenMessageInput{
+			Type:    input.KitchenMessageItemReady,
+			OrderID: "63c7bd6fbd4fa6b5ba0e5f1a",
+			Line:    line(-1),
+		})
+		require.False(t, ok)
+		require.Equal(t, invalidCartLine, msg)
+	})
+
+	t.Run("should return err because type is unknown", func(t *testing.T) {
+		ok, msg := ValidateKitchenMessageInput(input.KitchenMessageInput{
+			Type:    "order_burnt",
+			OrderID: "63c7bd6fbd4fa6b5ba0e5f1a",
+		})
+		require.False(t, ok)
+		require.Equal(t, invalidKitchenMessageType, msg)
+	})
+
+	t.Run("should return err because order id is missing", func(t *testing.T) {
+		ok, msg := ValidateKitchenMessageInput(input.KitchenMessageInput{
+			Type: input.KitchenMessageOrderReady,
+		})
+		require.False(t, ok)
+		require.NotZero(t, msg)
+	})
+}
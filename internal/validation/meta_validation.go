@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+)
+
+const (
+	invalidTimezone       = "invalid timezone"
+	invalidWeekday        = "weekday should be from 0 (Sunday) to 6 (Saturday)"
+	duplicateWeekday      = "weekday has working hours more than once"
+	invalidWorkingHours   = "working hours should be HH:MM and open should differ from close"
+	invalidOverrideDate   = "override date should be YYYY-MM-DD"
+	duplicateOverrideDate = "date is overridden more than once"
+	ambiguousOverride     = "closed day can not have working hours"
+)
+
+func ValidateUpdateScheduleInput(u input.UpdateScheduleInput) (ok bool, msg string) {
+	if ok, msg := ValidateStruct(u); !ok {
+		return false, msg
+	}
+	if _, err := time.LoadLocation(u.Timezone); err != nil {
+		return false, invalidTimezone
+	}
+
+	weekdays := make(map[time.Weekday]struct{}, len(u.Weekly))
+	for _, hours := range u.Weekly {
+		if hours.Weekday < time.Sunday || hours.Weekday > time.Saturday {
+			return false, invalidWeekday
+		}
+		if _, ok := weekdays[hours.Weekday]; ok {
+			return false, duplicateWeekday
+		}
+		weekdays[hours.Weekday] = struct{}{}
+		if !isValidWorkingHours(hours.Open, hours.Close) {
+			return false, invalidWorkingHours
+		}
+	}
+
+	dates := make(map[string]struct{}, len(u.Overrides))
+	for _, override := range u.Overrides {
+		if _, err := time.Parse(domain.DateLayout, override.Date); err != nil {
+			return false, invalidOverrideDate
+		}
+		if _, ok := dates[override.Date]; ok {
+			return false, duplicateOverrideDate
+		}
+		dates[override.Date] = struct{}{}
+		if override.IsClosed {
+			if override.Open != "" || override.Close != "" {
+				return false, ambiguousOverride
+			}
+			continue
+		}
+		if !isValidWorkingHours(override.Open, override.Close) {
+			return false, invalidWorkingHours
+		}
+	}
+	return true, ""
+}
+
+// isValidWorkingHours checks clock times. Close before open means store closes on the next day.
+func isValidWorkingHours(openClock, closeClock string) bool {
+	openMinutes, ok := domain.ParseClock(openClock)
+	if !ok {
+		return false
+	}
+	closeMinutes, ok := domain.ParseClock(closeClock)
+	if !ok {
+		return false
+	}
+	// "24:00" is only valid as closing time
+	if openMinutes == 24*60 {
+		return false
+	}
+	return openMinutes != closeMinutes
+}
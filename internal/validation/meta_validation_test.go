@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"testing"
+	"time"
+	// Validation loads timezones, so tests don't depend on zoneinfo of the machine
+	_ "time/tzdata"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateUpdateScheduleInput(t *testing.T) {
+	getInput := func() input.UpdateScheduleInput {
+		return input.UpdateScheduleInput{
+			Timezone: "Asia/Yekaterinburg",
+			Weekly: []domain.WorkingHours{
+				{Weekday: time.Monday, Open: "10:00", Close: "23:00"},
+				{Weekday: time.Saturday, Open: "12:00", Close: "24:00"},
+			},
+			Overrides: []domain.ScheduleOverride{
+				{Date: "2023-02-23", IsClosed: true},
+				{Date: "2023-03-07", Open: "10:00", Close: "18:00"},
+			},
+		}
+	}
+
+	t.Run("should return ok", func(t *testing.T) {
+		ok, msg := ValidateUpdateScheduleInput(getInput())
+		require.True(t, ok)
+		require.Zero(t, msg)
+	})
+
+	t.Run("should return invalidTimezone", func(t *testing.T) {
+		inp := getInput()
+		inp.Timezone = "Mars/Olympus"
+		ok, msg := ValidateUpdateScheduleInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidTimezone, msg)
+	})
+
+	t.Run("should return duplicateWeekday", func(t *testing.T) {
+		inp := getInput()
+		inp.Weekly = append(inp.Weekly, domain.WorkingHours{Weekday: time.Monday, Open: "08:00", Close: "09:00"})
+		ok, msg := ValidateUpdateScheduleInput(inp)
+		require.False(t, ok)
+		require.Equal(t, duplicateWeekday, msg)
+	})
+
+	t.Run("should return ok because hours span past midnight", func(t *testing.T) {
+		inp := getInput()
+		inp.Weekly[0].Close = "02:00"
+		ok, msg := ValidateUpdateScheduleInput(inp)
+		require.True(t, ok)
+		require.Zero(t, msg)
+	})
+
+	t.Run("should return invalidWorkingHours because store opens and closes at the same time", func(t *testing.T) {
+		inp := getInput()
+		inp.Weekly[0].Close = inp.Weekly[0].Open
+		ok, msg := ValidateUpdateScheduleInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidWorkingHours, msg)
+	})
+
+	t.Run("should return invalidWorkingHours because store opens at the end of day", func(t *testing.T) {
+		inp := getInput()
+		inp.Weekly[0].Open, inp.Weekly[0].Close = "24:00", "02:00"
+		ok, msg := ValidateUpdateScheduleInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidWorkingHours, msg)
+	})
+
+	t.Run("should return invalidOverrideDate", func(t *testing.T) {
+		inp := getInput()
+		inp.Overrides[0].Date = "23.02.2023"
+		ok, msg := ValidateUpdateScheduleInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidOverrideDate, msg)
+	})
+
+	t.Run("should return ambiguousOverride", func(t *testing.T) {
+		inp := getInput()
+		inp.Overrides[0].Open = "10:00"
+		ok, msg := ValidateUpdateScheduleInput(inp)
+		require.False(t, ok)
+		require.Equal(t, ambiguousOverride, msg)
+	})
+}
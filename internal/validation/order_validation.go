@@ -1,13 +1,22 @@
 package validation
 
 import (
+	"time"
+
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/sonyamoonglade/sancho-backend/pkg/cursor"
 )
 
 const (
-	invalidPayMethod = "invalid pay method"
-	emptyCart        = "cart is empty"
+	invalidPayMethod   = "invalid pay method"
+	invalidOrderStatus = "invalid order status"
+	invalidIsDelivered = "isDelivered should be either true or false"
+	invalidCreatedFrom = "createdFrom should be RFC3339 timestamp"
+	invalidCreatedTo   = "createdTo should be RFC3339 timestamp"
+	invalidLimit       = "limit can not be negative"
+	invalidCursor      = "invalid cursor"
+	emptyCart          = "cart is empty"
 )
 
 func ValidatePayMethod(p domain.Pay) (ok bool, msg string) {
@@ -18,6 +27,64 @@ func ValidatePayMethod(p domain.Pay) (ok bool, msg string) {
 	return true, ""
 }
 
+func ValidateOrderStatus(s domain.OrderStatus) (ok bool, msg string) {
+	switch s {
+	case domain.StatusWaitingForVerification,
+		domain.StatusVerified,
+		domain.StatusCompleted,
+		domain.StatusCancelled:
+		return true, ""
+	}
+	return false, invalidOrderStatus
+}
+
+func ValidateListOrdersInput(l input.ListOrdersInput) (ok bool, msg string) {
+	if l.Status != "" {
+		if ok, msg := ValidateOrderStatus(domain.OrderStatus{V: l.Status}); !ok {
+			return false, msg
+		}
+	}
+	if l.Pay != "" {
+		if ok, msg := ValidatePayMethod(domain.Pay{P: l.Pay}); !ok {
+			return false, msg
+		}
+	}
+	if l.IsDelivered != "" && l.IsDelivered != "true" && l.IsDelivered != "false" {
+		return false, invalidIsDelivered
+	}
+	if l.CreatedFrom != "" {
+		if _, err := time.Parse(time.RFC3339, l.CreatedFrom); err != nil {
+			return false, invalidCreatedFrom
+		}
+	}
+	if l.CreatedTo != "" {
+		if _, err := time.Parse(time.RFC3339, l.CreatedTo); err != nil {
+			return false, invalidCreatedTo
+		}
+	}
+	if l.Limit < 0 {
+		return false, invalidLimit
+	}
+	if l.Cursor != "" {
+		if _, err := cursor.Decode(l.Cursor); err != nil {
+			return false, invalidCursor
+		}
+	}
+	return true, ""
+}
+
+func ValidateCustomerOrdersInput(l input.CustomerOrdersInput) (ok bool, msg string) {
+	if l.Limit < 0 {
+		return false, invalidLimit
+	}
+	if l.Cursor != "" {
+		if _, err := cursor.Decode(l.Cursor); err != nil {
+			return false, invalidCursor
+		}
+	}
+	return true, ""
+}
+
 func ValidateCart(cart []input.CartProductInput) (ok bool, msg string) {
 	if len(cart) == 0 {
 		return false, emptyCart
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,3 +31,44 @@ func TestValidatePayMethod(t *testing.T) {
 		require.Equal(t, invalidPayMethod, msg)
 	})
 }
+
+func TestValidateListOrdersInput(t *testing.T) {
+	t.Run("should return ok because input is empty", func(t *testing.T) {
+		ok, msg := ValidateListOrdersInput(input.ListOrdersInput{})
+		require.True(t, ok)
+		require.Zero(t, msg)
+	})
+
+	t.Run("should return ok because all filters are valid", func(t *testing.T) {
+		ok, msg := ValidateListOrdersInput(input.ListOrdersInput{
+			Status:      domain.StatusWaitingForVerification.String(),
+			Pay:         domain.PayOnline.String(),
+			IsDelivered: "false",
+			CreatedFrom: "2023-01-10T10:00:00Z",
+			CreatedTo:   "2023-01-11T10:00:00+03:00",
+			Limit:       50,
+		})
+		require.True(t, ok)
+		require.Zero(t, msg)
+	})
+
+	t.Run("should return err because of invalid fields", func(t *testing.T) {
+		tests := []struct {
+			inp input.ListOrdersInput
+			msg string
+		}{
+			{input.ListOrdersInput{Status: "cooking"}, invalidOrderStatus},
+			{input.ListOrdersInput{Pay: "by crypto"}, invalidPayMethod},
+			{input.ListOrdersInput{IsDelivered: "yes"}, invalidIsDelivered},
+			{input.ListOrdersInput{CreatedFrom: "10.01.2023"}, invalidCreatedFrom},
+			{input.ListOrdersInput{CreatedTo: "yesterday"}, invalidCreatedTo},
+			{input.ListOrdersInput{Limit: -1}, invalidLimit},
+			{input.ListOrdersInput{Cursor: "abcd"}, invalidCursor},
+		}
+		for _, test := range tests {
+			ok, msg := ValidateListOrdersInput(test.inp)
+			require.False(t, ok)
+			require.Equal(t, test.msg, msg)
+		}
+	})
+}
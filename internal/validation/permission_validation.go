@@ -0,0 +1,19 @@
+package validation
+
+import (
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+)
+
+const invalidPermission = "unknown permission: "
+
+func ValidateUpdateRolePermissionsInput(u input.UpdateRolePermissionsInput) (ok bool, msg string) {
+	if ok, msg := ValidateStruct(u); !ok {
+		return false, msg
+	}
+	for _, permission := range u.Permissions {
+		if !permission.IsKnown() {
+			return false, invalidPermission + string(permission)
+		}
+	}
+	return true, ""
+}
@@ -0,0 +1,32 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateUpdateRolePermissionsInput(t *testing.T) {
+	t.Run("should return ok", func(t *testing.T) {
+		for _, permissions := range [][]domain.Permission{{}, {domain.PermissionOrdersView, domain.PermissionMetaEdit}} {
+			ok, msg := ValidateUpdateRolePermissionsInput(input.UpdateRolePermissionsInput{Permissions: permissions})
+			require.True(t, ok)
+			require.Zero(t, msg)
+		}
+	})
+
+	t.Run("should return invalidPermission", func(t *testing.T) {
+		ok, msg := ValidateUpdateRolePermissionsInput(input.UpdateRolePermissionsInput{
+			Permissions: []domain.Permission{domain.PermissionOrdersView, "orders.delete"},
+		})
+		require.False(t, ok)
+		require.Equal(t, invalidPermission+"orders.delete", msg)
+	})
+
+	t.Run("should fail because permissions are missing", func(t *testing.T) {
+		ok, _ := ValidateUpdateRolePermissionsInput(input.UpdateRolePermissionsInput{})
+		require.False(t, ok)
+	})
+}
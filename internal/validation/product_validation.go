@@ -1,15 +1,29 @@
 package validation
 
 import (
+	"strings"
+	"unicode/utf8"
+
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
 	LiquidEmptyVolume         = "isLiquid but volume is 0"
 	InsufficientEnergy        = "energy value can not be 0"
 	InvalidNutrientsForLiquid = "liquid can not have nutrients"
+	EmptyCategoryName         = "category name is empty"
+	CategoryNameTooLong       = "category name is too long"
+	InvalidCategoryID         = "invalid category id: "
+	InvalidOptionID           = "invalid option id: "
+	DuplicateOptionID         = "option id is used more than once: "
+	DuplicateOptionGroupName  = "option group names should be unique"
+	InvalidComboProductID     = "invalid combo product id: "
 )
 
+const maxCategoryNameLength = 64
+
 func ValidateFeatures(f domain.Features) (ok bool, msg string) {
 	if f.IsLiquid && f.Volume == 0 {
 		return false, LiquidEmptyVolume
@@ -22,3 +36,86 @@ func ValidateFeatures(f domain.Features) (ok bool, msg string) {
 	}
 	return true, ""
 }
+
+func ValidateCategoryInput(c input.CategoryInput) (ok bool, msg string) {
+	if ok, msg := ValidateStruct(c); !ok {
+		return false, msg
+	}
+	name := strings.TrimSpace(c.Name)
+	if name == "" {
+		return false, EmptyCategoryName
+	}
+	if utf8.RuneCountInString(name) > maxCategoryNameLength {
+		return false, CategoryNameTooLong
+	}
+	return true, ""
+}
+
+func ValidateReorderCategoriesInput(r input.ReorderCategoriesInput) (ok bool, msg string) {
+	if ok, msg := ValidateStruct(r); !ok {
+		return false, msg
+	}
+	for _, categoryID := range r.CategoryIDs {
+		if !primitive.IsValidObjectID(categoryID) {
+			return false, InvalidCategoryID + categoryID
+		}
+	}
+	return true, ""
+}
+
+func ValidateOptionGroups(groups []input.OptionGroupInput) (ok bool, msg string) {
+	var (
+		ids   = make(map[string]struct{})
+		names = make(map[string]struct{}, len(groups))
+	)
+	// Option ids should be unique across groups, otherwise one chosen id would pick several options
+	checkID := func(id string) (bool, string) {
+		if id == "" {
+			return true, ""
+		}
+		if !primitive.IsValidObjectID(id) {
+			return false, InvalidOptionID + id
+		}
+		if _, ok := ids[id]; ok {
+			return false, DuplicateOptionID + id
+		}
+		ids[id] = struct{}{}
+		return true, ""
+	}
+	for _, group := range groups {
+		if ok, msg := checkID(group.GroupID); !ok {
+			return false, msg
+		}
+		for _, option := range group.Options {
+			if ok, msg := checkID(option.OptionID); !ok {
+				return false, msg
+			}
+		}
+		optionGroup := group.ToDomain()
+		if ok, msg := optionGroup.IsValid(); !ok {
+			return false, msg
+		}
+		if _, ok := names[optionGroup.Name]; ok {
+			return false, DuplicateOptionGroupName
+		}
+		names[optionGroup.Name] = struct{}{}
+	}
+	return true, ""
+}
+
+func ValidateCombo(c *input.ComboInput) (ok bool, msg string) {
+	if c == nil {
+		return true, ""
+	}
+	for _, component := range c.Components {
+		if !primitive.IsValidObjectID(component.ProductID) {
+			return false, InvalidComboProductID + component.ProductID
+		}
+		for _, swap := range component.Swaps {
+			if !primitive.IsValidObjectID(swap.ProductID) {
+				return false, InvalidComboProductID + swap.ProductID
+			}
+		}
+	}
+	return c.ToDomain().IsValid()
+}
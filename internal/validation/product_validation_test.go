@@ -0,0 +1,159 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestValidateCategoryInput(t *testing.T) {
+	t.Run("should return ok", func(t *testing.T) {
+		for _, name := range []string{"pizza", " Пицца ", strings.Repeat("я", maxCategoryNameLength)} {
+			ok, msg := ValidateCategoryInput(input.CategoryInput{Name: name})
+			require.True(t, ok)
+			require.Zero(t, msg)
+		}
+	})
+
+	t.Run("should return EmptyCategoryName", func(t *testing.T) {
+		ok, msg := ValidateCategoryInput(input.CategoryInput{Name: "  "})
+		require.False(t, ok)
+		require.Equal(t, EmptyCategoryName, msg)
+	})
+
+	t.Run("should return CategoryNameTooLong", func(t *testing.T) {
+		ok, msg := ValidateCategoryInput(input.CategoryInput{Name: strings.Repeat("a", maxCategoryNameLength+1)})
+		require.False(t, ok)
+		require.Equal(t, CategoryNameTooLong, msg)
+	})
+
+	t.Run("should fail because name is missing", func(t *testing.T) {
+		ok, _ := ValidateCategoryInput(input.CategoryInput{})
+		require.False(t, ok)
+	})
+}
+
+func TestValidateReorderCategoriesInput(t *testing.T) {
+	t.Run("should return ok", func(t *testing.T) {
+		ok, msg := ValidateReorderCategoriesInput(input.ReorderCategoriesInput{
+			CategoryIDs: []string{"63e5e6f2a8a9b8b2d1c4e5f6", "63e5e6f2a8a9b8b2d1c4e5f7"},
+		})
+		require.True(t, ok)
+		require.Zero(t, msg)
+	})
+
+	t.Run("should return InvalidCategoryID", func(t *testing.T) {
+		ok, msg := ValidateReorderCategoriesInput(input.ReorderCategoriesInput{
+			CategoryIDs: []string{"63e5e6f2a8a9b8b2d1c4e5f6", "pizza"},
+		})
+		require.False(t, ok)
+		require.Equal(t, InvalidCategoryID+"pizza", msg)
+	})
+
+	t.Run("should fail because categoryIds are missing", func(t *testing.T) {
+		ok, _ := ValidateReorderCategoriesInput(input.ReorderCategoriesInput{})
+		require.False(t, ok)
+	})
+}
+
+func TestValidateOptionGroups(t *testing.T) {
+	var (
+		groupID  = primitive.NewObjectID().Hex()
+		optionID = primitive.NewObjectID().Hex()
+	)
+	valid := func() []input.OptionGroupInput {
+		return []input.OptionGroupInput{
+			{
+				GroupID:    groupID,
+				Name:       "size",
+				MinChoices: 1,
+				Options:    []input.OptionInput{{OptionID: optionID, Name: "small"}, {Name: "large", PriceDelta: 200}},
+			},
+			{
+				Name:       "toppings",
+				Multiple:   true,
+				MaxChoices: 2,
+				Options:    []input.OptionInput{{Name: "cheese", PriceDelta: 50}, {Name: "bacon", PriceDelta: 70}},
+			},
+		}
+	}
+
+	t.Run("should return ok", func(t *testing.T) {
+		ok, msg := ValidateOptionGroups(valid())
+		require.True(t, ok)
+		require.Zero(t, msg)
+
+		ok, _ = ValidateOptionGroups(nil)
+		require.True(t, ok)
+	})
+
+	t.Run("should return InvalidOptionID", func(t *testing.T) {
+		groups := valid()
+		groups[1].Options[0].OptionID = "cheese"
+		ok, msg := ValidateOptionGroups(groups)
+		require.False(t, ok)
+		require.Equal(t, InvalidOptionID+"cheese", msg)
+	})
+
+	t.Run("should return DuplicateOptionID", func(t *testing.T) {
+		groups := valid()
+		groups[1].Options[0].OptionID = optionID
+		ok, msg := ValidateOptionGroups(groups)
+		require.False(t, ok)
+		require.Equal(t, DuplicateOptionID+optionID, msg)
+	})
+
+	t.Run("should return DuplicateOptionGroupName", func(t *testing.T) {
+		groups := valid()
+		groups[1].Name = " size "
+		ok, msg := ValidateOptionGroups(groups)
+		require.False(t, ok)
+		require.Equal(t, DuplicateOptionGroupName, msg)
+	})
+
+	t.Run("should fail because single select group allows several choices", func(t *testing.T) {
+		groups := valid()
+		groups[0].MaxChoices = 2
+		ok, _ := ValidateOptionGroups(groups)
+		require.False(t, ok)
+	})
+}
+
+func TestValidateCombo(t *testing.T) {
+	valid := func() *input.ComboInput {
+		return &input.ComboInput{Components: []input.ComboComponentInput{
+			{ProductID: primitive.NewObjectID().Hex(), Quantity: 1},
+			{
+				ProductID: primitive.NewObjectID().Hex(),
+				Quantity:  1,
+				Swaps:     []input.ComboSwapInput{{ProductID: primitive.NewObjectID().Hex(), PriceDelta: 30}},
+			},
+		}}
+	}
+
+	t.Run("should return ok", func(t *testing.T) {
+		ok, msg := ValidateCombo(valid())
+		require.True(t, ok)
+		require.Zero(t, msg)
+
+		// Product is not a combo
+		ok, _ = ValidateCombo(nil)
+		require.True(t, ok)
+	})
+
+	t.Run("should return InvalidComboProductID", func(t *testing.T) {
+		combo := valid()
+		combo.Components[1].Swaps[0].ProductID = "juice"
+		ok, msg := ValidateCombo(combo)
+		require.False(t, ok)
+		require.Equal(t, InvalidComboProductID+"juice", msg)
+	})
+
+	t.Run("should fail because combo has no components", func(t *testing.T) {
+		ok, _ := ValidateCombo(&input.ComboInput{})
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"regexp"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	invalidPromoCode       = "code should consist of 3 to 32 latin letters and digits"
+	invalidDiscountKind    = "invalid discount kind"
+	invalidPercent         = "percent should be greater than 0 and not greater than 1"
+	invalidDiscountAmount  = "amount should be positive"
+	ambiguousDiscount      = "either percent or amount should be set according to discount kind"
+	invalidMinCartAmount   = "minCartAmount can not be negative"
+	invalidUsageLimit      = "usage limits can not be negative"
+	invalidValidityWindow  = "validFrom should be before validTo"
+	invalidPromoCategoryID = "invalid category id"
+	invalidPromoProductID  = "invalid product id"
+)
+
+var promoCodeRegexp = regexp.MustCompile(`^[a-zA-Z0-9]{3,32}$`)
+
+func ValidateCreatePromoCodeInput(c input.CreatePromoCodeInput) (ok bool, msg string) {
+	if ok, msg := ValidateStruct(c); !ok {
+		return false, msg
+	}
+	if !promoCodeRegexp.MatchString(c.Code) {
+		return false, invalidPromoCode
+	}
+	return ValidatePromoCodeTermsInput(c.PromoCodeTermsInput)
+}
+
+func ValidatePromoCodeTermsInput(p input.PromoCodeTermsInput) (ok bool, msg string) {
+	switch p.Kind {
+	case domain.DiscountPercent:
+		if p.Amount != 0 {
+			return false, ambiguousDiscount
+		}
+		if p.Percent <= 0 || p.Percent > 1 {
+			return false, invalidPercent
+		}
+	case domain.DiscountFixed:
+		if p.Percent != 0 {
+			return false, ambiguousDiscount
+		}
+		if p.Amount <= 0 {
+			return false, invalidDiscountAmount
+		}
+	default:
+		return false, invalidDiscountKind
+	}
+	if p.MinCartAmount < 0 {
+		return false, invalidMinCartAmount
+	}
+	if p.UsageLimit < 0 || p.PerCustomerLimit < 0 {
+		return false, invalidUsageLimit
+	}
+	if p.ValidFrom != nil && p.ValidTo != nil && !p.ValidFrom.Before(*p.ValidTo) {
+		return false, invalidValidityWindow
+	}
+	for _, id := range p.CategoryIDs {
+		if _, err := primitive.ObjectIDFromHex(id); err != nil {
+			return false, invalidPromoCategoryID
+		}
+	}
+	for _, id := range p.ProductIDs {
+		if _, err := primitive.ObjectIDFromHex(id); err != nil {
+			return false, invalidPromoProductID
+		}
+	}
+	return true, ""
+}
@@ -0,0 +1,99 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestValidateCreatePromoCodeInput(t *testing.T) {
+	getInput := func() input.CreatePromoCodeInput {
+		return input.CreatePromoCodeInput{
+			Code: "Summer10",
+			PromoCodeTermsInput: input.PromoCodeTermsInput{
+				Kind:        domain.DiscountPercent,
+				Percent:     0.1,
+				UsageLimit:  100,
+				CategoryIDs: []string{primitive.NewObjectID().Hex()},
+			},
+		}
+	}
+
+	t.Run("should return ok", func(t *testing.T) {
+		ok, msg := ValidateCreatePromoCodeInput(getInput())
+		require.True(t, ok)
+		require.Zero(t, msg)
+	})
+
+	t.Run("should return ok with fixed discount", func(t *testing.T) {
+		inp := getInput()
+		inp.Kind = domain.DiscountFixed
+		inp.Percent = 0
+		inp.Amount = 150
+		ok, msg := ValidateCreatePromoCodeInput(inp)
+		require.True(t, ok)
+		require.Zero(t, msg)
+	})
+
+	t.Run("should return err because code has spaces", func(t *testing.T) {
+		inp := getInput()
+		inp.Code = "summer 10"
+		ok, msg := ValidateCreatePromoCodeInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidPromoCode, msg)
+	})
+
+	t.Run("should return err because kind is unknown", func(t *testing.T) {
+		inp := getInput()
+		inp.Kind = domain.DiscountKind{K: "free"}
+		ok, msg := ValidateCreatePromoCodeInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidDiscountKind, msg)
+	})
+
+	t.Run("should return err because percent is greater than 1", func(t *testing.T) {
+		inp := getInput()
+		inp.Percent = 10
+		ok, msg := ValidateCreatePromoCodeInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidPercent, msg)
+	})
+
+	t.Run("should return err because both percent and amount are set", func(t *testing.T) {
+		inp := getInput()
+		inp.Amount = 100
+		ok, msg := ValidateCreatePromoCodeInput(inp)
+		require.False(t, ok)
+		require.Equal(t, ambiguousDiscount, msg)
+	})
+
+	t.Run("should return err because limit is negative", func(t *testing.T) {
+		inp := getInput()
+		inp.PerCustomerLimit = -1
+		ok, msg := ValidateCreatePromoCodeInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidUsageLimit, msg)
+	})
+
+	t.Run("should return err because validity window is reversed", func(t *testing.T) {
+		inp := getInput()
+		from := time.Now().UTC()
+		to := from.Add(-time.Hour)
+		inp.ValidFrom, inp.ValidTo = &from, &to
+		ok, msg := ValidateCreatePromoCodeInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidValidityWindow, msg)
+	})
+
+	t.Run("should return err because category id is invalid", func(t *testing.T) {
+		inp := getInput()
+		inp.CategoryIDs = []string{"pizza"}
+		ok, msg := ValidateCreatePromoCodeInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidPromoCategoryID, msg)
+	})
+}
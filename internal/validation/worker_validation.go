@@ -0,0 +1,39 @@
+package validation
+
+import (
+	"regexp"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+)
+
+const (
+	minPasswordLength = 8
+
+	invalidLogin    = "login should be 3 to 32 latin letters, digits, '.', '_' or '-'"
+	invalidPassword = "password should be at least 8 characters long"
+)
+
+var loginRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]{3,32}$`)
+
+func ValidateCreateWorkerInput(c input.CreateWorkerInput) (ok bool, msg string) {
+	if ok, msg := ValidateStruct(c); !ok {
+		return false, msg
+	}
+	if !loginRegexp.MatchString(c.Login) {
+		return false, invalidLogin
+	}
+	if len(c.Password) < minPasswordLength {
+		return false, invalidPassword
+	}
+	return true, ""
+}
+
+func ValidateResetWorkerPasswordInput(r input.ResetWorkerPasswordInput) (ok bool, msg string) {
+	if ok, msg := ValidateStruct(r); !ok {
+		return false, msg
+	}
+	if len(r.Password) < minPasswordLength {
+		return false, invalidPassword
+	}
+	return true, ""
+}
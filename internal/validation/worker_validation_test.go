@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCreateWorkerInput(t *testing.T) {
+	getInput := func() input.CreateWorkerInput {
+		return input.CreateWorkerInput{
+			Name:     "George",
+			Login:    "george.k",
+			Password: "kasdsjd*&1231mz",
+		}
+	}
+
+	t.Run("should return ok", func(t *testing.T) {
+		ok, msg := ValidateCreateWorkerInput(getInput())
+		require.True(t, ok)
+		require.Zero(t, msg)
+	})
+
+	t.Run("should return invalidLogin", func(t *testing.T) {
+		for _, login := range []string{"ab", "george k", "георгий"} {
+			inp := getInput()
+			inp.Login = login
+			ok, msg := ValidateCreateWorkerInput(inp)
+			require.False(t, ok)
+			require.Equal(t, invalidLogin, msg)
+		}
+	})
+
+	t.Run("should return invalidPassword", func(t *testing.T) {
+		inp := getInput()
+		inp.Password = "1234567"
+		ok, msg := ValidateCreateWorkerInput(inp)
+		require.False(t, ok)
+		require.Equal(t, invalidPassword, msg)
+	})
+}
+
+func TestValidateResetWorkerPasswordInput(t *testing.T) {
+	ok, msg := ValidateResetWorkerPasswordInput(input.ResetWorkerPasswordInput{Password: "12345678"})
+	require.True(t, ok)
+	require.Zero(t, msg)
+
+	ok, msg = ValidateResetWorkerPasswordInput(input.ResetWorkerPasswordInput{Password: "1234567"})
+	require.False(t, ok)
+	require.Equal(t, invalidPassword, msg)
+}
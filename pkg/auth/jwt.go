@@ -2,6 +2,7 @@ package auth
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/cristalhq/jwt/v4"
@@ -27,12 +28,42 @@ type UserAuth struct {
 	Role domain.Role `json:"rl"`
 	// UserID of issuer
 	UserID string `json:"usrId"`
+	// SessionID of the session token has been issued for
+	SessionID string `json:"sid,omitempty"`
 }
 
 type Claims struct {
 	UserAuth
-	Issuer    string    `json:"iss"`
-	ExpiresAt time.Time `json:"exp"`
+	// TokenID is unique id of access token, so that it can be revoked
+	TokenID   string      `json:"jti"`
+	Issuer    string      `json:"iss"`
+	IssuedAt  NumericDate `json:"iat"`
+	ExpiresAt NumericDate `json:"exp"`
+}
+
+// NumericDate is RFC 7519 NumericDate, so that other services verify tokens with standard JWT libraries.
+// Unlike jwt.NumericDate it keeps milliseconds, as tokens with short TTL and tokens issued
+// right after revocation must not be rounded down to the second.
+type NumericDate struct {
+	time.Time
+}
+
+func NewNumericDate(t time.Time) NumericDate {
+	return NumericDate{t}
+}
+
+func (d NumericDate) MarshalJSON() ([]byte, error) {
+	ms := d.UnixMilli()
+	return []byte(fmt.Sprintf("%d.%03d", ms/1000, ms%1000)), nil
+}
+
+func (d *NumericDate) UnmarshalJSON(data []byte) error {
+	var date jwt.NumericDate
+	if err := date.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	d.Time = date.Time
+	return nil
 }
 
 type TokenProvider interface {
@@ -40,59 +71,62 @@ type TokenProvider interface {
 	// GenerateNewPairWithTTL will return access token with custom ttl
 	GenerateNewPairWithTTL(data UserAuth, ttl time.Duration) (Pair, error)
 	ParseAndValidate(token string) (UserAuth, error)
+	// ParseAndValidateClaims is ParseAndValidate returning all claims of the token
+	ParseAndValidateClaims(token string) (Claims, error)
+	// JWKS returns public keys other services verify tokens with
+	JWKS() JWKS
 }
 
 type provider struct {
 	// Duration added to time.Now() in ExpiresAt field
-	ttl        time.Duration
-	signingKey []byte
+	ttl  time.Duration
+	keys *KeySet
 
-	builder  *jwt.Builder
-	verifier *jwt.HSAlg
+	builder *jwt.Builder
 
 	issuer string
 }
 
+// NewProvider returns provider signing and verifying tokens with a single HS256 key
 func NewProvider(ttl time.Duration, signingKey []byte, issuer string) (TokenProvider, error) {
-	signer, err := jwt.NewSignerHS(jwt.HS256, signingKey)
+	key, err := NewHSKey("", signingKey)
 	if err != nil {
 		return nil, err
 	}
-	verifier, err := jwt.NewVerifierHS(jwt.HS256, signingKey)
+	keys, err := NewKeySet(key.ID, key)
 	if err != nil {
 		return nil, err
 	}
-	return &provider{
-		ttl:        ttl,
-		signingKey: signingKey,
-		builder:    jwt.NewBuilder(signer),
-		verifier:   verifier,
-		issuer:     issuer,
-	}, nil
+	return NewProviderWithKeys(ttl, keys, issuer), nil
 }
 
-func (p provider) GenerateNewPair(data UserAuth) (Pair, error) {
-	payload := Claims{
-		UserAuth:  data,
-		Issuer:    p.issuer,
-		ExpiresAt: time.Now().Add(p.ttl),
+// NewProviderWithKeys returns provider signing tokens with signing key of the set
+// and verifying them with a key of the set the kid header points to
+func NewProviderWithKeys(ttl time.Duration, keys *KeySet, issuer string) TokenProvider {
+	var opts []jwt.BuilderOption
+	if keys.signing.ID != "" {
+		opts = append(opts, jwt.WithKeyID(keys.signing.ID))
 	}
-	accessToken, err := p.builder.Build(payload)
-	if err != nil {
-		return Pair{}, err
+	return &provider{
+		ttl:     ttl,
+		keys:    keys,
+		builder: jwt.NewBuilder(keys.signing.signer, opts...),
+		issuer:  issuer,
 	}
+}
 
-	return Pair{
-		RefreshToken: uuid.NewString(),
-		AccessToken:  accessToken.String(),
-	}, nil
+func (p provider) GenerateNewPair(data UserAuth) (Pair, error) {
+	return p.GenerateNewPairWithTTL(data, p.ttl)
 }
 
 func (p provider) GenerateNewPairWithTTL(data UserAuth, ttl time.Duration) (Pair, error) {
+	now := time.Now()
 	payload := Claims{
 		UserAuth:  data,
+		TokenID:   uuid.NewString(),
 		Issuer:    p.issuer,
-		ExpiresAt: time.Now().Add(ttl),
+		IssuedAt:  NewNumericDate(now),
+		ExpiresAt: NewNumericDate(now.Add(ttl)),
 	}
 	accessToken, err := p.builder.Build(payload)
 	if err != nil {
@@ -106,24 +140,46 @@ func (p provider) GenerateNewPairWithTTL(data UserAuth, ttl time.Duration) (Pair
 }
 
 func (p provider) ParseAndValidate(token string) (UserAuth, error) {
+	claims, err := p.ParseAndValidateClaims(token)
+	return claims.UserAuth, err
+}
+
+func (p provider) ParseAndValidateClaims(token string) (Claims, error) {
 	var (
 		tokenPayload Claims
 		now          = time.Now()
 		tokenBytes   = []byte(token)
 	)
-	if err := jwt.ParseClaims(tokenBytes, p.verifier, &tokenPayload); err != nil {
-		if errors.Is(err, jwt.ErrInvalidFormat) || errors.Is(err, jwt.ErrInvalidSignature) {
-			return UserAuth{}, ErrInvalidToken
+	// Signature is verified below with the key token points to
+	parsed, err := jwt.ParseNoVerify(tokenBytes)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	// Key may have been removed from the set after rotation
+	verifier, ok := p.keys.verifier(parsed.Header().KeyID)
+	if !ok {
+		return Claims{}, ErrInvalidToken
+	}
+	if err := verifier.Verify(parsed); err != nil {
+		if errors.Is(err, jwt.ErrInvalidSignature) || errors.Is(err, jwt.ErrAlgorithmMismatch) {
+			return Claims{}, ErrInvalidToken
 		}
-		logger.Get().Error("jwt.ParseClaims: ", zap.Error(err))
-		return UserAuth{}, err
+		logger.Get().Error("jwt.Verify: ", zap.Error(err))
+		return Claims{}, err
+	}
+	if err := parsed.DecodeClaims(&tokenPayload); err != nil {
+		return Claims{}, ErrInvalidToken
 	}
 	if tokenPayload.ExpiresAt.Before(now) {
-		return tokenPayload.UserAuth, ErrTokenExpired
+		return tokenPayload, ErrTokenExpired
 	}
 	if tokenPayload.Issuer != p.issuer {
-		return UserAuth{}, ErrInvalidIssuer
+		return Claims{}, ErrInvalidIssuer
 	}
 	// Validation successful
-	return tokenPayload.UserAuth, nil
+	return tokenPayload, nil
+}
+
+func (p provider) JWKS() JWKS {
+	return p.keys.JWKS()
 }
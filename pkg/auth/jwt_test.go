@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -31,7 +32,7 @@ func TestTokenProvider(t *testing.T) {
 				UserID: userID,
 			},
 			Issuer:    issuer,
-			ExpiresAt: time.Now().Add(ttl),
+			ExpiresAt: NewNumericDate(time.Now().Add(ttl)),
 		}
 		token, err := builder.Build(tokenPayload)
 		require.NoError(t, err)
@@ -65,6 +66,55 @@ func TestTokenProvider(t *testing.T) {
 		require.EqualValues(t, data, userAuth)
 	})
 
+	t.Run("should issue every access token with unique id", func(t *testing.T) {
+		data := UserAuth{
+			Role:   domain.RoleWorker,
+			UserID: uuid.NewString(),
+		}
+		p, err := NewProvider(time.Minute, key, issuer)
+		require.NoError(t, err)
+
+		first, err := p.GenerateNewPair(data)
+		require.NoError(t, err)
+		second, err := p.GenerateNewPair(data)
+		require.NoError(t, err)
+
+		firstClaims, err := p.ParseAndValidateClaims(first.AccessToken)
+		require.NoError(t, err)
+		secondClaims, err := p.ParseAndValidateClaims(second.AccessToken)
+		require.NoError(t, err)
+
+		require.EqualValues(t, data, firstClaims.UserAuth)
+		require.NotZero(t, firstClaims.TokenID)
+		require.NotEqual(t, firstClaims.TokenID, secondClaims.TokenID)
+		require.WithinDuration(t, time.Now(), firstClaims.IssuedAt.Time, time.Second)
+		require.Equal(t, time.Minute, firstClaims.ExpiresAt.Sub(firstClaims.IssuedAt.Time))
+	})
+
+	t.Run("should issue token with numeric iat and exp", func(t *testing.T) {
+		p, err := NewProvider(time.Minute, key, issuer)
+		require.NoError(t, err)
+		tokens, err := p.GenerateNewPair(UserAuth{Role: domain.RoleWorker, UserID: uuid.NewString()})
+		require.NoError(t, err)
+
+		parsed, err := jwt.ParseNoVerify([]byte(tokens.AccessToken))
+		require.NoError(t, err)
+		var payload map[string]interface{}
+		require.NoError(t, json.Unmarshal(parsed.Claims(), &payload))
+		iat, ok := payload["iat"].(float64)
+		require.True(t, ok, payload["iat"])
+		exp, ok := payload["exp"].(float64)
+		require.True(t, ok, payload["exp"])
+		require.InDelta(t, time.Now().Unix(), iat, 1)
+		require.InDelta(t, time.Minute.Seconds(), exp-iat, 0.001)
+
+		// Claims are read by standard JWT libraries
+		var registered jwt.RegisteredClaims
+		require.NoError(t, parsed.DecodeClaims(&registered))
+		require.True(t, registered.IsValidAt(time.Now()))
+		require.Equal(t, issuer, registered.Issuer)
+	})
+
 	t.Run("should generate new pair and wait for expiration then validate and return false with ErrExpired", func(t *testing.T) {
 		data := UserAuth{
 			Role:   domain.RoleCustomer,
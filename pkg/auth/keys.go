@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/cristalhq/jwt/v4"
+)
+
+var (
+	ErrUnsupportedKey     = errors.New("unsupported key")
+	ErrSigningKeyNotFound = errors.New("signing key not found")
+	ErrDuplicateKeyID     = errors.New("duplicate key id")
+)
+
+// Key signs and verifies tokens. Key made of public key only verifies tokens signed before rotation.
+type Key struct {
+	// ID is put to kid header of tokens signed with the key
+	ID        string
+	algorithm jwt.Algorithm
+	// signer is nil if key is only used for verification
+	signer   jwt.Signer
+	verifier jwt.Verifier
+	// public is nil for symmetric keys, they're never published
+	public crypto.PublicKey
+}
+
+// NewHSKey returns HS256 key. Everyone who verifies tokens with it can sign them as well.
+func NewHSKey(id string, secret []byte) (Key, error) {
+	signer, err := jwt.NewSignerHS(jwt.HS256, secret)
+	if err != nil {
+		return Key{}, err
+	}
+	verifier, err := jwt.NewVerifierHS(jwt.HS256, secret)
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{ID: id, algorithm: jwt.HS256, signer: signer, verifier: verifier}, nil
+}
+
+func NewRSKey(id string, private *rsa.PrivateKey) (Key, error) {
+	signer, err := jwt.NewSignerRS(jwt.RS256, private)
+	if err != nil {
+		return Key{}, err
+	}
+	key, err := NewRSPublicKey(id, &private.PublicKey)
+	if err != nil {
+		return Key{}, err
+	}
+	key.signer = signer
+	return key, nil
+}
+
+func NewRSPublicKey(id string, public *rsa.PublicKey) (Key, error) {
+	verifier, err := jwt.NewVerifierRS(jwt.RS256, public)
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{ID: id, algorithm: jwt.RS256, verifier: verifier, public: public}, nil
+}
+
+func NewEdDSAKey(id string, private ed25519.PrivateKey) (Key, error) {
+	signer, err := jwt.NewSignerEdDSA(private)
+	if err != nil {
+		return Key{}, err
+	}
+	key, err := NewEdDSAPublicKey(id, private.Public().(ed25519.PublicKey))
+	if err != nil {
+		return Key{}, err
+	}
+	key.signer = signer
+	return key, nil
+}
+
+func NewEdDSAPublicKey(id string, public ed25519.PublicKey) (Key, error) {
+	verifier, err := jwt.NewVerifierEdDSA(public)
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{ID: id, algorithm: jwt.EdDSA, verifier: verifier, public: public}, nil
+}
+
+// ParsePEMKey parses PKCS#8 or PKCS#1 private key, or PKIX public key. RSA and Ed25519 keys are supported.
+func ParsePEMKey(id string, data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, fmt.Errorf("%w: no PEM block found", ErrUnsupportedKey)
+	}
+
+	var (
+		parsed interface{}
+		err    error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("%w: PEM block %q", ErrUnsupportedKey, block.Type)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return NewRSKey(id, key)
+	case *rsa.PublicKey:
+		return NewRSPublicKey(id, key)
+	case ed25519.PrivateKey:
+		return NewEdDSAKey(id, key)
+	case ed25519.PublicKey:
+		return NewEdDSAPublicKey(id, key)
+	default:
+		return Key{}, fmt.Errorf("%w: %T", ErrUnsupportedKey, parsed)
+	}
+}
+
+// KeySet holds the key new tokens are signed with and every key tokens are verified with,
+// so that keys can be rotated without invalidating tokens that have been issued already.
+type KeySet struct {
+	signing Key
+	// keys are kept in order they're given for stable JWKS
+	keys []Key
+}
+
+func NewKeySet(signingKeyID string, keys ...Key) (*KeySet, error) {
+	set := &KeySet{keys: keys}
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if seen[key.ID] {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateKeyID, key.ID)
+		}
+		seen[key.ID] = true
+		if key.ID == signingKeyID && key.signer != nil {
+			set.signing = key
+		}
+	}
+	if set.signing.signer == nil {
+		return nil, fmt.Errorf("%w: %q", ErrSigningKeyNotFound, signingKeyID)
+	}
+	return set, nil
+}
+
+// verifier returns verifier of key by id. Tokens signed before keys had ids have empty kid.
+func (s *KeySet) verifier(keyID string) (jwt.Verifier, bool) {
+	for _, key := range s.keys {
+		if key.ID == keyID {
+			return key.verifier, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cristalhq/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySet(t *testing.T) {
+	const issuer = "app.com"
+	data := UserAuth{
+		Role:   domain.RoleWorker,
+		UserID: uuid.NewString(),
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("should sign with RS256 and EdDSA keys and put kid to header", func(t *testing.T) {
+		rs, err := NewRSKey("rs-1", rsaKey)
+		require.NoError(t, err)
+		ed, err := NewEdDSAKey("ed-1", edKey)
+		require.NoError(t, err)
+
+		for _, key := range []Key{rs, ed} {
+			keys, err := NewKeySet(key.ID, key)
+			require.NoError(t, err)
+			p := NewProviderWithKeys(time.Minute, keys, issuer)
+
+			tokens, err := p.GenerateNewPair(data)
+			require.NoError(t, err)
+
+			token, err := jwt.ParseNoVerify([]byte(tokens.AccessToken))
+			require.NoError(t, err)
+			require.Equal(t, key.ID, token.Header().KeyID)
+			require.Equal(t, key.algorithm, token.Header().Algorithm)
+
+			userAuth, err := p.ParseAndValidate(tokens.AccessToken)
+			require.NoError(t, err)
+			require.Equal(t, data, userAuth)
+		}
+	})
+
+	t.Run("should verify tokens signed with the previous key after rotation", func(t *testing.T) {
+		legacy, err := NewHSKey("", []byte("shared secret"))
+		require.NoError(t, err)
+		old, err := NewRSKey("rs-1", rsaKey)
+		require.NoError(t, err)
+		current, err := NewEdDSAKey("ed-1", edKey)
+		require.NoError(t, err)
+
+		legacyProvider, err := NewProvider(time.Minute, []byte("shared secret"), issuer)
+		require.NoError(t, err)
+		oldKeys, err := NewKeySet(old.ID, old)
+		require.NoError(t, err)
+		rotatedKeys, err := NewKeySet(current.ID, current, old, legacy)
+		require.NoError(t, err)
+
+		var (
+			oldProvider     = NewProviderWithKeys(time.Minute, oldKeys, issuer)
+			rotatedProvider = NewProviderWithKeys(time.Minute, rotatedKeys, issuer)
+		)
+		for _, p := range []TokenProvider{legacyProvider, oldProvider} {
+			tokens, err := p.GenerateNewPair(data)
+			require.NoError(t, err)
+			userAuth, err := rotatedProvider.ParseAndValidate(tokens.AccessToken)
+			require.NoError(t, err)
+			require.Equal(t, data, userAuth)
+		}
+
+		// Old key is dropped once its tokens have expired
+		tokens, err := rotatedProvider.GenerateNewPair(data)
+		require.NoError(t, err)
+		_, err = oldProvider.ParseAndValidate(tokens.AccessToken)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("should reject token signed with another key of the same id", func(t *testing.T) {
+		otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		key, err := NewRSKey("rs-1", rsaKey)
+		require.NoError(t, err)
+		forged, err := NewRSKey("rs-1", otherRSAKey)
+		require.NoError(t, err)
+
+		keys, err := NewKeySet(key.ID, key)
+		require.NoError(t, err)
+		forgedKeys, err := NewKeySet(forged.ID, forged)
+		require.NoError(t, err)
+
+		tokens, err := NewProviderWithKeys(time.Minute, forgedKeys, issuer).GenerateNewPair(data)
+		require.NoError(t, err)
+		_, err = NewProviderWithKeys(time.Minute, keys, issuer).ParseAndValidate(tokens.AccessToken)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("should not sign with public key", func(t *testing.T) {
+		key, err := NewEdDSAPublicKey("ed-1", edKey.Public().(ed25519.PublicKey))
+		require.NoError(t, err)
+
+		_, err = NewKeySet(key.ID, key)
+		require.ErrorIs(t, err, ErrSigningKeyNotFound)
+	})
+
+	t.Run("should not accept keys with the same id", func(t *testing.T) {
+		rs, err := NewRSKey("key", rsaKey)
+		require.NoError(t, err)
+		ed, err := NewEdDSAKey("key", edKey)
+		require.NoError(t, err)
+
+		_, err = NewKeySet("key", rs, ed)
+		require.ErrorIs(t, err, ErrDuplicateKeyID)
+	})
+
+	t.Run("should parse PEM keys", func(t *testing.T) {
+		pkcs8, err := x509.MarshalPKCS8PrivateKey(edKey)
+		require.NoError(t, err)
+		pkix, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+		require.NoError(t, err)
+
+		ed, err := ParsePEMKey("ed-1", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))
+		require.NoError(t, err)
+		require.Equal(t, jwt.EdDSA, ed.algorithm)
+		require.NotNil(t, ed.signer)
+
+		rs, err := ParsePEMKey("rs-1", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}))
+		require.NoError(t, err)
+		require.Equal(t, jwt.RS256, rs.algorithm)
+		require.Nil(t, rs.signer)
+
+		_, err = ParsePEMKey("bad", []byte("not a key"))
+		require.ErrorIs(t, err, ErrUnsupportedKey)
+	})
+
+	t.Run("should publish public keys only", func(t *testing.T) {
+		legacy, err := NewHSKey("", []byte("shared secret"))
+		require.NoError(t, err)
+		rs, err := NewRSKey("rs-1", rsaKey)
+		require.NoError(t, err)
+		ed, err := NewEdDSAKey("ed-1", edKey)
+		require.NoError(t, err)
+		keys, err := NewKeySet(ed.ID, ed, rs, legacy)
+		require.NoError(t, err)
+
+		jwks := keys.JWKS()
+		require.Len(t, jwks.Keys, 2)
+
+		okp := jwks.Keys[0]
+		require.Equal(t, "OKP", okp.KeyType)
+		require.Equal(t, "Ed25519", okp.Curve)
+		require.Equal(t, "EdDSA", okp.Algorithm)
+		require.Equal(t, "ed-1", okp.KeyID)
+		x, err := base64.RawURLEncoding.DecodeString(okp.X)
+		require.NoError(t, err)
+		require.Equal(t, []byte(edKey.Public().(ed25519.PublicKey)), x)
+
+		rsa := jwks.Keys[1]
+		require.Equal(t, "RSA", rsa.KeyType)
+		require.Equal(t, "RS256", rsa.Algorithm)
+		require.Equal(t, "sig", rsa.Use)
+		n, err := base64.RawURLEncoding.DecodeString(rsa.N)
+		require.NoError(t, err)
+		require.Zero(t, rsaKey.N.Cmp(new(big.Int).SetBytes(n)))
+		e, err := base64.RawURLEncoding.DecodeString(rsa.E)
+		require.NoError(t, err)
+		require.Equal(t, int64(rsaKey.E), new(big.Int).SetBytes(e).Int64())
+	})
+}
@@ -0,0 +1,94 @@
+// Package blob stores files such as product images
+package blob
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// LocalStore writes blobs to directory on local filesystem. The directory is served
+// by the app itself under URLPrefix, so it suits single instance deployments.
+type LocalStore struct {
+	root      string
+	urlPrefix string
+}
+
+func NewLocalStore(root, urlPrefix string) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{
+		root:      root,
+		urlPrefix: strings.TrimSuffix(urlPrefix, "/"),
+	}, nil
+}
+
+// Root is directory blobs are written to
+func (l *LocalStore) Root() string {
+	return l.root
+}
+
+// URLPrefix is path the directory is served at
+func (l *LocalStore) URLPrefix() string {
+	return l.urlPrefix
+}
+
+// Put writes blob and returns URL it's served at. Blob is written to temporary file
+// first, so that a half-written file is never served.
+func (l *LocalStore) Put(ctx context.Context, key string, data []byte) (string, error) {
+	filename, err := l.filename(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() //nolint:errcheck
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return "", err
+	}
+	return l.urlPrefix + "/" + key, nil
+}
+
+// Delete removes blob. Missing blob is not an error.
+func (l *LocalStore) Delete(ctx context.Context, key string) error {
+	filename, err := l.filename(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filename); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// filename rejects keys that would escape root directory
+func (l *LocalStore) filename(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,58 @@
+package blob
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should put blob and return its url", func(t *testing.T) {
+		root := t.TempDir()
+		store, err := NewLocalStore(root, "/static/")
+		require.NoError(t, err)
+
+		url, err := store.Put(ctx, "products/1/medium.jpg", []byte("image"))
+		require.NoError(t, err)
+		require.Equal(t, "/static/products/1/medium.jpg", url)
+
+		data, err := os.ReadFile(filepath.Join(root, "products", "1", "medium.jpg"))
+		require.NoError(t, err)
+		require.Equal(t, "image", string(data))
+
+		// Temporary files are not left behind
+		entries, err := os.ReadDir(filepath.Join(root, "products", "1"))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("should delete blob and ignore missing one", func(t *testing.T) {
+		root := t.TempDir()
+		store, err := NewLocalStore(root, "/static")
+		require.NoError(t, err)
+
+		_, err = store.Put(ctx, "a.jpg", []byte("image"))
+		require.NoError(t, err)
+		require.NoError(t, store.Delete(ctx, "a.jpg"))
+		require.NoError(t, store.Delete(ctx, "a.jpg"))
+
+		_, err = os.Stat(filepath.Join(root, "a.jpg"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("should reject keys escaping root", func(t *testing.T) {
+		store, err := NewLocalStore(t.TempDir(), "/static")
+		require.NoError(t, err)
+
+		for _, key := range []string{"", "/etc/passwd", "../a.jpg", "..", "a/../../b.jpg", "a//b.jpg", `a\..\b.jpg`} {
+			_, err := store.Put(ctx, key, []byte("image"))
+			require.ErrorIs(t, err, ErrInvalidKey, key)
+			require.ErrorIs(t, store.Delete(ctx, key), ErrInvalidKey, key)
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package cursor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("should encode and decode cursor", func(t *testing.T) {
+		c := New(time.Now().UTC().Truncate(time.Millisecond), primitive.NewObjectID())
+		decoded, err := Decode(c.Encode())
+		require.NoError(t, err)
+		require.True(t, c.CreatedAt.Equal(decoded.CreatedAt))
+		require.Equal(t, c.ID, decoded.ID)
+	})
+
+	t.Run("should return ErrInvalidCursor because cursor is malformed", func(t *testing.T) {
+		for _, s := range []string{"some-random-cursor", "e30", "!!!"} {
+			_, err := Decode(s)
+			require.Equal(t, ErrInvalidCursor, err)
+		}
+	})
+}
@@ -2,12 +2,16 @@ package database
 
 import (
 	"context"
+	"errors"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
+var ErrTransactionsNotSupported = errors.New("mongo does not support transactions, run it as replica set or connect to mongos")
+
 type Mongo struct {
 	c  *mongo.Client
 	db *mongo.Database
@@ -30,6 +34,23 @@ func Connect(ctx context.Context, uri string, DBName string) (*Mongo, error) {
 	return &Mongo{c: client, db: client.Database(DBName)}, nil
 }
 
+// CheckTransactions returns ErrTransactionsNotSupported if mongo is standalone.
+// Transactions are supported only by replica set members and mongos.
+func (m *Mongo) CheckTransactions(ctx context.Context) error {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := m.db.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return err
+	}
+	// mongos answers with msg isdbgrid
+	if hello.SetName == "" && hello.Msg != "isdbgrid" {
+		return ErrTransactionsNotSupported
+	}
+	return nil
+}
+
 func (m *Mongo) Collection(collection string) *mongo.Collection {
 	return m.db.Collection(collection)
 }
@@ -0,0 +1,193 @@
+package eventbus
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBufferSize = 256
+	// Amount of events subscriber can lag behind before it's dropped
+	subscriberBufferSize = 64
+	// ResumeWindow is how long events of topic without subscribers are kept for them to reconnect.
+	// Topics idle for longer are removed, so that per customer topics don't pile up.
+	ResumeWindow  = time.Minute * 15
+	pruneInterval = time.Minute
+)
+
+type Event struct {
+	ID    uint64
+	Topic string
+	Type  string
+	Data  []byte
+}
+
+// Bus is an in-process publish/subscribe bus. Every topic keeps last N events
+// in a ring buffer, so subscribers that reconnect can resume from the last event they've seen.
+type Bus struct {
+	mu         sync.Mutex
+	lastID     uint64
+	bufferSize int
+	topics     map[string]*topic
+	prunedAt   time.Time
+
+	now func() time.Time
+}
+
+type topic struct {
+	// ring buffer of last events, head points to the oldest one
+	ring        []Event
+	head        int
+	subscribers map[*Subscription]struct{}
+	// Time of the last event or of creation if nothing has been published yet
+	activeAt time.Time
+}
+
+type Subscription struct {
+	bus    *Bus
+	topic  string
+	events chan Event
+	once   sync.Once
+}
+
+func New(bufferSize int) *Bus {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Bus{
+		// Event ids are seeded with current time, so ids issued
+		// after restart are still greater than those clients have seen before.
+		lastID:     uint64(time.Now().UnixNano()),
+		bufferSize: bufferSize,
+		topics:     make(map[string]*topic),
+		prunedAt:   time.Now(),
+		now:        time.Now,
+	}
+}
+
+// Publish sends event to every subscriber of the topic. Subscribers that can't keep up
+// are dropped (their channel is closed), they're expected to resubscribe with last seen event id.
+func (b *Bus) Publish(topicName, eventType string, data []byte) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := Event{
+		ID:    b.lastID,
+		Topic: topicName,
+		Type:  eventType,
+		Data:  data,
+	}
+
+	t := b.getTopic(topicName)
+	t.activeAt = b.now()
+	if len(t.ring) < b.bufferSize {
+		t.ring = append(t.ring, event)
+	} else {
+		t.ring[t.head] = event
+		t.head = (t.head + 1) % b.bufferSize
+	}
+
+	for sub := range t.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			b.unsubscribe(sub)
+		}
+	}
+
+	return event
+}
+
+// Subscribe returns subscription to the topic and buffered events that have id greater than lastEventID.
+// If lastEventID is 0 no buffered events are returned.
+func (b *Bus) Subscribe(topicName string, lastEventID uint64) (*Subscription, []Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t := b.getTopic(topicName)
+	sub := &Subscription{
+		bus:    b,
+		topic:  topicName,
+		events: make(chan Event, subscriberBufferSize),
+	}
+	t.subscribers[sub] = struct{}{}
+
+	var missed []Event
+	if lastEventID != 0 {
+		for i := 0; i < len(t.ring); i++ {
+			event := t.ring[(t.head+i)%len(t.ring)]
+			if event.ID > lastEventID {
+				missed = append(missed, event)
+			}
+		}
+	}
+
+	return sub, missed
+}
+
+func (b *Bus) getTopic(name string) *topic {
+	now := b.now()
+	if now.Sub(b.prunedAt) >= pruneInterval {
+		b.prune(now)
+	}
+
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{
+			ring:        make([]Event, 0, b.bufferSize),
+			subscribers: make(map[*Subscription]struct{}),
+			activeAt:    now,
+		}
+		b.topics[name] = t
+	}
+	return t
+}
+
+// prune removes topics nobody is subscribed to, whose events are too old to resume from.
+// It expects b.mu to be held
+func (b *Bus) prune(now time.Time) {
+	for name, t := range b.topics {
+		if len(t.subscribers) == 0 && now.Sub(t.activeAt) >= ResumeWindow {
+			delete(b.topics, name)
+		}
+	}
+	b.prunedAt = now
+}
+
+// unsubscribe expects b.mu to be held
+func (b *Bus) unsubscribe(sub *Subscription) {
+	t, ok := b.topics[sub.topic]
+	if !ok {
+		return
+	}
+	if _, ok := t.subscribers[sub]; !ok {
+		return
+	}
+	delete(t.subscribers, sub)
+	close(sub.events)
+	// There is nothing to resume from
+	if len(t.subscribers) == 0 && len(t.ring) == 0 {
+		delete(b.topics, sub.topic)
+	}
+}
+
+func (b *Bus) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.topics)
+}
+
+// Events returns channel of live events. It's closed when subscription is closed or dropped.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close unsubscribes from the topic. It's safe to call Close multiple times.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		defer s.bus.mu.Unlock()
+		s.bus.unsubscribe(s)
+	})
+}
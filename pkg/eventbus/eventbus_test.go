@@ -0,0 +1,129 @@
+package eventbus
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBus(t *testing.T) {
+	t.Run("should deliver published event to topic subscribers only", func(t *testing.T) {
+		bus := New(10)
+		sub, missed := bus.Subscribe("a", 0)
+		defer sub.Close()
+		require.Empty(t, missed)
+
+		other, _ := bus.Subscribe("b", 0)
+		defer other.Close()
+
+		published := bus.Publish("a", "created", []byte("data"))
+
+		received := <-sub.Events()
+		require.Equal(t, published, received)
+		require.Equal(t, "created", received.Type)
+		require.Len(t, other.Events(), 0)
+	})
+
+	t.Run("should return missed events after last event id", func(t *testing.T) {
+		bus := New(10)
+		var events []Event
+		for i := 0; i < 5; i++ {
+			events = append(events, bus.Publish("a", "created", nil))
+		}
+
+		sub, missed := bus.Subscribe("a", events[1].ID)
+		defer sub.Close()
+		require.Equal(t, events[2:], missed)
+	})
+
+	t.Run("should keep only last events in ring buffer", func(t *testing.T) {
+		bus := New(3)
+		var events []Event
+		for i := 0; i < 7; i++ {
+			events = append(events, bus.Publish("a", "created", nil))
+		}
+
+		sub, missed := bus.Subscribe("a", events[0].ID)
+		defer sub.Close()
+		require.Equal(t, events[4:], missed)
+	})
+
+	t.Run("should close channel on unsubscribe", func(t *testing.T) {
+		bus := New(10)
+		sub, _ := bus.Subscribe("a", 0)
+		sub.Close()
+		// Second close is no-op
+		sub.Close()
+
+		_, ok := <-sub.Events()
+		require.False(t, ok)
+
+		// Publishing to topic without subscribers should not block
+		bus.Publish("a", "created", nil)
+	})
+
+	t.Run("should drop subscriber that can not keep up", func(t *testing.T) {
+		bus := New(10)
+		sub, _ := bus.Subscribe("a", 0)
+		for i := 0; i < subscriberBufferSize+1; i++ {
+			bus.Publish("a", "created", nil)
+		}
+
+		var received int
+		for range sub.Events() {
+			received++
+		}
+		require.Equal(t, subscriberBufferSize, received)
+		sub.Close()
+	})
+
+	t.Run("should remove topic when last subscriber of empty topic leaves", func(t *testing.T) {
+		bus := New(10)
+		sub, _ := bus.Subscribe("a", 0)
+		require.Equal(t, 1, bus.Len())
+		sub.Close()
+		require.Equal(t, 0, bus.Len())
+	})
+
+	t.Run("should remove idle topics without subscribers after resume window", func(t *testing.T) {
+		now := time.Now()
+		bus := New(10)
+		bus.now = func() time.Time { return now }
+		bus.prunedAt = now
+
+		published := bus.Publish("customer:1", "created", nil)
+		sub, _ := bus.Subscribe("workers", 0)
+		defer sub.Close()
+
+		// Customer can still resume within the window
+		now = now.Add(ResumeWindow - time.Second)
+		resumed, missed := bus.Subscribe("customer:1", published.ID-1)
+		require.Equal(t, []Event{published}, missed)
+		resumed.Close()
+		require.Equal(t, 2, bus.Len())
+
+		now = now.Add(pruneInterval)
+		bus.Publish("workers", "created", nil)
+		require.Equal(t, 1, bus.Len())
+	})
+
+	t.Run("concurrent publish and subscribe", func(t *testing.T) {
+		bus := New(10)
+		wg := new(sync.WaitGroup)
+		wg.Add(200)
+		for i := 0; i < 100; i++ {
+			go func() {
+				defer wg.Done()
+				bus.Publish("a", "created", nil)
+			}()
+			go func() {
+				defer wg.Done()
+				sub, _ := bus.Subscribe("a", 1)
+				sub.Close()
+			}()
+		}
+		wg.Wait()
+	})
+}
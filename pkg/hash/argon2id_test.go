@@ -0,0 +1,79 @@
+package hash
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Cheap parameters keep tests fast
+var testParams = Argon2idParams{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher := NewArgon2idHasher(testParams)
+
+	t.Run("should verify password", func(t *testing.T) {
+		passwordHash, err := hasher.Hash("password")
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(passwordHash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+		ok, needsRehash := hasher.Verify("password", passwordHash)
+		require.True(t, ok)
+		require.False(t, needsRehash)
+
+		ok, _ = hasher.Verify("Password", passwordHash)
+		require.False(t, ok)
+	})
+
+	t.Run("should salt hashes", func(t *testing.T) {
+		first, err := hasher.Hash("password")
+		require.NoError(t, err)
+		second, err := hasher.Hash("password")
+		require.NoError(t, err)
+		require.NotEqual(t, first, second)
+	})
+
+	t.Run("should verify legacy SHA-1 hash and ask for rehash", func(t *testing.T) {
+		legacyHash, err := NewSHA1Hasher().Hash("password")
+		require.NoError(t, err)
+
+		ok, needsRehash := hasher.Verify("password", legacyHash)
+		require.True(t, ok)
+		require.True(t, needsRehash)
+
+		ok, needsRehash = hasher.Verify("Password", legacyHash)
+		require.False(t, ok)
+		require.False(t, needsRehash)
+	})
+
+	t.Run("should ask for rehash because parameters have changed", func(t *testing.T) {
+		passwordHash, err := hasher.Hash("password")
+		require.NoError(t, err)
+
+		stronger := testParams
+		stronger.Iterations = 2
+		ok, needsRehash := NewArgon2idHasher(stronger).Verify("password", passwordHash)
+		require.True(t, ok)
+		require.True(t, needsRehash)
+	})
+
+	t.Run("should not verify malformed hash", func(t *testing.T) {
+		for _, passwordHash := range []string{
+			"$argon2id$",
+			"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA",
+			"$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5",
+			"$argon2id$v=19$m=1024,t=1,p=1$!!!$a2V5",
+		} {
+			ok, needsRehash := hasher.Verify("password", passwordHash)
+			require.False(t, ok)
+			require.False(t, needsRehash)
+		}
+	})
+}
@@ -2,16 +2,26 @@ package hash
 
 import (
 	"crypto/sha1"
+	"crypto/subtle"
 	"encoding/hex"
 )
 
+// SHA1Hasher makes unsalted SHA-1 hashes. It's kept to verify passwords hashed before Argon2idHasher.
 type SHA1Hasher struct{}
 
 func NewSHA1Hasher() *SHA1Hasher {
 	return &SHA1Hasher{}
 }
 
-func (s SHA1Hasher) Hash(password string) string {
+func (s SHA1Hasher) Hash(password string) (string, error) {
+	return sha1Hex(password), nil
+}
+
+func (s SHA1Hasher) Verify(password, passwordHash string) (ok bool, needsRehash bool) {
+	return subtle.ConstantTimeCompare([]byte(sha1Hex(password)), []byte(passwordHash)) == 1, false
+}
+
+func sha1Hex(password string) string {
 	h := sha1.New()
 	h.Write([]byte(password))
 	return hex.EncodeToString(h.Sum(nil))
@@ -0,0 +1,134 @@
+// Package imaging decodes uploaded images and makes resized variants of them
+// using only the standard library.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	// Registers decoders of formats accepted by Decode
+	_ "image/gif"
+	_ "image/png"
+)
+
+// DefaultMaxPixels limits size of decoded image, about 200MB of RGBA pixels
+const DefaultMaxPixels = 50_000_000
+
+var ErrTooManyPixels = errors.New("image has too many pixels")
+
+// Decode decodes jpeg, png or gif image. Dimensions are checked before decoding, so
+// small file that expands to huge image returns ErrTooManyPixels instead of taking all memory.
+func Decode(data []byte, maxPixels int) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, image.ErrFormat
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// Fit scales image down so that its longest side is at most size, keeping aspect ratio.
+// Smaller images are not scaled up. Pixels are averaged over the area they cover,
+// which keeps downscaled image sharp without aliasing.
+func Fit(src image.Image, size int) *image.RGBA {
+	rgba := toRGBA(src)
+	w, h := fitSize(rgba.Rect.Dx(), rgba.Rect.Dy(), size)
+	if w == rgba.Rect.Dx() && h == rgba.Rect.Dy() {
+		return rgba
+	}
+	return resize(rgba, w, h)
+}
+
+// EncodeJPEG encodes image flattened onto white background, as jpeg has no transparency
+func EncodeJPEG(img image.Image, quality int) ([]byte, error) {
+	flat := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(flat, flat.Rect, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Rect, img, img.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func fitSize(w, h, size int) (int, int) {
+	if w <= size && h <= size {
+		return w, h
+	}
+	if w >= h {
+		return size, max(1, (h*size+w/2)/w)
+	}
+	return max(1, (w*size+h/2)/h), size
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Rect, src, b.Min, draw.Src)
+	return rgba
+}
+
+// resize averages source pixels falling into every destination pixel. Premultiplied alpha
+// of image.RGBA keeps transparent pixels from bleeding their color into neighbours.
+func resize(src *image.RGBA, w, h int) *image.RGBA {
+	var (
+		dst  = image.NewRGBA(image.Rect(0, 0, w, h))
+		srcW = src.Rect.Dx()
+		srcH = src.Rect.Dy()
+	)
+	for y := 0; y < h; y++ {
+		y0, y1 := span(y, h, srcH)
+		for x := 0; x < w; x++ {
+			x0, x1 := span(x, w, srcW)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride+x0*4 : sy*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += uint64(row[i])
+					g += uint64(row[i+1])
+					b += uint64(row[i+2])
+					a += uint64(row[i+3])
+				}
+				n += uint64(x1 - x0)
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8((r + n/2) / n)
+			dst.Pix[i+1] = uint8((g + n/2) / n)
+			dst.Pix[i+2] = uint8((b + n/2) / n)
+			dst.Pix[i+3] = uint8((a + n/2) / n)
+		}
+	}
+	return dst
+}
+
+// span returns source pixels [from, to) covered by destination pixel i, at least one
+func span(i, dstSize, srcSize int) (int, int) {
+	from := i * srcSize / dstSize
+	to := (i + 1) * srcSize / dstSize
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,91 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFit(t *testing.T) {
+	t.Run("should keep aspect ratio of landscape and portrait images", func(t *testing.T) {
+		for _, tc := range []struct {
+			w, h, size   int
+			wantW, wantH int
+		}{
+			{w: 1600, h: 1200, size: 800, wantW: 800, wantH: 600},
+			{w: 1200, h: 1600, size: 200, wantW: 150, wantH: 200},
+			{w: 1000, h: 1, size: 100, wantW: 100, wantH: 1},
+		} {
+			dst := Fit(image.NewRGBA(image.Rect(0, 0, tc.w, tc.h)), tc.size)
+			require.Equal(t, tc.wantW, dst.Bounds().Dx())
+			require.Equal(t, tc.wantH, dst.Bounds().Dy())
+		}
+	})
+
+	t.Run("should not scale up small image", func(t *testing.T) {
+		src := image.NewNRGBA(image.Rect(10, 10, 60, 40))
+		dst := Fit(src, 800)
+		require.Equal(t, image.Rect(0, 0, 50, 30), dst.Bounds())
+	})
+
+	t.Run("should average pixels of the area", func(t *testing.T) {
+		// Black and white stripes turn gray
+		src := image.NewGray(image.Rect(0, 0, 100, 100))
+		for y := 0; y < 100; y++ {
+			for x := 0; x < 100; x += 2 {
+				src.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+		dst := Fit(src, 10)
+		for y := 0; y < 10; y++ {
+			for x := 0; x < 10; x++ {
+				c := dst.RGBAAt(x, y)
+				require.InDelta(t, 128, int(c.R), 1)
+				require.Equal(t, uint8(255), c.A)
+			}
+		}
+	})
+}
+
+func TestDecode(t *testing.T) {
+	encode := func(img image.Image) []byte {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, img))
+		return buf.Bytes()
+	}
+
+	t.Run("should decode image", func(t *testing.T) {
+		img, err := Decode(encode(image.NewRGBA(image.Rect(0, 0, 30, 20))), DefaultMaxPixels)
+		require.NoError(t, err)
+		require.Equal(t, 30, img.Bounds().Dx())
+	})
+
+	t.Run("should return ErrTooManyPixels", func(t *testing.T) {
+		_, err := Decode(encode(image.NewGray(image.Rect(0, 0, 300, 200))), 300*200-1)
+		require.ErrorIs(t, err, ErrTooManyPixels)
+	})
+
+	t.Run("should return error because data is not an image", func(t *testing.T) {
+		_, err := Decode([]byte("GIF89a but not really"), DefaultMaxPixels)
+		require.Error(t, err)
+	})
+}
+
+func TestEncodeJPEG(t *testing.T) {
+	t.Run("should flatten transparent pixels onto white", func(t *testing.T) {
+		data, err := EncodeJPEG(image.NewNRGBA(image.Rect(0, 0, 16, 16)), 90)
+		require.NoError(t, err)
+
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		r, g, b, _ := img.At(8, 8).RGBA()
+		require.Greater(t, r>>8, uint32(250))
+		require.Greater(t, g>>8, uint32(250))
+		require.Greater(t, b>>8, uint32(250))
+	})
+}
@@ -0,0 +1,47 @@
+package permission_cache
+
+import (
+	"sync"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+)
+
+// PermissionCache keeps permissions of roles in memory, so that they're checked without a trip to storage
+type PermissionCache struct {
+	mu    sync.RWMutex
+	roles map[domain.Role]map[domain.Permission]struct{}
+}
+
+func NewPermissionCache() *PermissionCache {
+	return &PermissionCache{
+		roles: make(map[domain.Role]map[domain.Permission]struct{}),
+	}
+}
+
+// Has checks if role has every one of permissions. Role that has not been set has none.
+func (p *PermissionCache) Has(role domain.Role, permissions ...domain.Permission) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	granted := p.roles[role]
+	for _, permission := range permissions {
+		if _, ok := granted[permission]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Set replaces permissions of the roles. Permissions of other roles are kept.
+func (p *PermissionCache) Set(rolePermissions ...domain.RolePermissions) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, rp := range rolePermissions {
+		granted := make(map[domain.Permission]struct{}, len(rp.Permissions))
+		for _, permission := range rp.Permissions {
+			granted[permission] = struct{}{}
+		}
+		p.roles[rp.Role] = granted
+	}
+}
@@ -0,0 +1,38 @@
+package permission_cache
+
+import (
+	"testing"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionCache(t *testing.T) {
+
+	t.Run("should check every permission", func(t *testing.T) {
+		cache := NewPermissionCache()
+		cache.Set(domain.RolePermissions{
+			Role:        domain.RoleWorker,
+			Permissions: []domain.Permission{domain.PermissionOrdersView, domain.PermissionOrdersCancel},
+		})
+
+		require.True(t, cache.Has(domain.RoleWorker, domain.PermissionOrdersView))
+		require.True(t, cache.Has(domain.RoleWorker, domain.PermissionOrdersView, domain.PermissionOrdersCancel))
+		require.False(t, cache.Has(domain.RoleWorker, domain.PermissionOrdersView, domain.PermissionMetaEdit))
+		// Ranks are gone, admin has nothing it's not granted
+		require.False(t, cache.Has(domain.RoleAdmin, domain.PermissionOrdersView))
+	})
+
+	t.Run("should replace permissions of the role only", func(t *testing.T) {
+		cache := NewPermissionCache()
+		cache.Set(
+			domain.RolePermissions{Role: domain.RoleWorker, Permissions: []domain.Permission{domain.PermissionOrdersView}},
+			domain.RolePermissions{Role: domain.RoleAdmin, Permissions: []domain.Permission{domain.PermissionMetaEdit}},
+		)
+		cache.Set(domain.RolePermissions{Role: domain.RoleWorker, Permissions: []domain.Permission{domain.PermissionOrdersCancel}})
+
+		require.False(t, cache.Has(domain.RoleWorker, domain.PermissionOrdersView))
+		require.True(t, cache.Has(domain.RoleWorker, domain.PermissionOrdersCancel))
+		require.True(t, cache.Has(domain.RoleAdmin, domain.PermissionMetaEdit))
+	})
+}
@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often buckets that have refilled are removed, so that memory doesn't grow with every key seen
+const pruneInterval = time.Minute
+
+// Limit allows Requests per Period on average with bursts of up to Burst requests. Requests must be positive.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	// Requests is used if Burst is zero
+	Burst int
+}
+
+func (l Limit) burst() float64 {
+	if l.Burst <= 0 {
+		return float64(l.Requests)
+	}
+	return float64(l.Burst)
+}
+
+// perToken is time it takes to refill one token
+func (l Limit) perToken() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+type bucket struct {
+	tokens float64
+	// Time tokens have been counted at
+	updatedAt time.Time
+}
+
+// Limiter is in-memory token bucket rate limiter keeping a bucket per key.
+// Every instance limits requests it receives on its own.
+type Limiter struct {
+	limit Limit
+
+	mu       sync.Mutex
+	buckets  map[string]*bucket
+	prunedAt time.Time
+
+	now func() time.Time
+}
+
+func NewLimiter(limit Limit) *Limiter {
+	return &Limiter{
+		limit:    limit,
+		buckets:  make(map[string]*bucket),
+		prunedAt: time.Now(),
+		now:      time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of key. If bucket is empty, it returns false
+// and time after which the next token is available.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.prunedAt) >= pruneInterval {
+		l.prune(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.limit.burst(), updatedAt: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.updatedAt = now
+
+	if b.tokens < 1 {
+		retryAfter := time.Duration((1 - b.tokens) * float64(l.limit.perToken()))
+		return false, retryAfter
+	}
+	b.tokens--
+	return true, 0
+}
+
+func (l *Limiter) refill(b *bucket, now time.Time) float64 {
+	tokens := b.tokens + float64(now.Sub(b.updatedAt))/float64(l.limit.perToken())
+	if burst := l.limit.burst(); tokens > burst {
+		return burst
+	}
+	return tokens
+}
+
+// prune removes full buckets, which are no different from the missing ones
+func (l *Limiter) prune(now time.Time) {
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= l.limit.burst() {
+			delete(l.buckets, key)
+		}
+	}
+	l.prunedAt = now
+}
+
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func TestLimiter(t *testing.T) {
+	newLimiter := func(limit Limit) (*Limiter, *clock) {
+		c := &clock{t: time.Now()}
+		l := NewLimiter(limit)
+		l.now = c.now
+		l.prunedAt = c.t
+		return l, c
+	}
+
+	t.Run("should allow burst and then one request per token refill", func(t *testing.T) {
+		l, c := newLimiter(Limit{Requests: 6, Period: time.Minute, Burst: 3})
+
+		for i := 0; i < 3; i++ {
+			ok, _ := l.Allow("ip:1")
+			require.True(t, ok)
+		}
+		ok, retryAfter := l.Allow("ip:1")
+		require.False(t, ok)
+		require.Equal(t, time.Second*10, retryAfter)
+
+		c.advance(time.Second * 4)
+		ok, retryAfter = l.Allow("ip:1")
+		require.False(t, ok)
+		require.Equal(t, time.Second*6, retryAfter)
+
+		c.advance(time.Second * 6)
+		ok, _ = l.Allow("ip:1")
+		require.True(t, ok)
+	})
+
+	t.Run("should keep bucket per key", func(t *testing.T) {
+		l, _ := newLimiter(Limit{Requests: 1, Period: time.Minute})
+
+		ok, _ := l.Allow("ip:1")
+		require.True(t, ok)
+		ok, _ = l.Allow("ip:1")
+		require.False(t, ok)
+		ok, _ = l.Allow("ip:2")
+		require.True(t, ok)
+	})
+
+	t.Run("should not refill above burst", func(t *testing.T) {
+		l, c := newLimiter(Limit{Requests: 2, Period: time.Minute})
+
+		c.advance(time.Hour)
+		for i := 0; i < 2; i++ {
+			ok, _ := l.Allow("ip:1")
+			require.True(t, ok)
+		}
+		ok, _ := l.Allow("ip:1")
+		require.False(t, ok)
+	})
+
+	t.Run("should prune refilled buckets", func(t *testing.T) {
+		l, c := newLimiter(Limit{Requests: 1, Period: time.Minute})
+
+		l.Allow("ip:1")
+		l.Allow("ip:2")
+		require.Equal(t, 2, l.Len())
+
+		c.advance(pruneInterval)
+		l.Allow("ip:3")
+		require.Equal(t, 1, l.Len())
+	})
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// LockoutPolicy locks key out for Duration once it fails MaxFailures times in a row.
+// Every failure after that doubles the duration up to MaxDuration.
+type LockoutPolicy struct {
+	MaxFailures int
+	Duration    time.Duration
+	MaxDuration time.Duration
+}
+
+type lockoutEntry struct {
+	failures    int
+	failedAt    time.Time
+	lockedUntil time.Time
+}
+
+// Lockout counts consecutive failures of keys in memory. Failures are forgotten
+// after MaxDuration without new ones.
+type Lockout struct {
+	policy LockoutPolicy
+
+	mu       sync.Mutex
+	entries  map[string]*lockoutEntry
+	prunedAt time.Time
+
+	now func() time.Time
+}
+
+func NewLockout(policy LockoutPolicy) *Lockout {
+	return &Lockout{
+		policy:   policy,
+		entries:  make(map[string]*lockoutEntry),
+		prunedAt: time.Now(),
+		now:      time.Now,
+	}
+}
+
+// Locked returns true and time left if key is locked out
+func (l *Lockout) Locked(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok {
+		return false, 0
+	}
+	if left := entry.lockedUntil.Sub(l.now()); left > 0 {
+		return true, left
+	}
+	return false, 0
+}
+
+// Fail counts failure of key. It returns duration key is locked out for, zero if it's not.
+func (l *Lockout) Fail(key string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.prunedAt) >= pruneInterval {
+		l.prune(now)
+	}
+
+	entry, ok := l.entries[key]
+	if !ok || l.forgotten(entry, now) {
+		entry = new(lockoutEntry)
+		l.entries[key] = entry
+	}
+	entry.failures++
+	entry.failedAt = now
+
+	if entry.failures < l.policy.MaxFailures {
+		return 0
+	}
+	duration := l.policy.Duration
+	for i := l.policy.MaxFailures; i < entry.failures && duration < l.policy.MaxDuration; i++ {
+		duration *= 2
+	}
+	if duration > l.policy.MaxDuration {
+		duration = l.policy.MaxDuration
+	}
+	entry.lockedUntil = now.Add(duration)
+	return duration
+}
+
+// Reset forgets failures of key, e.g. after successful login
+func (l *Lockout) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.entries, key)
+}
+
+func (l *Lockout) forgotten(entry *lockoutEntry, now time.Time) bool {
+	return now.After(entry.lockedUntil) && now.Sub(entry.failedAt) > l.policy.MaxDuration
+}
+
+func (l *Lockout) prune(now time.Time) {
+	for key, entry := range l.entries {
+		if l.forgotten(entry, now) {
+			delete(l.entries, key)
+		}
+	}
+	l.prunedAt = now
+}
+
+func (l *Lockout) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
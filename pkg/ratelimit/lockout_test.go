@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockout(t *testing.T) {
+	policy := LockoutPolicy{
+		MaxFailures: 3,
+		Duration:    time.Minute,
+		MaxDuration: time.Minute * 5,
+	}
+	newLockout := func() (*Lockout, *clock) {
+		c := &clock{t: time.Now()}
+		l := NewLockout(policy)
+		l.now = c.now
+		l.prunedAt = c.t
+		return l, c
+	}
+
+	t.Run("should lock out progressively after max failures", func(t *testing.T) {
+		l, c := newLockout()
+
+		require.Zero(t, l.Fail("admin"))
+		require.Zero(t, l.Fail("admin"))
+		locked, _ := l.Locked("admin")
+		require.False(t, locked)
+
+		require.Equal(t, time.Minute, l.Fail("admin"))
+		locked, left := l.Locked("admin")
+		require.True(t, locked)
+		require.Equal(t, time.Minute, left)
+
+		c.advance(time.Minute)
+		locked, _ = l.Locked("admin")
+		require.False(t, locked)
+
+		require.Equal(t, time.Minute*2, l.Fail("admin"))
+		require.Equal(t, time.Minute*4, l.Fail("admin"))
+		require.Equal(t, time.Minute*5, l.Fail("admin"))
+		require.Equal(t, time.Minute*5, l.Fail("admin"))
+	})
+
+	t.Run("should forget failures on reset", func(t *testing.T) {
+		l, _ := newLockout()
+
+		l.Fail("admin")
+		l.Fail("admin")
+		l.Reset("admin")
+		require.Zero(t, l.Fail("admin"))
+	})
+
+	t.Run("should forget failures after max duration", func(t *testing.T) {
+		l, c := newLockout()
+
+		l.Fail("admin")
+		l.Fail("admin")
+		c.advance(policy.MaxDuration + time.Second)
+		require.Zero(t, l.Fail("admin"))
+		require.Equal(t, 1, l.Len())
+	})
+}
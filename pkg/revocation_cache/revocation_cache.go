@@ -0,0 +1,64 @@
+package revocation_cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+)
+
+// RevocationCache keeps revocations in memory, so that access token is checked without a trip to storage
+type RevocationCache struct {
+	mu          sync.RWMutex
+	revocations map[string]domain.Revocation
+}
+
+func NewRevocationCache() *RevocationCache {
+	return &RevocationCache{
+		revocations: make(map[string]domain.Revocation),
+	}
+}
+
+// Get returns revocation by key unless it has expired
+func (r *RevocationCache) Get(key string) (domain.Revocation, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revocation, ok := r.revocations[key]
+	if !ok || revocation.IsExpiredAt(time.Now().UTC()) {
+		return domain.Revocation{}, false
+	}
+	return revocation, true
+}
+
+// Set adds revocations to cache. Revocation of the same key is replaced only by a later one.
+func (r *RevocationCache) Set(revocations ...domain.Revocation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, revocation := range revocations {
+		cached, ok := r.revocations[revocation.Key]
+		if ok && cached.RevokedAt.After(revocation.RevokedAt) {
+			continue
+		}
+		r.revocations[revocation.Key] = revocation
+	}
+}
+
+// Prune removes revocations that have expired at t
+func (r *RevocationCache) Prune(t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, revocation := range r.revocations {
+		if revocation.IsExpiredAt(t) {
+			delete(r.revocations, key)
+		}
+	}
+}
+
+func (r *RevocationCache) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.revocations)
+}
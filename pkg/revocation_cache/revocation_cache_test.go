@@ -0,0 +1,84 @@
+package revocation_cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevocationCache(t *testing.T) {
+	t.Run("should set and get revocation", func(t *testing.T) {
+		cache := NewRevocationCache()
+		revocation := domain.Revocation{
+			Key:       domain.TokenRevocationKey("jti"),
+			RevokedAt: time.Now().UTC(),
+			ExpiresAt: time.Now().UTC().Add(time.Minute),
+		}
+		cache.Set(revocation)
+
+		cached, ok := cache.Get(revocation.Key)
+		require.True(t, ok)
+		require.Equal(t, revocation, cached)
+
+		_, ok = cache.Get(domain.TokenRevocationKey("another jti"))
+		require.False(t, ok)
+	})
+
+	t.Run("should not return expired revocation and prune it", func(t *testing.T) {
+		cache := NewRevocationCache()
+		key := domain.UserRevocationKey(domain.RoleWorker, "worker id")
+		cache.Set(domain.Revocation{
+			Key:       key,
+			RevokedAt: time.Now().UTC().Add(-time.Hour),
+			ExpiresAt: time.Now().UTC().Add(-time.Second),
+		})
+
+		_, ok := cache.Get(key)
+		require.False(t, ok)
+
+		cache.Prune(time.Now().UTC())
+		require.Zero(t, cache.Len())
+	})
+
+	t.Run("should keep the latest revocation of the key", func(t *testing.T) {
+		cache := NewRevocationCache()
+		var (
+			key     = domain.UserRevocationKey(domain.RoleCustomer, "customer id")
+			now     = time.Now().UTC()
+			earlier = domain.Revocation{Key: key, RevokedAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour)}
+			later   = domain.Revocation{Key: key, RevokedAt: now, ExpiresAt: now.Add(time.Hour)}
+		)
+
+		cache.Set(later)
+		// E.g. stale revocation reloaded from storage
+		cache.Set(earlier)
+
+		cached, ok := cache.Get(key)
+		require.True(t, ok)
+		require.Equal(t, later, cached)
+	})
+
+	t.Run("concurrent set and get", func(t *testing.T) {
+		cache := NewRevocationCache()
+		wg := new(sync.WaitGroup)
+		revocation := domain.Revocation{
+			Key:       domain.SessionRevocationKey("session id"),
+			RevokedAt: time.Now().UTC(),
+			ExpiresAt: time.Now().UTC().Add(time.Minute),
+		}
+
+		wg.Add(100)
+		for i := 0; i < 100; i++ {
+			go func() {
+				defer wg.Done()
+				cache.Set(revocation)
+				_, ok := cache.Get(revocation.Key)
+				require.True(t, ok)
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,82 @@
+package sms
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Message is SMS written by FileSender
+type Message struct {
+	PhoneNumber string    `json:"phoneNumber"`
+	Text        string    `json:"text"`
+	SentAt      time.Time `json:"sentAt"`
+}
+
+// LogSender writes messages to application log instead of sending them. Used in development.
+type LogSender struct{}
+
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+func (l LogSender) Send(ctx context.Context, phoneNumber, text string) error {
+	logger.Get().Info("sms is sent",
+		zap.String("phoneNumber", phoneNumber),
+		zap.String("text", text),
+	)
+	return nil
+}
+
+// FileSender appends messages to file, one JSON per line. Used in development and tests to read sent codes.
+type FileSender struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileSender(path string) *FileSender {
+	return &FileSender{path: path}
+}
+
+func (f *FileSender) Send(ctx context.Context, phoneNumber, text string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewEncoder(file).Encode(Message{
+		PhoneNumber: phoneNumber,
+		Text:        text,
+		SentAt:      time.Now().UTC(),
+	})
+}
+
+// ReadMessages returns messages written by FileSender in order they were sent
+func ReadMessages(path string) ([]Message, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var messages []Message
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var message Message
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, scanner.Err()
+}
@@ -0,0 +1,26 @@
+package sms
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSender(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sms.log")
+	sender := NewFileSender(path)
+
+	require.NoError(t, sender.Send(context.Background(), "+79128557826", "first"))
+	require.NoError(t, sender.Send(context.Background(), "+79128557827", "second"))
+
+	messages, err := ReadMessages(path)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	require.Equal(t, "+79128557826", messages[0].PhoneNumber)
+	require.Equal(t, "first", messages[0].Text)
+	require.Equal(t, "+79128557827", messages[1].PhoneNumber)
+	require.Equal(t, "second", messages[1].Text)
+	require.False(t, messages[1].SentAt.IsZero())
+}
@@ -6,12 +6,15 @@ CONTAINER_NAME="debug_mongo-e2e"
 APP_SRC=$(cat .env | grep "APP_SRC" | cut -d "=" -f2)
 DB_NAME="testdb"
 
-export MONGO_URI=mongodb://localhost:$MONGO_TEST_PORT
+# member host is the container name, connect to it directly
+export MONGO_URI="mongodb://localhost:$MONGO_TEST_PORT/?directConnection=true"
 export DB_NAME=$DB_NAME
 # run mongo
-CONTAINER_ID=$(docker run --rm -d -p $MONGO_TEST_PORT:27017 --name=$CONTAINER_NAME -e MONGODB_DATABASE=$DB_NAME $MONGO_IMAGE)
+CONTAINER_ID=$(docker run --rm -d -p $MONGO_TEST_PORT:27017 --name=$CONTAINER_NAME -e MONGODB_DATABASE=$DB_NAME $MONGO_IMAGE --replSet rs0)
+# transactions require a replica set, wait for mongo to become primary
+until docker exec $CONTAINER_NAME mongosh --quiet --eval "rs.initiate(); while (!db.hello().isWritablePrimary) sleep(100)" > /dev/null 2>&1; do sleep 1; done
 # run migrations
-docker run -v $APP_SRC/migrations:/migrations --network host --rm migrate/migrate -path=/migrations/ -database $MONGO_URI/$DB_NAME up
+docker run -v $APP_SRC/migrations:/migrations --network host --rm migrate/migrate -path=/migrations/ -database "mongodb://localhost:$MONGO_TEST_PORT/$DB_NAME?directConnection=true" up
 # run tests
 dlv test ./tests/
 
@@ -1,16 +1,30 @@
 package tests
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	f "github.com/brianvoe/gofakeit/v6"
 	"github.com/google/uuid"
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
 	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
+	"github.com/sonyamoonglade/sancho-backend/pkg/hash"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func (s *APISuite) TestCreateProduct() {
@@ -95,7 +109,7 @@ func (s *APISuite) TestDeleteProduct() {
 	)
 
 	t.Run("should delete product", func(t *testing.T) {
-		var productForDeletion = products[0].(domain.Product)
+		var productForDeletion = products[2].(domain.Product)
 		url := fmt.Sprintf("/api/admins/products/%s/delete", productForDeletion.ProductID.Hex())
 		req, _ := http.NewRequest(http.MethodDelete, buildURL(url), nil)
 		tokens, _ := s.tokenProvider.GenerateNewPair(auth.UserAuth{
@@ -133,7 +147,7 @@ func (s *APISuite) TestApproveProduct() {
 	)
 
 	t.Run("should approve product because it exists and not approved yet", func(t *testing.T) {
-		var existingProduct = products[0].(domain.Product)
+		var existingProduct = products[2].(domain.Product)
 		require.False(existingProduct.IsApproved)
 		url := fmt.Sprintf("/api/admins/products/%s/approve", existingProduct.ProductID.Hex())
 		req, _ := http.NewRequest(http.MethodPut, buildURL(url), nil)
@@ -290,7 +304,6 @@ func (s *APISuite) TestUpdateProduct() {
 			Name:        StringPtr(f.LoremIpsumSentence(15)),
 			TranslateRU: StringPtr(f.LoremIpsumSentence(12)),
 			Description: StringPtr(f.LoremIpsumSentence(30)),
-			ImageURL:    StringPtr(f.ImageURL(200, 599)),
 			Price:       IntPtr(int64(f.IntRange(500, 1000))),
 		}
 		url := fmt.Sprintf("/api/admins/products/%s/update", productID)
@@ -312,7 +325,6 @@ func (s *APISuite) TestUpdateProduct() {
 
 		require.Equal(*updateBody.Price, product.Price)
 		require.Equal(*updateBody.Name, product.Name)
-		require.Equal(*updateBody.ImageURL, *product.ImageURL)
 		require.Equal(*updateBody.TranslateRU, product.TranslateRU)
 		require.Equal(*updateBody.Description, product.Description)
 	})
@@ -323,7 +335,6 @@ func (s *APISuite) TestUpdateProduct() {
 			Name:        StringPtr(f.Word()),
 			TranslateRU: StringPtr(f.Word()),
 			Description: StringPtr(f.Word()),
-			ImageURL:    StringPtr(f.ImageURL(200, 599)),
 			Price:       IntPtr(int64(f.IntRange(500, 1000))),
 		}
 		url := fmt.Sprintf("/api/admins/products/%s/update", randomID)
@@ -410,3 +421,775 @@ func getNonLiquidFeatures() domain.Features {
 		},
 	}
 }
+
+func (s *APISuite) TestGetMeta() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	t.Run("should return meta", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, buildURL("/api/admins/meta"), nil)
+		req.Header.Set("Authorization", "Bearer "+newAccessToken(s.tokenProvider, uuid.NewString(), domain.RoleAdmin))
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var out domain.BusinessMeta
+		require.NoError(json.Unmarshal(readBody(res.Body), &out))
+	})
+
+	t.Run("should not let worker read meta", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, buildURL("/api/admins/meta"), nil)
+		req.Header.Set("Authorization", "Bearer "+newAccessToken(s.tokenProvider, uuid.NewString(), domain.RoleWorker))
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		require.Equal(http.StatusForbidden, res.StatusCode)
+	})
+}
+
+func (s *APISuite) TestDeliveryZones() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	adminToken := newAccessToken(s.tokenProvider, uuid.NewString(), domain.RoleAdmin)
+	createZone := func(inp input.DeliveryZoneInput) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, buildURL("/api/admins/delivery-zones/create"), newBody(inp))
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+	inp := input.DeliveryZoneInput{
+		Name: "Челябинск",
+		Area: domain.NewGeoPolygon([][]float64{
+			{61.30, 55.10},
+			{61.50, 55.10},
+			{61.50, 55.20},
+			{61.30, 55.20},
+			{61.30, 55.10},
+		}),
+		Fee:                   300,
+		FreeDeliveryThreshold: 3000,
+		MinOrderAmount:        1000,
+		ETAMinutes:            90,
+	}
+
+	t.Run("should create delivery zone and list it", func(t *testing.T) {
+		res := createZone(inp)
+		require.Equal(http.StatusCreated, res.StatusCode)
+
+		var created struct {
+			DeliveryZoneID string `json:"deliveryZoneId"`
+		}
+		require.NoError(json.Unmarshal(readBody(res.Body), &created))
+		// Other tests rely on the only zone from data.go
+		defer func() {
+			require.NoError(s.services.DeliveryZone.Delete(context.Background(), created.DeliveryZoneID))
+		}()
+
+		req, _ := http.NewRequest(http.MethodGet, buildURL("/api/delivery/zones"), nil)
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var zones []domain.DeliveryZone
+		require.NoError(json.Unmarshal(readBody(res.Body), &zones))
+		var found bool
+		for _, zone := range zones {
+			if zone.DeliveryZoneID.Hex() == created.DeliveryZoneID {
+				found = true
+				require.Equal(inp.Name, zone.Name)
+				require.Equal(inp.Area, zone.Area)
+				require.Equal(inp.Fee, zone.Fee)
+			}
+		}
+		require.True(found)
+	})
+
+	t.Run("should not create delivery zone because area ring is not closed", func(t *testing.T) {
+		invalid := inp
+		invalid.Area = domain.NewGeoPolygon(inp.Area.Coordinates[0][:4])
+		res := createZone(invalid)
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func (s *APISuite) TestUpdateSchedule() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	adminToken := newAccessToken(s.tokenProvider, uuid.NewString(), domain.RoleAdmin)
+	updateSchedule := func(inp input.UpdateScheduleInput) *http.Response {
+		req, _ := http.NewRequest(http.MethodPut, buildURL("/api/admins/meta/schedule"), newBody(inp))
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+	// Other tests rely on store being always open
+	defer updateSchedule(input.UpdateScheduleInput{Timezone: "UTC"})
+
+	t.Run("should close store for today", func(t *testing.T) {
+		var (
+			today    = time.Now().UTC()
+			tomorrow = today.Add(time.Hour * 24)
+		)
+		res := updateSchedule(input.UpdateScheduleInput{
+			Timezone: "UTC",
+			Overrides: []domain.ScheduleOverride{
+				{Date: today.Format(domain.DateLayout), IsClosed: true},
+				{Date: tomorrow.Format(domain.DateLayout), Open: "10:00", Close: "18:00"},
+			},
+		})
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		req, _ := http.NewRequest(http.MethodGet, buildURL("/api/schedule"), nil)
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var out struct {
+			IsOpen  bool       `json:"isOpen"`
+			OpensAt *time.Time `json:"opensAt"`
+		}
+		require.NoError(json.Unmarshal(readBody(res.Body), &out))
+		require.False(out.IsOpen)
+		require.NotNil(out.OpensAt)
+		require.Equal(tomorrow.Format(domain.DateLayout), out.OpensAt.Format(domain.DateLayout))
+		require.Equal(10, out.OpensAt.Hour())
+
+		cartProduct := products[1].(domain.Product)
+		inp := input.CreateUserOrderInput{
+			Pay: domain.PayOnPickup,
+			Cart: []input.CartProductInput{
+				{ProductID: cartProduct.ProductID.Hex(), Quantity: 1},
+			},
+		}
+		accessToken := newAccessToken(s.tokenProvider, customer.UserID.Hex(), customer.Role)
+		req = newRequest("/api/order/create", http.MethodPost, accessToken, newBody(inp))
+		res, err = s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		require.Equal(http.StatusConflict, res.StatusCode)
+	})
+
+	t.Run("should update only schedule of meta", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := s.db.Collection(storage.CollectionMeta).UpdateOne(ctx,
+			bson.M{"_id": "business"},
+			bson.M{"$set": bson.M{"deliveryPunishmentThreshold": meta.DeliveryPunishmentThreshold}},
+			options.Update().SetUpsert(true),
+		)
+		require.NoError(err)
+
+		res := updateSchedule(input.UpdateScheduleInput{Timezone: "Asia/Yekaterinburg"})
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		saved, err := s.storages.Meta.Get(ctx)
+		require.NoError(err)
+		require.Equal("Asia/Yekaterinburg", saved.Schedule.Timezone)
+		require.Equal(meta.DeliveryPunishmentThreshold, saved.DeliveryPunishmentThreshold)
+	})
+
+	t.Run("should update schedule with hours spanning past midnight", func(t *testing.T) {
+		res := updateSchedule(input.UpdateScheduleInput{
+			Timezone: "UTC",
+			Weekly:   []domain.WorkingHours{{Weekday: time.Friday, Open: "18:00", Close: "02:00"}},
+		})
+		require.Equal(http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("should not update schedule because store opens and closes at the same time", func(t *testing.T) {
+		res := updateSchedule(input.UpdateScheduleInput{
+			Timezone: "UTC",
+			Weekly:   []domain.WorkingHours{{Weekday: time.Friday, Open: "18:00", Close: "18:00"}},
+		})
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func (s *APISuite) TestWorkers() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	adminToken := newAccessToken(s.tokenProvider, uuid.NewString(), domain.RoleAdmin)
+	adminRequest := func(method, url string, body interface{}) *http.Response {
+		req, _ := http.NewRequest(method, buildURL(url), newBody(body))
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+	login := func(login, password string) *http.Response {
+		req := newRequest("/api/auth/worker/login", http.MethodPost, "", newBody(input.LoginInput{
+			Login:    login,
+			Password: password,
+		}))
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+	inp := input.CreateWorkerInput{
+		Name:     "Анна",
+		Login:    "anna.w",
+		Password: "anna_password",
+	}
+
+	t.Run("should create worker, log in, reset password and disable", func(t *testing.T) {
+		res := adminRequest(http.MethodPost, "/api/admins/workers/create", inp)
+		require.Equal(http.StatusCreated, res.StatusCode)
+		var created struct {
+			WorkerID string `json:"workerId"`
+		}
+		require.NoError(json.Unmarshal(readBody(res.Body), &created))
+		defer s.db.Collection(storage.CollectionAdminsAndWorkers).DeleteOne(context.Background(), bson.M{"_id": storage.ToObjectID(created.WorkerID)}) //nolint:errcheck
+
+		// Login is unique
+		res = adminRequest(http.MethodPost, "/api/admins/workers/create", inp)
+		require.Equal(http.StatusConflict, res.StatusCode)
+
+		res = adminRequest(http.MethodGet, "/api/admins/workers/", nil)
+		require.Equal(http.StatusOK, res.StatusCode)
+		var workers []domain.Worker
+		require.NoError(json.Unmarshal(readBody(res.Body), &workers))
+		require.Len(workers, 1)
+		require.Equal(inp.Login, workers[0].Login)
+		require.Zero(workers[0].Password)
+
+		res = login(inp.Login, inp.Password)
+		require.Equal(http.StatusOK, res.StatusCode)
+		var tokens struct {
+			AccessToken  string `json:"accessToken"`
+			RefreshToken string `json:"refreshToken"`
+		}
+		require.NoError(json.Unmarshal(readBody(res.Body), &tokens))
+		userAuth, err := s.tokenProvider.ParseAndValidate(tokens.AccessToken)
+		require.NoError(err)
+		require.Equal(domain.RoleWorker, userAuth.Role)
+		require.Equal(created.WorkerID, userAuth.UserID)
+
+		res = login(inp.Login, "wrong password")
+		require.Equal(http.StatusUnauthorized, res.StatusCode)
+
+		newPassword := "new_anna_password"
+		res = adminRequest(http.MethodPut, "/api/admins/workers/"+created.WorkerID+"/password", input.ResetWorkerPasswordInput{
+			Password: newPassword,
+		})
+		require.Equal(http.StatusOK, res.StatusCode)
+		// Worker is logged out
+		_, err = s.services.Auth.RefreshTokens(context.Background(), dto.RefreshTokensDTO{
+			Role:         domain.RoleWorker,
+			UserID:       created.WorkerID,
+			RefreshToken: tokens.RefreshToken,
+		})
+		require.ErrorIs(err, domain.ErrSessionNotFound)
+		res = login(inp.Login, inp.Password)
+		require.Equal(http.StatusUnauthorized, res.StatusCode)
+		res = login(inp.Login, newPassword)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		res = adminRequest(http.MethodPut, "/api/admins/workers/"+created.WorkerID+"/disable", nil)
+		require.Equal(http.StatusOK, res.StatusCode)
+		res = login(inp.Login, newPassword)
+		require.Equal(http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("should return 404 because worker does not exist", func(t *testing.T) {
+		res := adminRequest(http.MethodPut, "/api/admins/workers/"+primitive.NewObjectID().Hex()+"/disable", nil)
+		require.Equal(http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("should return 400 because password is too short", func(t *testing.T) {
+		invalid := inp
+		invalid.Password = "short"
+		res := adminRequest(http.MethodPost, "/api/admins/workers/create", invalid)
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func (s *APISuite) TestAdminLoginRehashesLegacyPassword() {
+	var (
+		require  = s.Require()
+		ctx      = context.Background()
+		password = "admin_password"
+	)
+
+	legacyHash, err := hash.NewSHA1Hasher().Hash(password)
+	require.NoError(err)
+	admin := domain.Admin{
+		UserID:   primitive.NewObjectID(),
+		Login:    "legacy.admin",
+		Role:     domain.RoleAdmin,
+		Password: legacyHash,
+	}
+	_, err = s.db.Collection(storage.CollectionAdminsAndWorkers).InsertOne(ctx, admin)
+	require.NoError(err)
+	defer s.db.Collection(storage.CollectionAdminsAndWorkers).DeleteOne(ctx, bson.M{"_id": admin.UserID}) //nolint:errcheck
+
+	req := newRequest("/api/auth/admin/login", http.MethodPost, "", newBody(input.LoginInput{
+		Login:    admin.Login,
+		Password: password,
+	}))
+	res, err := s.app.Test(req, -1)
+	require.NoError(err)
+	printResponseDetails(res)
+	require.Equal(http.StatusOK, res.StatusCode)
+
+	rehashed, err := s.services.User.GetAdminByLogin(ctx, admin.Login)
+	require.NoError(err)
+	require.True(strings.HasPrefix(rehashed.Password, "$argon2id$"))
+
+	// Password still works with the new hash
+	req = newRequest("/api/auth/admin/login", http.MethodPost, "", newBody(input.LoginInput{
+		Login:    admin.Login,
+		Password: password,
+	}))
+	res, err = s.app.Test(req, -1)
+	require.NoError(err)
+	require.Equal(http.StatusOK, res.StatusCode)
+}
+
+func (s *APISuite) TestRolePermissions() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	var (
+		adminToken  = newAccessToken(s.tokenProvider, uuid.NewString(), domain.RoleAdmin)
+		workerToken = newAccessToken(s.tokenProvider, uuid.NewString(), domain.RoleWorker)
+	)
+	request := func(method, url, token string, body interface{}) *http.Response {
+		req, _ := http.NewRequest(method, buildURL(url), newBody(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+	// Other tests rely on default permissions
+	defer func() {
+		s.db.Collection(storage.CollectionRolePermissions).DeleteMany(context.Background(), bson.M{}) //nolint:errcheck
+		require.NoError(s.services.Permission.Load(context.Background()))
+	}()
+
+	t.Run("should return default permissions of every role", func(t *testing.T) {
+		res := request(http.MethodGet, "/api/admins/roles", adminToken, nil)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var out struct {
+			Roles       []domain.RolePermissions `json:"roles"`
+			Permissions []domain.Permission      `json:"permissions"`
+		}
+		require.NoError(json.Unmarshal(readBody(res.Body), &out))
+		require.Equal(domain.Permissions, out.Permissions)
+		require.Len(out.Roles, len(domain.Roles))
+		for _, rp := range out.Roles {
+			require.Equal(domain.DefaultRolePermissions[rp.Role], rp.Permissions)
+		}
+
+		res = request(http.MethodGet, "/api/admins/roles", workerToken, nil)
+		require.Equal(http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("should grant permission to worker", func(t *testing.T) {
+		res := request(http.MethodGet, "/api/admins/meta", workerToken, nil)
+		require.Equal(http.StatusForbidden, res.StatusCode)
+
+		res = request(http.MethodPut, "/api/admins/roles/worker/permissions", adminToken, input.UpdateRolePermissionsInput{
+			Permissions: append(domain.DefaultRolePermissions[domain.RoleWorker], domain.PermissionMetaEdit),
+		})
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		// Issued token gets the permission without logging in again
+		res = request(http.MethodGet, "/api/admins/meta", workerToken, nil)
+		require.Equal(http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("should deny customer own orders once orders.own is revoked", func(t *testing.T) {
+		customerToken := newAccessToken(s.tokenProvider, primitive.NewObjectID().Hex(), domain.RoleCustomer)
+		res := request(http.MethodGet, "/api/order/my", customerToken, nil)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		res = request(http.MethodPut, "/api/admins/roles/customer/permissions", adminToken, input.UpdateRolePermissionsInput{
+			Permissions: []domain.Permission{},
+		})
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		res = request(http.MethodGet, "/api/order/my", customerToken, nil)
+		require.Equal(http.StatusForbidden, res.StatusCode)
+		res = request(http.MethodGet, "/api/order/events", customerToken, nil)
+		require.Equal(http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("should not update permissions", func(t *testing.T) {
+		res := request(http.MethodPut, "/api/admins/roles/admin/permissions", adminToken, input.UpdateRolePermissionsInput{
+			Permissions: []domain.Permission{domain.PermissionMetaEdit},
+		})
+		require.Equal(http.StatusConflict, res.StatusCode)
+
+		res = request(http.MethodPut, "/api/admins/roles/superuser/permissions", adminToken, input.UpdateRolePermissionsInput{
+			Permissions: []domain.Permission{},
+		})
+		require.Equal(http.StatusNotFound, res.StatusCode)
+
+		res = request(http.MethodPut, "/api/admins/roles/worker/permissions", adminToken, input.UpdateRolePermissionsInput{
+			Permissions: []domain.Permission{"orders.delete"},
+		})
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func (s *APISuite) TestCategories() {
+	var (
+		t       = s.T()
+		require = s.Require()
+		ctx     = context.Background()
+	)
+
+	adminToken := newAccessToken(s.tokenProvider, uuid.NewString(), domain.RoleAdmin)
+	request := func(method, url string, body interface{}) *http.Response {
+		req, _ := http.NewRequest(method, buildURL(url), newBody(body))
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+	getProduct := func(productID string) domain.Product {
+		product, err := s.services.Product.GetByID(ctx, productID)
+		require.NoError(err)
+		return product
+	}
+
+	res := request(http.MethodPost, "/api/admins/categories/create", input.CategoryInput{Name: "Десерты"})
+	require.Equal(http.StatusCreated, res.StatusCode)
+	var out struct {
+		CategoryID string `json:"categoryId"`
+	}
+	require.NoError(json.Unmarshal(readBody(res.Body), &out))
+	categoryID := out.CategoryID
+
+	productID, err := s.services.Product.Create(ctx, dto.CreateProductDTO{
+		Name:         f.BeerName() + uuid.NewString(),
+		TranslateRU:  "Чизкейк",
+		Description:  f.Sentence(10),
+		CategoryName: "Десерты",
+		Price:        300,
+		Features:     domain.Features{Weight: 150, EnergyValue: 320},
+	})
+	require.NoError(err)
+
+	// Other tests rely on initial categories and their ranks
+	defer func() {
+		s.services.Product.Delete(ctx, productID)                                                                 //nolint:errcheck
+		s.db.Collection(storage.CollectionCategory).DeleteOne(ctx, bson.M{"_id": storage.ToObjectID(categoryID)}) //nolint:errcheck
+		require.NoError(s.services.Product.ReorderCategories(ctx, []string{
+			categoryDrinks.CategoryID.Hex(),
+			categoryPizza.CategoryID.Hex(),
+		}))
+	}()
+
+	t.Run("should put created category last raising ranks of the others", func(t *testing.T) {
+		categories, err := s.services.Product.GetAllCategories(ctx, true)
+		require.NoError(err)
+		require.Len(categories, 3)
+		for i, category := range categories {
+			require.Equal(int32(len(categories)-i), category.Rank)
+		}
+		require.Equal(categoryID, categories[2].CategoryID.Hex())
+		require.Equal(categories[2].Rank, getProduct(productID).Category.Rank)
+		// Products of the other categories keep ranks of their categories
+		require.Equal(categories[1].Rank, getProduct(products[0].(domain.Product).ProductID.Hex()).Category.Rank)
+	})
+
+	t.Run("should not create category with the same name", func(t *testing.T) {
+		res := request(http.MethodPost, "/api/admins/categories/create", input.CategoryInput{Name: categoryPizza.Name})
+		require.Equal(http.StatusConflict, res.StatusCode)
+
+		res = request(http.MethodPost, "/api/admins/categories/create", input.CategoryInput{Name: " "})
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("should rename category in its products", func(t *testing.T) {
+		res := request(http.MethodPut, "/api/admins/categories/"+categoryID+"/rename", input.CategoryInput{Name: "Чизкейки"})
+		require.Equal(http.StatusOK, res.StatusCode)
+		require.Equal("Чизкейки", getProduct(productID).Category.Name)
+
+		res = request(http.MethodPut, "/api/admins/categories/"+categoryID+"/rename", input.CategoryInput{Name: categoryDrinks.Name})
+		require.Equal(http.StatusConflict, res.StatusCode)
+
+		res = request(http.MethodPut, "/api/admins/categories/"+primitive.NewObjectID().Hex()+"/rename", input.CategoryInput{Name: "Салаты"})
+		require.Equal(http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("should reorder categories and catalog", func(t *testing.T) {
+		res := request(http.MethodPut, "/api/admins/categories/reorder", input.ReorderCategoriesInput{
+			CategoryIDs: []string{categoryID, categoryPizza.CategoryID.Hex(), categoryDrinks.CategoryID.Hex()},
+		})
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		catalog, err := s.services.Product.GetAll(ctx)
+		require.NoError(err)
+		require.Equal(productID, catalog[0].ProductID.Hex())
+		require.Equal(int32(3), catalog[0].Category.Rank)
+		require.Equal(categoryDrinks.CategoryID, catalog[len(catalog)-1].Category.CategoryID)
+		require.Equal(int32(1), catalog[len(catalog)-1].Category.Rank)
+	})
+
+	t.Run("should not reorder categories if some are missing", func(t *testing.T) {
+		res := request(http.MethodPut, "/api/admins/categories/reorder", input.ReorderCategoriesInput{
+			CategoryIDs: []string{categoryPizza.CategoryID.Hex(), categoryDrinks.CategoryID.Hex()},
+		})
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+
+		res = request(http.MethodPut, "/api/admins/categories/reorder", input.ReorderCategoriesInput{
+			CategoryIDs: []string{categoryID, categoryID, categoryDrinks.CategoryID.Hex()},
+		})
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("should delete category only without products", func(t *testing.T) {
+		res := request(http.MethodDelete, "/api/admins/categories/"+categoryID+"/delete", nil)
+		require.Equal(http.StatusConflict, res.StatusCode)
+
+		require.NoError(s.services.Product.Delete(ctx, productID))
+		res = request(http.MethodDelete, "/api/admins/categories/"+categoryID+"/delete", nil)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		res = request(http.MethodDelete, "/api/admins/categories/"+categoryID+"/delete", nil)
+		require.Equal(http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func (s *APISuite) TestProductImage() {
+	var (
+		t       = s.T()
+		require = s.Require()
+		ctx     = context.Background()
+	)
+
+	productID, err := s.services.Product.Create(ctx, dto.CreateProductDTO{
+		Name:         f.BeerName() + uuid.NewString(),
+		TranslateRU:  f.Word(),
+		Description:  f.LoremIpsumSentence(5),
+		CategoryName: categoryPizza.Name,
+		Price:        int64(f.IntRange(100, 500)),
+		Features:     getNonLiquidFeatures(),
+	})
+	require.NoError(err)
+
+	adminToken := newAccessToken(s.tokenProvider, uuid.NewString(), domain.RoleAdmin)
+	upload := func(productID string, data []byte) *http.Response {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("image", "pizza.png")
+		require.NoError(err)
+		_, err = part.Write(data)
+		require.NoError(err)
+		require.NoError(form.Close())
+
+		req, _ := http.NewRequest(http.MethodPut, buildURL("/api/admins/products/"+productID+"/image"), &body)
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+	get := func(url string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, buildURL(url), nil)
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		return res
+	}
+	var pngImage bytes.Buffer
+	require.NoError(png.Encode(&pngImage, image.NewRGBA(image.Rect(0, 0, 1200, 900))))
+
+	var previous domain.ImageURLs
+	t.Run("should upload image and serve its variants", func(t *testing.T) {
+		res := upload(productID, pngImage.Bytes())
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var out struct {
+			ImageURL domain.ImageURLs `json:"imageUrl"`
+		}
+		require.NoError(json.Unmarshal(readBody(res.Body), &out))
+
+		for url, size := range map[string]int{out.ImageURL.Medium: 800, out.ImageURL.Thumbnail: 200} {
+			res := get(url)
+			require.Equal(http.StatusOK, res.StatusCode)
+			img, err := jpeg.Decode(res.Body)
+			require.NoError(err)
+			require.Equal(size, img.Bounds().Dx())
+		}
+
+		product, err := s.services.Product.GetByID(ctx, productID)
+		require.NoError(err)
+		require.Equal(out.ImageURL.Medium, product.ImageURL.Medium)
+		require.Equal(out.ImageURL.Thumbnail, product.ImageURL.Thumbnail)
+		previous = *product.ImageURL
+	})
+
+	t.Run("should replace image and delete previous one", func(t *testing.T) {
+		res := upload(productID, pngImage.Bytes())
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		require.Equal(http.StatusNotFound, get(previous.Medium).StatusCode)
+		require.Equal(http.StatusNotFound, get(previous.Thumbnail).StatusCode)
+	})
+
+	t.Run("should not upload image", func(t *testing.T) {
+		res := upload(productID, []byte("definitely not an image"))
+		require.Equal(http.StatusUnsupportedMediaType, res.StatusCode)
+
+		res = upload(primitive.NewObjectID().Hex(), pngImage.Bytes())
+		require.Equal(http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("should delete image along with product", func(t *testing.T) {
+		product, err := s.services.Product.GetByID(ctx, productID)
+		require.NoError(err)
+		require.Equal(http.StatusOK, get(product.ImageURL.Medium).StatusCode)
+
+		req := newRequest(buildURL("/api/admins/products/"+productID+"/delete"), http.MethodDelete, adminToken, nil)
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		require.Equal(http.StatusNotFound, get(product.ImageURL.Medium).StatusCode)
+		require.Equal(http.StatusNotFound, get(product.ImageURL.Thumbnail).StatusCode)
+	})
+}
+
+func (s *APISuite) TestCombos() {
+	var (
+		t       = s.T()
+		require = s.Require()
+		ctx     = context.Background()
+		burger  = products[0].(domain.Product)
+		cola    = products[1].(domain.Product)
+	)
+
+	juiceID, err := s.services.Product.Create(ctx, dto.CreateProductDTO{
+		Name:         f.BeerName() + uuid.NewString(),
+		TranslateRU:  f.Word(),
+		Description:  f.LoremIpsumSentence(5),
+		CategoryName: categoryDrinks.Name,
+		Price:        150,
+		Features:     domain.Features{IsLiquid: true, Volume: 300, EnergyValue: 120},
+	})
+	require.NoError(err)
+	defer s.services.Product.Delete(ctx, juiceID) //nolint:errcheck
+
+	adminToken := newAccessToken(s.tokenProvider, uuid.NewString(), domain.RoleAdmin)
+	createCombo := func(combo *input.ComboInput) *http.Response {
+		req := newRequest("/api/admins/products/create", http.MethodPost, adminToken, newBody(input.CreateProductInput{
+			Name:         f.BeerName() + uuid.NewString(),
+			TranslateRU:  f.Word(),
+			Description:  f.LoremIpsumSentence(5),
+			CategoryName: categoryPizza.Name,
+			Price:        499,
+			Features:     getNonLiquidFeatures(),
+			Combo:        combo,
+		}))
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+	approve := func(productID string) *http.Response {
+		req := newRequest("/api/admins/products/"+productID+"/approve", http.MethodPut, adminToken, nil)
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+
+	t.Run("should not create combo of products that don't exist", func(t *testing.T) {
+		res := createCombo(&input.ComboInput{Components: []input.ComboComponentInput{
+			{ProductID: primitive.NewObjectID().Hex(), Quantity: 1},
+		}})
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+
+	res := createCombo(&input.ComboInput{Components: []input.ComboComponentInput{
+		{ProductID: burger.ProductID.Hex(), Quantity: 1},
+		{
+			ProductID: cola.ProductID.Hex(),
+			Quantity:  1,
+			Swaps:     []input.ComboSwapInput{{ProductID: juiceID, PriceDelta: 30}},
+		},
+	}})
+	require.Equal(http.StatusCreated, res.StatusCode)
+	var created struct {
+		ProductID string `json:"productId"`
+	}
+	require.NoError(json.NewDecoder(res.Body).Decode(&created))
+	comboID := created.ProductID
+	defer s.services.Product.Delete(ctx, comboID) //nolint:errcheck
+
+	t.Run("should not approve combo until its components are approved", func(t *testing.T) {
+		require.Equal(http.StatusConflict, approve(comboID).StatusCode)
+
+		require.NoError(s.services.Product.Approve(ctx, juiceID))
+		require.Equal(http.StatusOK, approve(comboID).StatusCode)
+	})
+
+	t.Run("should order combo with swapped component", func(t *testing.T) {
+		combo, err := s.services.Product.GetByID(ctx, comboID)
+		require.NoError(err)
+		drink := combo.Combo.Components[1]
+
+		inp := input.CreateWorkerOrderInput{
+			CustomerName: "Vivaldi",
+			PhoneNumber:  "+79458508377",
+			Cart: []input.CartProductInput{
+				{ProductID: comboID, Quantity: 2, Swaps: map[string]string{drink.ComponentID.Hex(): juiceID}},
+			},
+			Pay: domain.PayOnPickup,
+		}
+		accessToken := newAccessToken(s.tokenProvider, worker.UserID.Hex(), worker.Role)
+		res, err := s.app.Test(newRequest("/api/order/worker/create", http.MethodPost, accessToken, newBody(inp)), -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusCreated, res.StatusCode)
+
+		var out struct {
+			OrderID string `json:"orderId"`
+		}
+		require.NoError(json.NewDecoder(res.Body).Decode(&out))
+		order, err := s.services.Order.GetOrderByID(ctx, out.OrderID)
+		require.NoError(err)
+
+		// 2 * (499 + 30)
+		require.Equal(int64(1058), order.Amount)
+		require.Len(order.Cart, 1)
+		require.Nil(order.Cart[0].Combo)
+		require.Len(order.Cart[0].Components, 2)
+		require.Equal(burger.ProductID, order.Cart[0].Components[0].ProductID)
+		require.Equal(juiceID, order.Cart[0].Components[1].ProductID.Hex())
+		require.True(order.Cart[0].Components[1].IsSwapped)
+		require.Equal(int32(2), order.ProductQuantities()[burger.ProductID])
+	})
+}
@@ -2,7 +2,9 @@ package tests
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -12,10 +14,16 @@ import (
 	service "github.com/sonyamoonglade/sancho-backend/internal/services"
 	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
 	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
+	"github.com/sonyamoonglade/sancho-backend/pkg/blob"
 	"github.com/sonyamoonglade/sancho-backend/pkg/database"
+	"github.com/sonyamoonglade/sancho-backend/pkg/eventbus"
 	"github.com/sonyamoonglade/sancho-backend/pkg/hash"
 	"github.com/sonyamoonglade/sancho-backend/pkg/logger"
 	"github.com/sonyamoonglade/sancho-backend/pkg/meta_cache"
+	"github.com/sonyamoonglade/sancho-backend/pkg/permission_cache"
+	"github.com/sonyamoonglade/sancho-backend/pkg/ratelimit"
+	"github.com/sonyamoonglade/sancho-backend/pkg/revocation_cache"
+	"github.com/sonyamoonglade/sancho-backend/pkg/sms"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -35,6 +43,10 @@ type APISuite struct {
 	storages *storage.Storages
 
 	tokenProvider auth.TokenProvider
+	// smsFile is where sent codes are read from
+	smsFile string
+	// blobStore keeps uploaded images in temporary directory
+	blobStore *blob.LocalStore
 
 	app *fiber.App
 }
@@ -56,6 +68,10 @@ func (s *APISuite) SetupSuite() {
 		s.FailNow("failed to connect to mongodb", err)
 		return
 	}
+	if err := mongo.CheckTransactions(ctx); err != nil {
+		s.FailNow("mongodb should be run as replica set", err)
+		return
+	}
 
 	s.initDeps(mongo)
 	if err := s.populateDB(ctx); err != nil {
@@ -70,10 +86,13 @@ func (s *APISuite) SetupSuite() {
 	})
 
 	s.app = app
+	app.Static(s.blobStore.URLPrefix(), s.blobStore.Root())
 	s.handler.InitAPI(app)
 }
 
 func (s *APISuite) TearDownSuite() {
+	os.Remove(s.smsFile)             //nolint:errcheck
+	os.RemoveAll(s.blobStore.Root()) //nolint:errcheck
 	s.db.Close(context.Background()) //nolint:errcheck
 }
 
@@ -99,25 +118,54 @@ func (s *APISuite) initDeps(mongo *database.Mongo) {
 	metaCache := meta_cache.NewMetaCache()
 	metaCache.Set(meta)
 
+	smsFile := filepath.Join(os.TempDir(), fmt.Sprintf("sancho-sms-%d.log", time.Now().UnixNano()))
+
+	blobStore, err := blob.NewLocalStore(filepath.Join(os.TempDir(), fmt.Sprintf("sancho-blobs-%d", time.Now().UnixNano())), "/static")
+	if err != nil {
+		panic(err)
+	}
+
 	storages := storage.NewStorages(mongo)
 	services := service.NewServices(service.Deps{
-		Storages:      storages,
-		TokenProvider: tokenProvider,
-		MetaProvider:  metaCache,
-		Hasher:        hash.NewSHA1Hasher(),
-		TTLStrategy:   ttlStrategy,
-		OrderConfig:   service.OrderConfig{},
+		Storages:            storages,
+		TokenProvider:       tokenProvider,
+		MetaProvider:        metaCache,
+		RevocationProvider:  revocation_cache.NewRevocationCache(),
+		PermissionsProvider: permission_cache.NewPermissionCache(),
+		Hasher:              hash.NewArgon2idHasher(hash.DefaultArgon2idParams),
+		TTLStrategy:         ttlStrategy,
+		OrderConfig:         service.OrderConfig{},
+		SMSSender:           sms.NewFileSender(smsFile),
+		BlobStore:           blobStore,
+		EventBus:            eventbus.New(eventbus.DefaultBufferSize),
 	})
 
-	jwtAuth := middleware.NewJWTAuthMiddleware(services.Auth, tokenProvider)
+	// Suite doesn't save permissions, so roles have the defaults
+	if err := services.Permission.Load(context.Background()); err != nil {
+		panic(err)
+	}
+
+	jwtAuth := middleware.NewJWTAuthMiddleware(services.Auth, services.Revocation, services.Permission, tokenProvider)
 	xReqID := new(middleware.XRequestIDMiddleware)
-	middlewares := middleware.NewMiddlewares(jwtAuth, xReqID)
+	// Every request of the suite comes from the same IP, so limits are covered by middleware tests
+	unlimited := ratelimit.Limit{Requests: 1000, Period: time.Second}
+	rateLimit := middleware.NewRateLimitMiddleware(map[string]middleware.RateLimit{
+		middleware.RateLimitAuth:   {Limit: unlimited, Key: middleware.RateLimitByIP},
+		middleware.RateLimitOTP:    {Limit: unlimited, Key: middleware.RateLimitByPhone},
+		middleware.RateLimitOTPIP:  {Limit: unlimited, Key: middleware.RateLimitByIP},
+		middleware.RateLimitOrders: {Limit: unlimited, Key: middleware.RateLimitByUser},
+	})
+	lockout := middleware.NewLockoutMiddleware(ratelimit.LockoutPolicy{MaxFailures: 1000}, ratelimit.LockoutPolicy{MaxFailures: 1000})
+	idempotency := middleware.NewIdempotencyMiddleware(services.Idempotency)
+	middlewares := middleware.NewMiddlewares(jwtAuth, xReqID, rateLimit, lockout, idempotency)
 
 	h := handler.NewHandler(services, middlewares)
 	s.handler = h
 	s.storages = storages
 	s.services = services
 	s.tokenProvider = tokenProvider
+	s.smsFile = smsFile
+	s.blobStore = blobStore
 	s.db = mongo
 }
 
@@ -134,5 +182,9 @@ func (s *APISuite) populateDB(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	_, err = s.db.Collection(storage.CollectionDeliveryZones).InsertOne(ctx, deliveryZone, nil)
+	if err != nil {
+		return err
+	}
 	return nil
 }
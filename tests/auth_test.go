@@ -0,0 +1,394 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	service "github.com/sonyamoonglade/sancho-backend/internal/services"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
+	"github.com/sonyamoonglade/sancho-backend/pkg/auth"
+	"github.com/sonyamoonglade/sancho-backend/pkg/sms"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *APISuite) TestCustomerOTPLogin() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	type loginResponse struct {
+		AccessToken  string `json:"accessToken"`
+		RefreshToken string `json:"refreshToken"`
+	}
+
+	requestCode := func(phoneNumber string) *http.Response {
+		req := newRequest("/api/auth/customer/otp", http.MethodPost, "", newBody(input.RequestOTPInput{
+			PhoneNumber: phoneNumber,
+		}))
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+	login := func(phoneNumber, code string) *http.Response {
+		req := newRequest("/api/auth/customer/login", http.MethodPost, "", newBody(input.LoginCustomerInput{
+			PhoneNumber: phoneNumber,
+			Code:        code,
+		}))
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+	lastCode := func(phoneNumber string) string {
+		messages, err := sms.ReadMessages(s.smsFile)
+		require.NoError(err)
+		for i := len(messages) - 1; i >= 0; i-- {
+			if messages[i].PhoneNumber == phoneNumber {
+				text := messages[i].Text
+				return text[strings.LastIndex(text, " ")+1:]
+			}
+		}
+		require.FailNow("code has not been sent")
+		return ""
+	}
+
+	t.Run("should log existing customer in", func(t *testing.T) {
+		res := requestCode(customer.PhoneNumber)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		res = login(customer.PhoneNumber, lastCode(customer.PhoneNumber))
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var resp loginResponse
+		require.NoError(json.NewDecoder(res.Body).Decode(&resp))
+		require.NotZero(resp.RefreshToken)
+
+		userAuth, err := s.tokenProvider.ParseAndValidate(resp.AccessToken)
+		require.NoError(err)
+		require.Equal(customer.UserID.Hex(), userAuth.UserID)
+		require.Equal(domain.RoleCustomer, userAuth.Role)
+
+		// Code can be used only once
+		res = login(customer.PhoneNumber, lastCode(customer.PhoneNumber))
+		require.Equal(http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("should rotate refresh token of customer", func(t *testing.T) {
+		phoneNumber := "+79120000004"
+		defer s.db.Collection(storage.CollectionCustomers).DeleteOne(context.Background(), bson.M{"phoneNumber": phoneNumber}) //nolint:errcheck
+
+		res := requestCode(phoneNumber)
+		require.Equal(http.StatusOK, res.StatusCode)
+		res = login(phoneNumber, lastCode(phoneNumber))
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var resp loginResponse
+		require.NoError(json.NewDecoder(res.Body).Decode(&resp))
+		userAuth, err := s.tokenProvider.ParseAndValidate(resp.AccessToken)
+		require.NoError(err)
+
+		tokens, err := s.services.Auth.RefreshTokens(context.Background(), dto.RefreshTokensDTO{
+			Role:         domain.RoleCustomer,
+			UserID:       userAuth.UserID,
+			RefreshToken: resp.RefreshToken,
+		})
+		require.NoError(err)
+		require.NotEqual(resp.RefreshToken, tokens.RefreshToken)
+
+		// Refresh token is rotated and can't be used again
+		_, err = s.services.Auth.RefreshTokens(context.Background(), dto.RefreshTokensDTO{
+			Role:         domain.RoleCustomer,
+			UserID:       userAuth.UserID,
+			RefreshToken: resp.RefreshToken,
+		})
+		require.ErrorIs(err, domain.ErrSessionNotFound)
+
+		// Session of a customer can't be refreshed as worker's
+		_, err = s.services.Auth.RefreshTokens(context.Background(), dto.RefreshTokensDTO{
+			Role:         domain.RoleWorker,
+			UserID:       userAuth.UserID,
+			RefreshToken: tokens.RefreshToken,
+		})
+		require.ErrorIs(err, domain.ErrSessionNotFound)
+	})
+
+	t.Run("should register customer on the first login", func(t *testing.T) {
+		phoneNumber := "+79120000001"
+		defer s.db.Collection(storage.CollectionCustomers).DeleteOne(context.Background(), bson.M{"phoneNumber": phoneNumber}) //nolint:errcheck
+
+		res := requestCode(phoneNumber)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		res = login(phoneNumber, lastCode(phoneNumber))
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		registered, err := s.services.User.GetCustomerByPhoneNumber(context.Background(), phoneNumber)
+		require.NoError(err)
+
+		var resp loginResponse
+		require.NoError(json.NewDecoder(res.Body).Decode(&resp))
+		userAuth, err := s.tokenProvider.ParseAndValidate(resp.AccessToken)
+		require.NoError(err)
+		require.Equal(registered.UserID.Hex(), userAuth.UserID)
+	})
+
+	t.Run("should not resend code right away", func(t *testing.T) {
+		phoneNumber := "+79120000002"
+
+		res := requestCode(phoneNumber)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		res = requestCode(phoneNumber)
+		require.Equal(http.StatusTooManyRequests, res.StatusCode)
+	})
+
+	t.Run("should reject code after too many attempts", func(t *testing.T) {
+		phoneNumber := "+79120000003"
+
+		res := requestCode(phoneNumber)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		code := lastCode(phoneNumber)
+		wrongCode := "000000"
+		if code == wrongCode {
+			wrongCode = "111111"
+		}
+		for i := int64(0); i < service.DefaultOTPMaxAttempts; i++ {
+			res = login(phoneNumber, wrongCode)
+			require.Equal(http.StatusUnauthorized, res.StatusCode)
+		}
+
+		res = login(phoneNumber, code)
+		require.Equal(http.StatusTooManyRequests, res.StatusCode)
+	})
+
+	t.Run("should return 400 because phone number is invalid", func(t *testing.T) {
+		res := requestCode("not a phone")
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func (s *APISuite) TestSessions() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	const password = "sessions_password"
+	workerID, err := s.services.Auth.RegisterWorker(context.Background(), dto.RegisterWorkerDTO{
+		Name:     "Ольга",
+		Login:    "olga.w",
+		Password: password,
+	})
+	require.NoError(err)
+	defer s.db.Collection(storage.CollectionAdminsAndWorkers).DeleteOne(context.Background(), bson.M{"_id": storage.ToObjectID(workerID)}) //nolint:errcheck
+	defer s.db.Collection(storage.CollectionSessions).DeleteMany(context.Background(), bson.M{"userId": workerID})                         //nolint:errcheck
+
+	type loginResponse struct {
+		AccessToken  string `json:"accessToken"`
+		RefreshToken string `json:"refreshToken"`
+	}
+	type sessionsResponse struct {
+		Sessions         []domain.Session `json:"sessions"`
+		CurrentSessionID string           `json:"currentSessionId"`
+	}
+
+	login := func(device string) loginResponse {
+		req := newRequest("/api/auth/worker/login", http.MethodPost, "", newBody(input.LoginInput{
+			Login:    "olga.w",
+			Password: password,
+			Device:   device,
+		}))
+		req.Header.Set("User-Agent", "sancho-pos/1.0")
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var resp loginResponse
+		require.NoError(json.NewDecoder(res.Body).Decode(&resp))
+		return resp
+	}
+	refresh := func(refreshToken string) error {
+		_, err := s.services.Auth.RefreshTokens(context.Background(), dto.RefreshTokensDTO{
+			Role:         domain.RoleWorker,
+			UserID:       workerID,
+			RefreshToken: refreshToken,
+		})
+		return err
+	}
+	request := func(method, url, token string) *http.Response {
+		res, err := s.app.Test(newRequest(url, method, token, nil), -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+
+	t.Run("should keep session per device and revoke them", func(t *testing.T) {
+		counter := login("counter tablet")
+		kitchen := login("kitchen tablet")
+
+		// Logging in on the second device doesn't log the first one out
+		require.NoError(refresh(counter.RefreshToken))
+
+		res := request(http.MethodGet, "/api/sessions", kitchen.AccessToken)
+		require.Equal(http.StatusOK, res.StatusCode)
+		var resp sessionsResponse
+		require.NoError(json.NewDecoder(res.Body).Decode(&resp))
+		require.Len(resp.Sessions, 2)
+
+		// The most recently used goes first
+		require.Equal("counter tablet", resp.Sessions[0].Device)
+		require.Equal("kitchen tablet", resp.Sessions[1].Device)
+		require.Equal(resp.Sessions[1].SessionID.Hex(), resp.CurrentSessionID)
+		counterSessionID := resp.Sessions[0].SessionID.Hex()
+		for _, session := range resp.Sessions {
+			require.Equal("sancho-pos/1.0", session.UserAgent)
+			require.Zero(session.RefreshToken)
+		}
+
+		res = request(http.MethodDelete, "/api/sessions/"+counterSessionID, kitchen.AccessToken)
+		require.Equal(http.StatusOK, res.StatusCode)
+		res = request(http.MethodDelete, "/api/sessions/"+counterSessionID, kitchen.AccessToken)
+		require.Equal(http.StatusNotFound, res.StatusCode)
+
+		// Session of another user can't be revoked
+		otherToken := newAccessToken(s.tokenProvider, primitive.NewObjectID().Hex(), domain.RoleWorker)
+		res = request(http.MethodDelete, "/api/sessions/"+resp.CurrentSessionID, otherToken)
+		require.Equal(http.StatusNotFound, res.StatusCode)
+
+		require.Error(refresh(counter.RefreshToken))
+		require.NoError(refresh(kitchen.RefreshToken))
+	})
+
+	t.Run("should revoke every session of worker by admin", func(t *testing.T) {
+		first := login("first tablet")
+		second := login("second tablet")
+
+		adminToken := newAccessToken(s.tokenProvider, primitive.NewObjectID().Hex(), domain.RoleAdmin)
+		res := request(http.MethodDelete, "/api/admins/workers/"+workerID+"/sessions", adminToken)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		require.ErrorIs(refresh(first.RefreshToken), domain.ErrSessionNotFound)
+		require.ErrorIs(refresh(second.RefreshToken), domain.ErrSessionNotFound)
+
+		res = request(http.MethodGet, "/api/sessions", first.AccessToken)
+		require.Equal(http.StatusOK, res.StatusCode)
+		var resp sessionsResponse
+		require.NoError(json.NewDecoder(res.Body).Decode(&resp))
+		require.Empty(resp.Sessions)
+	})
+
+	t.Run("should not revoke sessions because caller is not admin", func(t *testing.T) {
+		workerToken := newAccessToken(s.tokenProvider, workerID, domain.RoleWorker)
+		res := request(http.MethodDelete, "/api/admins/customers/"+customer.UserID.Hex()+"/sessions", workerToken)
+		require.Equal(http.StatusForbidden, res.StatusCode)
+	})
+}
+
+func (s *APISuite) TestTokenRevocation() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	const password = "revocation_password"
+	workerID, err := s.services.Auth.RegisterWorker(context.Background(), dto.RegisterWorkerDTO{
+		Name:     "Пётр",
+		Login:    "petr.w",
+		Password: password,
+	})
+	require.NoError(err)
+	defer s.db.Collection(storage.CollectionAdminsAndWorkers).DeleteOne(context.Background(), bson.M{"_id": storage.ToObjectID(workerID)}) //nolint:errcheck
+	defer s.db.Collection(storage.CollectionSessions).DeleteMany(context.Background(), bson.M{"userId": workerID})                         //nolint:errcheck
+
+	login := func() (accessToken, refreshToken string) {
+		req := newRequest("/api/auth/worker/login", http.MethodPost, "", newBody(input.LoginInput{
+			Login:    "petr.w",
+			Password: password,
+		}))
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var resp struct {
+			AccessToken  string `json:"accessToken"`
+			RefreshToken string `json:"refreshToken"`
+		}
+		require.NoError(json.NewDecoder(res.Body).Decode(&resp))
+		return resp.AccessToken, resp.RefreshToken
+	}
+	request := func(method, url, token string) int {
+		res, err := s.app.Test(newRequest(url, method, token, nil), -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res.StatusCode
+	}
+
+	t.Run("should reject access token after logout", func(t *testing.T) {
+		accessToken, refreshToken := login()
+		otherAccessToken, _ := login()
+
+		require.Equal(http.StatusOK, request(http.MethodGet, "/api/sessions", accessToken))
+		require.Equal(http.StatusOK, request(http.MethodPost, "/api/auth/logout", accessToken))
+		require.Equal(http.StatusUnauthorized, request(http.MethodGet, "/api/sessions", accessToken))
+
+		_, err := s.services.Auth.RefreshTokens(context.Background(), dto.RefreshTokensDTO{
+			Role:         domain.RoleWorker,
+			UserID:       workerID,
+			RefreshToken: refreshToken,
+		})
+		require.ErrorIs(err, domain.ErrSessionNotFound)
+
+		// Other device is still logged in
+		require.Equal(http.StatusOK, request(http.MethodGet, "/api/sessions", otherAccessToken))
+	})
+
+	t.Run("should reject every access token after logout from all devices", func(t *testing.T) {
+		first, _ := login()
+		second, _ := login()
+
+		require.Equal(http.StatusOK, request(http.MethodPost, "/api/auth/logout/all", first))
+		require.Equal(http.StatusUnauthorized, request(http.MethodGet, "/api/sessions", first))
+		require.Equal(http.StatusUnauthorized, request(http.MethodGet, "/api/sessions", second))
+
+		// Tokens issued after revocation are valid
+		third, _ := login()
+		require.Equal(http.StatusOK, request(http.MethodGet, "/api/sessions", third))
+	})
+
+	t.Run("should reject access token of disabled worker", func(t *testing.T) {
+		accessToken, _ := login()
+		require.Equal(http.StatusOK, request(http.MethodGet, "/api/sessions", accessToken))
+
+		require.NoError(s.services.User.DisableWorker(context.Background(), workerID))
+		require.Equal(http.StatusUnauthorized, request(http.MethodGet, "/api/sessions", accessToken))
+	})
+}
+
+func (s *APISuite) TestJWKS() {
+	require := s.Require()
+
+	req := newRequest("/.well-known/jwks.json", http.MethodGet, "", nil)
+	res, err := s.app.Test(req, -1)
+	require.NoError(err)
+	printResponseDetails(res)
+	require.Equal(http.StatusOK, res.StatusCode)
+	require.Equal("public, max-age=300", res.Header.Get("Cache-Control"))
+
+	var jwks auth.JWKS
+	require.NoError(json.NewDecoder(res.Body).Decode(&jwks))
+	// Suite signs tokens with shared secret which must never be published
+	require.NotNil(jwks.Keys)
+	require.Empty(jwks.Keys)
+}
@@ -4,7 +4,6 @@ import (
 	"time"
 
 	f "github.com/brianvoe/gofakeit/v6"
-	"github.com/google/uuid"
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	service "github.com/sonyamoonglade/sancho-backend/internal/services"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -30,8 +29,8 @@ var (
 			Name:        f.LoremIpsumSentence(4),
 			TranslateRU: f.LoremIpsumSentence(5),
 			Description: f.LoremIpsumSentence(10),
-			ImageURL:    StringPtr(f.ImageURL(200, 200)),
-			IsApproved:  false,
+			ImageURL:    &domain.ImageURLs{Thumbnail: f.ImageURL(200, 200), Medium: f.ImageURL(800, 800)},
+			IsApproved:  true,
 			Price:       int64(f.IntRange(200, 500)),
 			Category:    categoryPizza,
 			Features: domain.Features{
@@ -51,7 +50,7 @@ var (
 			Name:        f.LoremIpsumSentence(2),
 			TranslateRU: f.LoremIpsumSentence(5),
 			Description: f.LoremIpsumSentence(10),
-			ImageURL:    StringPtr(f.ImageURL(200, 200)),
+			ImageURL:    &domain.ImageURLs{Thumbnail: f.ImageURL(200, 200), Medium: f.ImageURL(800, 800)},
 			IsApproved:  true,
 			Price:       int64(f.IntRange(50, 100)),
 			Category:    categoryDrinks,
@@ -63,6 +62,23 @@ var (
 				Nutrients:   nil,
 			},
 		},
+		// Draft that is not in catalog yet
+		domain.Product{
+			ProductID:   primitive.NewObjectID(),
+			Name:        f.LoremIpsumSentence(3),
+			TranslateRU: f.LoremIpsumSentence(5),
+			Description: f.LoremIpsumSentence(10),
+			ImageURL:    nil,
+			IsApproved:  false,
+			Price:       int64(f.IntRange(200, 500)),
+			Category:    categoryPizza,
+			Features: domain.Features{
+				IsLiquid:    false,
+				Weight:      400,
+				Volume:      0,
+				EnergyValue: 300,
+			},
+		},
 	}
 
 	customer = domain.Customer{
@@ -76,10 +92,6 @@ var (
 		},
 		Role: domain.RoleCustomer,
 		Name: StringPtr("Филипп"),
-		Session: &domain.Session{
-			RefreshToken: uuid.NewString(),
-			ExpiresAt:    time.Now().UTC().Add(time.Hour * 24),
-		},
 	}
 
 	worker = domain.Worker{
@@ -88,12 +100,26 @@ var (
 		Login:    "jqkweixuch",
 		Password: "kasdsjd*&1231mz",
 		Role:     domain.RoleWorker,
-		Session: domain.Session{
-			RefreshToken: uuid.NewString(),
-			ExpiresAt:    time.Now().UTC().Add(time.Hour * 24),
-		},
 	}
 
+	deliveryZone = domain.DeliveryZone{
+		DeliveryZoneID: primitive.NewObjectID(),
+		Name:           "Центр",
+		Area: domain.NewGeoPolygon([][]float64{
+			{60.55, 56.80},
+			{60.70, 56.80},
+			{60.70, 56.90},
+			{60.55, 56.90},
+			{60.55, 56.80},
+		}),
+		Fee:        150,
+		ETAMinutes: 45,
+	}
+
+	// Coordinates within deliveryZone
+	deliveryLatitude  = 56.8519
+	deliveryLongitude = 60.6122
+
 	meta = domain.BusinessMeta{
 		DeliveryPunishmentThreshold: 500,
 		DeliveryPunishmentValue:     100,
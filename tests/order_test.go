@@ -3,15 +3,25 @@ package tests
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"math"
 	"net/http"
+	neturl "net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	f "github.com/brianvoe/gofakeit/v6"
+	"github.com/google/uuid"
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
 	"github.com/sonyamoonglade/sancho-backend/internal/handler/input"
+	"github.com/sonyamoonglade/sancho-backend/internal/handler/middleware"
 	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
+	storage "github.com/sonyamoonglade/sancho-backend/internal/storages"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func (s *APISuite) TestCreateUserOrder() {
@@ -74,6 +84,8 @@ func (s *APISuite) TestCreateUserOrder() {
 				Floor:       2,
 				Apartment:   3,
 				DeliveredAt: time.Now().UTC().Add(time.Hour * 1),
+				Latitude:    deliveryLatitude,
+				Longitude:   deliveryLongitude,
 			},
 		}
 
@@ -98,9 +110,10 @@ func (s *APISuite) TestCreateUserOrder() {
 		require.NotZero(len(order.Cart))
 		require.True(order.Status == domain.StatusWaitingForVerification)
 		require.True(order.CustomerID == customer.UserID.Hex())
-		// delivery punishment is applied because order is delivered
-		require.True(order.DiscountedAmount != order.Amount)
-		require.True(order.DiscountedAmount == order.Amount+meta.DeliveryPunishmentValue)
+		// zone fee is the only delivery charge
+		require.Equal(order.Amount+deliveryZone.Fee, order.DiscountedAmount)
+		require.NotNil(order.Delivery)
+		require.Equal(deliveryZone.DeliveryZoneID, order.Delivery.DeliveryZoneID)
 	})
 
 	t.Run("should create user order that is delivered but punishment is not applied", func(t *testing.T) {
@@ -122,6 +135,8 @@ func (s *APISuite) TestCreateUserOrder() {
 				Floor:       2,
 				Apartment:   3,
 				DeliveredAt: time.Now().UTC().Add(time.Hour * 1),
+				Latitude:    deliveryLatitude,
+				Longitude:   deliveryLongitude,
 			},
 		}
 
@@ -147,9 +162,38 @@ func (s *APISuite) TestCreateUserOrder() {
 		require.True(order.Status == domain.StatusWaitingForVerification)
 		require.True(order.CustomerID == customer.UserID.Hex())
 		// delivery punishment is not applied because order is delivered but threshold is not reached
-		require.True(order.DiscountedAmount == order.Amount)
+		require.True(order.DiscountedAmount == order.Amount+deliveryZone.Fee)
 		require.True(order.Amount <= meta.DeliveryPunishmentThreshold)
 	})
+
+	t.Run("should not create user order because address is outside of delivery zones", func(t *testing.T) {
+		cartProduct := products[0].(domain.Product)
+		inp := input.CreateUserOrderInput{
+			Pay: domain.PayOnPickup,
+			Cart: []input.CartProductInput{
+				{ProductID: cartProduct.ProductID.Hex(), Quantity: 1},
+			},
+			IsDelivered: true,
+			DeliveryAddress: &domain.OrderDeliveryAddress{
+				IsAsap:      true,
+				Address:     "blabla",
+				Entrance:    1,
+				Floor:       2,
+				Apartment:   3,
+				DeliveredAt: time.Now().UTC().Add(time.Hour * 1),
+				// Moscow
+				Latitude:  55.7558,
+				Longitude: 37.6173,
+			},
+		}
+
+		accessToken := newAccessToken(s.tokenProvider, customer.UserID.Hex(), customer.Role)
+		req := newRequest("/api/order/create", http.MethodPost, accessToken, newBody(inp))
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
 }
 
 func (s *APISuite) TestCreateWorkerOrder() {
@@ -272,6 +316,8 @@ func (s *APISuite) TestCreateWorkerOrder() {
 				Floor:       3,
 				Apartment:   4,
 				DeliveredAt: time.Now().UTC().Add(time.Hour * 1),
+				Latitude:    deliveryLatitude,
+				Longitude:   deliveryLongitude,
 			},
 		}
 
@@ -307,9 +353,404 @@ func (s *APISuite) TestCreateWorkerOrder() {
 
 		discountedAmount := s.services.Order.CalculateDiscountedAmount(cartAmount, inp.DiscountPercent)
 
-		// because delivery punishment is applied (amount is more than meta.DeliveryPunishmentThreshold)
-		require.NotEqual(order.DiscountedAmount, discountedAmount)
-		require.True(order.DiscountedAmount == discountedAmount+meta.DeliveryPunishmentValue)
+		// amount is more than meta.DeliveryPunishmentThreshold, but only zone fee is charged
+		require.Equal(discountedAmount+deliveryZone.Fee, order.DiscountedAmount)
+	})
+
+	t.Run("should not create order with missing and not approved products", func(t *testing.T) {
+		draftID, err := s.services.Product.Create(context.Background(), dto.CreateProductDTO{
+			Name:         f.BeerName() + uuid.NewString(),
+			TranslateRU:  f.Word(),
+			Description:  f.LoremIpsumSentence(5),
+			CategoryName: categoryDrinks.Name,
+			Price:        100,
+			Features:     domain.Features{IsLiquid: true, Volume: 300, EnergyValue: 120},
+		})
+		require.NoError(err)
+		defer s.services.Product.Delete(context.Background(), draftID) //nolint:errcheck
+
+		missingID := primitive.NewObjectID().Hex()
+		inp := input.CreateWorkerOrderInput{
+			CustomerName: "Bach",
+			PhoneNumber:  "+79458508375",
+			Cart: []input.CartProductInput{
+				{ProductID: products[1].(domain.Product).ProductID.Hex(), Quantity: 1},
+				{ProductID: draftID, Quantity: 1},
+				{ProductID: missingID, Quantity: 1},
+			},
+			Pay: domain.PayOnPickup,
+		}
+
+		accessToken := newAccessToken(s.tokenProvider, worker.UserID.Hex(), worker.Role)
+		req := newRequest("/api/order/worker/create", http.MethodPost, accessToken, newBody(inp))
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+
+		var out struct {
+			Lines []domain.CartLineError `json:"lines"`
+		}
+		require.NoError(json.NewDecoder(res.Body).Decode(&out))
+		require.Equal([]domain.CartLineError{
+			{Line: 1, ProductID: draftID, Reason: domain.CartLineProductNotApproved},
+			{Line: 2, ProductID: missingID, Reason: domain.CartLineProductNotFound},
+		}, out.Lines)
+	})
+}
+
+func (s *APISuite) TestOrderLifecycle() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	newWorkerOrder := func() string {
+		cartProduct := products[1].(domain.Product)
+		orderID, err := s.services.Order.CreateWorkerOrder(context.Background(), dto.CreateWorkerOrderDTO{
+			CustomerID: customer.UserID.Hex(),
+			Cart: []dto.CartProductDTO{
+				{ProductID: cartProduct.ProductID.Hex(), Quantity: 1},
+			},
+			Pay: domain.PayOnPickup,
+		})
+		require.NoError(err)
+		return orderID
+	}
+
+	t.Run("should complete verified order and not allow to verify it again", func(t *testing.T) {
+		orderID := newWorkerOrder()
+		accessToken := newAccessToken(s.tokenProvider, worker.UserID.Hex(), worker.Role)
+
+		req := newRequest(fmt.Sprintf("/api/order/worker/%s/complete", orderID), http.MethodPut, accessToken, nil)
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		order, err := s.services.Order.GetOrderByID(context.Background(), orderID)
+		require.NoError(err)
+		require.Equal(domain.StatusCompleted, order.Status)
+		require.NotNil(order.CompletedAt)
+
+		req = newRequest(fmt.Sprintf("/api/order/worker/%s/verify", orderID), http.MethodPut, accessToken, nil)
+		res, err = s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusConflict, res.StatusCode)
+	})
+
+	t.Run("should cancel verified order with explanation", func(t *testing.T) {
+		orderID := newWorkerOrder()
+		accessToken := newAccessToken(s.tokenProvider, worker.UserID.Hex(), worker.Role)
+		inp := input.CancelOrderInput{Explanation: "out of dough"}
+
+		req := newRequest(fmt.Sprintf("/api/order/worker/%s/cancel", orderID), http.MethodPut, accessToken, newBody(inp))
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		order, err := s.services.Order.GetOrderByID(context.Background(), orderID)
+		require.NoError(err)
+		require.Equal(domain.StatusCancelled, order.Status)
+		require.NotNil(order.CancelledAt)
+		require.Equal(inp.Explanation, *order.CancelExplanation)
+	})
+
+	t.Run("should return 404 because order does not exist", func(t *testing.T) {
+		accessToken := newAccessToken(s.tokenProvider, worker.UserID.Hex(), worker.Role)
+		req := newRequest(fmt.Sprintf("/api/order/worker/%s/verify", primitive.NewObjectID().Hex()), http.MethodPut, accessToken, nil)
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func (s *APISuite) TestListOrders() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	type listOrdersResponse struct {
+		Orders     []domain.Order `json:"orders"`
+		NextCursor *string        `json:"nextCursor"`
+	}
+
+	t.Run("should list orders page by page", func(t *testing.T) {
+		cartProduct := products[1].(domain.Product)
+		for i := 0; i < 3; i++ {
+			_, err := s.services.Order.CreateWorkerOrder(context.Background(), dto.CreateWorkerOrderDTO{
+				CustomerID: customer.UserID.Hex(),
+				Cart: []dto.CartProductDTO{
+					{ProductID: cartProduct.ProductID.Hex(), Quantity: 1},
+				},
+				Pay: domain.PayOnline,
+			})
+			require.NoError(err)
+		}
+
+		accessToken := newAccessToken(s.tokenProvider, worker.UserID.Hex(), worker.Role)
+		url := "/api/order/worker/list?limit=2&pay=online&phoneNumber=" + neturl.QueryEscape(customer.PhoneNumber)
+		req := newRequest(url, http.MethodGet, accessToken, nil)
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var firstPage listOrdersResponse
+		require.NoError(json.NewDecoder(res.Body).Decode(&firstPage))
+		require.Len(firstPage.Orders, 2)
+		require.NotNil(firstPage.NextCursor)
+
+		req = newRequest(url+"&cursor="+*firstPage.NextCursor, http.MethodGet, accessToken, nil)
+		res, err = s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var secondPage listOrdersResponse
+		require.NoError(json.NewDecoder(res.Body).Decode(&secondPage))
+		require.NotZero(len(secondPage.Orders))
+		for _, order := range secondPage.Orders {
+			require.Equal(customer.UserID.Hex(), order.CustomerID)
+			require.Equal(domain.PayOnline, order.Pay)
+			require.True(order.CreatedAt.Before(firstPage.Orders[1].CreatedAt) || order.CreatedAt.Equal(firstPage.Orders[1].CreatedAt))
+			require.NotEqual(firstPage.Orders[0].OrderID, order.OrderID)
+			require.NotEqual(firstPage.Orders[1].OrderID, order.OrderID)
+		}
+	})
+
+	t.Run("should return 400 because status is invalid", func(t *testing.T) {
+		accessToken := newAccessToken(s.tokenProvider, worker.UserID.Hex(), worker.Role)
+		req := newRequest("/api/order/worker/list?status=cooking", http.MethodGet, accessToken, nil)
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("should return 400 because cursor is invalid", func(t *testing.T) {
+		accessToken := newAccessToken(s.tokenProvider, worker.UserID.Hex(), worker.Role)
+		req := newRequest("/api/order/worker/list?cursor=not-a-cursor", http.MethodGet, accessToken, nil)
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("should return 403 because customers can not list all orders", func(t *testing.T) {
+		accessToken := newAccessToken(s.tokenProvider, customer.UserID.Hex(), customer.Role)
+		req := newRequest("/api/order/worker/list", http.MethodGet, accessToken, nil)
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusForbidden, res.StatusCode)
+	})
+}
+
+func (s *APISuite) TestCustomerOrders() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	t.Run("should return customer's own orders only", func(t *testing.T) {
+		accessToken := newAccessToken(s.tokenProvider, customer.UserID.Hex(), customer.Role)
+		req := newRequest("/api/order/my?limit=5", http.MethodGet, accessToken, nil)
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var out struct {
+			Orders []domain.Order `json:"orders"`
+		}
+		require.NoError(json.NewDecoder(res.Body).Decode(&out))
+		require.True(len(out.Orders) <= 5)
+		for _, order := range out.Orders {
+			require.Equal(customer.UserID.Hex(), order.CustomerID)
+		}
+	})
+
+	t.Run("should return 400 because cursor is invalid", func(t *testing.T) {
+		accessToken := newAccessToken(s.tokenProvider, customer.UserID.Hex(), customer.Role)
+		req := newRequest("/api/order/my?cursor=not-a-cursor", http.MethodGet, accessToken, nil)
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("should return current order with its nanoId", func(t *testing.T) {
+		cartProduct := products[1].(domain.Product)
+		orderID, err := s.services.Order.CreateWorkerOrder(context.Background(), dto.CreateWorkerOrderDTO{
+			CustomerID: customer.UserID.Hex(),
+			Cart: []dto.CartProductDTO{
+				{ProductID: cartProduct.ProductID.Hex(), Quantity: 1},
+			},
+			Pay: domain.PayOnPickup,
+		})
+		require.NoError(err)
+
+		accessToken := newAccessToken(s.tokenProvider, customer.UserID.Hex(), customer.Role)
+		req := newRequest("/api/order/my/current", http.MethodGet, accessToken, nil)
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var out struct {
+			NanoID string       `json:"nanoId"`
+			Order  domain.Order `json:"order"`
+		}
+		require.NoError(json.NewDecoder(res.Body).Decode(&out))
+		require.Equal(orderID, out.Order.OrderID.Hex())
+		require.Equal(out.Order.NanoID, out.NanoID)
+		require.NotZero(out.NanoID)
+	})
+
+	t.Run("should return 404 because customer has no current order", func(t *testing.T) {
+		accessToken := newAccessToken(s.tokenProvider, primitive.NewObjectID().Hex(), domain.RoleCustomer)
+		req := newRequest("/api/order/my/current", http.MethodGet, accessToken, nil)
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func (s *APISuite) TestCreateUserOrderWithPromoCode() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	adminToken := newAccessToken(s.tokenProvider, primitive.NewObjectID().Hex(), domain.RoleAdmin)
+	code := fmt.Sprintf("HALF%d", time.Now().UnixNano()%100000)
+	req := newRequest("/api/admins/promocodes/create", http.MethodPost, adminToken, newBody(input.CreatePromoCodeInput{
+		Code: code,
+		PromoCodeTermsInput: input.PromoCodeTermsInput{
+			Kind:             domain.DiscountPercent,
+			Percent:          0.5,
+			PerCustomerLimit: 1,
+		},
+	}))
+	res, err := s.app.Test(req, -1)
+	printResponseDetails(res)
+	require.NoError(err)
+	require.Equal(http.StatusCreated, res.StatusCode)
+
+	var (
+		customerID  = primitive.NewObjectID().Hex()
+		accessToken = newAccessToken(s.tokenProvider, customerID, domain.RoleCustomer)
+		cartProduct = products[0].(domain.Product)
+	)
+	createOrder := func(promoCode string) *http.Response {
+		req := newRequest("/api/order/create", http.MethodPost, accessToken, newBody(input.CreateUserOrderInput{
+			Pay: domain.PayOnPickup,
+			Cart: []input.CartProductInput{
+				{ProductID: cartProduct.ProductID.Hex(), Quantity: 2},
+			},
+			PromoCode: &promoCode,
+		}))
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+
+	t.Run("should create order with discount", func(t *testing.T) {
+		// Code is case-insensitive
+		res := createOrder(strings.ToLower(code))
+		require.Equal(http.StatusCreated, res.StatusCode)
+
+		var out struct {
+			OrderID string `json:"orderId"`
+		}
+		require.NoError(json.NewDecoder(res.Body).Decode(&out))
+
+		order, err := s.services.Order.GetOrderByID(context.Background(), out.OrderID)
+		require.NoError(err)
+		require.Equal(0.5, order.Discount)
+		require.Equal(cartProduct.Price*2, order.Amount)
+		require.Equal(cartProduct.Price, order.DiscountedAmount)
+		require.NotNil(order.PromoCode)
+		require.Equal(code, order.PromoCode.Code)
+	})
+
+	t.Run("should not create order because customer has used promo code", func(t *testing.T) {
+		res := createOrder(code)
+		require.Equal(http.StatusConflict, res.StatusCode)
+	})
+
+	t.Run("should not create order because promo code does not exist", func(t *testing.T) {
+		res := createOrder("NOSUCHCODE")
+		require.Equal(http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func (s *APISuite) TestCreateOrderIdempotency() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	cartProduct := products[0].(domain.Product)
+	inp := input.CreateWorkerOrderInput{
+		CustomerName: "Salieri",
+		PhoneNumber:  "+79458508375",
+		Cart: []input.CartProductInput{
+			{ProductID: cartProduct.ProductID.Hex(), Quantity: 1},
+		},
+		Pay: domain.PayOnPickup,
+	}
+	accessToken := newAccessToken(s.tokenProvider, worker.UserID.Hex(), worker.Role)
+	idempotencyKey := uuid.NewString()
+	create := func(inp input.CreateWorkerOrderInput) *http.Response {
+		req := newRequest("/api/order/worker/create", http.MethodPost, accessToken, newBody(inp))
+		req.Header.Set(middleware.HeaderIdempotencyKey, idempotencyKey)
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		return res
+	}
+	type createWorkerOrderResponse struct {
+		OrderID string `json:"orderId"`
+	}
+
+	t.Run("should replay response to retry instead of creating order again", func(t *testing.T) {
+		res := create(inp)
+		require.Equal(http.StatusCreated, res.StatusCode)
+		require.Empty(res.Header.Get(middleware.HeaderIdempotentReplayed))
+		var created createWorkerOrderResponse
+		require.NoError(json.NewDecoder(res.Body).Decode(&created))
+
+		res = create(inp)
+		require.Equal(http.StatusCreated, res.StatusCode)
+		require.Equal("true", res.Header.Get(middleware.HeaderIdempotentReplayed))
+		var replayed createWorkerOrderResponse
+		require.NoError(json.NewDecoder(res.Body).Decode(&replayed))
+		require.Equal(created.OrderID, replayed.OrderID)
+
+		customer, err := s.services.User.GetCustomerByPhoneNumber(context.Background(), inp.PhoneNumber)
+		require.NoError(err)
+		count, err := s.db.Collection(storage.CollectionOrders).CountDocuments(context.Background(), bson.M{
+			"customerId": customer.UserID.Hex(),
+		})
+		require.NoError(err)
+		require.Equal(int64(1), count)
+	})
+
+	t.Run("should return 422 because key is reused with another body", func(t *testing.T) {
+		other := inp
+		other.Cart = []input.CartProductInput{
+			{ProductID: cartProduct.ProductID.Hex(), Quantity: 2},
+		}
+		res := create(other)
+		require.Equal(http.StatusUnprocessableEntity, res.StatusCode)
 	})
 }
 
@@ -322,3 +763,255 @@ func newRequest(url, method, token string, body io.Reader) *http.Request {
 	req.Header.Set("Content-Type", "application/json")
 	return req
 }
+
+func (s *APISuite) TestDeliverySlots() {
+	var (
+		t       = s.T()
+		require = s.Require()
+	)
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	date := tomorrow.Format(domain.DateLayout)
+	slotStartsAt := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 12, 0, 0, 0, time.UTC)
+
+	getSlot := func() domain.DeliverySlot {
+		req, _ := http.NewRequest(http.MethodGet, buildURL("/api/delivery/slots?date="+date), nil)
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		var slots []domain.DeliverySlot
+		require.NoError(json.Unmarshal(readBody(res.Body), &slots))
+		// Store has no schedule, so it's open all day long
+		require.Len(slots, 48)
+		for _, slot := range slots {
+			if slot.StartsAt.Equal(slotStartsAt) {
+				return slot
+			}
+		}
+		require.FailNow("slot is not found")
+		return domain.DeliverySlot{}
+	}
+
+	t.Run("should reserve slot and release it when order is cancelled", func(t *testing.T) {
+		available := getSlot().Available
+
+		cartProduct := products[1].(domain.Product)
+		inp := input.CreateUserOrderInput{
+			Pay: domain.PayOnPickup,
+			Cart: []input.CartProductInput{
+				{ProductID: cartProduct.ProductID.Hex(), Quantity: 1},
+			},
+			IsDelivered: true,
+			DeliveryAddress: &domain.OrderDeliveryAddress{
+				IsAsap:      false,
+				Address:     "blabla",
+				Entrance:    1,
+				Floor:       2,
+				Apartment:   3,
+				DeliveredAt: slotStartsAt.Add(time.Minute * 10),
+				Latitude:    deliveryLatitude,
+				Longitude:   deliveryLongitude,
+			},
+		}
+		accessToken := newAccessToken(s.tokenProvider, customer.UserID.Hex(), customer.Role)
+		req := newRequest("/api/order/create", http.MethodPost, accessToken, newBody(inp))
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		require.Equal(http.StatusCreated, res.StatusCode)
+
+		var out struct {
+			OrderID string `json:"orderId"`
+		}
+		require.NoError(json.Unmarshal(readBody(res.Body), &out))
+
+		order, err := s.services.Order.GetOrderByID(context.Background(), out.OrderID)
+		require.NoError(err)
+		require.NotNil(order.Delivery.SlotStartsAt)
+		require.True(slotStartsAt.Equal(*order.Delivery.SlotStartsAt))
+		require.Equal(available-1, getSlot().Available)
+
+		require.NoError(s.services.Order.CancelOrder(context.Background(), out.OrderID, "changed mind"))
+		require.Equal(available, getSlot().Available)
+	})
+
+	t.Run("should reserve every place of new slot concurrently", func(t *testing.T) {
+		ctx := context.Background()
+		const capacity = 8
+		startsAt := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 3, 0, 0, 0, time.UTC)
+		defer s.db.Collection(storage.CollectionDeliverySlots).DeleteOne(ctx, bson.M{"_id": startsAt}) //nolint:errcheck
+
+		errs := make(chan error, capacity)
+		wg := new(sync.WaitGroup)
+		for i := 0; i < capacity; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- s.storages.DeliverySlot.Reserve(ctx, startsAt, capacity)
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(err)
+		}
+
+		require.ErrorIs(s.storages.DeliverySlot.Reserve(ctx, startsAt, capacity), domain.ErrDeliverySlotFull)
+	})
+
+	t.Run("should not list slots because date is invalid", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, buildURL("/api/delivery/slots?date=tomorrow"), nil)
+		res, err := s.app.Test(req, -1)
+		require.NoError(err)
+		printResponseDetails(res)
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func (s *APISuite) TestCreateOrderWithOptions() {
+	var (
+		t       = s.T()
+		require = s.Require()
+		ctx     = context.Background()
+	)
+
+	adminToken := newAccessToken(s.tokenProvider, uuid.NewString(), domain.RoleAdmin)
+	req := newRequest("/api/admins/products/create", http.MethodPost, adminToken, newBody(input.CreateProductInput{
+		Name:         f.BeerName() + uuid.NewString(),
+		TranslateRU:  f.Word(),
+		Description:  f.LoremIpsumSentence(5),
+		CategoryName: categoryPizza.Name,
+		Price:        500,
+		Features:     getNonLiquidFeatures(),
+		OptionGroups: []input.OptionGroupInput{
+			{
+				Name:       "size",
+				MinChoices: 1,
+				Options:    []input.OptionInput{{Name: "small"}, {Name: "large", PriceDelta: 200}},
+			},
+		},
+	}))
+	res, err := s.app.Test(req, -1)
+	printResponseDetails(res)
+	require.NoError(err)
+	require.Equal(http.StatusCreated, res.StatusCode)
+
+	var created struct {
+		ProductID string `json:"productId"`
+	}
+	require.NoError(json.NewDecoder(res.Body).Decode(&created))
+	productID := created.ProductID
+	defer s.services.Product.Delete(ctx, productID) //nolint:errcheck
+	require.NoError(s.services.Product.Approve(ctx, productID))
+
+	product, err := s.services.Product.GetByID(ctx, productID)
+	require.NoError(err)
+	require.Len(product.OptionGroups, 1)
+	size := product.OptionGroups[0]
+	require.Equal(int32(1), size.MaxChoices)
+
+	t.Run("should not update options with invalid limits", func(t *testing.T) {
+		req := newRequest("/api/admins/products/"+productID+"/options", http.MethodPut, adminToken, newBody(input.UpdateOptionGroupsInput{
+			OptionGroups: []input.OptionGroupInput{
+				{Name: "sauce", Multiple: true, MaxChoices: 3, Options: []input.OptionInput{{Name: "garlic"}}},
+			},
+		}))
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("should add option group keeping ids of existing options", func(t *testing.T) {
+		sizeInput := input.OptionGroupInput{GroupID: size.GroupID.Hex(), Name: size.Name, MinChoices: 1}
+		for _, option := range size.Options {
+			sizeInput.Options = append(sizeInput.Options, input.OptionInput{
+				OptionID:   option.OptionID.Hex(),
+				Name:       option.Name,
+				PriceDelta: option.PriceDelta,
+			})
+		}
+		req := newRequest("/api/admins/products/"+productID+"/options", http.MethodPut, adminToken, newBody(input.UpdateOptionGroupsInput{
+			OptionGroups: []input.OptionGroupInput{
+				sizeInput,
+				{
+					Name:       "toppings",
+					Multiple:   true,
+					MaxChoices: 2,
+					Options:    []input.OptionInput{{Name: "cheese", PriceDelta: 50}, {Name: "bacon", PriceDelta: 70}},
+				},
+			},
+		}))
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusOK, res.StatusCode)
+
+		product, err = s.services.Product.GetByID(ctx, productID)
+		require.NoError(err)
+		require.Len(product.OptionGroups, 2)
+		require.Equal(size, product.OptionGroups[0])
+	})
+
+	t.Run("should create order with options priced in", func(t *testing.T) {
+		var (
+			large  = product.OptionGroups[0].Options[1]
+			cheese = product.OptionGroups[1].Options[0]
+		)
+		inp := input.CreateWorkerOrderInput{
+			CustomerName: "Haydn",
+			PhoneNumber:  "+79458508376",
+			Cart: []input.CartProductInput{
+				{ProductID: productID, Quantity: 2, OptionIDs: []string{large.OptionID.Hex(), cheese.OptionID.Hex()}},
+			},
+			Pay: domain.PayOnPickup,
+		}
+
+		accessToken := newAccessToken(s.tokenProvider, worker.UserID.Hex(), worker.Role)
+		res, err := s.app.Test(newRequest("/api/order/worker/create", http.MethodPost, accessToken, newBody(inp)), -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusCreated, res.StatusCode)
+
+		var out struct {
+			OrderID string `json:"orderId"`
+		}
+		require.NoError(json.NewDecoder(res.Body).Decode(&out))
+		order, err := s.services.Order.GetOrderByID(ctx, out.OrderID)
+		require.NoError(err)
+
+		// 2 * (500 + 200 + 50)
+		require.Equal(int64(1500), order.Amount)
+		require.Len(order.Cart, 1)
+		require.Nil(order.Cart[0].OptionGroups)
+		require.Equal([]domain.ChosenOption{
+			{GroupID: product.OptionGroups[0].GroupID, GroupName: "size", OptionID: large.OptionID, Name: "large", PriceDelta: 200},
+			{GroupID: product.OptionGroups[1].GroupID, GroupName: "toppings", OptionID: cheese.OptionID, Name: "cheese", PriceDelta: 50},
+		}, order.Cart[0].Options)
+	})
+
+	t.Run("should not create order without required option", func(t *testing.T) {
+		inp := input.CreateWorkerOrderInput{
+			CustomerName: "Haydn",
+			PhoneNumber:  "+79458508376",
+			Cart:         []input.CartProductInput{{ProductID: productID, Quantity: 1}},
+			Pay:          domain.PayOnPickup,
+		}
+
+		accessToken := newAccessToken(s.tokenProvider, worker.UserID.Hex(), worker.Role)
+		res, err := s.app.Test(newRequest("/api/order/worker/create", http.MethodPost, accessToken, newBody(inp)), -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusBadRequest, res.StatusCode)
+
+		var out struct {
+			Lines []domain.CartLineError `json:"lines"`
+		}
+		require.NoError(json.NewDecoder(res.Body).Decode(&out))
+		require.Equal([]domain.CartLineError{
+			{Line: 0, ProductID: productID, GroupID: size.GroupID.Hex(), Reason: domain.CartLineTooFewOptions},
+		}, out.Lines)
+	})
+}
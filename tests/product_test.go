@@ -1,37 +1,83 @@
 package tests
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"testing"
 
+	f "github.com/brianvoe/gofakeit/v6"
+	"github.com/google/uuid"
 	"github.com/sonyamoonglade/sancho-backend/internal/domain"
+	"github.com/sonyamoonglade/sancho-backend/internal/services/dto"
 )
 
 func (s *APISuite) TestGetCatalog() {
-	require := s.Require()
-	req, _ := http.NewRequest(http.MethodGet, buildURL("/api/products/catalog"), nil)
+	var (
+		t       = s.T()
+		require = s.Require()
+		ctx     = context.Background()
+	)
 
-	res, err := s.app.Test(req)
-	printResponseDetails(res)
+	draftID, err := s.services.Product.Create(ctx, dto.CreateProductDTO{
+		Name:         f.BeerName() + uuid.NewString(),
+		TranslateRU:  f.Word(),
+		Description:  f.LoremIpsumSentence(5),
+		CategoryName: categoryPizza.Name,
+		Price:        int64(f.IntRange(100, 500)),
+		Features:     getNonLiquidFeatures(),
+	})
 	require.NoError(err)
-	require.Equal(http.StatusOK, res.StatusCode)
+	defer s.services.Product.Delete(ctx, draftID) //nolint:errcheck
 
-	body := readBody(res.Body)
+	t.Run("should return approved products grouped by category", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, buildURL("/api/products/catalog"), nil)
+		res, err := s.app.Test(req)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusOK, res.StatusCode)
 
-	var out struct {
-		Catalog []domain.Product `json:"catalog"`
-	}
+		var out struct {
+			Catalog []domain.CatalogCategory `json:"catalog"`
+		}
+		require.NoError(json.Unmarshal(readBody(res.Body), &out))
+		require.NotEmpty(out.Catalog)
 
-	err = json.Unmarshal(body, &out)
-	require.NoError(err)
+		var ranks []int32
+		for _, category := range out.Catalog {
+			ranks = append(ranks, category.Rank)
+			require.NotEmpty(category.Products)
+			for _, product := range category.Products {
+				require.True(product.IsApproved)
+				require.NotEqual(draftID, product.ProductID.Hex())
+				require.Equal(category.CategoryID, product.Category.CategoryID)
+			}
+		}
+		require.True(checkIsDescending(ranks))
+	})
 
-	var ranks []int32
-	for _, product := range out.Catalog {
-		require.NotNil(product)
-		ranks = append(ranks, product.Category.Rank)
-	}
+	t.Run("should return drafts to admin", func(t *testing.T) {
+		accessToken := newAccessToken(s.tokenProvider, uuid.NewString(), domain.RoleAdmin)
+		req := newRequest("/api/admins/products", http.MethodGet, accessToken, nil)
+		res, err := s.app.Test(req, -1)
+		printResponseDetails(res)
+		require.NoError(err)
+		require.Equal(http.StatusOK, res.StatusCode)
 
-	require.True(checkIsDescending(ranks))
+		var out struct {
+			Products []domain.Product `json:"products"`
+		}
+		require.NoError(json.Unmarshal(readBody(res.Body), &out))
+
+		var found bool
+		for _, product := range out.Products {
+			if product.ProductID.Hex() == draftID {
+				found = true
+				require.False(product.IsApproved)
+			}
+		}
+		require.True(found)
+	})
 }
 
 func (s *APISuite) TestGetCategories() {